@@ -0,0 +1,63 @@
+// Command fakemarket runs a local stand-in for the Alpaca, Public.com, FRED
+// and Ethereum JSON-RPC APIs and a bitcoin ETF flows source. Start it, export the variables it prints and
+// run the app against it to exercise the collector, handlers and workers
+// without credentials or network access.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/fakemarket"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8099", "address to listen on")
+	expiries := flag.Int("expiries", 8, "weekly expirations listed per symbol")
+	strikes := flag.Int("strikes", 15, "strikes listed on each side of spot")
+	flag.Parse()
+
+	market := fakemarket.New()
+	market.Expiries = *expiries
+	market.StrikesPerSide = *strikes
+
+	base := "http://" + *addr
+	fmt.Printf("Fake market listening on %s. Point the app at it with:\n\n", base)
+	for _, kv := range [][2]string{
+		{"ALPACA_API_KEY", "fake"},
+		{"ALPACA_API_SECRET", "fake"},
+		{"ALPACA_DATA_BASE_URL", base},
+		{"ALPACA_LIVE_BASE_URL", base},
+		{"ALPACA_PAPER_BASE_URL", base},
+		{"PUBLIC_PERSONAL_SECRET", "fake"},
+		{"PUBLIC_API_BASE_URL", base},
+		{"FRED_API_KEY", "fake"},
+		{"FRED_API_BASE_URL", base + "/fred"},
+		{"ETH_RPC_URL", base + "/rpc"},
+		{"BTC_ETF_FLOWS_URL", base + "/btc-etf/flows.csv"},
+	} {
+		fmt.Printf("  export %s=%s\n", kv[0], kv[1])
+	}
+	fmt.Println()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           logRequests(market.Handler()),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "fakemarket: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		fmt.Printf("[%s] %s %s (%s)\n", start.Format(time.RFC3339), r.Method, r.URL.RequestURI(), time.Since(start))
+	})
+}
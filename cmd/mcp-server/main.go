@@ -3,19 +3,27 @@ package main
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/arnabmitra/eth-proxy/internal/handler/gex"
 	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/arnabmitra/eth-proxy/internal/zscore"
 	"github.com/jackc/pgx/v5/pgxpool"
 	mcp "github.com/metoro-io/mcp-golang"
 	"github.com/metoro-io/mcp-golang/transport/stdio"
 )
 
 type GEXMcpServer struct {
-	db   *pgxpool.Pool
-	repo *repository.Queries
+	db     *pgxpool.Pool
+	repo   *repository.Queries
+	scorer *zscore.Scorer
+	zscore zscore.Config
 }
 
 type RegimeArgs struct {
@@ -29,17 +37,17 @@ func (s *GEXMcpServer) GetRegime(ctx context.Context, args RegimeArgs) (*mcp.Too
 	}
 
 	symbol := strings.ToUpper(args.Symbol)
-	price, err := gex.GetSpotPrice(apiKey, apiSecret, symbol)
+	price, err := gex.GetSpotPrice(ctx, apiKey, apiSecret, symbol)
 	if err != nil {
 		return nil, fmt.Errorf("error getting spot price: %v", err)
 	}
 
-	expirations, err := gex.GetExpirationDates(apiKey, apiSecret, symbol)
+	expirations, err := gex.GetExpirationDates(ctx, apiKey, apiSecret, symbol)
 	if err != nil || len(expirations) == 0 {
 		return nil, fmt.Errorf("error getting expirations: %v", err)
 	}
 
-	options, _, warning, err := gex.FetchOptionsChain(symbol, expirations[0], apiKey, apiSecret)
+	options, _, warning, err := gex.FetchOptionsChain(ctx, symbol, expirations[0], apiKey, apiSecret)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching options: %v", err)
 	}
@@ -77,7 +85,10 @@ func (s *GEXMcpServer) GetRegime(ctx context.Context, args RegimeArgs) (*mcp.Too
 }
 
 type AnomaliesArgs struct {
-	Limit int `json:"limit" jsonschema:"description=Number of anomalies to return,default=5"`
+	Limit        int    `json:"limit" jsonschema:"description=Number of anomalies to return,default=5"`
+	Method       string `json:"method,omitempty" jsonschema:"description=Z-score method: stddev, mad, ewma or percentile"`
+	LookbackDays int    `json:"lookback_days,omitempty" jsonschema:"description=Days of history to compare against (5-365)"`
+	Normalize    string `json:"normalize,omitempty" jsonschema:"description=Normalize GEX before scoring: none, market_cap or open_interest"`
 }
 
 func (s *GEXMcpServer) GetAnomalies(ctx context.Context, args AnomaliesArgs) (*mcp.ToolResponse, error) {
@@ -85,13 +96,37 @@ func (s *GEXMcpServer) GetAnomalies(ctx context.Context, args AnomaliesArgs) (*m
 		args.Limit = 5
 	}
 
-	anomalies, err := s.repo.GetGEXAnomalies(ctx)
+	q := url.Values{}
+	if args.Method != "" {
+		q.Set("z_method", args.Method)
+	}
+	if args.LookbackDays != 0 {
+		q.Set("z_lookback", strconv.Itoa(args.LookbackDays))
+	}
+	if args.Normalize != "" {
+		q.Set("z_norm", args.Normalize)
+	}
+	cfg, err := zscore.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+	cfg = cfg.WithDefaults(s.zscore)
+
+	scores, err := s.scorer.Latest(ctx, cfg, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("error fetching anomalies: %v", err)
 	}
 
+	anomalies := make([]zscore.Result, 0, len(scores))
+	for _, r := range scores {
+		anomalies = append(anomalies, r)
+	}
+	sort.Slice(anomalies, func(i, j int) bool {
+		return math.Abs(anomalies[i].Score) > math.Abs(anomalies[j].Score)
+	})
+
 	var sb strings.Builder
-	sb.WriteString("Current GEX Anomalies (Z-Score):\n\n")
+	sb.WriteString(fmt.Sprintf("Current GEX Anomalies (Z-Score, %s):\n\n", cfg))
 	
 	count := 0
 	for _, a := range anomalies {
@@ -99,26 +134,13 @@ func (s *GEXMcpServer) GetAnomalies(ctx context.Context, args AnomaliesArgs) (*m
 			break
 		}
 		
-		var zScoreVal float64
-		if a.ZScore.Valid {
-			f, _ := a.ZScore.Float64Value()
-			zScoreVal = f.Float64
-		}
-
-		var gexValueM float64
-		if a.GexValue.Valid {
-			f, _ := a.GexValue.Float64Value()
-			gexValueM = f.Float64 / 1000000.0
-		}
-		
-		// Handle pgtype.Text for SpotPrice
 		spotPriceStr := "N/A"
-		if a.SpotPrice.Valid {
-			spotPriceStr = a.SpotPrice.String
+		if a.Spot > 0 {
+			spotPriceStr = strconv.FormatFloat(a.Spot, 'f', 2, 64)
 		}
 		
 		sb.WriteString(fmt.Sprintf("- %s: GEX $%.2fM (Z-Score: %.2f) @ Price %s\n",
-			a.Symbol, gexValueM, zScoreVal, spotPriceStr))
+			a.Symbol, a.GEX/1000000.0, a.Score, spotPriceStr))
 		count++
 	}
 
@@ -138,9 +160,17 @@ func main() {
 	}
 	defer pool.Close()
 
+	zcfg, err := zscore.FromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid Z-score settings, using defaults: %v\n", err)
+	}
+
+	repo := repository.New(pool)
 	server := &GEXMcpServer{
-		db:   pool,
-		repo: repository.New(pool),
+		db:     pool,
+		repo:   repo,
+		scorer: zscore.NewScorer(repo),
+		zscore: zcfg,
 	}
 
 	mcpServer := mcp.NewServer(stdio.NewStdioServerTransport())
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -37,29 +38,30 @@ func main() {
 	}
 
 	client := public.NewClient(secret, accountID)
+	ctx := context.Background()
 
 	fmt.Printf("Authenticating with Public.com API...\n")
-	err := client.Authenticate()
+	err := client.Authenticate(ctx)
 	if err != nil {
 		log.Fatalf("Authentication failed: %v", err)
 	}
 
 	fmt.Printf("Fetching Account ID...\n")
-	err = client.FetchAccountID()
+	err = client.FetchAccountID(ctx)
 	if err != nil {
 		log.Fatalf("Failed to fetch Account ID: %v", err)
 	}
-	fmt.Printf("Using Account ID: %s\n", client.AccountID)
+	fmt.Printf("Using Account ID: %s\n", client.AccountID())
 
 	fmt.Printf("Fetching spot price for %s...\n", symbol)
-	spotPrice, err := client.GetSpotPrice(symbol)
+	spotPrice, err := client.GetSpotPrice(ctx, symbol)
 	if err != nil {
 		log.Fatalf("Failed to fetch spot price: %v", err)
 	}
 	fmt.Printf("Spot Price: %.2f\n", spotPrice)
 
 	fmt.Printf("Fetching option chain for %s (Exp: %s)...\n", symbol, expiration)
-	chain, _, err := client.GetOptionChain(symbol, expiration)
+	chain, _, err := client.GetOptionChain(ctx, symbol, expiration)
 	if err != nil {
 		log.Fatalf("Failed to fetch option chain: %v", err)
 	}
@@ -2,22 +2,24 @@ package app
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
-	"io/ioutil"
-	"log"
 	"log/slog"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/arnabmitra/eth-proxy/internal/btcetf"
+	"github.com/arnabmitra/eth-proxy/internal/cache"
 	"github.com/arnabmitra/eth-proxy/internal/database"
+	"github.com/arnabmitra/eth-proxy/internal/eth"
+	"github.com/arnabmitra/eth-proxy/internal/macro"
 	"github.com/arnabmitra/eth-proxy/internal/middleware"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
 	"github.com/arnabmitra/eth-proxy/internal/worker"
+	"github.com/arnabmitra/eth-proxy/internal/zscore"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
@@ -27,9 +29,14 @@ type App struct {
 	router                    *http.ServeMux
 	db                        *pgxpool.Pool
 	rdb                       *redis.Client
+	cache                     *cache.Cache
 	gexCollector              *worker.GexCollector
 	economicCalendarCollector *worker.EconomicCalendarCollector
+	macroCollector            *worker.MacroCollector
+	btcETFCollector           *worker.BTCETFCollector
 	alertWorker               *worker.AlertWorker
+	snapshotRefresher         *worker.SnapshotRefresher
+	zscore                    zscore.Config
 }
 
 func New(logger *slog.Logger) *App {
@@ -61,18 +68,73 @@ func (a *App) Start(ctx context.Context) error {
 
 	tmpl := template.Must(template.New("").ParseGlob("./templates/*"))
 
+	// Default Z-score method for the scanner, sector pages and alerts, from
+	// ZSCORE_METHOD, ZSCORE_LOOKBACK_DAYS, ZSCORE_NORMALIZE and
+	// ZSCORE_HALF_LIFE_DAYS.
+	a.zscore, err = zscore.FromEnv()
+	if err != nil {
+		a.logger.Error("invalid Z-score settings, using defaults for those values", slog.Any("error", err))
+	}
+
+	a.cache = newCache(a.logger, a.rdb)
+
 	gexHandler, queries := a.loadRoutes()
 
-	// Initialize the GexCollector with the handler and symbols
-	a.gexCollector = worker.NewGEXCollector(gexHandler, worker.SP500Symbols(), 30*time.Minute)
+	// Initialize the GexCollector with per-symbol schedules built from the
+	// universes in GEX_COLLECTOR_UNIVERSES (default "sp500"), re-read every 15
+	// minutes. GEX_COLLECTOR_SCHEDULE can override individual symbols, e.g.
+	// "SPY=2m:200,NVDA=15m".
+	universes := universe.NewStore(a.db)
+	overrides := os.Getenv("GEX_COLLECTOR_SCHEDULE")
+	if _, err := worker.ApplyScheduleOverrides(nil, overrides); err != nil {
+		a.logger.Error("invalid GEX_COLLECTOR_SCHEDULE, ignoring overrides", slog.Any("error", err))
+		overrides = ""
+	}
+	scheduleSource := collectorScheduleSource(universes, envList("GEX_COLLECTOR_UNIVERSES", universe.SP500), overrides)
+	schedules, err := scheduleSource(ctx)
+	if err != nil || len(schedules) == 0 {
+		a.logger.Error("failed to load collector universes, using built-in S&P 500 list", slog.Any("error", err))
+		schedules = worker.DefaultSchedules(worker.SP500Symbols())
+	}
+	a.gexCollector = worker.NewGEXCollector(gexHandler, worker.NewRunLedger(queries), schedules)
+	a.gexCollector.SetScheduleSource(scheduleSource, 15*time.Minute)
 	a.gexCollector.Start()
 
+	// Keep the all-expiry GEX snapshots behind the MAG7, universe grid and
+	// all-expiry pages fresh for the universes in GEX_SNAPSHOT_UNIVERSES
+	// (default "mag7"). GEX_SNAPSHOT_TTL (default 5m) is how old a snapshot
+	// may get before a page view refreshes it in the background.
+	if ttl := os.Getenv("GEX_SNAPSHOT_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil || d < time.Minute {
+			a.logger.Error("invalid GEX_SNAPSHOT_TTL, using default", slog.String("value", ttl), slog.Any("error", err))
+		} else {
+			gexHandler.SetSnapshotTTL(d)
+		}
+	}
+	a.snapshotRefresher = worker.NewSnapshotRefresher(gexHandler, universes, envList("GEX_SNAPSHOT_UNIVERSES", universe.Mag7), a.logger)
+	a.snapshotRefresher.Start()
+
 	// Initialize Economic Calendar Collector
 	a.economicCalendarCollector = worker.NewEconomicCalendarCollector(queries)
 	a.economicCalendarCollector.Start()
 
+	// Keep the macro dashboard's FRED series up to date
+	a.macroCollector = worker.NewMacroCollector(macro.NewStore(a.db), a.logger)
+	a.macroCollector.Start()
+
+	// Keep the spot bitcoin ETF flows up to date from BTC_ETF_FLOWS_URL
+	if source, ok := btcetf.SourceFromEnv(); ok {
+		a.btcETFCollector = worker.NewBTCETFCollector(source, btcetf.NewStore(a.db), a.logger)
+		a.btcETFCollector.Start()
+	} else {
+		a.logger.Info("BTC_ETF_FLOWS_URL not set, BTC ETF flows collection disabled")
+	}
+
+	a.loadAdminRoutes(tmpl, queries)
+
 	// Initialize Alert Worker
-	a.alertWorker = worker.NewAlertWorker(queries, a.logger)
+	a.alertWorker = worker.NewAlertWorker(queries, a.logger, universes, envList("ALERT_UNIVERSES", ""), a.zscore)
 	a.alertWorker.Start()
 
 	server := http.Server{
@@ -101,54 +163,57 @@ func (a *App) Start(ctx context.Context) error {
 		if a.economicCalendarCollector != nil {
 			a.economicCalendarCollector.Stop()
 		}
+		if a.macroCollector != nil {
+			a.macroCollector.Stop()
+		}
+		if a.btcETFCollector != nil {
+			a.btcETFCollector.Stop()
+		}
 		if a.alertWorker != nil {
 			a.alertWorker.Stop()
 		}
+		if a.snapshotRefresher != nil {
+			a.snapshotRefresher.Stop()
+		}
 		server.Shutdown(ctx)
 		cancel()
 	}
 
 	return nil
 }
-func btcEtfHandler(w http.ResponseWriter, r *http.Request) {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-
-	logger.Info("Received request", slog.String("method", r.Method), slog.String("url", r.URL.String()))
 
-	tmpl, err := template.ParseFiles("templates/navigation.html", "templates/btc_etf.html")
-	if err != nil {
-		http.Error(w, "Failed to load template", http.StatusInternalServerError)
-		return
+// collectorScheduleSource builds the collector's schedules from the members
+// of slugs with overrides applied on top.
+func collectorScheduleSource(universes *universe.Store, slugs []string, overrides string) worker.ScheduleSource {
+	return func(ctx context.Context) ([]worker.SymbolSchedule, error) {
+		symbols, err := universes.Union(ctx, slugs)
+		if err != nil {
+			return nil, fmt.Errorf("load universes %v: %w", slugs, err)
+		}
+		if len(symbols) == 0 {
+			return nil, fmt.Errorf("universes %v have no members", slugs)
+		}
+		schedules, err := worker.ApplyScheduleOverrides(worker.DefaultSchedules(symbols), overrides)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GEX_COLLECTOR_SCHEDULE: %w", err)
+		}
+		return schedules, nil
 	}
-
-	tmpl.ExecuteTemplate(w, "btc_etf.html", nil)
 }
 
-func ethTxHandler(w http.ResponseWriter, r *http.Request) {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-
-	logger.Info("Received request", slog.String("method", r.Method), slog.String("url", r.URL.String()))
-
-	tmpl, err := template.ParseFiles("templates/navigation.html", "templates/eth.html")
-	if err != nil {
-		http.Error(w, "Failed to load template", http.StatusInternalServerError)
-		return
+// envList splits a comma-separated env var, falling back to def when unset.
+func envList(key, def string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		value = def
 	}
-	if r.Method == http.MethodPost {
-		txHash := r.FormValue("txhash")
-		details, err := fetchTxDetails(txHash)
-		if err != nil {
-			http.Error(w, "Failed to fetch transaction details", http.StatusInternalServerError)
-			return
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
-
-		tmpl.ExecuteTemplate(w, "transaction-details", struct {
-			TxHash  string
-			Details *AlchemyResponse
-		}{TxHash: txHash, Details: details})
-	} else {
-		tmpl.Execute(w, nil)
 	}
+	return items
 }
 
 // Add this new handler function
@@ -258,68 +323,36 @@ func aboutUsHandler(w http.ResponseWriter, r *http.Request) {
 	tmpl.ExecuteTemplate(w, "about-us.html", nil)
 }
 
-type AlchemyResponse struct {
-	Jsonrpc string `json:"jsonrpc"`
-	Id      int    `json:"id"`
-	Result  struct {
-		BlockHash        string `json:"blockHash"`
-		BlockNumber      HexInt `json:"blockNumber"`
-		From             string `json:"from"`
-		Gas              HexInt `json:"gas"`
-		GasPrice         HexInt `json:"gasPrice"`
-		Hash             string `json:"hash"`
-		Input            string `json:"input"`
-		Nonce            HexInt `json:"nonce"`
-		To               string `json:"to"`
-		TransactionIndex HexInt `json:"transactionIndex"`
-		Value            HexInt `json:"value"`
-		V                string `json:"v"`
-		R                string `json:"r"`
-		S                string `json:"s"`
-	} `json:"result"`
-}
-type HexInt int64
+// ethHTTPClient carries JSON-RPC calls to the Ethereum node.
+var ethHTTPClient = &http.Client{Timeout: 15 * time.Second}
 
-func (h *HexInt) UnmarshalJSON(data []byte) error {
-	// Remove the quotes around the JSON string
-	hexStr := strings.Trim(string(data), "\"")
-	if len(hexStr) > 2 && hexStr[:2] == "0x" {
-		hexStr = hexStr[3:]
-	}
-	// Convert the hexadecimal string to an integer
-	intValue, err := strconv.ParseInt(hexStr, 16, 64)
-	if err != nil {
-		return err
-	}
-	*h = HexInt(intValue)
-	return nil
-}
-
-func fetchTxDetails(txHash string) (*AlchemyResponse, error) {
-
-	//apiKey := os.Getenv("ETH_API_KEY")
-
-	ethAPIKeyFile := os.Getenv("ETH_API_KEY_FILE")
-	ethAPIKey, err := ioutil.ReadFile(ethAPIKeyFile)
-	if err != nil {
-		log.Fatalf("failed to read ETH_API_KEY_FILE: %v", err)
-	}
-	trimmedAPIKey := strings.TrimSpace(string(ethAPIKey))
-	// print out the api key in the logs temporarily
-	url := fmt.Sprintf("https://eth-mainnet.alchemyapi.io/v2/%s", trimmedAPIKey)
-
-	payload := fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_getTransactionByHash","params":["%s"],"id":1}`, txHash)
-	resp, err := http.Post(url, "application/json", strings.NewReader(payload))
-	if err != nil {
-		return nil, err
+// ethClients returns a client for every network with an RPC endpoint
+// configured (see eth.EndpointsFromEnv) and checks in the background that
+// each endpoint is on the right chain.
+func (a *App) ethClients() []*eth.Client {
+	var clients []*eth.Client
+	for _, n := range eth.Networks {
+		endpoints, err := eth.EndpointsFromEnv(n)
+		if err != nil {
+			a.logger.Warn("Ethereum RPC endpoint not configured", slog.String("network", n.Name), slog.Any("error", err))
+		}
+		if len(endpoints) == 0 {
+			continue
+		}
+		clients = append(clients, eth.NewClient(n, endpoints, ethHTTPClient))
 	}
-	defer resp.Body.Close()
-
-	var alchemyResponse AlchemyResponse
-	if err := json.NewDecoder(resp.Body).Decode(&alchemyResponse); err != nil {
-		return nil, err
+	if len(clients) == 0 {
+		a.logger.Warn("No Ethereum RPC endpoints configured; set ETH_RPC_URL or ETH_API_KEY_FILE")
 	}
 
-	fmt.Printf("The response from alchemy is %v \n ", alchemyResponse)
-	return &alchemyResponse, nil
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		for _, c := range clients {
+			if err := c.Verify(ctx); err != nil {
+				a.logger.Warn("Ethereum RPC endpoint check failed", slog.String("network", c.Network().Name), slog.Any("error", err))
+			}
+		}
+	}()
+	return clients
 }
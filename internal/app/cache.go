@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// newCache builds the provider cache. CACHE_BACKEND picks "redis" or
+// "memory"; it defaults to redis when REDIS_ADDR is set. An unreachable Redis
+// falls back to memory rather than failing every lookup. CACHE_TTL overrides
+// per-kind TTLs, e.g. "spot=30s,chain=2m".
+func newCache(logger *slog.Logger, rdb *redis.Client) *cache.Cache {
+	policy, err := cache.ParsePolicy(os.Getenv("CACHE_TTL"), cache.DefaultPolicy())
+	if err != nil {
+		logger.Error("invalid CACHE_TTL, using default TTLs", slog.Any("error", err))
+		policy = cache.DefaultPolicy()
+	}
+
+	backend := os.Getenv("CACHE_BACKEND")
+	if backend == "" {
+		backend = "memory"
+		if _, ok := os.LookupEnv("REDIS_ADDR"); ok {
+			backend = "redis"
+		}
+	}
+
+	if backend == "redis" {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			logger.Error("redis unreachable, caching in memory", slog.Any("error", err))
+		} else {
+			logger.Info("caching provider data in redis")
+			return cache.New(cache.NewRedis(rdb, "cache:"), policy)
+		}
+	} else if backend != "memory" {
+		logger.Error("unknown CACHE_BACKEND, caching in memory", slog.String("backend", backend))
+	}
+	return cache.New(cache.NewMemory(), policy)
+}
+
+// cacheStats serves the hit and miss counters of each kind of cached data.
+func cacheStats(c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(c.Stats())
+	}
+}
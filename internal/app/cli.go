@@ -0,0 +1,248 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/database"
+	"github.com/arnabmitra/eth-proxy/internal/handler"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+	"github.com/arnabmitra/eth-proxy/internal/worker"
+)
+
+// RunCommand runs a one-off maintenance subcommand instead of the server:
+//
+//	collect  -symbols SPY,QQQ [-expiries 2026-10-23,2026-10-30]
+//	backfill -from 2026-01-01 [-to 2026-02-01] [-symbols SPY] [-dry-run]
+//	import-universe -universe nasdaq100 -file ndx.csv [-name "Nasdaq 100"] [-kind index] [-append]
+//	import-symbols  -file symbols.csv
+func RunCommand(ctx context.Context, logger *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command given")
+	}
+
+	switch args[0] {
+	case "collect":
+		return runCollect(ctx, logger, args[1:])
+	case "backfill":
+		return runBackfill(ctx, logger, args[1:])
+	case "import-universe":
+		return runImportUniverse(ctx, logger, args[1:])
+	case "import-symbols":
+		return runImportSymbols(ctx, logger, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q (want collect, backfill, import-universe or import-symbols)", args[0])
+	}
+}
+
+func runCollect(ctx context.Context, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("collect", flag.ContinueOnError)
+	symbols := fs.String("symbols", "", "comma-separated symbols to collect")
+	expiries := fs.String("expiries", "", "comma-separated expiries (YYYY-MM-DD); nearest expiry if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	req := worker.CollectRequest{
+		Symbols:  splitList(*symbols),
+		Expiries: splitList(*expiries),
+		Trigger:  worker.TriggerCLI,
+	}.Normalize()
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	db, err := database.Connect(ctx, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to db: %w", err)
+	}
+	defer db.Close()
+
+	queries := repository.New(db)
+	collector := worker.NewGEXCollector(handler.NewGEXHandler(logger, nil, db), worker.NewRunLedger(queries), nil)
+
+	runID, err := collector.StartManualRun(ctx, req)
+	if err != nil {
+		return err
+	}
+	attempts, err := collector.CollectNow(ctx, runID, req)
+	if err != nil {
+		return err
+	}
+
+	failures := 0
+	for _, a := range attempts {
+		if a.Err != nil {
+			failures++
+			fmt.Printf("%-6s %-12s %s\n", a.Symbol, a.Status, a.Err)
+			continue
+		}
+		fmt.Printf("%-6s %-12s %s in %s\n", a.Symbol, a.Status, a.Provider, a.Duration.Round(time.Millisecond))
+	}
+	fmt.Printf("run %s: %d attempted, %d failed\n", runID, len(attempts), failures)
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d collections failed", failures, len(attempts))
+	}
+	return nil
+}
+
+func runBackfill(ctx context.Context, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	symbols := fs.String("symbols", "", "comma-separated symbols; all symbols if empty")
+	from := fs.String("from", "", "start of the range (YYYY-MM-DD or RFC 3339)")
+	to := fs.String("to", "", "end of the range, exclusive; now if empty")
+	dryRun := fs.Bool("dry-run", false, "report changes without writing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts, err := backfillOptions(splitList(*symbols), *from, *to, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Connect(ctx, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to db: %w", err)
+	}
+	defer db.Close()
+
+	result, err := worker.Backfill(ctx, repository.New(db), opts)
+	if err != nil {
+		return err
+	}
+
+	verb := "updated"
+	if opts.DryRun {
+		verb = "would update"
+	}
+	fmt.Printf("scanned %d rows: %s %d, unchanged %d, skipped %d\n",
+		result.Scanned, verb, result.Updated, result.Unchanged, result.Skipped)
+	return nil
+}
+
+func runImportUniverse(ctx context.Context, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("import-universe", flag.ContinueOnError)
+	slug := fs.String("universe", "", "universe slug, e.g. nasdaq100")
+	name := fs.String("name", "", "display name; defaults to the slug")
+	kind := fs.String("kind", universe.KindIndex, "index, sector or watchlist")
+	owner := fs.String("owner", "", "owner of a watchlist")
+	file := fs.String("file", "", "CSV file with a symbol or ticker column")
+	appendMembers := fs.Bool("append", false, "keep existing members instead of replacing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	def := universe.Definition{Slug: *slug, Name: *name, Kind: *kind, Owner: *owner}
+	if err := def.Validate(); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("file is required")
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	symbols, err := universe.ParseCSV(f)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Connect(ctx, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to db: %w", err)
+	}
+	defer db.Close()
+
+	n, err := universe.NewStore(db).Import(ctx, def, symbols, !*appendMembers)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("imported %d symbols into %s\n", n, def.Slug)
+	return nil
+}
+
+func runImportSymbols(ctx context.Context, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("import-symbols", flag.ContinueOnError)
+	file := fs.String("file", "", "CSV file with symbol, name, sector, industry and market_cap columns")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return fmt.Errorf("file is required")
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := universe.ParseMetadataCSV(f)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Connect(ctx, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to db: %w", err)
+	}
+	defer db.Close()
+
+	n, err := universe.NewStore(db).ImportMetadata(ctx, rows)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("imported metadata for %d symbols\n", n)
+	return nil
+}
+
+func backfillOptions(symbols []string, from, to string, dryRun bool) (worker.BackfillOptions, error) {
+	opts := worker.BackfillOptions{DryRun: dryRun}
+	for _, s := range symbols {
+		if s = strings.ToUpper(strings.TrimSpace(s)); s != "" {
+			opts.Symbols = append(opts.Symbols, s)
+		}
+	}
+
+	if from == "" {
+		return opts, fmt.Errorf("from is required")
+	}
+	var err error
+	if opts.From, err = parseDateOrTime(from); err != nil {
+		return opts, fmt.Errorf("invalid from: %w", err)
+	}
+	if to != "" {
+		if opts.To, err = parseDateOrTime(to); err != nil {
+			return opts, fmt.Errorf("invalid to: %w", err)
+		}
+		if !opts.To.After(opts.From) {
+			return opts, fmt.Errorf("to must be after from")
+		}
+	}
+	return opts, nil
+}
+
+func parseDateOrTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
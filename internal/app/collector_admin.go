@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/arnabmitra/eth-proxy/internal/worker"
+)
+
+// collectorAdmin serves the authenticated endpoints that drive the GEX
+// collector on demand. It lives here rather than in the handler package
+// because the worker package already depends on handler.
+type collectorAdmin struct {
+	logger    *slog.Logger
+	collector *worker.GexCollector
+	queries   *repository.Queries
+}
+
+type triggerRequest struct {
+	Symbols  []string `json:"symbols"`
+	Expiries []string `json:"expiries"`
+	Wait     bool     `json:"wait"`
+}
+
+type attemptResult struct {
+	Symbol     string `json:"symbol"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	Provider   string `json:"provider,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Trigger collects the requested symbols (and optionally expiries) right
+// away. Symbols and expiries come from a JSON body or from comma-separated
+// query parameters. By default the collection runs in the background and the
+// run ID is returned for /admin/collector; wait=true blocks until it is done.
+func (h *collectorAdmin) Trigger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body triggerRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	}
+	q := r.URL.Query()
+	if v := q.Get("symbols"); v != "" {
+		body.Symbols = append(body.Symbols, strings.Split(v, ",")...)
+	}
+	if v := q.Get("expiries"); v != "" {
+		body.Expiries = append(body.Expiries, strings.Split(v, ",")...)
+	}
+	if q.Get("wait") == "true" {
+		body.Wait = true
+	}
+
+	req := worker.CollectRequest{
+		Symbols:  body.Symbols,
+		Expiries: body.Expiries,
+		Trigger:  worker.TriggerManual,
+	}.Normalize()
+	if err := req.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	runID, err := h.collector.StartManualRun(r.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to start manual collector run", slog.Any("error", err))
+		http.Error(w, "Failed to start run", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !body.Wait {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+			defer cancel()
+			if _, err := h.collector.CollectNow(ctx, runID, req); err != nil {
+				h.logger.Error("Manual collection failed", slog.String("run_id", runID.String()), slog.Any("error", err))
+			}
+		}()
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"run_id":   runID.String(),
+			"symbols":  req.Symbols,
+			"expiries": req.Expiries,
+		})
+		return
+	}
+
+	attempts, err := h.collector.CollectNow(r.Context(), runID, req)
+	if err != nil {
+		h.logger.Error("Manual collection failed", slog.Any("error", err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	results := make([]attemptResult, 0, len(attempts))
+	for _, a := range attempts {
+		res := attemptResult{
+			Symbol:     a.Symbol,
+			Status:     a.Status,
+			Provider:   a.Provider,
+			DurationMs: a.Duration.Milliseconds(),
+		}
+		if a.Err != nil {
+			res.Error = a.Err.Error()
+		}
+		results = append(results, res)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"run_id":  runID.String(),
+		"results": results,
+	})
+}
+
+type backfillRequest struct {
+	Symbols []string `json:"symbols"`
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	DryRun  bool     `json:"dry_run"`
+}
+
+// Backfill recomputes stored gex_history values from their option chain
+// snapshots. from/to are YYYY-MM-DD or RFC 3339; from is required.
+func (h *collectorAdmin) Backfill(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body backfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := backfillOptions(body.Symbols, body.From, body.To, body.DryRun)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := worker.Backfill(r.Context(), h.queries, opts)
+	if err != nil {
+		h.logger.Error("GEX backfill failed", slog.Any("error", err))
+		http.Error(w, "Backfill failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"dry_run": opts.DryRun,
+		"result":  result,
+	})
+}
@@ -2,11 +2,20 @@ package app
 
 import (
 	"html/template"
+	"log/slog"
 	"net/http"
+	"os"
 
+	"github.com/arnabmitra/eth-proxy/internal/btcetf"
+	"github.com/arnabmitra/eth-proxy/internal/config"
+	"github.com/arnabmitra/eth-proxy/internal/events"
+	"github.com/arnabmitra/eth-proxy/internal/eventstudy"
+	"github.com/arnabmitra/eth-proxy/internal/fred"
 	"github.com/arnabmitra/eth-proxy/internal/handler"
+	"github.com/arnabmitra/eth-proxy/internal/macro"
+	"github.com/arnabmitra/eth-proxy/internal/middleware"
 	"github.com/arnabmitra/eth-proxy/internal/repository"
-	"github.com/arnabmitra/eth-proxy/internal/worker"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
 )
 
 func (a *App) loadRoutes() (*handler.GEXHandler, *repository.Queries) {
@@ -14,7 +23,6 @@ func (a *App) loadRoutes() (*handler.GEXHandler, *repository.Queries) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
-	a.router.HandleFunc("/btc-etf", btcEtfHandler)
 	a.router.Handle("/static/", http.StripPrefix("/static", http.FileServer(http.Dir("./static"))))
 
 	// Serve SEO and verification files from root
@@ -38,7 +46,6 @@ func (a *App) loadRoutes() (*handler.GEXHandler, *repository.Queries) {
 		http.ServeFile(w, r, "./static/favicon.svg")
 	})
 
-	a.router.HandleFunc("/eth-tx", ethTxHandler)
 	a.router.HandleFunc("/about", gexTradingHandler)
 	a.router.HandleFunc("/strategies", strategiesHandler)
 	a.router.HandleFunc("/faq", faqHandler)
@@ -50,26 +57,73 @@ func (a *App) loadRoutes() (*handler.GEXHandler, *repository.Queries) {
 	// Register the GEX handler
 	tmpl := template.Must(template.ParseGlob("templates/*.html"))
 	gexHandler := handler.NewGEXHandler(a.logger, tmpl, a.db)
+	gexHandler.SetCache(a.cache)
 	a.router.HandleFunc("/gex", gexHandler.ServeHTTP)
 	a.router.HandleFunc("/", gexTradingHandler)
+
+	// Ethereum transaction lookup
+	ethHandler := handler.NewEthHandler(a.logger, tmpl, a.ethClients()...)
+	a.router.Handle("/eth-tx", ethHandler)
+	a.router.HandleFunc("/api/eth/tx", ethHandler.GetTransaction)
+	a.router.HandleFunc("/eth/search", ethHandler.Search)
+	a.router.HandleFunc("/eth/address/", ethHandler.Address)
+	a.router.HandleFunc("/eth/block/", ethHandler.Block)
+	a.router.HandleFunc("/api/eth/address", ethHandler.GetAddress)
+	a.router.HandleFunc("/api/eth/block", ethHandler.GetBlock)
 	// Register the expiry dates handler
 	a.router.HandleFunc("/expiry-dates", gexHandler.GetExpiryDatesHandler)
 	// Add this new route for the all-expiry GEX page
 	a.router.HandleFunc("/all-gex", gexHandler.AllGEXHandler)
 
 	a.router.HandleFunc("/gex-history", gexHandler.DisplayGEXHistoryPage)
+	a.router.HandleFunc("/api/gex-timeline", gexHandler.HandleGEXTimeline)
 	a.router.HandleFunc("/mag7-gex", gexHandler.MAG7GEXHandler)
+	a.router.HandleFunc("/gex-compare", gexHandler.HandleComparePage)
+	a.router.HandleFunc("/api/gex-compare", gexHandler.HandleCompareAPI)
+	a.router.HandleFunc("/universe-gex", gexHandler.UniverseGridHandler)
+
+	// Symbol universes
+	universes := universe.NewStore(a.db)
+	universeHandler := handler.NewUniverseHandler(a.logger, universes)
+	a.router.HandleFunc("/api/universes", universeHandler.List)
+	a.router.HandleFunc("/api/universes/members", universeHandler.Members)
 
 	// GEX Scanner
-	gexScannerHandler := handler.NewGEXScannerHandler(a.logger, tmpl, a.db, worker.SP500Symbols())
+	gexScannerHandler := handler.NewGEXScannerHandler(a.logger, tmpl, a.db, universes, universe.SP500, a.zscore)
 	a.router.HandleFunc("/gex-scanner", gexScannerHandler.HandleGEXScanner)
 	a.router.HandleFunc("/api/gex-zscore-history", gexScannerHandler.HandleZScoreHistory)
+	a.router.HandleFunc("/api/gex-scanner/views", gexScannerHandler.HandleViews)
+	a.router.HandleFunc("/gex-sectors", gexScannerHandler.HandleSectorHeatmap)
+	a.router.HandleFunc("/api/gex-sectors", gexScannerHandler.HandleSectorAPI)
 
 	// Economic Calendar
 	queries := repository.New(a.db)
 	economicCalendarHandler := handler.NewEconomicCalendarHandler(a.logger, tmpl, a.db)
 	a.router.HandleFunc("/economic-calendar", economicCalendarHandler.ServeHTTP)
 	a.router.HandleFunc("/api/economic-calendar/week", economicCalendarHandler.GetThisWeek)
+	eventStudyHandler := handler.NewEventStudyHandler(a.logger, eventstudy.NewAnalyzer(a.db, a.zscore))
+	a.router.HandleFunc("/api/economic-calendar/studies", eventStudyHandler.GetStudies)
+
+	// Calendar feeds
+	calendarFeedHandler := handler.NewCalendarFeedHandler(a.logger, a.db, universes)
+	a.router.HandleFunc("/economic-calendar.ics", calendarFeedHandler.EconomicCalendar)
+	a.router.HandleFunc("/calendar/", calendarFeedHandler.Watchlist)
+
+	// Macro dashboard
+	macroHandler := handler.NewMacroHandler(a.logger, tmpl, macro.NewStore(a.db))
+	a.router.Handle("/macro", macroHandler)
+	a.router.HandleFunc("/api/macro", macroHandler.GetDashboard)
+	a.router.HandleFunc("/api/macro/series", macroHandler.GetSeries)
+
+	// Spot bitcoin ETF flows
+	btcETFHandler := handler.NewBTCETFHandler(a.logger, tmpl, btcetf.NewStore(a.db))
+	a.router.Handle("/btc-etf", btcETFHandler)
+	a.router.HandleFunc("/api/btc-etf/flows", btcETFHandler.GetFlows)
+
+	// Event Calendar
+	eventCalendarHandler := handler.NewEventCalendarHandler(a.logger, tmpl, events.NewCalendar(a.db))
+	a.router.Handle("/event-calendar", eventCalendarHandler)
+	a.router.HandleFunc("/api/events", eventCalendarHandler.GetEvents)
 
 	// Blog
 	blogHandler := handler.NewBlogHandler(a.logger, tmpl)
@@ -78,3 +132,41 @@ func (a *App) loadRoutes() (*handler.GEXHandler, *repository.Queries) {
 
 	return gexHandler, queries
 }
+
+// loadAdminRoutes registers the token-protected admin pages and endpoints.
+// Without an admin token configured these always return 401.
+func (a *App) loadAdminRoutes(tmpl *template.Template, queries *repository.Queries) {
+	adminToken, err := config.LoadAdminToken()
+	if err != nil {
+		a.logger.Warn("admin routes disabled", slog.Any("error", err))
+	}
+	adminAuth := middleware.AdminAuth(adminToken)
+
+	collectorStatusHandler := handler.NewCollectorStatusHandler(a.logger, tmpl, a.db)
+	a.router.Handle("/admin/collector", adminAuth(collectorStatusHandler))
+	a.router.Handle("/api/admin/collector", adminAuth(http.HandlerFunc(collectorStatusHandler.GetStatus)))
+
+	collectorAdmin := &collectorAdmin{logger: a.logger, collector: a.gexCollector, queries: queries}
+	a.router.Handle("/api/admin/collector/trigger", adminAuth(http.HandlerFunc(collectorAdmin.Trigger)))
+	a.router.Handle("/api/admin/collector/backfill", adminAuth(http.HandlerFunc(collectorAdmin.Backfill)))
+
+	universeHandler := handler.NewUniverseHandler(a.logger, universe.NewStore(a.db))
+	a.router.Handle("/api/admin/universes/import", adminAuth(http.HandlerFunc(universeHandler.Import)))
+	a.router.Handle("/api/admin/symbols/import", adminAuth(http.HandlerFunc(universeHandler.ImportMetadata)))
+
+	a.router.Handle("/api/admin/cache", adminAuth(cacheStats(a.cache)))
+
+	economicCalendarHandler := handler.NewEconomicCalendarHandler(a.logger, tmpl, a.db)
+	a.router.Handle("/api/admin/economic-calendar/consensus", adminAuth(http.HandlerFunc(economicCalendarHandler.SetConsensus)))
+	a.router.Handle("/api/admin/economic-calendar/consensus/import", adminAuth(http.HandlerFunc(economicCalendarHandler.ImportConsensus)))
+
+	eventCalendarHandler := handler.NewEventCalendarHandler(a.logger, tmpl, events.NewCalendar(a.db))
+	a.router.Handle("/api/admin/earnings/import", adminAuth(http.HandlerFunc(eventCalendarHandler.ImportEarnings)))
+
+	releaseAdmin := handler.NewReleaseAdminHandler(a.logger, tmpl, a.db, fred.NewClient(os.Getenv("FRED_API_KEY")))
+	releaseAdmin.SetOnChange(a.economicCalendarCollector.Refresh)
+	a.router.Handle("/admin/releases", adminAuth(releaseAdmin))
+	a.router.Handle("/api/admin/releases", adminAuth(http.HandlerFunc(releaseAdmin.Releases)))
+	a.router.Handle("/api/admin/releases/catalogue", adminAuth(http.HandlerFunc(releaseAdmin.Catalogue)))
+	a.router.Handle("/api/admin/releases/series", adminAuth(http.HandlerFunc(releaseAdmin.Series)))
+}
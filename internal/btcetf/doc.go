@@ -0,0 +1,8 @@
+// Package btcetf ingests the daily flows and holdings of the US spot bitcoin
+// ETFs and summarizes them: the latest day, week and month per fund, the
+// cumulative flows since launch and the daily history behind the /btc-etf
+// charts.
+//
+// Flows come from the CSV or JSON source in BTC_ETF_FLOWS_URL (see Source);
+// funds are the rows of btc_etf_funds and flows are stored in btc_etf_flows.
+package btcetf
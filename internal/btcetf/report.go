@@ -0,0 +1,281 @@
+package btcetf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+)
+
+// Trading days in the week and month columns.
+const (
+	weekDays  = 5
+	monthDays = 21
+)
+
+// FundSummary is the flows of a fund, or of all funds, up to the report
+// date.
+type FundSummary struct {
+	Ticker string `json:"ticker"`
+	Name   string `json:"name"`
+	Issuer string `json:"issuer"`
+	// Day is the flow on the report date, nil when none was reported.
+	Day *float64 `json:"day_usd"`
+	// Week and Month are over the last 5 and 21 trading days.
+	Week  float64 `json:"week_usd"`
+	Month float64 `json:"month_usd"`
+	// Cumulative is every flow since launch.
+	Cumulative float64 `json:"cumulative_usd"`
+	// HoldingsBTC is the latest reported holdings, as of HoldingsDate.
+	HoldingsBTC  *float64 `json:"holdings_btc,omitempty"`
+	HoldingsDate string   `json:"holdings_date,omitempty"`
+}
+
+// DayText formats the flow on the report date, e.g. "+$123.4M".
+func (f FundSummary) DayText() string {
+	if f.Day == nil {
+		return "-"
+	}
+	return USDText(*f.Day)
+}
+
+// Outflow reports whether the flow on the report date was negative.
+func (f FundSummary) Outflow() bool {
+	return f.Day != nil && *f.Day < 0
+}
+
+func (f FundSummary) WeekText() string       { return USDText(f.Week) }
+func (f FundSummary) MonthText() string      { return USDText(f.Month) }
+func (f FundSummary) CumulativeText() string { return USDText(f.Cumulative) }
+
+// HoldingsText formats the holdings, e.g. "286,412 BTC".
+func (f FundSummary) HoldingsText() string {
+	if f.HoldingsBTC == nil {
+		return "-"
+	}
+	return groupThousands(int64(math.Round(*f.HoldingsBTC))) + " BTC"
+}
+
+// Day is the flows of every fund on a trading day.
+type Day struct {
+	Date  time.Time
+	Flows map[string]float64
+	Total float64
+	// Cumulative is the total of every flow up to and including Date.
+	Cumulative float64
+}
+
+func (d Day) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Date       string             `json:"date"`
+		Flows      map[string]float64 `json:"flows_usd"`
+		Total      float64            `json:"total_usd"`
+		Cumulative float64            `json:"cumulative_usd"`
+	}{d.Date.Format("2006-01-02"), d.Flows, d.Total, d.Cumulative})
+}
+
+// FlowText formats the flow of ticker, "-" when none was reported.
+func (d Day) FlowText(ticker string) string {
+	v, ok := d.Flows[ticker]
+	if !ok {
+		return "-"
+	}
+	return USDText(v)
+}
+
+func (d Day) TotalText() string      { return USDText(d.Total) }
+func (d Day) CumulativeText() string { return USDText(d.Cumulative) }
+
+// Report is the flows of every fund as of the latest trading day.
+type Report struct {
+	// AsOf is the latest trading day with flows; zero when there are none.
+	AsOf time.Time `json:"-"`
+	// Funds are in the order of btc_etf_funds, leaving out funds without
+	// flows.
+	Funds []FundSummary `json:"funds"`
+	Total FundSummary   `json:"total"`
+	// Days are the trading days in the window ending at AsOf, oldest first.
+	Days []Day `json:"days"`
+	// Streak is how many trading days in a row ending at AsOf had net
+	// inflows, or outflows when negative.
+	Streak int `json:"streak"`
+}
+
+func (r *Report) MarshalJSON() ([]byte, error) {
+	type report Report
+	var asOf string
+	if !r.AsOf.IsZero() {
+		asOf = r.AsOf.Format("2006-01-02")
+	}
+	return json.Marshal(struct {
+		AsOf string `json:"as_of,omitempty"`
+		*report
+	}{asOf, (*report)(r)})
+}
+
+// StreakText describes the streak, e.g. "3 days of inflows".
+func (r *Report) StreakText() string {
+	n, kind := r.Streak, "inflows"
+	if n < 0 {
+		n, kind = -n, "outflows"
+	}
+	switch n {
+	case 0:
+		return "-"
+	case 1:
+		return "1 day of " + kind
+	}
+	return fmt.Sprintf("%d days of %s", n, kind)
+}
+
+// RecentDays returns the latest n days of the window, newest first.
+func (r *Report) RecentDays(n int) []Day {
+	if n > len(r.Days) {
+		n = len(r.Days)
+	}
+	days := make([]Day, n)
+	for i := range days {
+		days[i] = r.Days[len(r.Days)-1-i]
+	}
+	return days
+}
+
+// NewReport summarizes flows, in any order, for funds, with the trading days
+// of the past days days.
+func NewReport(funds []repository.BtcEtfFund, flows []Flow, days int) *Report {
+	byDate := make(map[time.Time]map[string]float64)
+	for _, f := range flows {
+		if byDate[f.Date] == nil {
+			byDate[f.Date] = make(map[string]float64)
+		}
+		byDate[f.Date][f.Ticker] += f.USD
+	}
+	dates := make([]time.Time, 0, len(byDate))
+	for d := range byDate {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	r := &Report{Total: FundSummary{Ticker: "Total", Name: "All funds"}}
+	if len(dates) == 0 {
+		return r
+	}
+	r.AsOf = dates[len(dates)-1]
+
+	// Every trading day, with the running total.
+	all := make([]Day, len(dates))
+	cumulative := 0.0
+	for i, d := range dates {
+		day := Day{Date: d, Flows: byDate[d]}
+		for _, v := range day.Flows {
+			day.Total += v
+		}
+		cumulative += day.Total
+		day.Cumulative = cumulative
+		all[i] = day
+	}
+	from := r.AsOf.AddDate(0, 0, -days)
+	start := sort.Search(len(all), func(i int) bool { return all[i].Date.After(from) })
+	r.Days = all[start:]
+
+	for i := len(all) - 1; i >= 0; i-- {
+		t := all[i].Total
+		if t > 0 && r.Streak >= 0 {
+			r.Streak++
+		} else if t < 0 && r.Streak <= 0 {
+			r.Streak--
+		} else {
+			break
+		}
+	}
+
+	summaries := make(map[string]*FundSummary)
+	var order []string
+	for _, f := range funds {
+		summaries[f.Ticker] = &FundSummary{Ticker: f.Ticker, Name: f.Name, Issuer: f.Issuer}
+		order = append(order, f.Ticker)
+	}
+	reported := make(map[string]bool)
+	for i, day := range all {
+		// 1 on the latest day.
+		recent := len(all) - i
+		for ticker, v := range day.Flows {
+			s := summaries[ticker]
+			if s == nil {
+				s = &FundSummary{Ticker: ticker, Name: ticker}
+				summaries[ticker] = s
+				order = append(order, ticker)
+			}
+			reported[ticker] = true
+			s.add(v, recent)
+			r.Total.add(v, recent)
+		}
+	}
+	for _, f := range flows {
+		s := summaries[f.Ticker]
+		if f.HoldingsBTC != nil && (s.HoldingsBTC == nil || f.Date.Format("2006-01-02") > s.HoldingsDate) {
+			h := *f.HoldingsBTC
+			s.HoldingsBTC, s.HoldingsDate = &h, f.Date.Format("2006-01-02")
+		}
+	}
+
+	for _, ticker := range order {
+		if reported[ticker] {
+			r.Funds = append(r.Funds, *summaries[ticker])
+		}
+	}
+	return r
+}
+
+// add adds the flow v of the recent-th latest trading day.
+func (f *FundSummary) add(v float64, recent int) {
+	if recent == 1 {
+		day := v
+		if f.Day != nil {
+			day += *f.Day
+		}
+		f.Day = &day
+	}
+	if recent <= weekDays {
+		f.Week += v
+	}
+	if recent <= monthDays {
+		f.Month += v
+	}
+	f.Cumulative += v
+}
+
+// USDText formats a flow in millions, or billions from a billion, with its
+// sign: "+$123.4M", "-$1.25B".
+func USDText(v float64) string {
+	sign := "+"
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	if math.Round(v/1e5) == 0 {
+		return "$0.0M"
+	}
+	// Anything that would round to $1000.0M is a billion.
+	if v >= 999.95e6 {
+		return fmt.Sprintf("%s$%.2fB", sign, v/1e9)
+	}
+	return fmt.Sprintf("%s$%.1fM", sign, v/1e6)
+}
+
+func groupThousands(n int64) string {
+	s := fmt.Sprintf("%d", n)
+	neg := n < 0
+	if neg {
+		s = s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	if neg {
+		return "-" + s
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package btcetf
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+)
+
+func TestNewReport(t *testing.T) {
+	funds := []repository.BtcEtfFund{
+		{Ticker: "IBIT", Name: "iShares Bitcoin Trust", Issuer: "BlackRock"},
+		{Ticker: "FBTC", Name: "Fidelity Wise Origin Bitcoin Fund", Issuer: "Fidelity"},
+		{Ticker: "BTCW", Name: "WisdomTree Bitcoin Fund", Issuer: "WisdomTree"},
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var flows []Flow
+	// 30 trading days: IBIT takes in $10M a day, FBTC loses $2M a day from
+	// the 25th, and an unknown fund reports once.
+	for i := 0; i < 30; i++ {
+		d := start.AddDate(0, 0, i)
+		flows = append(flows, Flow{Ticker: "IBIT", Date: d, USD: 10e6})
+		if i >= 25 {
+			flows = append(flows, Flow{Ticker: "FBTC", Date: d, USD: -2e6})
+		}
+	}
+	h1, h2 := 100.0, 150.0
+	flows = append(flows,
+		Flow{Ticker: "NEWB", Date: start, USD: 1e6},
+		Flow{Ticker: "IBIT", Date: start.AddDate(0, 0, 40), USD: -50e6, HoldingsBTC: &h2},
+		Flow{Ticker: "IBIT", Date: start.AddDate(0, 0, 39), USD: -5e6, HoldingsBTC: &h1},
+	)
+
+	r := NewReport(funds, flows, 7)
+	if got := r.AsOf.Format("2006-01-02"); got != "2024-02-10" {
+		t.Errorf("as of %s", got)
+	}
+	if len(r.Funds) != 3 || r.Funds[0].Ticker != "IBIT" || r.Funds[1].Ticker != "FBTC" || r.Funds[2].Ticker != "NEWB" {
+		t.Fatalf("funds = %+v", r.Funds)
+	}
+	ibit := r.Funds[0]
+	if ibit.DayText() != "-$50.0M" || ibit.Week != -25e6 || ibit.Month != 135e6 || ibit.CumulativeText() != "+$245.0M" {
+		t.Errorf("IBIT day %s, week %v, month %v, cumulative %s", ibit.DayText(), ibit.Week, ibit.Month, ibit.CumulativeText())
+	}
+	if ibit.HoldingsText() != "150 BTC" || ibit.HoldingsDate != "2024-02-10" {
+		t.Errorf("IBIT holdings %s on %s", ibit.HoldingsText(), ibit.HoldingsDate)
+	}
+	if fbtc := r.Funds[1]; fbtc.Day != nil || fbtc.DayText() != "-" || fbtc.Week != -6e6 || fbtc.Cumulative != -10e6 {
+		t.Errorf("FBTC = %+v", fbtc)
+	}
+	if r.Total.Cumulative != 236e6 || *r.Total.Day != -50e6 || r.Streak != -2 || r.StreakText() != "2 days of outflows" {
+		t.Errorf("total %+v, streak %d", r.Total, r.Streak)
+	}
+
+	// The window is the past week: the 30th day and the two outflows.
+	if len(r.Days) != 2 || r.Days[0].Date.Format("2006-01-02") != "2024-02-09" {
+		t.Fatalf("days = %+v", r.Days)
+	}
+	if d := r.Days[1]; d.Total != -50e6 || d.Cumulative != 236e6 || d.FlowText("IBIT") != "-$50.0M" || d.FlowText("FBTC") != "-" {
+		t.Errorf("last day = %+v", d)
+	}
+
+	if recent := r.RecentDays(5); len(recent) != 2 || !recent[0].Date.Equal(r.AsOf) {
+		t.Errorf("recent days = %+v", recent)
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"as_of":"2024-02-10"`, `"date":"2024-02-10"`, `"cumulative_usd":236000000`, `"streak":-2`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON lacks %s: %s", want, data)
+		}
+	}
+
+	if empty := NewReport(funds, nil, 30); empty.Funds != nil || empty.Days != nil || !empty.AsOf.IsZero() || empty.StreakText() != "-" {
+		t.Errorf("empty report = %+v", empty)
+	}
+}
+
+func TestUSDText(t *testing.T) {
+	for v, want := range map[float64]string{
+		123.44e6:  "+$123.4M",
+		-1.254e9:  "-$1.25B",
+		0:         "$0.0M",
+		-20000:    "$0.0M",
+		999.96e6:  "+$1.00B",
+		36.1234e9: "+$36.12B",
+	} {
+		if got := USDText(v); got != want {
+			t.Errorf("USDText(%v) = %s, want %s", v, got, want)
+		}
+	}
+}
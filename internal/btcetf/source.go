@@ -0,0 +1,338 @@
+package btcetf
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/outbound"
+)
+
+// Formats of a source.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// millions converts the flows of a source, which are in US$ millions as
+// every flows tracker reports them, to US$.
+const millions = 1e6
+
+// maxSourceSize bounds a download; years of daily flows are a few hundred
+// KB.
+const maxSourceSize = 16 << 20
+
+// Flow is the net flow of a fund on a day.
+type Flow struct {
+	Ticker string
+	Date   time.Time
+	// USD is creations less redemptions, in US$.
+	USD float64
+	// HoldingsBTC is the BTC held at the close, nil when not reported.
+	HoldingsBTC *float64
+}
+
+// Source is where flows are read from: an http(s) URL or a local file, in
+// one of two layouts.
+//
+// Long CSV has a row per fund and day, with the columns date, fund (or
+// ticker), flow_usd_m and optionally holdings_btc. JSON is an array of
+// objects with the same keys, optionally under "flows".
+//
+// Wide CSV, the layout of the public trackers, has a row per day with a
+// column per ticker; a Total column and rows that aren't dated, such as
+// totals and averages, are skipped. Flows may be written "(12.5)" for an
+// outflow, and an empty cell or "-" means no flow was reported.
+type Source struct {
+	Location string
+	// Format is FormatCSV or FormatJSON; empty infers it from the
+	// extension, or else the Content-Type.
+	Format     string
+	HTTPClient *http.Client
+}
+
+// SourceFromEnv returns the source in BTC_ETF_FLOWS_URL, with the format in
+// BTC_ETF_FLOWS_FORMAT, and false when no source is configured.
+func SourceFromEnv() (*Source, bool) {
+	location := strings.TrimSpace(os.Getenv("BTC_ETF_FLOWS_URL"))
+	if location == "" {
+		return nil, false
+	}
+	return &Source{
+		Location:   location,
+		Format:     strings.ToLower(strings.TrimSpace(os.Getenv("BTC_ETF_FLOWS_FORMAT"))),
+		HTTPClient: outbound.HTTPClient(outbound.For("btcetf"), 30*time.Second),
+	}, true
+}
+
+// Fetch reads and parses the source.
+func (s *Source) Fetch(ctx context.Context) ([]Flow, error) {
+	data, contentType, err := s.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	format := s.Format
+	if format == "" {
+		format = inferFormat(s.Location, contentType)
+	}
+	switch format {
+	case FormatCSV:
+		return ParseCSV(bytes.NewReader(data))
+	case FormatJSON:
+		return ParseJSON(bytes.NewReader(data))
+	}
+	return nil, fmt.Errorf("unknown flows format %q; set BTC_ETF_FLOWS_FORMAT to csv or json", format)
+}
+
+func (s *Source) read(ctx context.Context) ([]byte, string, error) {
+	if !strings.HasPrefix(s.Location, "http://") && !strings.HasPrefix(s.Location, "https://") {
+		data, err := os.ReadFile(strings.TrimPrefix(s.Location, "file://"))
+		return data, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.Location, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch flows: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetch flows: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSourceSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch flows: %w", err)
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+func inferFormat(location, contentType string) string {
+	if i := strings.IndexAny(location, "?#"); i >= 0 {
+		location = location[:i]
+	}
+	switch strings.ToLower(path.Ext(location)) {
+	case ".csv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	}
+	switch {
+	case strings.Contains(contentType, "json"):
+		return FormatJSON
+	case strings.Contains(contentType, "csv"), strings.HasPrefix(contentType, "text/plain"):
+		return FormatCSV
+	}
+	return ""
+}
+
+// ParseCSV parses long or wide CSV, telling them apart by a fund or ticker
+// column. Rows before the header, the first row starting with "date", are
+// skipped.
+func ParseCSV(r io.Reader) ([]Flow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse flows CSV: %w", err)
+	}
+
+	start := -1
+	for i, rec := range records {
+		if len(rec) > 0 && strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(rec[0], "\ufeff")), "date") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, errors.New("parse flows CSV: no header row starting with date")
+	}
+	header := make([]string, len(records[start]))
+	for i, h := range records[start] {
+		header[i] = strings.ToLower(strings.TrimSpace(h))
+	}
+	col := func(names ...string) int {
+		for i, h := range header {
+			for _, name := range names {
+				if h == name {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+
+	if fund := col("fund", "ticker"); fund >= 0 {
+		flow := col("flow_usd_m", "flow")
+		if flow < 0 {
+			return nil, errors.New("parse flows CSV: no flow_usd_m column")
+		}
+		return parseLong(records[start+1:], start+2, fund, flow, col("holdings_btc", "holdings"))
+	}
+	return parseWide(records[start+1:], start+2, records[start])
+}
+
+func parseLong(records [][]string, line, fund, flow, holdings int) ([]Flow, error) {
+	var flows []Flow
+	for i, rec := range records {
+		date, ok := cell(rec, 0)
+		if !ok {
+			continue
+		}
+		d, err := parseDate(date)
+		if err != nil {
+			return nil, fmt.Errorf("parse flows CSV: line %d: %w", line+i, err)
+		}
+		ticker, _ := cell(rec, fund)
+		value, ok := cell(rec, flow)
+		if ticker == "" || !ok {
+			continue
+		}
+		f := Flow{Ticker: strings.ToUpper(ticker), Date: d}
+		if f.USD, err = parseAmount(value); err != nil {
+			return nil, fmt.Errorf("parse flows CSV: line %d: %w", line+i, err)
+		}
+		f.USD *= millions
+		if h, ok := cell(rec, holdings); ok {
+			v, err := parseAmount(h)
+			if err != nil {
+				return nil, fmt.Errorf("parse flows CSV: line %d: holdings: %w", line+i, err)
+			}
+			f.HoldingsBTC = &v
+		}
+		flows = append(flows, f)
+	}
+	return flows, nil
+}
+
+func parseWide(records [][]string, line int, header []string) ([]Flow, error) {
+	var flows []Flow
+	for i, rec := range records {
+		date, ok := cell(rec, 0)
+		if !ok {
+			continue
+		}
+		d, err := parseDate(date)
+		if err != nil {
+			// Totals, averages and notes.
+			continue
+		}
+		for c := 1; c < len(header) && c < len(rec); c++ {
+			ticker := strings.ToUpper(strings.TrimSpace(header[c]))
+			value, ok := cell(rec, c)
+			if ticker == "" || ticker == "TOTAL" || !ok {
+				continue
+			}
+			usd, err := parseAmount(value)
+			if err != nil {
+				return nil, fmt.Errorf("parse flows CSV: line %d: %s: %w", line+i, ticker, err)
+			}
+			flows = append(flows, Flow{Ticker: ticker, Date: d, USD: usd * millions})
+		}
+	}
+	return flows, nil
+}
+
+// cell returns column c of rec, and false when it is missing or reports no
+// value.
+func cell(rec []string, c int) (string, bool) {
+	if c < 0 || c >= len(rec) {
+		return "", false
+	}
+	v := strings.TrimSpace(rec[c])
+	return v, v != "" && v != "-"
+}
+
+// parseAmount parses "1,234.5", "$12.3", "-4" or "(4)".
+func parseAmount(s string) (float64, error) {
+	v := strings.NewReplacer(",", "", "$", "", " ", "").Replace(s)
+	negative := strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")")
+	if negative {
+		v = v[1 : len(v)-1]
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if negative {
+		f = -f
+	}
+	return f, nil
+}
+
+var dateLayouts = []string{"2006-01-02", "02 Jan 2006", "2 Jan 2006", "01/02/2006", "Jan 2, 2006"}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+type jsonFlow struct {
+	Date        string   `json:"date"`
+	Fund        string   `json:"fund"`
+	Ticker      string   `json:"ticker"`
+	FlowUSDM    *float64 `json:"flow_usd_m"`
+	HoldingsBTC *float64 `json:"holdings_btc"`
+}
+
+// ParseJSON parses an array of flows, or an object with them under "flows".
+func ParseJSON(r io.Reader) ([]Flow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var rows []jsonFlow
+	if err := json.Unmarshal(data, &rows); err != nil {
+		var wrapped struct {
+			Flows []jsonFlow `json:"flows"`
+		}
+		if err2 := json.Unmarshal(data, &wrapped); err2 != nil {
+			return nil, fmt.Errorf("parse flows JSON: %w", err)
+		}
+		rows = wrapped.Flows
+	}
+
+	flows := make([]Flow, 0, len(rows))
+	for i, row := range rows {
+		ticker := row.Fund
+		if ticker == "" {
+			ticker = row.Ticker
+		}
+		if ticker == "" || row.FlowUSDM == nil {
+			continue
+		}
+		d, err := parseDate(row.Date)
+		if err != nil {
+			return nil, fmt.Errorf("parse flows JSON: flow %d: %w", i, err)
+		}
+		flows = append(flows, Flow{
+			Ticker:      strings.ToUpper(ticker),
+			Date:        d,
+			USD:         *row.FlowUSDM * millions,
+			HoldingsBTC: row.HoldingsBTC,
+		})
+	}
+	return flows, nil
+}
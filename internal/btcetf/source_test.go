@@ -0,0 +1,111 @@
+package btcetf
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A Farside-style export: a title row, dated rows with a column per fund,
+// and footer rows.
+const wideCSV = `Bitcoin ETF Flow (US$m),,,,
+Date,IBIT,FBTC,GBTC,Total
+11 Jan 2024,111.7,227.0,(95.1),243.6
+12 Jan 2024,386.0,-,(484.1),(98.1)
+15 Jan 2024,,,,
+Total,497.7,227.0,(579.2),145.5
+Average,248.9,113.5,(289.6),72.8
+`
+
+func TestParseCSVWide(t *testing.T) {
+	flows, err := ParseCSV(strings.NewReader(wideCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flows) != 5 {
+		t.Fatalf("flows = %+v", flows)
+	}
+	if f := flows[2]; f.Ticker != "GBTC" || f.Date.Format("2006-01-02") != "2024-01-11" || f.USD != -95.1e6 {
+		t.Errorf("GBTC flow = %+v", f)
+	}
+	// FBTC reported nothing on the 12th.
+	if f := flows[4]; f.Ticker != "GBTC" || f.USD != -484.1e6 {
+		t.Errorf("last flow = %+v", f)
+	}
+}
+
+func TestParseCSVLong(t *testing.T) {
+	flows, err := ParseCSV(strings.NewReader("date,fund,flow_usd_m,holdings_btc\n" +
+		"2024-03-12,ibit,\"1,045.3\",204000\n" +
+		"2024-03-12,GBTC,-79,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flows) != 2 || flows[0].Ticker != "IBIT" || flows[0].USD != 1045.3e6 || *flows[0].HoldingsBTC != 204000 || flows[1].HoldingsBTC != nil {
+		t.Errorf("flows = %+v", flows)
+	}
+
+	if _, err := ParseCSV(strings.NewReader("date,fund,flow_usd_m\nyesterday,IBIT,1\n")); err == nil {
+		t.Error("undated long row accepted")
+	}
+	if _, err := ParseCSV(strings.NewReader("fund,flow\nIBIT,1\n")); err == nil {
+		t.Error("CSV without a date header accepted")
+	}
+	if _, err := ParseCSV(strings.NewReader("Date,IBIT\n2024-01-11,lots\n")); err == nil {
+		t.Error("invalid amount accepted")
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	for _, data := range []string{
+		`[{"date":"2024-01-11","ticker":"IBIT","flow_usd_m":111.7,"holdings_btc":2621}]`,
+		`{"flows":[{"date":"2024-01-11","fund":"ibit","flow_usd_m":111.7,"holdings_btc":2621},{"date":"2024-01-11","fund":"FBTC"}]}`,
+	} {
+		flows, err := ParseJSON(strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(flows) != 1 || flows[0].Ticker != "IBIT" || flows[0].USD != 111.7e6 || *flows[0].HoldingsBTC != 2621 {
+			t.Errorf("flows = %+v", flows)
+		}
+	}
+	if _, err := ParseJSON(strings.NewReader(`{"flows":`)); err == nil {
+		t.Error("truncated JSON accepted")
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/flows" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"date":"2024-01-11","fund":"IBIT","flow_usd_m":1}]`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	flows, err := (&Source{Location: srv.URL + "/flows", HTTPClient: srv.Client()}).Fetch(context.Background())
+	if err != nil || len(flows) != 1 {
+		t.Errorf("JSON by Content-Type: %+v, %v", flows, err)
+	}
+	if _, err := (&Source{Location: srv.URL + "/missing.csv", HTTPClient: srv.Client()}).Fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("missing source: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "flows.txt")
+	if err := os.WriteFile(path, []byte(wideCSV), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Source{Location: path}).Fetch(context.Background()); err == nil {
+		t.Error("file of unknown format parsed")
+	}
+	flows, err = (&Source{Location: path, Format: FormatCSV}).Fetch(context.Background())
+	if err != nil || len(flows) != 5 {
+		t.Errorf("CSV file: %d flows, %v", len(flows), err)
+	}
+}
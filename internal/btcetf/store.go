@@ -0,0 +1,83 @@
+package btcetf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Store reads and writes the funds and their daily flows.
+type Store struct {
+	repo *repository.Queries
+}
+
+func NewStore(db *pgxpool.Pool) *Store {
+	return &Store{repo: repository.New(db)}
+}
+
+// Ingest fetches every flow in src and stores it, replacing what is stored
+// for the same fund and day so revisions are picked up. Funds that aren't in
+// btc_etf_funds yet are added with their ticker as the name. It returns the
+// number of flows stored.
+func (s *Store) Ingest(ctx context.Context, src *Source) (int, error) {
+	flows, err := src.Fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	funds, err := s.repo.ListBTCETFFunds(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list funds: %w", err)
+	}
+	known := make(map[string]bool, len(funds))
+	for _, f := range funds {
+		known[f.Ticker] = true
+	}
+
+	for i, f := range flows {
+		if !known[f.Ticker] {
+			if err := s.repo.EnsureBTCETFFund(ctx, f.Ticker); err != nil {
+				return i, fmt.Errorf("add fund %s: %w", f.Ticker, err)
+			}
+			known[f.Ticker] = true
+		}
+		params := repository.UpsertBTCETFFlowParams{
+			Ticker:  f.Ticker,
+			Date:    pgtype.Date{Time: f.Date, Valid: true},
+			FlowUsd: f.USD,
+		}
+		if f.HoldingsBTC != nil {
+			params.HoldingsBtc = pgtype.Float8{Float64: *f.HoldingsBTC, Valid: true}
+		}
+		if err := s.repo.UpsertBTCETFFlow(ctx, params); err != nil {
+			return i, fmt.Errorf("store %s flow of %s: %w", f.Ticker, f.Date.Format("2006-01-02"), err)
+		}
+	}
+	return len(flows), nil
+}
+
+// Report summarizes the stored flows, with the daily history of the past
+// days days.
+func (s *Store) Report(ctx context.Context, days int) (*Report, error) {
+	funds, err := s.repo.ListBTCETFFunds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list funds: %w", err)
+	}
+	rows, err := s.repo.ListBTCETFFlows(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list flows: %w", err)
+	}
+
+	flows := make([]Flow, len(rows))
+	for i, r := range rows {
+		flows[i] = Flow{Ticker: r.Ticker, Date: r.Date.Time, USD: r.FlowUsd}
+		if r.HoldingsBtc.Valid {
+			v := r.HoldingsBtc.Float64
+			flows[i].HoldingsBTC = &v
+		}
+	}
+	return NewReport(funds, flows, days), nil
+}
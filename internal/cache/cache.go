@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Store is a cache backend holding raw values with a per-entry expiry.
+type Store interface {
+	// Get returns the value of key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Cache stores values of each Kind for the TTL its Policy gives, counting
+// hits and misses per kind.
+type Cache struct {
+	store  Store
+	policy Policy
+	group  singleflight.Group
+
+	mu    sync.Mutex
+	stats map[Kind]*counters
+}
+
+type counters struct {
+	hits, misses, shared, errors atomic.Int64
+}
+
+// Stats are a kind's counters since the process started.
+type Stats struct {
+	Hits int64 `json:"hits"`
+	// Misses called the fetch function; Shared waited for another caller's
+	// fetch of the same key instead.
+	Misses int64 `json:"misses"`
+	Shared int64 `json:"shared"`
+	// Errors counts backend failures, which are treated as misses.
+	Errors  int64   `json:"errors"`
+	TTL     string  `json:"ttl"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+func New(store Store, policy Policy) *Cache {
+	return &Cache{store: store, policy: policy, stats: make(map[Kind]*counters)}
+}
+
+// TTL returns how long values of kind are kept.
+func (c *Cache) TTL(kind Kind) time.Duration {
+	return c.policy[kind]
+}
+
+// Stats returns the counters of every kind in the policy.
+func (c *Cache) Stats() map[Kind]Stats {
+	stats := make(map[Kind]Stats, len(c.policy))
+	for kind, ttl := range c.policy {
+		n := c.counters(kind)
+		s := Stats{
+			Hits:   n.hits.Load(),
+			Misses: n.misses.Load(),
+			Shared: n.shared.Load(),
+			Errors: n.errors.Load(),
+			TTL:    ttl.String(),
+		}
+		if total := s.Hits + s.Misses + s.Shared; total > 0 {
+			s.HitRate = float64(s.Hits+s.Shared) / float64(total)
+		}
+		stats[kind] = s
+	}
+	return stats
+}
+
+// Invalidate drops the cached value of key.
+func (c *Cache) Invalidate(ctx context.Context, kind Kind, key string) error {
+	return c.store.Delete(ctx, storeKey(kind, key))
+}
+
+func (c *Cache) counters(kind Kind) *counters {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, ok := c.stats[kind]
+	if !ok {
+		n = &counters{}
+		c.stats[kind] = n
+	}
+	return n
+}
+
+// fetchTimeout bounds a fetch, which outlives the caller that started it.
+const fetchTimeout = 2 * time.Minute
+
+func storeKey(kind Kind, key string) string {
+	return string(kind) + ":" + key
+}
+
+// Fetch returns the cached value of key, or calls fetch and caches what it
+// returns for kind's TTL. Concurrent callers missing the same key share one
+// fetch, which runs on for up to fetchTimeout even if the caller that
+// started it gives up. Callers sharing a fetch get the same value and must
+// not modify it. Errors from fetch are returned and not cached.
+func Fetch[T any](ctx context.Context, c *Cache, kind Kind, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	n := c.counters(kind)
+	skey := storeKey(kind, key)
+
+	if raw, ok, err := c.store.Get(ctx, skey); err != nil {
+		n.errors.Add(1)
+	} else if ok {
+		var v T
+		if err := json.Unmarshal(raw, &v); err == nil {
+			n.hits.Add(1)
+			return v, nil
+		}
+		n.errors.Add(1)
+	}
+
+	// fetched is only set for the caller whose fetch runs; the rest share it.
+	fetched := false
+	ch := c.group.DoChan(skey, func() (interface{}, error) {
+		fetched = true
+		n.misses.Add(1)
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+		v, err := fetch(fetchCtx)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("encode %s: %w", skey, err)
+		}
+		if err := c.store.Set(fetchCtx, skey, raw, c.TTL(kind)); err != nil {
+			n.errors.Add(1)
+		}
+		return v, nil
+	})
+
+	select {
+	case res := <-ch:
+		if !fetched {
+			n.shared.Add(1)
+		}
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type quote struct {
+	Symbol string
+	Price  float64
+}
+
+func TestFetchCachesPerKind(t *testing.T) {
+	c := New(NewMemory(), DefaultPolicy())
+	ctx := context.Background()
+
+	var calls int
+	fetch := func(context.Context) (quote, error) {
+		calls++
+		return quote{"SPY", 500.25}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		q, err := Fetch(ctx, c, KindSpot, "SPY", fetch)
+		if err != nil || q.Price != 500.25 {
+			t.Fatalf("Fetch = %+v, %v", q, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times, want 1", calls)
+	}
+
+	// The same key under another kind is a separate entry.
+	if _, err := Fetch(ctx, c, KindGEX, "SPY", fetch); err != nil || calls != 2 {
+		t.Errorf("other kind: calls = %d, err = %v", calls, err)
+	}
+
+	if err := c.Invalidate(ctx, KindSpot, "SPY"); err != nil {
+		t.Fatal(err)
+	}
+	Fetch(ctx, c, KindSpot, "SPY", fetch)
+	if calls != 3 {
+		t.Errorf("invalidated key wasn't refetched: calls = %d", calls)
+	}
+
+	s := c.Stats()[KindSpot]
+	if s.Hits != 2 || s.Misses != 2 || s.HitRate != 0.5 || s.TTL != "15s" {
+		t.Errorf("spot stats = %+v", s)
+	}
+}
+
+func TestFetchDeduplicatesConcurrentMisses(t *testing.T) {
+	c := New(NewMemory(), DefaultPolicy())
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q, err := Fetch(context.Background(), c, KindChain, "SPY:2026-10-23", func(context.Context) (quote, error) {
+				calls.Add(1)
+				<-release
+				return quote{"SPY", 1}, nil
+			})
+			if err != nil || q.Symbol != "SPY" {
+				t.Errorf("Fetch = %+v, %v", q, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fetched %d times for concurrent callers, want 1", n)
+	}
+	if s := c.Stats()[KindChain]; s.Misses != 1 || s.Shared != 9 {
+		t.Errorf("chain stats = %+v, want 1 miss and 9 shared", s)
+	}
+}
+
+func TestFetchDoesNotCacheErrors(t *testing.T) {
+	c := New(NewMemory(), DefaultPolicy())
+	ctx := context.Background()
+
+	_, err := Fetch(ctx, c, KindSpot, "BAD", func(context.Context) (quote, error) {
+		return quote{}, errors.New("provider down")
+	})
+	if err == nil {
+		t.Fatal("expected the fetch error")
+	}
+	q, err := Fetch(ctx, c, KindSpot, "BAD", func(context.Context) (quote, error) {
+		return quote{"BAD", 1}, nil
+	})
+	if err != nil || q.Price != 1 {
+		t.Errorf("retry after error = %+v, %v", q, err)
+	}
+}
+
+func TestFetchCallerCancel(t *testing.T) {
+	c := New(NewMemory(), DefaultPolicy())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := Fetch(ctx, c, KindGEX, "SPY", func(fctx context.Context) (quote, error) {
+			cancel()
+			time.Sleep(10 * time.Millisecond)
+			if fctx.Err() != nil {
+				t.Error("fetch was canceled with its caller")
+			}
+			if _, ok := fctx.Deadline(); !ok {
+				t.Error("fetch has no deadline")
+			}
+			return quote{"SPY", 2}, nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	}()
+	<-done
+
+	// The abandoned fetch still lands in the cache.
+	time.Sleep(20 * time.Millisecond)
+	q, err := Fetch(context.Background(), c, KindGEX, "SPY", func(context.Context) (quote, error) {
+		return quote{}, errors.New("should have been cached")
+	})
+	if err != nil || q.Price != 2 {
+		t.Errorf("Fetch = %+v, %v", q, err)
+	}
+}
+
+func TestMemoryExpiry(t *testing.T) {
+	m := NewMemory()
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	m.Set(ctx, "a", []byte("1"), time.Minute)
+	m.Set(ctx, "b", []byte("2"), time.Hour)
+	if v, ok, _ := m.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Errorf("Get(a) = %q, %v", v, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := m.Get(ctx, "a"); ok {
+		t.Error("expired entry was returned")
+	}
+	m.Set(ctx, "c", []byte("3"), time.Minute)
+	if _, ok, _ := m.Get(ctx, "b"); !ok || len(m.entries) != 2 {
+		t.Errorf("entries after sweep = %d, want b and c", len(m.entries))
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy(" spot=30s, CHAIN=2m ,", DefaultPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p[KindSpot] != 30*time.Second || p[KindChain] != 2*time.Minute || p[KindExpiries] != 24*time.Hour {
+		t.Errorf("policy = %v", p)
+	}
+	if DefaultPolicy()[KindSpot] != 15*time.Second {
+		t.Error("ParsePolicy modified its base")
+	}
+
+	for _, bad := range []string{"spot", "quotes=1m", "spot=soon", "chain=-1m"} {
+		if _, err := ParsePolicy(bad, DefaultPolicy()); err == nil {
+			t.Errorf("ParsePolicy(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+// Package cache is the shared cache for provider data: option chains, spot
+// prices and the GEX computed from them. Values live in Redis when it is
+// configured, so every instance shares them, or in process memory otherwise.
+// Each kind of data has its own TTL, and concurrent fetches of the same key
+// are collapsed into one provider call.
+package cache
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often Set drops expired entries from a Memory store.
+const sweepInterval = time.Minute
+
+// Memory is a Store in process memory, for single instances and tests.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !m.now().Before(e.expiresAt) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		for k, e := range m.entries {
+			if !now.Before(e.expiresAt) {
+				delete(m.entries, k)
+			}
+		}
+		m.lastSweep = now
+	}
+	m.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind is a type of cached data. Keys are namespaced by kind and each kind
+// has its own TTL.
+type Kind string
+
+const (
+	// KindSpot is a symbol's last trade price.
+	KindSpot Kind = "spot"
+	// KindChain is the option chain of one expiry as shown on the tracker,
+	// where intraday changes in open interest and greeks matter.
+	KindChain Kind = "chain"
+	// KindAllExpiryChain is the option chain of one expiry when it is summed
+	// into the all-expiry GEX; one expiry's drift barely moves the total.
+	KindAllExpiryChain Kind = "chain_all_expiry"
+	// KindExpiries is a symbol's list of expiration dates.
+	KindExpiries Kind = "expiries"
+	// KindGEX is a computed all-expiry GEX profile.
+	KindGEX Kind = "gex"
+)
+
+// Policy maps each kind to how long its values are kept.
+type Policy map[Kind]time.Duration
+
+// DefaultPolicy returns the TTLs used unless CACHE_TTL overrides them.
+func DefaultPolicy() Policy {
+	return Policy{
+		KindSpot:           15 * time.Second,
+		KindChain:          time.Minute,
+		KindAllExpiryChain: 4 * time.Hour,
+		KindExpiries:       24 * time.Hour,
+		KindGEX:            time.Minute,
+	}
+}
+
+// ParsePolicy applies overrides such as "spot=30s,chain=2m" on top of base.
+// Unknown kinds and non-positive durations are rejected.
+func ParsePolicy(overrides string, base Policy) (Policy, error) {
+	policy := make(Policy, len(base))
+	for kind, ttl := range base {
+		policy[kind] = ttl
+	}
+	for _, item := range strings.Split(overrides, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("cache TTL %q: want kind=duration", item)
+		}
+		kind := Kind(strings.ToLower(strings.TrimSpace(name)))
+		if _, known := base[kind]; !known {
+			return nil, fmt.Errorf("cache TTL %q: unknown kind %q (want one of %s)", item, kind, strings.Join(base.kinds(), ", "))
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("cache TTL %q: invalid duration", item)
+		}
+		policy[kind] = ttl
+	}
+	return policy, nil
+}
+
+func (p Policy) kinds() []string {
+	kinds := make([]string, 0, len(p))
+	for kind := range p {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	return kinds
+}
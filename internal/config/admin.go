@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadAdminToken reads the shared secret protecting the /admin routes from
+// ADMIN_TOKEN or, failing that, the file named by ADMIN_TOKEN_FILE.
+func LoadAdminToken() (string, error) {
+	token, ok := os.LookupEnv("ADMIN_TOKEN")
+	if ok {
+		return strings.TrimSpace(token), nil
+	}
+
+	tokenFile, ok := os.LookupEnv("ADMIN_TOKEN_FILE")
+	if !ok {
+		return "", fmt.Errorf("no ADMIN_TOKEN or ADMIN_TOKEN_FILE env var set")
+	}
+
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from admin token file: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,426 @@
+package eth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Param is a named argument of a function or event, e.g. "address to".
+type Param struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed,omitempty"`
+}
+
+// Function is a contract function known by its selector.
+type Function struct {
+	Name string `json:"name"`
+	// Signature is the canonical form the selector hashes, e.g.
+	// "transfer(address,uint256)".
+	Signature string  `json:"signature"`
+	Selector  string  `json:"selector"`
+	Inputs    []Param `json:"inputs"`
+}
+
+// Event is a contract event known by its first topic.
+type Event struct {
+	Name      string  `json:"name"`
+	Signature string  `json:"signature"`
+	Topic     string  `json:"topic"`
+	Inputs    []Param `json:"inputs"`
+}
+
+// Token is an ERC-20 or ERC-721 contract.
+type Token struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// Arg is a decoded argument, its value formatted for display.
+type Arg struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Registry holds the functions, events and tokens calls and logs are decoded
+// with. Several events may share a topic when they differ only in which
+// arguments are indexed, as ERC-20 and ERC-721 Transfer do.
+type Registry struct {
+	functions map[string]Function
+	events    map[string][]Event
+	tokens    map[string]Token
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		functions: make(map[string]Function),
+		events:    make(map[string][]Event),
+		tokens:    make(map[string]Token),
+	}
+}
+
+// DefaultRegistry knows the ERC-20 and ERC-721 functions and events, WETH and
+// the Uniswap V2 router's swaps, and the major tokens.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, sig := range []string{
+		"transfer(address to, uint256 amount)",
+		"transferFrom(address from, address to, uint256 amount)",
+		"approve(address spender, uint256 amount)",
+		"safeTransferFrom(address from, address to, uint256 tokenId)",
+		"safeTransferFrom(address from, address to, uint256 tokenId, bytes data)",
+		"setApprovalForAll(address operator, bool approved)",
+		"deposit()",
+		"withdraw(uint256 amount)",
+		"swapExactETHForTokens(uint256 amountOutMin, address[] path, address to, uint256 deadline)",
+		"swapExactTokensForETH(uint256 amountIn, uint256 amountOutMin, address[] path, address to, uint256 deadline)",
+		"swapExactTokensForTokens(uint256 amountIn, uint256 amountOutMin, address[] path, address to, uint256 deadline)",
+	} {
+		if err := r.AddFunction(sig); err != nil {
+			panic(err)
+		}
+	}
+	for _, sig := range []string{
+		"Transfer(address indexed from, address indexed to, uint256 value)",
+		"Transfer(address indexed from, address indexed to, uint256 indexed tokenId)",
+		"Approval(address indexed owner, address indexed spender, uint256 value)",
+		"ApprovalForAll(address indexed owner, address indexed operator, bool approved)",
+		"Deposit(address indexed dst, uint256 wad)",
+		"Withdrawal(address indexed src, uint256 wad)",
+	} {
+		if err := r.AddEvent(sig); err != nil {
+			panic(err)
+		}
+	}
+	r.AddToken("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Token{Symbol: "USDC", Decimals: 6})
+	r.AddToken("0xdac17f958d2ee523a2206206994597c13d831ec7", Token{Symbol: "USDT", Decimals: 6})
+	r.AddToken("0x6b175474e89094c44da98b954eedeac495271d0f", Token{Symbol: "DAI", Decimals: 18})
+	r.AddToken("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", Token{Symbol: "WETH", Decimals: 18})
+	r.AddToken("0x2260fac5e5542a773aa44fbcfedf7c193bc2c599", Token{Symbol: "WBTC", Decimals: 8})
+	return r
+}
+
+// AddFunction registers a function from its declaration with parameter
+// names, e.g. "transfer(address to, uint256 amount)".
+func (r *Registry) AddFunction(declaration string) error {
+	name, inputs, err := parseDeclaration(declaration)
+	if err != nil {
+		return err
+	}
+	sig := signature(name, inputs)
+	f := Function{Name: name, Signature: sig, Selector: Keccak(sig)[:10], Inputs: inputs}
+	r.functions[f.Selector] = f
+	return nil
+}
+
+// AddEvent registers an event from its declaration, with "indexed" on the
+// indexed parameters.
+func (r *Registry) AddEvent(declaration string) error {
+	name, inputs, err := parseDeclaration(declaration)
+	if err != nil {
+		return err
+	}
+	sig := signature(name, inputs)
+	e := Event{Name: name, Signature: sig, Topic: Keccak(sig), Inputs: inputs}
+	r.events[e.Topic] = append(r.events[e.Topic], e)
+	return nil
+}
+
+// AddToken registers the token contract at address.
+func (r *Registry) AddToken(address string, t Token) {
+	t.Address = strings.ToLower(address)
+	r.tokens[t.Address] = t
+}
+
+// Tokens returns the registered tokens by symbol.
+func (r *Registry) Tokens() []Token {
+	tokens := make([]Token, 0, len(r.tokens))
+	for _, t := range r.tokens {
+		tokens = append(tokens, t)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Symbol < tokens[j].Symbol })
+	return tokens
+}
+
+// Token returns the token contract at address.
+func (r *Registry) Token(address string) (Token, bool) {
+	t, ok := r.tokens[strings.ToLower(address)]
+	return t, ok
+}
+
+// DecodeCall decodes transaction input. It reports false when the input is
+// empty or its selector unknown; the error is for input that doesn't match
+// the known function.
+func (r *Registry) DecodeCall(input string) (Function, []Arg, bool, error) {
+	data, err := decodeHex(input)
+	if err != nil || len(data) < 4 {
+		return Function{}, nil, false, err
+	}
+	f, ok := r.functions["0x"+hex.EncodeToString(data[:4])]
+	if !ok {
+		return Function{}, nil, false, nil
+	}
+	args, err := decodeArgs(f.Inputs, data[4:])
+	if err != nil {
+		return f, nil, true, fmt.Errorf("decode %s: %w", f.Signature, err)
+	}
+	return f, args, true, nil
+}
+
+// DecodeLog decodes an event. It reports false when no registered event
+// matches the log's topic and number of indexed arguments.
+func (r *Registry) DecodeLog(l Log) (Event, []Arg, bool) {
+	if len(l.Topics) == 0 {
+		return Event{}, nil, false
+	}
+	data, err := decodeHex(l.Data)
+	if err != nil {
+		return Event{}, nil, false
+	}
+	for _, e := range r.events[strings.ToLower(l.Topics[0])] {
+		var indexed, unindexed []Param
+		for _, p := range e.Inputs {
+			if p.Indexed {
+				indexed = append(indexed, p)
+			} else {
+				unindexed = append(unindexed, p)
+			}
+		}
+		if len(indexed) != len(l.Topics)-1 {
+			continue
+		}
+		values, err := decodeArgs(unindexed, data)
+		if err != nil {
+			continue
+		}
+		// Indexed arguments are the topics after the first, the others
+		// the data, each in declaration order.
+		args := make([]Arg, 0, len(e.Inputs))
+		ok := true
+		topics := l.Topics[1:]
+		for _, p := range e.Inputs {
+			if !p.Indexed {
+				args = append(args, values[0])
+				values = values[1:]
+				continue
+			}
+			topic, err := decodeHex(topics[0])
+			topics = topics[1:]
+			if err != nil || len(topic) != 32 {
+				ok = false
+				break
+			}
+			v, err := decodeStatic(p.Type, topic)
+			if err != nil {
+				ok = false
+				break
+			}
+			args = append(args, Arg{Name: p.Name, Type: p.Type, Value: v})
+		}
+		if ok {
+			return e, args, true
+		}
+	}
+	return Event{}, nil, false
+}
+
+// Keccak returns the 0x-prefixed Keccak-256 hash of s.
+func Keccak(s string) string {
+	return "0x" + hex.EncodeToString(keccak([]byte(s)))
+}
+
+func keccak(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		h.Write(b)
+	}
+	return h.Sum(nil)
+}
+
+// parseDeclaration splits "name(type name, type indexed name)".
+func parseDeclaration(declaration string) (string, []Param, error) {
+	open := strings.Index(declaration, "(")
+	if open <= 0 || !strings.HasSuffix(declaration, ")") {
+		return "", nil, fmt.Errorf("invalid declaration %q", declaration)
+	}
+	name := strings.TrimSpace(declaration[:open])
+	list := strings.TrimSpace(declaration[open+1 : len(declaration)-1])
+	if list == "" {
+		return name, nil, nil
+	}
+	var params []Param
+	for _, field := range strings.Split(list, ",") {
+		parts := strings.Fields(field)
+		if len(parts) == 0 {
+			return "", nil, fmt.Errorf("invalid declaration %q", declaration)
+		}
+		p := Param{Type: parts[0]}
+		for _, part := range parts[1:] {
+			if part == "indexed" {
+				p.Indexed = true
+			} else {
+				p.Name = part
+			}
+		}
+		if !supportedType(p.Type) {
+			return "", nil, fmt.Errorf("%s: unsupported type %s", declaration, p.Type)
+		}
+		params = append(params, p)
+	}
+	return name, params, nil
+}
+
+// signature is the canonical signature: the name and parameter types.
+func signature(name string, params []Param) string {
+	types := make([]string, len(params))
+	for i, p := range params {
+		types[i] = p.Type
+	}
+	return name + "(" + strings.Join(types, ",") + ")"
+}
+
+// supportedType reports whether the decoder handles t: static types, bytes,
+// string and arrays of static types.
+func supportedType(t string) bool {
+	if elem, ok := strings.CutSuffix(t, "[]"); ok {
+		return isStatic(elem)
+	}
+	return t == "bytes" || t == "string" || isStatic(t)
+}
+
+func isStatic(t string) bool {
+	switch {
+	case t == "address" || t == "bool":
+		return true
+	case strings.HasPrefix(t, "uint"):
+		return validBits(t[4:])
+	case strings.HasPrefix(t, "int"):
+		return validBits(t[3:])
+	case strings.HasPrefix(t, "bytes"):
+		n, err := strconv.Atoi(t[5:])
+		return err == nil && n >= 1 && n <= 32
+	}
+	return false
+}
+
+func validBits(s string) bool {
+	if s == "" {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 8 && n <= 256 && n%8 == 0
+}
+
+var errShortData = errors.New("data too short")
+
+// decodeArgs decodes ABI-encoded arguments: one 32-byte head slot each,
+// dynamic values at the offset in their slot.
+func decodeArgs(params []Param, data []byte) ([]Arg, error) {
+	args := make([]Arg, 0, len(params))
+	for i, p := range params {
+		slot, err := word(data, 32*i)
+		if err != nil {
+			return nil, err
+		}
+		var v string
+		if isStatic(p.Type) {
+			v, err = decodeStatic(p.Type, slot)
+		} else {
+			v, err = decodeDynamic(p.Type, data, slot)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Name, err)
+		}
+		args = append(args, Arg{Name: p.Name, Type: p.Type, Value: v})
+	}
+	return args, nil
+}
+
+func decodeStatic(t string, w []byte) (string, error) {
+	switch {
+	case t == "address":
+		return "0x" + hex.EncodeToString(w[12:]), nil
+	case t == "bool":
+		return strconv.FormatBool(w[31] != 0), nil
+	case strings.HasPrefix(t, "uint"):
+		return new(big.Int).SetBytes(w).String(), nil
+	case strings.HasPrefix(t, "int"):
+		v := new(big.Int).SetBytes(w)
+		if w[0]&0x80 != 0 {
+			v.Sub(v, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return v.String(), nil
+	case strings.HasPrefix(t, "bytes"):
+		n, _ := strconv.Atoi(t[5:])
+		return "0x" + hex.EncodeToString(w[:n]), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+func decodeDynamic(t string, data, slot []byte) (string, error) {
+	offset, err := smallInt(slot)
+	if err != nil {
+		return "", err
+	}
+	lengthWord, err := word(data, offset)
+	if err != nil {
+		return "", err
+	}
+	length, err := smallInt(lengthWord)
+	if err != nil {
+		return "", err
+	}
+	start := offset + 32
+
+	if elem, ok := strings.CutSuffix(t, "[]"); ok {
+		values := make([]string, length)
+		for i := range values {
+			w, err := word(data, start+32*i)
+			if err != nil {
+				return "", err
+			}
+			if values[i], err = decodeStatic(elem, w); err != nil {
+				return "", err
+			}
+		}
+		return "[" + strings.Join(values, ", ") + "]", nil
+	}
+	if start+length > len(data) {
+		return "", errShortData
+	}
+	b := data[start : start+length]
+	if t == "string" {
+		return string(b), nil
+	}
+	return "0x" + hex.EncodeToString(b), nil
+}
+
+func word(data []byte, offset int) ([]byte, error) {
+	if offset < 0 || offset+32 > len(data) {
+		return nil, errShortData
+	}
+	return data[offset : offset+32], nil
+}
+
+// smallInt reads an offset or length, which must fit comfortably in an int.
+func smallInt(w []byte) (int, error) {
+	v := new(big.Int).SetBytes(w)
+	if !v.IsInt64() || v.Int64() > 1<<24 {
+		return 0, errors.New("offset out of range")
+	}
+	return int(v.Int64()), nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(s, "0x")
+	return hex.DecodeString(s)
+}
@@ -0,0 +1,119 @@
+package eth
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const (
+	usdc         = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	alice        = "0x00000000000000000000000000000000000a11ce"
+	bob          = "0x0000000000000000000000000000000000000b0b"
+	transferSig  = "0xa9059cbb"
+	transferLog  = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	approvalLog  = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+	latestAnswer = "0x50d25bcd"
+)
+
+// pad left-pads a hex value, with or without 0x, to a 32-byte word.
+func pad(v string) string {
+	v = strings.TrimPrefix(v, "0x")
+	return strings.Repeat("0", 64-len(v)) + v
+}
+
+func TestKeccakSelectors(t *testing.T) {
+	for sig, want := range map[string]string{
+		"transfer(address,uint256)":         transferSig,
+		"Transfer(address,address,uint256)": transferLog,
+		"Approval(address,address,uint256)": approvalLog,
+		"latestAnswer()":                    latestAnswer,
+	} {
+		if got := Keccak(sig)[:len(want)]; got != want {
+			t.Errorf("Keccak(%s) = %s, want %s", sig, got, want)
+		}
+	}
+}
+
+func TestDecodeCall(t *testing.T) {
+	reg := DefaultRegistry()
+
+	input := transferSig + pad(bob) + pad(fmt.Sprintf("%x", 1500000))
+	f, args, ok, err := reg.DecodeCall(input)
+	if !ok || err != nil {
+		t.Fatalf("transfer not decoded: %v", err)
+	}
+	if f.Name != "transfer" || len(args) != 2 || args[0].Name != "to" || args[0].Value != bob || args[1].Value != "1500000" {
+		t.Errorf("transfer = %s %+v", f.Signature, args)
+	}
+
+	// swapExactETHForTokens(uint256, address[], address, uint256): the path
+	// is at offset 0x80, after the four head slots.
+	input = Keccak("swapExactETHForTokens(uint256,address[],address,uint256)")[:10] +
+		pad("64") + pad("80") + pad(alice) + pad("ffff") +
+		pad("2") + pad("c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2") + pad(usdc)
+	f, args, ok, err = reg.DecodeCall(input)
+	if !ok || err != nil {
+		t.Fatalf("swap not decoded: %v", err)
+	}
+	if want := "[0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2, " + usdc + "]"; args[1].Value != want {
+		t.Errorf("path = %s, want %s", args[1].Value, want)
+	}
+	if args[0].Value != "100" || args[3].Value != "65535" {
+		t.Errorf("swap = %s %+v", f.Signature, args)
+	}
+
+	if _, _, ok, _ := reg.DecodeCall("0xdeadbeef"); ok {
+		t.Error("unknown selector decoded")
+	}
+	if _, _, ok, _ := reg.DecodeCall("0x"); ok {
+		t.Error("empty input decoded")
+	}
+	if _, _, ok, err := reg.DecodeCall(transferSig + pad(bob)); !ok || err == nil {
+		t.Errorf("truncated transfer: ok %v, err %v", ok, err)
+	}
+}
+
+func TestDecodeLog(t *testing.T) {
+	reg := DefaultRegistry()
+
+	// ERC-20: the amount is in the data.
+	e, args, ok := reg.DecodeLog(Log{
+		Address: usdc,
+		Topics:  []string{transferLog, "0x" + pad(alice), "0x" + pad(bob)},
+		Data:    "0x" + pad(fmt.Sprintf("%x", 2500000)),
+	})
+	if !ok || e.Inputs[2].Indexed || args[0].Value != alice || args[1].Value != bob || args[2].Value != "2500000" {
+		t.Errorf("ERC-20 transfer = %v %+v", ok, args)
+	}
+
+	// ERC-721: the token ID is the third topic and there is no data.
+	e, args, ok = reg.DecodeLog(Log{
+		Topics: []string{transferLog, "0x" + pad(alice), "0x" + pad(bob), "0x" + pad("7")},
+		Data:   "0x",
+	})
+	if !ok || !e.Inputs[2].Indexed || args[2].Name != "tokenId" || args[2].Value != "7" {
+		t.Errorf("ERC-721 transfer = %v %+v", ok, args)
+	}
+
+	// A signed value and an unknown event.
+	reg.AddEvent("Moved(address indexed who, int256 delta)")
+	_, args, ok = reg.DecodeLog(Log{
+		Topics: []string{Keccak("Moved(address,int256)"), "0x" + pad(alice)},
+		Data:   "0x" + strings.Repeat("f", 64),
+	})
+	if !ok || args[1].Value != "-1" {
+		t.Errorf("Moved = %v %+v", ok, args)
+	}
+	if _, _, ok := reg.DecodeLog(Log{Topics: []string{"0x" + pad("1")}}); ok {
+		t.Error("unknown event decoded")
+	}
+}
+
+func TestParseDeclaration(t *testing.T) {
+	for _, bad := range []string{"transfer", "transfer(address to", "f(tuple x)", "f(uint7 x)", "f(bytes33 x)", "f(string[] x)"} {
+		if _, _, err := parseDeclaration(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
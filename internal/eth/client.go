@@ -0,0 +1,389 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for a transaction the node doesn't know.
+	ErrNotFound = errors.New("not found")
+	// ErrNoPriceFeed is returned by ETHUSD on a network without a feed.
+	ErrNoPriceFeed = errors.New("no ETH/USD price feed on this network")
+)
+
+const (
+	// ChainlinkETHUSD is the Chainlink ETH/USD price feed on mainnet; its
+	// answer has 8 decimals.
+	ChainlinkETHUSD = "0x5f4ec3df9cbd43714fe2740f5e3616155c5b8419"
+	priceDecimals   = 8
+	// priceTTL is how long a price is reused; the feed updates at most
+	// every few minutes.
+	priceTTL = time.Minute
+	// downFor is how long an endpoint that failed is tried only after the
+	// others.
+	downFor = 30 * time.Second
+)
+
+// RPCError is an error returned by the node.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// Client calls the JSON-RPC API of a network's nodes. Calls go to the
+// first endpoint that is up and fail over to the next one when it can't be
+// reached, answers with an HTTP error or sends something that isn't
+// JSON-RPC. Errors the node itself returns are passed on as they are.
+type Client struct {
+	network   Network
+	endpoints []*endpoint
+	http      *http.Client
+	registry  *Registry
+
+	mu      sync.Mutex
+	price   float64
+	priceAt time.Time
+}
+
+type endpoint struct {
+	url  string
+	name string
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+func (e *endpoint) up(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !now.Before(e.downUntil)
+}
+
+func (e *endpoint) report(err error, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err != nil {
+		e.downUntil = now.Add(downFor)
+	} else {
+		e.downUntil = time.Time{}
+	}
+}
+
+// NewClient returns a client of network's nodes at endpoints, in order of
+// preference, that decodes with DefaultRegistry.
+func NewClient(network Network, endpoints []string, httpClient *http.Client) *Client {
+	c := &Client{
+		network:  network,
+		http:     httpClient,
+		registry: DefaultRegistry(),
+	}
+	for _, u := range endpoints {
+		c.endpoints = append(c.endpoints, &endpoint{url: u, name: redact(u)})
+	}
+	return c
+}
+
+// Network returns the network the client queries.
+func (c *Client) Network() Network {
+	return c.network
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+	ID      int           `json:"id"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// call makes one JSON-RPC call, decoding its result into result. A null
+// result leaves result untouched and returns ErrNotFound.
+func (c *Client) call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	if len(c.endpoints) == 0 {
+		return fmt.Errorf("%s: no %s endpoints configured", method, c.network.Name)
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", Method: method, Params: params, ID: 1})
+	if err != nil {
+		return err
+	}
+
+	// Endpoints that failed recently go last rather than not at all, so a
+	// call still has a chance when every one of them did.
+	now := time.Now()
+	order := make([]*endpoint, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		if e.up(now) {
+			order = append(order, e)
+		}
+	}
+	for _, e := range c.endpoints {
+		if !e.up(now) {
+			order = append(order, e)
+		}
+	}
+
+	var (
+		out  *rpcResponse
+		errs []error
+	)
+	for _, e := range order {
+		out, err = c.post(ctx, e, body)
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about the endpoint.
+			return fmt.Errorf("%s: %w", method, ctx.Err())
+		}
+		e.report(err, time.Now())
+		if err == nil {
+			break
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", e.name, err))
+	}
+	switch {
+	case out == nil && len(errs) == 1:
+		return fmt.Errorf("%s: %w", method, errs[0])
+	case out == nil:
+		return fmt.Errorf("%s: all %d endpoints failed: %w", method, len(errs), errors.Join(errs...))
+	}
+
+	if out.Error != nil {
+		return fmt.Errorf("%s: %w", method, out.Error)
+	}
+	if len(out.Result) == 0 || string(out.Result) == "null" {
+		return ErrNotFound
+	}
+	if err := json.Unmarshal(out.Result, result); err != nil {
+		return fmt.Errorf("%s: decode result: %w", method, err)
+	}
+	return nil
+}
+
+// post sends body to e. Its errors are the endpoint's fault and leave out
+// the URL, which may hold an API key.
+func (c *Client) post(ctx context.Context, e *endpoint, body []byte) (*rpcResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.New("invalid endpoint URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+
+	var out rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &out, nil
+}
+
+// Verify asks every endpoint for its chain ID and returns an error for
+// each one that doesn't answer or is on another chain, which catches a URL
+// configured under the wrong network.
+func (c *Client) Verify(ctx context.Context) error {
+	var errs []error
+	for _, e := range c.endpoints {
+		single := &Client{network: c.network, endpoints: []*endpoint{e}, http: c.http}
+		var id Uint64
+		if err := single.call(ctx, &id, "eth_chainId"); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if uint64(id) != c.network.ChainID {
+			errs = append(errs, fmt.Errorf("%s: chain ID %d, want %d for %s", e.name, id, c.network.ChainID, c.network.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TransactionByHash returns the transaction with hash, or ErrNotFound.
+func (c *Client) TransactionByHash(ctx context.Context, hash string) (*Transaction, error) {
+	var tx Transaction
+	if err := c.call(ctx, &tx, "eth_getTransactionByHash", hash); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// TransactionReceipt returns the receipt of the transaction with hash, or
+// ErrNotFound while it is pending.
+func (c *Client) TransactionReceipt(ctx context.Context, hash string) (*Receipt, error) {
+	var r Receipt
+	if err := c.call(ctx, &r, "eth_getTransactionReceipt", hash); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// BlockNumber returns the number of the latest block.
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var n Uint64
+	if err := c.call(ctx, &n, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	return uint64(n), nil
+}
+
+// BlockByNumber returns block n with its transactions, or ErrNotFound when
+// it hasn't been mined yet.
+func (c *Client) BlockByNumber(ctx context.Context, n uint64) (*Block, error) {
+	return c.block(ctx, "eth_getBlockByNumber", quantity(n))
+}
+
+// LatestBlock returns the latest block with its transactions.
+func (c *Client) LatestBlock(ctx context.Context) (*Block, error) {
+	return c.block(ctx, "eth_getBlockByNumber", "latest")
+}
+
+// BlockByHash returns the block with hash and its transactions, or
+// ErrNotFound.
+func (c *Client) BlockByHash(ctx context.Context, hash string) (*Block, error) {
+	return c.block(ctx, "eth_getBlockByHash", hash)
+}
+
+func (c *Client) block(ctx context.Context, method string, id string) (*Block, error) {
+	var b Block
+	if err := c.call(ctx, &b, method, id, true); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Balance returns the wei held by address at the latest block.
+func (c *Client) Balance(ctx context.Context, address string) (*big.Int, error) {
+	var b Big
+	if err := c.call(ctx, &b, "eth_getBalance", address, "latest"); err != nil {
+		return nil, err
+	}
+	return &b.Int, nil
+}
+
+// TransactionCount returns the nonce of address: how many transactions it
+// has sent.
+func (c *Client) TransactionCount(ctx context.Context, address string) (uint64, error) {
+	var n Uint64
+	if err := c.call(ctx, &n, "eth_getTransactionCount", address, "latest"); err != nil {
+		return 0, err
+	}
+	return uint64(n), nil
+}
+
+// Code returns the bytecode at address, "0x" for an account without code.
+func (c *Client) Code(ctx context.Context, address string) (string, error) {
+	var code string
+	if err := c.call(ctx, &code, "eth_getCode", address, "latest"); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// CallContract runs data against the contract at to on the latest block and
+// returns its output, which is empty when there is no contract there.
+func (c *Client) CallContract(ctx context.Context, to, data string) ([]byte, error) {
+	var out string
+	if err := c.call(ctx, &out, "eth_call", map[string]string{"to": to, "data": data}, "latest"); err != nil {
+		return nil, err
+	}
+	b, err := decodeHex(out)
+	if err != nil {
+		return nil, fmt.Errorf("eth_call: %w", err)
+	}
+	return b, nil
+}
+
+// LogFilter selects logs for Logs. Each position of Topics matches any of
+// its values, and a nil position matches every topic.
+type LogFilter struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Address   []string
+	Topics    [][]string
+}
+
+func (f LogFilter) MarshalJSON() ([]byte, error) {
+	topics := make([]interface{}, len(f.Topics))
+	for i, t := range f.Topics {
+		if t != nil {
+			topics[i] = t
+		}
+	}
+	out := map[string]interface{}{
+		"fromBlock": quantity(f.FromBlock),
+		"toBlock":   quantity(f.ToBlock),
+		"topics":    topics,
+	}
+	if len(f.Address) > 0 {
+		out["address"] = f.Address
+	}
+	return json.Marshal(out)
+}
+
+// Logs returns the logs matching f. Providers cap the block range and the
+// number of results, and return an error beyond them.
+func (c *Client) Logs(ctx context.Context, f LogFilter) ([]Log, error) {
+	var logs []Log
+	if err := c.call(ctx, &logs, "eth_getLogs", f); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func quantity(n uint64) string {
+	return fmt.Sprintf("0x%x", n)
+}
+
+// ETHUSD returns the latest answer of the network's Chainlink ETH/USD feed,
+// or ErrNoPriceFeed.
+func (c *Client) ETHUSD(ctx context.Context) (float64, error) {
+	if c.network.PriceFeed == "" {
+		return 0, ErrNoPriceFeed
+	}
+	c.mu.Lock()
+	if !c.priceAt.IsZero() && time.Since(c.priceAt) < priceTTL {
+		defer c.mu.Unlock()
+		return c.price, nil
+	}
+	c.mu.Unlock()
+
+	data, err := c.CallContract(ctx, c.network.PriceFeed, Keccak("latestAnswer()")[:10])
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 32 {
+		return 0, fmt.Errorf("eth_call: unexpected price answer 0x%x", data)
+	}
+	price := toFloat(new(big.Int).SetBytes(data), priceDecimals)
+
+	c.mu.Lock()
+	c.price, c.priceAt = price, time.Now()
+	c.mu.Unlock()
+	return price, nil
+}
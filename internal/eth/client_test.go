@@ -0,0 +1,137 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/arnabmitra/eth-proxy/internal/httpreplay"
+)
+
+func TestLookupReplay(t *testing.T) {
+	rec := httpreplay.ForTest(t, "alchemy_tx")
+
+	key := "test-key"
+	if data, err := os.ReadFile(os.Getenv("ETH_API_KEY_FILE")); err == nil {
+		key = strings.TrimSpace(string(data))
+	}
+	// The Alchemy key is part of the path, so keep it out of the cassette.
+	rec.Secrets = []string{key}
+
+	const txHash = "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+	c := NewClient(Mainnet, []string{"https://eth-mainnet.alchemyapi.io/v2/" + key}, rec.Client())
+	d, err := c.Lookup(context.Background(), txHash)
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if d.Hash != txHash || d.Network != "mainnet" || d.Status != StatusSuccess || d.From == "" {
+		t.Errorf("incomplete transaction: %+v", d)
+	}
+	// 0x116c197, every digit of it.
+	if d.BlockNumber != 18268567 {
+		t.Errorf("block = %d, want 18268567", d.BlockNumber)
+	}
+	if d.Value != "0.567" || d.GasUsed != 21000 || d.GasPrice != "18" || d.Fee != "0.000378" {
+		t.Errorf("value %s, gas used %d at %s gwei, fee %s", d.Value, d.GasUsed, d.GasPrice, d.Fee)
+	}
+	if d.ValueUSDText() != "$1,417.50" || d.FeeUSD == nil || math.Abs(*d.FeeUSD-0.945) > 1e-9 {
+		t.Errorf("value %s, fee %s", d.ValueUSDText(), d.FeeUSDText())
+	}
+	if d.Call != nil || len(d.Transfers) != 0 {
+		t.Errorf("plain transfer decoded as a call: %+v", d.Call)
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "missing"):
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`))
+		case strings.Contains(r.URL.Path, "broken"):
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument"}}`))
+		default:
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	hash := "0x" + strings.Repeat("ab", 32)
+	_, err := NewClient(Mainnet, []string{srv.URL + "/missing"}, srv.Client()).TransactionByHash(context.Background(), hash)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("null result: %v", err)
+	}
+	_, err = NewClient(Mainnet, []string{srv.URL + "/broken"}, srv.Client()).TransactionByHash(context.Background(), hash)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32602 {
+		t.Errorf("rpc error: %v", err)
+	}
+	_, err = NewClient(Mainnet, []string{srv.URL + "/limited"}, srv.Client()).TransactionByHash(context.Background(), hash)
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("http error: %v", err)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		switch r.URL.Path {
+		case "/down/secret-key":
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		case "/node":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(Mainnet, []string{srv.URL + "/down/secret-key", srv.URL + "/node"}, srv.Client())
+	if err := c.Verify(context.Background()); err == nil || !strings.Contains(err.Error(), "502") || strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("Verify: %v", err)
+	}
+
+	calls = nil
+	var id Uint64
+	if err := c.call(context.Background(), &id, "eth_chainId"); err != nil || id != 1 {
+		t.Fatalf("call = %d, %v", id, err)
+	}
+	// The endpoint that just failed is tried last.
+	if len(calls) != 1 || calls[0] != "/node" {
+		t.Errorf("calls = %v", calls)
+	}
+
+	// Every endpoint down: each error is kept and no URL leaks.
+	c = NewClient(Mainnet, []string{srv.URL + "/down/secret-key", "http://127.0.0.1:1/other-key"}, srv.Client())
+	err := c.call(context.Background(), &id, "eth_chainId")
+	if err == nil || !strings.Contains(err.Error(), "all 2 endpoints failed") || strings.Contains(err.Error(), "key") {
+		t.Errorf("all down: %v", err)
+	}
+
+	// A node error is an answer, not a reason to fail over.
+	calls = nil
+	c = NewClient(Mainnet, []string{srv.URL + "/reverts", srv.URL + "/node"}, srv.Client())
+	var rpcErr *RPCError
+	if err := c.call(context.Background(), &id, "eth_call"); !errors.As(err, &rpcErr) || len(calls) != 1 {
+		t.Errorf("node error: %v after %v", err, calls)
+	}
+
+	if err := NewClient(Mainnet, []string{srv.URL + "/node"}, srv.Client()).Verify(context.Background()); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	local, _ := NetworkByName("local")
+	if err := NewClient(local, []string{srv.URL + "/node"}, srv.Client()).Verify(context.Background()); err == nil || !strings.Contains(err.Error(), "want 31337") {
+		t.Errorf("Verify on the wrong chain: %v", err)
+	}
+	if _, err := NewClient(local, nil, srv.Client()).ETHUSD(context.Background()); !errors.Is(err, ErrNoPriceFeed) {
+		t.Errorf("ETHUSD without a feed: %v", err)
+	}
+	if _, err := NewClient(local, nil, srv.Client()).TransactionByHash(context.Background(), "0x01"); err == nil {
+		t.Error("call without endpoints succeeded")
+	}
+}
@@ -0,0 +1,9 @@
+// Package eth reads transactions from an Ethereum node over JSON-RPC and
+// describes them for the /eth-tx page: the transaction with its receipt, the
+// fee in ETH and USD, the decoded call and the ERC-20 and ERC-721 transfers
+// it emitted.
+//
+// Calls and events are decoded with a Registry of known function and event
+// signatures and token contracts; anything it doesn't know is shown raw. The
+// ETH/USD price is read from the Chainlink feed on the same node.
+package eth
@@ -0,0 +1,124 @@
+package eth
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ENSRegistry is the ENS registry, at the same address on mainnet and
+// Sepolia.
+const ENSRegistry = "0x00000000000c2e074ec69a0dfb2997ba6c7d2e1e"
+
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// ErrNoENS is returned by ENS lookups on a network without ENS.
+var ErrNoENS = errors.New("ENS is not available on this network")
+
+// IsENSName reports whether s looks like an ENS name, e.g. "vitalik.eth":
+// dot-separated labels without spaces.
+func IsENSName(s string) bool {
+	labels := strings.Split(s, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, l := range labels {
+		if l == "" || strings.ContainsAny(l, " \t/?#%") {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeName lowercases name. Full ENSIP-15 normalization, which also
+// maps emoji and rejects confusable characters, is left to the user: a name
+// that isn't normalized simply won't resolve.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Namehash returns the ENS node of name: the Keccak-256 of the parent's
+// node and the label's hash, from the root down.
+func Namehash(name string) []byte {
+	node := make([]byte, 32)
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = keccak(node, keccak([]byte(labels[i])))
+	}
+	return node
+}
+
+// ResolveName returns the address name points to, or ErrNotFound when it has
+// no resolver or no address.
+func (c *Client) ResolveName(ctx context.Context, name string) (string, error) {
+	if !c.network.ENS {
+		return "", ErrNoENS
+	}
+	node := Namehash(NormalizeName(name))
+	resolver, err := c.ensAddress(ctx, ENSRegistry, "resolver(bytes32)", node)
+	if err != nil {
+		return "", err
+	}
+	return c.ensAddress(ctx, resolver, "addr(bytes32)", node)
+}
+
+// LookupAddress returns the primary ENS name of address, or ErrNotFound when
+// it has none or the name doesn't resolve back to address.
+func (c *Client) LookupAddress(ctx context.Context, address string) (string, error) {
+	if !c.network.ENS {
+		return "", ErrNoENS
+	}
+	node := Namehash(strings.ToLower(strings.TrimPrefix(address, "0x")) + ".addr.reverse")
+	resolver, err := c.ensAddress(ctx, ENSRegistry, "resolver(bytes32)", node)
+	if err != nil {
+		return "", err
+	}
+	out, err := c.CallContract(ctx, resolver, ensCall("name(bytes32)", node))
+	if err != nil {
+		return "", err
+	}
+	slot, err := word(out, 0)
+	if err != nil {
+		return "", ErrNotFound
+	}
+	name, err := decodeDynamic("string", out, slot)
+	if err != nil || name == "" {
+		return "", ErrNotFound
+	}
+
+	// Anyone can claim any name in reverse records; only the forward record
+	// proves it.
+	forward, err := c.ResolveName(ctx, name)
+	if errors.Is(err, ErrNotFound) || (err == nil && !strings.EqualFold(forward, address)) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// ensAddress calls sig(node) on contract and returns the address it
+// answers, or ErrNotFound for the zero address or no contract.
+func (c *Client) ensAddress(ctx context.Context, contract, sig string, node []byte) (string, error) {
+	out, err := c.CallContract(ctx, contract, ensCall(sig, node))
+	if err != nil {
+		return "", err
+	}
+	if len(out) < 32 {
+		return "", ErrNotFound
+	}
+	addr, _ := decodeStatic("address", out[:32])
+	if addr == zeroAddress {
+		return "", ErrNotFound
+	}
+	return addr, nil
+}
+
+func ensCall(sig string, node []byte) string {
+	return Keccak(sig)[:10] + hex.EncodeToString(node)
+}
@@ -0,0 +1,31 @@
+package eth
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestNamehash(t *testing.T) {
+	for name, want := range map[string]string{
+		"":        "0000000000000000000000000000000000000000000000000000000000000000",
+		"eth":     "93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae",
+		"foo.eth": "de9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f",
+	} {
+		if got := hex.EncodeToString(Namehash(name)); got != want {
+			t.Errorf("Namehash(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestIsENSName(t *testing.T) {
+	for _, name := range []string{"vitalik.eth", "sub.Domain.eth", "xn--ls8h.eth"} {
+		if !IsENSName(name) {
+			t.Errorf("%q rejected", name)
+		}
+	}
+	for _, bad := range []string{"", "eth", "vitalik..eth", ".eth", "vitalik eth.eth", alice} {
+		if IsENSName(bad) {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ScanBlocks is how many of the latest blocks an address lookup reads
+	// for the address's transactions: JSON-RPC has no index by address, so
+	// every transaction of those blocks is fetched.
+	ScanBlocks = 20
+	// TransferBlocks is how far back an address lookup asks for its token
+	// transfers, about seven hours of mainnet blocks.
+	TransferBlocks = 2000
+	// maxTransfers caps the transfers shown, newest first.
+	maxTransfers = 50
+	// maxConcurrent is how many blocks are fetched at once.
+	maxConcurrent = 5
+)
+
+// TxSummary is a transaction of a block or an address.
+type TxSummary struct {
+	Hash  string `json:"hash"`
+	Block uint64 `json:"block"`
+	From  string `json:"from"`
+	To    string `json:"to,omitempty"`
+	// Value is in ETH.
+	Value string `json:"value"`
+	// Method is the name of the function called, its selector when unknown
+	// and empty for a plain transfer.
+	Method string `json:"method,omitempty"`
+}
+
+func summarize(tx Transaction, reg *Registry) TxSummary {
+	s := TxSummary{
+		Hash:  tx.Hash,
+		From:  tx.From,
+		To:    tx.To,
+		Value: FormatUnits(&tx.Value.Int, etherDecimals),
+	}
+	if tx.BlockNumber != nil {
+		s.Block = uint64(*tx.BlockNumber)
+	}
+	if len(strings.TrimPrefix(tx.Input, "0x")) >= 8 {
+		s.Method = strings.ToLower(tx.Input[:10])
+		if f, _, ok, _ := reg.DecodeCall(tx.Input); ok {
+			s.Method = f.Name
+		}
+	}
+	return s
+}
+
+// BlockDetails describes a block and its transactions.
+type BlockDetails struct {
+	Network     string    `json:"network"`
+	ExplorerURL string    `json:"explorer_url,omitempty"`
+	Number      uint64    `json:"number"`
+	Hash        string    `json:"hash"`
+	ParentHash  string    `json:"parent_hash"`
+	Time        time.Time `json:"time"`
+	Miner       string    `json:"miner"`
+	GasUsed     uint64    `json:"gas_used"`
+	GasLimit    uint64    `json:"gas_limit"`
+	// BaseFee is in gwei and BurntFees, the base fee of the gas used, in
+	// ETH; both are empty before the London fork.
+	BaseFee      string      `json:"base_fee_gwei,omitempty"`
+	BurntFees    string      `json:"burnt_fees,omitempty"`
+	Transactions []TxSummary `json:"transactions"`
+}
+
+// GasUsedPercent is the share of the gas limit used.
+func (b *BlockDetails) GasUsedPercent() float64 {
+	if b.GasLimit == 0 {
+		return 0
+	}
+	return 100 * float64(b.GasUsed) / float64(b.GasLimit)
+}
+
+// ValidBlockID reports whether id names a block for Block: a decimal
+// number, "latest" or a block hash.
+func ValidBlockID(id string) bool {
+	if id == "latest" || ValidHash(id) {
+		return true
+	}
+	_, err := strconv.ParseUint(id, 10, 64)
+	return err == nil
+}
+
+// Block fetches the block id, as accepted by ValidBlockID, and describes it.
+func (c *Client) Block(ctx context.Context, id string) (*BlockDetails, error) {
+	var (
+		b   *Block
+		err error
+	)
+	switch {
+	case id == "latest":
+		b, err = c.LatestBlock(ctx)
+	case ValidHash(id):
+		b, err = c.BlockByHash(ctx, id)
+	default:
+		n, perr := strconv.ParseUint(id, 10, 64)
+		if perr != nil {
+			return nil, perr
+		}
+		b, err = c.BlockByNumber(ctx, n)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d := DescribeBlock(b, c.registry)
+	d.Network, d.ExplorerURL = c.network.Name, c.network.BlockURL(d.Number)
+	return d, nil
+}
+
+// DescribeBlock summarizes b and its transactions.
+func DescribeBlock(b *Block, reg *Registry) *BlockDetails {
+	d := &BlockDetails{
+		Number:       uint64(b.Number),
+		Hash:         b.Hash,
+		ParentHash:   b.ParentHash,
+		Time:         time.Unix(int64(b.Timestamp), 0).UTC(),
+		Miner:        b.Miner,
+		GasUsed:      uint64(b.GasUsed),
+		GasLimit:     uint64(b.GasLimit),
+		Transactions: make([]TxSummary, 0, len(b.Transactions)),
+	}
+	if b.BaseFeePerGas != nil {
+		d.BaseFee = FormatUnits(&b.BaseFeePerGas.Int, gweiDecimals)
+		d.BurntFees = FormatUnits(new(big.Int).Mul(&b.BaseFeePerGas.Int, new(big.Int).SetUint64(d.GasUsed)), etherDecimals)
+	}
+	for _, tx := range b.Transactions {
+		d.Transactions = append(d.Transactions, summarize(tx, reg))
+	}
+	return d
+}
+
+// TokenBalance is a non-zero balance of a registered token.
+type TokenBalance struct {
+	Token  string `json:"token"`
+	Symbol string `json:"symbol"`
+	// Balance is in whole tokens.
+	Balance string `json:"balance"`
+}
+
+// AddressDetails describes an account or contract.
+type AddressDetails struct {
+	Network     string `json:"network"`
+	ExplorerURL string `json:"explorer_url,omitempty"`
+	Address     string `json:"address"`
+	// Name is the ENS name the address was looked up by, otherwise its
+	// primary name when it has one.
+	Name     string `json:"name,omitempty"`
+	Contract bool   `json:"contract"`
+
+	// Balance is in ETH.
+	Balance    string         `json:"balance"`
+	BalanceWei string         `json:"balance_wei"`
+	BalanceUSD *float64       `json:"balance_usd"`
+	ETHUSD     *float64       `json:"eth_usd"`
+	Nonce      uint64         `json:"nonce"`
+	Tokens     []TokenBalance `json:"tokens"`
+
+	// Transactions are the ones sent or received in blocks ScannedFrom to
+	// ScannedTo, newest first.
+	Transactions []TxSummary `json:"transactions"`
+	ScannedFrom  uint64      `json:"scanned_from"`
+	ScannedTo    uint64      `json:"scanned_to"`
+	// Transfers are the token transfers from or to the address since block
+	// TransfersFrom, newest first.
+	Transfers     []Transfer `json:"transfers"`
+	TransfersFrom uint64     `json:"transfers_from"`
+
+	// Warnings name the parts that couldn't be loaded; the rest is still
+	// shown.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// BalanceUSDText formats BalanceUSD like TxDetails.ValueUSDText.
+func (d *AddressDetails) BalanceUSDText() string {
+	return usdText(d.BalanceUSD)
+}
+
+// Address looks up query, an address or an ENS name, and describes it: its
+// balances and nonce, its transactions of the last ScanBlocks blocks and its
+// token transfers of the last TransferBlocks. Only the balance, nonce and
+// code are required; anything else that fails becomes a warning.
+func (c *Client) Address(ctx context.Context, query string) (*AddressDetails, error) {
+	d := &AddressDetails{
+		Network:      c.network.Name,
+		Address:      strings.ToLower(query),
+		Tokens:       []TokenBalance{},
+		Transactions: []TxSummary{},
+		Transfers:    []Transfer{},
+	}
+	if !ValidAddress(query) {
+		addr, err := c.ResolveName(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		d.Address, d.Name = addr, NormalizeName(query)
+	}
+	d.ExplorerURL = c.network.AddressURL(d.Address)
+
+	balance, err := c.Balance(ctx, d.Address)
+	if err != nil {
+		return nil, err
+	}
+	d.Balance, d.BalanceWei = FormatUnits(balance, etherDecimals), balance.String()
+	if d.Nonce, err = c.TransactionCount(ctx, d.Address); err != nil {
+		return nil, err
+	}
+	code, err := c.Code(ctx, d.Address)
+	if err != nil {
+		return nil, err
+	}
+	d.Contract = len(strings.TrimPrefix(code, "0x")) > 0
+	latest, err := c.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	warn := func(part string) {
+		mu.Lock()
+		d.Warnings = append(d.Warnings, part)
+		mu.Unlock()
+	}
+	run := func(fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+
+	run(func() {
+		if price, err := c.ETHUSD(ctx); err == nil {
+			usd := toFloat(balance, etherDecimals) * price
+			d.ETHUSD, d.BalanceUSD = &price, &usd
+		}
+	})
+	if d.Name == "" && c.network.ENS {
+		run(func() {
+			name, err := c.LookupAddress(ctx, d.Address)
+			if err == nil {
+				d.Name = name
+			} else if !errors.Is(err, ErrNotFound) {
+				warn("ENS name")
+			}
+		})
+	}
+	run(func() {
+		tokens, err := c.tokenBalances(ctx, d.Address)
+		if err != nil {
+			warn("token balances")
+		}
+		d.Tokens = tokens
+	})
+	run(func() {
+		d.ScannedFrom, d.ScannedTo = blocksBack(latest, ScanBlocks), latest
+		txs, err := c.scanTransactions(ctx, d.Address, d.ScannedFrom, latest)
+		if err != nil {
+			warn("recent transactions")
+		}
+		d.Transactions = txs
+	})
+	run(func() {
+		d.TransfersFrom = blocksBack(latest, TransferBlocks)
+		transfers, err := c.transfers(ctx, d.Address, d.TransfersFrom, latest)
+		if err != nil {
+			warn("token transfers")
+		}
+		d.Transfers = transfers
+	})
+	wg.Wait()
+
+	sort.Strings(d.Warnings)
+	return d, nil
+}
+
+// blocksBack is the first of the n blocks up to latest.
+func blocksBack(latest, n uint64) uint64 {
+	if latest < n {
+		return 0
+	}
+	return latest - n + 1
+}
+
+// tokenBalances returns the non-zero balances of address in the registered
+// tokens. Tokens not deployed on the network answer nothing and are left
+// out.
+func (c *Client) tokenBalances(ctx context.Context, address string) ([]TokenBalance, error) {
+	balances := []TokenBalance{}
+	data := Keccak("balanceOf(address)")[:10] + pad32(address)
+	var errs []error
+	for _, t := range c.registry.Tokens() {
+		out, err := c.CallContract(ctx, t.Address, data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(out) != 32 {
+			continue
+		}
+		if v := new(big.Int).SetBytes(out); v.Sign() > 0 {
+			balances = append(balances, TokenBalance{Token: t.Address, Symbol: t.Symbol, Balance: FormatUnits(v, t.Decimals)})
+		}
+	}
+	return balances, errors.Join(errs...)
+}
+
+// scanTransactions returns the transactions from or to address in blocks
+// from to to, newest first.
+func (c *Client) scanTransactions(ctx context.Context, address string, from, to uint64) ([]TxSummary, error) {
+	blocks := make([][]TxSummary, to-from+1)
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+		sem  = make(chan struct{}, maxConcurrent)
+	)
+	for n := from; n <= to; n++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(n uint64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			b, err := c.BlockByNumber(ctx, n)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				return
+			}
+			var txs []TxSummary
+			for _, tx := range b.Transactions {
+				if strings.EqualFold(tx.From, address) || strings.EqualFold(tx.To, address) {
+					txs = append(txs, summarize(tx, c.registry))
+				}
+			}
+			blocks[to-n] = txs
+		}(n)
+	}
+	wg.Wait()
+
+	out := []TxSummary{}
+	for _, txs := range blocks {
+		out = append(out, txs...)
+	}
+	return out, errors.Join(errs...)
+}
+
+// transfers returns the ERC-20 and ERC-721 transfers from or to address in
+// blocks from to to, newest first.
+func (c *Client) transfers(ctx context.Context, address string, from, to uint64) ([]Transfer, error) {
+	topic := Keccak("Transfer(address,address,uint256)")
+	padded := "0x" + pad32(address)
+	var logs []Log
+	for _, topics := range [][][]string{
+		{{topic}, {padded}},
+		{{topic}, nil, {padded}},
+	} {
+		l, err := c.Logs(ctx, LogFilter{FromBlock: from, ToBlock: to, Topics: topics})
+		if err != nil {
+			return []Transfer{}, err
+		}
+		logs = append(logs, l...)
+	}
+
+	type logID struct {
+		tx    string
+		index Uint64
+	}
+	seen := make(map[logID]bool)
+	out := []Transfer{}
+	for _, l := range logs {
+		id := logID{l.TransactionHash, l.LogIndex}
+		if seen[id] {
+			// A transfer to oneself matches both filters.
+			continue
+		}
+		seen[id] = true
+		e, args, ok := c.registry.DecodeLog(l)
+		if !ok {
+			continue
+		}
+		t, ok := newTransfer(l, e, args, c.registry)
+		if !ok {
+			continue
+		}
+		t.TxHash = l.TransactionHash
+		if l.BlockNumber != nil {
+			t.Block = uint64(*l.BlockNumber)
+		}
+		out = append(out, t)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Block > out[j].Block })
+	if len(out) > maxTransfers {
+		out = out[:maxTransfers]
+	}
+	return out, nil
+}
+
+// pad32 left-pads an address to a 32-byte word, without 0x.
+func pad32(address string) string {
+	a := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	return strings.Repeat("0", 64-len(a)) + a
+}
@@ -0,0 +1,168 @@
+package eth
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const resolver = "0x0000000000000000000000000000000000000e75"
+
+// fakeNode answers the calls of an address lookup of alice, who sent bob
+// 0.5 ETH in block 100 and 2.5 USDC in block 99.
+func fakeNode(t *testing.T, failLogs bool) *httptest.Server {
+	t.Helper()
+	aliceNode := "0x" + hex.EncodeToString(Namehash("alice.eth"))
+	reverseNode := "0x" + hex.EncodeToString(Namehash(strings.TrimPrefix(alice, "0x")+".addr.reverse"))
+	usdcTransfer := map[string]interface{}{
+		"address": usdc, "topics": []string{transferLog, "0x" + pad(alice), "0x" + pad(bob)},
+		"data": "0x" + pad(fmt.Sprintf("%x", 2500000)), "logIndex": "0x3", "blockNumber": "0x63",
+		"transactionHash": "0x" + pad("99"),
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var result interface{}
+		switch req.Method {
+		case "eth_getBalance":
+			result = "0xde0b6b3a7640000"
+		case "eth_getTransactionCount":
+			result = "0x7"
+		case "eth_getCode":
+			result = "0x"
+		case "eth_blockNumber":
+			result = "0x64"
+		case "eth_getBlockByNumber":
+			var n string
+			json.Unmarshal(req.Params[0], &n)
+			to := "0x0000000000000000000000000000000000000c01"
+			if n == "0x64" {
+				to = bob
+			}
+			result = map[string]interface{}{
+				"number": n, "hash": "0x" + pad(n), "parentHash": "0x" + pad("1"), "timestamp": "0x6553f100",
+				"miner": bob, "gasUsed": "0x5208", "gasLimit": "0x1c9c380", "baseFeePerGas": "0x3b9aca00",
+				"transactions": []map[string]interface{}{{
+					"hash": "0x" + pad("a"+n[2:]), "blockNumber": n, "from": alice, "to": to,
+					"value": "0x6f05b59d3b20000", "nonce": "0x6", "gas": "0x5208", "input": "0x",
+				}},
+			}
+		case "eth_getLogs":
+			if failLogs {
+				w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"query returned more than 10000 results"}}`))
+				return
+			}
+			result = []interface{}{usdcTransfer}
+		case "eth_call":
+			var call struct{ To, Data string }
+			json.Unmarshal(req.Params[0], &call)
+			node := "0x" + call.Data[10:]
+			switch {
+			case call.To == ENSRegistry && (node == aliceNode || node == reverseNode):
+				result = "0x" + pad(resolver)
+			case call.To == resolver && call.Data[:10] == Keccak("addr(bytes32)")[:10] && node == aliceNode:
+				result = "0x" + pad(alice)
+			case call.To == resolver && call.Data[:10] == Keccak("name(bytes32)")[:10]:
+				result = "0x" + pad("20") + pad("9") + hex.EncodeToString([]byte("alice.eth")) + strings.Repeat("0", 46)
+			case call.To == usdc:
+				result = "0x" + pad(fmt.Sprintf("%x", 2500000))
+			case call.To == ChainlinkETHUSD:
+				result = "0x" + pad(fmt.Sprintf("%x", 2000*100000000))
+			case call.To == ENSRegistry:
+				result = "0x" + pad("0")
+			default:
+				// No contract at the address.
+				result = "0x"
+			}
+		default:
+			t.Errorf("unexpected call %s", req.Method)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAddress(t *testing.T) {
+	srv := fakeNode(t, false)
+	c := NewClient(Mainnet, []string{srv.URL}, srv.Client())
+
+	d, err := c.Address(context.Background(), "Alice.eth")
+	if err != nil {
+		t.Fatalf("Address: %v", err)
+	}
+	if d.Address != alice || d.Name != "alice.eth" || d.Contract || d.Nonce != 7 || d.ExplorerURL != "https://etherscan.io/address/"+alice {
+		t.Errorf("details = %+v", d)
+	}
+	if d.Balance != "1" || d.BalanceUSDText() != "$2,000.00" {
+		t.Errorf("balance %s (%s)", d.Balance, d.BalanceUSDText())
+	}
+	if len(d.Tokens) != 1 || d.Tokens[0].Symbol != "USDC" || d.Tokens[0].Balance != "2.5" {
+		t.Errorf("tokens = %+v", d.Tokens)
+	}
+	if d.ScannedFrom != 81 || d.ScannedTo != 100 || len(d.Transactions) != 20 || d.Transactions[0].To != bob || d.Transactions[0].Value != "0.5" {
+		t.Errorf("scanned %d-%d: %+v", d.ScannedFrom, d.ScannedTo, d.Transactions)
+	}
+	// The transfer matches both the from and the to filter but is listed once.
+	if len(d.Transfers) != 1 || d.Transfers[0].Block != 99 || d.Transfers[0].Summary() != "2.5 USDC" || d.TransfersFrom != 0 {
+		t.Errorf("transfers from %d = %+v", d.TransfersFrom, d.Transfers)
+	}
+	if len(d.Warnings) != 0 {
+		t.Errorf("warnings = %v", d.Warnings)
+	}
+
+	// By address, the name comes from the reverse record.
+	d, err = c.Address(context.Background(), alice)
+	if err != nil || d.Name != "alice.eth" {
+		t.Errorf("reverse lookup = %+v, %v", d, err)
+	}
+	if _, err := c.Address(context.Background(), "bob.eth"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unregistered name: %v", err)
+	}
+
+	srv = fakeNode(t, true)
+	d, err = NewClient(Mainnet, []string{srv.URL}, srv.Client()).Address(context.Background(), alice)
+	if err != nil || len(d.Warnings) != 1 || d.Warnings[0] != "token transfers" || len(d.Tokens) != 1 {
+		t.Errorf("failed logs = %+v, %v", d, err)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	srv := fakeNode(t, false)
+	c := NewClient(Mainnet, []string{srv.URL}, srv.Client())
+
+	d, err := c.Block(context.Background(), "100")
+	if err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if d.Number != 100 || d.Time.Year() != 2023 || d.BaseFee != "1" || d.BurntFees != "0.000021" || d.ExplorerURL != "https://etherscan.io/block/100" {
+		t.Errorf("block = %+v", d)
+	}
+	if p := d.GasUsedPercent(); p < 0.07 || p > 0.071 {
+		t.Errorf("gas used %.3f%%", p)
+	}
+	if len(d.Transactions) != 1 || d.Transactions[0].Method != "" || d.Transactions[0].Block != 100 {
+		t.Errorf("transactions = %+v", d.Transactions)
+	}
+
+	for _, id := range []string{"latest", "0", "18000000", "0x" + pad("1")} {
+		if !ValidBlockID(id) {
+			t.Errorf("%q rejected", id)
+		}
+	}
+	for _, id := range []string{"", "-1", "0x10", "pending", "1.5"} {
+		if ValidBlockID(id) {
+			t.Errorf("%q accepted", id)
+		}
+	}
+}
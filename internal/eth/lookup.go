@@ -0,0 +1,246 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Transaction statuses.
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusPending = "pending"
+)
+
+const gweiDecimals = 9
+
+// Call is decoded transaction input. Name, Signature and Args are empty when
+// the selector is unknown.
+type Call struct {
+	Selector  string `json:"selector"`
+	Name      string `json:"name,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Args      []Arg  `json:"args,omitempty"`
+}
+
+// Transfer is an ERC-20 or ERC-721 Transfer event.
+type Transfer struct {
+	Standard string `json:"standard"`
+	Token    string `json:"token"`
+	Symbol   string `json:"symbol,omitempty"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	// Amount is an ERC-20 amount, in whole tokens when the token's decimals
+	// are known and in its smallest unit otherwise.
+	Amount  string `json:"amount,omitempty"`
+	TokenID string `json:"token_id,omitempty"`
+	// Block and TxHash are set for transfers found by an address lookup.
+	Block  uint64 `json:"block,omitempty"`
+	TxHash string `json:"tx_hash,omitempty"`
+}
+
+// EventLog is a log of the receipt, decoded when its event is known.
+type EventLog struct {
+	Index   uint64 `json:"index"`
+	Address string `json:"address"`
+	Topic   string `json:"topic,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Args    []Arg  `json:"args,omitempty"`
+}
+
+// TxDetails describes a transaction with its receipt. Receipt fields are
+// zero while the transaction is pending.
+type TxDetails struct {
+	Hash            string `json:"hash"`
+	Network         string `json:"network"`
+	ExplorerURL     string `json:"explorer_url,omitempty"`
+	Status          string `json:"status"`
+	BlockNumber     uint64 `json:"block_number,omitempty"`
+	From            string `json:"from"`
+	To              string `json:"to,omitempty"`
+	ContractAddress string `json:"contract_address,omitempty"`
+	Nonce           uint64 `json:"nonce"`
+
+	// Value and Fee are in ETH.
+	Value    string   `json:"value"`
+	ValueWei string   `json:"value_wei"`
+	ValueUSD *float64 `json:"value_usd"`
+
+	GasLimit uint64 `json:"gas_limit"`
+	GasUsed  uint64 `json:"gas_used,omitempty"`
+	// GasPrice is the effective gas price in gwei once mined, otherwise the
+	// legacy gas price or the max fee per gas.
+	GasPrice string   `json:"gas_price_gwei"`
+	Fee      string   `json:"fee,omitempty"`
+	FeeUSD   *float64 `json:"fee_usd"`
+	// ETHUSD is the price the USD amounts use: the current price, not the
+	// price when the transaction was mined.
+	ETHUSD *float64 `json:"eth_usd"`
+
+	Input     string     `json:"input"`
+	Call      *Call      `json:"call,omitempty"`
+	Transfers []Transfer `json:"transfers"`
+	Logs      []EventLog `json:"logs"`
+}
+
+// ValueUSDText formats ValueUSD, e.g. "$1,234.56"; empty when unknown.
+func (d *TxDetails) ValueUSDText() string {
+	return usdText(d.ValueUSD)
+}
+
+// FeeUSDText formats FeeUSD like ValueUSDText.
+func (d *TxDetails) FeeUSDText() string {
+	return usdText(d.FeeUSD)
+}
+
+func usdText(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return "$" + humanize.FormatFloat("#,###.##", *v)
+}
+
+// Lookup fetches the transaction with hash and its receipt and describes
+// them. A failure to read the ETH/USD price only leaves the USD amounts out.
+func (c *Client) Lookup(ctx context.Context, hash string) (*TxDetails, error) {
+	tx, err := c.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := c.TransactionReceipt(ctx, hash)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	price, err := c.ETHUSD(ctx)
+	if err != nil {
+		price = 0
+	}
+	d := Describe(tx, receipt, price, c.registry)
+	d.Network, d.ExplorerURL = c.network.Name, c.network.TxURL(d.Hash)
+	return d, nil
+}
+
+// Describe combines tx with its receipt, nil while pending, and prices it at
+// ethUSD when that is positive.
+func Describe(tx *Transaction, receipt *Receipt, ethUSD float64, reg *Registry) *TxDetails {
+	d := &TxDetails{
+		Hash:      tx.Hash,
+		Status:    StatusPending,
+		From:      tx.From,
+		To:        tx.To,
+		Nonce:     uint64(tx.Nonce),
+		Value:     FormatUnits(&tx.Value.Int, etherDecimals),
+		ValueWei:  tx.Value.String(),
+		GasLimit:  uint64(tx.Gas),
+		Input:     tx.Input,
+		Transfers: []Transfer{},
+		Logs:      []EventLog{},
+	}
+	if tx.BlockNumber != nil {
+		d.BlockNumber = uint64(*tx.BlockNumber)
+	}
+
+	gasPrice := tx.GasPrice
+	if gasPrice == nil {
+		gasPrice = tx.MaxFeePerGas
+	}
+	var fee *big.Int
+	if receipt != nil {
+		d.Status = StatusFailed
+		if receipt.Status == 1 {
+			d.Status = StatusSuccess
+		}
+		d.BlockNumber = uint64(receipt.BlockNumber)
+		d.GasUsed = uint64(receipt.GasUsed)
+		d.ContractAddress = receipt.ContractAddress
+		if receipt.EffectiveGasPrice != nil {
+			gasPrice = receipt.EffectiveGasPrice
+		}
+		if gasPrice != nil {
+			fee = new(big.Int).Mul(new(big.Int).SetUint64(d.GasUsed), &gasPrice.Int)
+			d.Fee = FormatUnits(fee, etherDecimals)
+		}
+		for _, l := range receipt.Logs {
+			d.addLog(l, reg)
+		}
+	}
+	if gasPrice != nil {
+		d.GasPrice = FormatUnits(&gasPrice.Int, gweiDecimals)
+	}
+
+	if ethUSD > 0 {
+		d.ETHUSD = &ethUSD
+		value := toFloat(&tx.Value.Int, etherDecimals) * ethUSD
+		d.ValueUSD = &value
+		if fee != nil {
+			feeUSD := toFloat(fee, etherDecimals) * ethUSD
+			d.FeeUSD = &feeUSD
+		}
+	}
+
+	if len(strings.TrimPrefix(tx.Input, "0x")) >= 8 {
+		d.Call = &Call{Selector: strings.ToLower(tx.Input[:10])}
+		if f, args, ok, err := reg.DecodeCall(tx.Input); ok && err == nil {
+			d.Call.Name, d.Call.Signature, d.Call.Args = f.Name, f.Signature, args
+		}
+	}
+	return d
+}
+
+// addLog adds a log of the receipt, and a transfer when it is one.
+func (d *TxDetails) addLog(l Log, reg *Registry) {
+	el := EventLog{Index: uint64(l.LogIndex), Address: l.Address}
+	if len(l.Topics) > 0 {
+		el.Topic = l.Topics[0]
+	}
+	e, args, ok := reg.DecodeLog(l)
+	if ok {
+		el.Name, el.Args = e.Name, args
+	}
+	d.Logs = append(d.Logs, el)
+	if ok {
+		if t, ok := newTransfer(l, e, args, reg); ok {
+			d.Transfers = append(d.Transfers, t)
+		}
+	}
+}
+
+// newTransfer returns the transfer of a decoded log, if it is one.
+func newTransfer(l Log, e Event, args []Arg, reg *Registry) (Transfer, bool) {
+	if e.Name != "Transfer" || len(args) != 3 {
+		return Transfer{}, false
+	}
+	t := Transfer{Token: l.Address, From: args[0].Value, To: args[1].Value}
+	token, known := reg.Token(l.Address)
+	if known {
+		t.Symbol = token.Symbol
+	}
+	if e.Inputs[2].Indexed {
+		t.Standard = "ERC-721"
+		t.TokenID = args[2].Value
+	} else {
+		t.Standard = "ERC-20"
+		t.Amount = args[2].Value
+		if amount, ok := new(big.Int).SetString(args[2].Value, 10); ok && known {
+			t.Amount = FormatUnits(amount, token.Decimals)
+		}
+	}
+	return t, true
+}
+
+// Summary is the amount or token ID, e.g. "1.5 USDC" or "BAYC #7".
+func (t Transfer) Summary() string {
+	symbol := t.Symbol
+	if symbol == "" {
+		symbol = t.Token
+	}
+	if t.Standard == "ERC-721" {
+		return fmt.Sprintf("%s #%s", symbol, t.TokenID)
+	}
+	return fmt.Sprintf("%s %s", t.Amount, symbol)
+}
@@ -0,0 +1,83 @@
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestDescribeTokenTransfer(t *testing.T) {
+	var tx Transaction
+	var receipt Receipt
+	input := transferSig + pad(bob) + pad(fmt.Sprintf("%x", 2500000))
+	mustUnmarshal(t, fmt.Sprintf(`{
+		"hash": "0x%s", "type": "0x2", "blockNumber": "0x10", "from": %q, "to": %q,
+		"value": "0x0", "nonce": "0x5", "gas": "0x186a0",
+		"maxFeePerGas": "0x6fc23ac00", "maxPriorityFeePerGas": "0x3b9aca00",
+		"input": %q
+	}`, pad("1"), alice, usdc, input), &tx)
+	mustUnmarshal(t, fmt.Sprintf(`{
+		"status": "0x1", "blockNumber": "0x10", "gasUsed": "0xc350", "cumulativeGasUsed": "0xc350",
+		"effectiveGasPrice": "0x4a817c800",
+		"logs": [
+			{"address": %q, "topics": [%q, "0x%s", "0x%s"], "data": "0x%s", "logIndex": "0x0"},
+			{"address": "0x0000000000000000000000000000000000000c01", "topics": [%q, "0x%s", "0x%s", "0x%s"], "data": "0x", "logIndex": "0x1"},
+			{"address": %q, "topics": ["0x%s"], "data": "0x", "logIndex": "0x2"}
+		]
+	}`, usdc, transferLog, pad(alice), pad(bob), pad(fmt.Sprintf("%x", 2500000)),
+		transferLog, pad(alice), pad(bob), pad("2a"),
+		usdc, pad("1")), &receipt)
+
+	d := Describe(&tx, &receipt, 2000, DefaultRegistry())
+	if d.Status != StatusSuccess || d.BlockNumber != 16 || d.Nonce != 5 || d.GasLimit != 100000 || d.GasUsed != 50000 {
+		t.Errorf("details = %+v", d)
+	}
+	// 50,000 gas at 20 gwei is 0.001 ETH, $2 at $2,000.
+	if d.GasPrice != "20" || d.Fee != "0.001" || d.FeeUSDText() != "$2.00" || d.ValueUSDText() != "$0.00" {
+		t.Errorf("gas price %s, fee %s (%s), value %s", d.GasPrice, d.Fee, d.FeeUSDText(), d.ValueUSDText())
+	}
+	if d.Call == nil || d.Call.Name != "transfer" || d.Call.Selector != transferSig || len(d.Call.Args) != 2 {
+		t.Errorf("call = %+v", d.Call)
+	}
+
+	if len(d.Transfers) != 2 {
+		t.Fatalf("transfers = %+v", d.Transfers)
+	}
+	if tr := d.Transfers[0]; tr.Standard != "ERC-20" || tr.Symbol != "USDC" || tr.Amount != "2.5" || tr.Summary() != "2.5 USDC" || tr.From != alice || tr.To != bob {
+		t.Errorf("ERC-20 transfer = %+v", tr)
+	}
+	if tr := d.Transfers[1]; tr.Standard != "ERC-721" || tr.TokenID != "42" || tr.Summary() != "0x0000000000000000000000000000000000000c01 #42" {
+		t.Errorf("ERC-721 transfer = %+v", tr)
+	}
+	if len(d.Logs) != 3 || d.Logs[0].Name != "Transfer" || d.Logs[2].Name != "" || d.Logs[2].Index != 2 {
+		t.Errorf("logs = %+v", d.Logs)
+	}
+}
+
+func TestDescribePending(t *testing.T) {
+	var tx Transaction
+	mustUnmarshal(t, `{
+		"hash": "0x01", "blockHash": null, "blockNumber": null, "transactionIndex": null,
+		"from": "0x01", "to": null, "value": "0xde0b6b3a7640000", "nonce": "0x0", "gas": "0x5208",
+		"gasPrice": "0x3b9aca00", "input": "0x6080"
+	}`, &tx)
+
+	d := Describe(&tx, nil, 0, DefaultRegistry())
+	if d.Status != StatusPending || d.BlockNumber != 0 || d.GasUsed != 0 || d.Fee != "" {
+		t.Errorf("pending details = %+v", d)
+	}
+	if d.Value != "1" || d.GasPrice != "1" || d.ValueUSD != nil || d.ValueUSDText() != "" {
+		t.Errorf("value %s at %s gwei, %v USD", d.Value, d.GasPrice, d.ValueUSD)
+	}
+	// Contract creation code is too short to have a selector.
+	if d.Call != nil {
+		t.Errorf("call = %+v", d.Call)
+	}
+}
+
+func mustUnmarshal(t *testing.T, s string, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(s), v); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, s)
+	}
+}
@@ -0,0 +1,111 @@
+package eth
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Network is a chain the lookup can query. Every one of them uses ETH for
+// gas, so fees are priced with an ETH/USD feed on the chain itself.
+type Network struct {
+	// Name is the identifier used in URLs and environment variables.
+	Name    string
+	Label   string
+	ChainID uint64
+	// Explorer is the block explorer's base URL, empty for a local node.
+	Explorer string
+	// PriceFeed is the Chainlink ETH/USD feed on this chain, empty when
+	// there is none and USD amounts are left out.
+	PriceFeed string
+	// ENS reports whether ENSRegistry is deployed on the network.
+	ENS bool
+	// alchemy is Alchemy's subdomain for the network, empty when Alchemy
+	// doesn't serve it.
+	alchemy string
+}
+
+// Networks are the chains the lookup knows, mainnet first. Local is a
+// development node such as anvil or hardhat.
+var Networks = []Network{
+	{Name: "mainnet", Label: "Ethereum", ChainID: 1, Explorer: "https://etherscan.io", PriceFeed: ChainlinkETHUSD, ENS: true, alchemy: "eth-mainnet"},
+	{Name: "sepolia", Label: "Sepolia", ChainID: 11155111, Explorer: "https://sepolia.etherscan.io", PriceFeed: "0x694aa1769357215de4fac081bf1f309adc325306", ENS: true, alchemy: "eth-sepolia"},
+	{Name: "base", Label: "Base", ChainID: 8453, Explorer: "https://basescan.org", PriceFeed: "0x71041dddad3595f9ced3dccfbe3d1f4b0a16bb70", alchemy: "base-mainnet"},
+	{Name: "arbitrum", Label: "Arbitrum One", ChainID: 42161, Explorer: "https://arbiscan.io", PriceFeed: "0x639fe6ab55c921f74e7fac1ee960c0b6293ba612", alchemy: "arb-mainnet"},
+	{Name: "local", Label: "Local node", ChainID: 31337},
+}
+
+// Mainnet is the network used when none is asked for.
+var Mainnet = Networks[0]
+
+// NetworkByName returns the network called name.
+func NetworkByName(name string) (Network, bool) {
+	for _, n := range Networks {
+		if n.Name == name {
+			return n, true
+		}
+	}
+	return Network{}, false
+}
+
+// TxURL links to the transaction on the network's explorer, or is empty.
+func (n Network) TxURL(hash string) string {
+	if n.Explorer == "" {
+		return ""
+	}
+	return n.Explorer + "/tx/" + hash
+}
+
+// BlockURL links to block n on the network's explorer, or is empty.
+func (n Network) BlockURL(number uint64) string {
+	if n.Explorer == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/block/%d", n.Explorer, number)
+}
+
+// AddressURL links to address on the network's explorer, or is empty.
+func (n Network) AddressURL(address string) string {
+	if n.Explorer == "" {
+		return ""
+	}
+	return n.Explorer + "/address/" + address
+}
+
+// EndpointsFromEnv returns the JSON-RPC endpoints of n in order of
+// preference: the comma-separated ETH_RPC_URL_<NAME> (ETH_RPC_URL for
+// mainnet), then Alchemy's with the key in ETH_API_KEY_FILE. It returns an
+// error only for a key file that is set but can't be read.
+func EndpointsFromEnv(n Network) ([]string, error) {
+	env := "ETH_RPC_URL_" + strings.ToUpper(n.Name)
+	if n.Name == Mainnet.Name {
+		env = "ETH_RPC_URL"
+	}
+	var endpoints []string
+	for _, u := range strings.Split(os.Getenv(env), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			endpoints = append(endpoints, u)
+		}
+	}
+
+	keyFile := os.Getenv("ETH_API_KEY_FILE")
+	if keyFile == "" || n.alchemy == "" {
+		return endpoints, nil
+	}
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return endpoints, fmt.Errorf("read ETH_API_KEY_FILE: %w", err)
+	}
+	return append(endpoints, fmt.Sprintf("https://%s.g.alchemy.com/v2/%s", n.alchemy, strings.TrimSpace(string(key)))), nil
+}
+
+// redact names an endpoint by its host; providers put the API key in the
+// path or query.
+func redact(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "endpoint"
+	}
+	return u.Host
+}
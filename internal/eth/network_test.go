@@ -0,0 +1,56 @@
+package eth
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEndpointsFromEnv(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte("abc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ETH_RPC_URL", "http://localhost:8545, https://mainnet.infura.io/v3/xyz,")
+	t.Setenv("ETH_RPC_URL_BASE", "")
+	t.Setenv("ETH_RPC_URL_LOCAL", "http://localhost:8545")
+	t.Setenv("ETH_API_KEY_FILE", keyFile)
+
+	base, _ := NetworkByName("base")
+	local, _ := NetworkByName("local")
+	tests := []struct {
+		network Network
+		want    []string
+	}{
+		{Mainnet, []string{"http://localhost:8545", "https://mainnet.infura.io/v3/xyz", "https://eth-mainnet.g.alchemy.com/v2/abc"}},
+		{base, []string{"https://base-mainnet.g.alchemy.com/v2/abc"}},
+		{local, []string{"http://localhost:8545"}},
+	}
+	for _, tt := range tests {
+		got, err := EndpointsFromEnv(tt.network)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, %v; want %v", tt.network.Name, got, err, tt.want)
+		}
+	}
+
+	t.Setenv("ETH_API_KEY_FILE", filepath.Join(t.TempDir(), "missing"))
+	if got, err := EndpointsFromEnv(Mainnet); err == nil || len(got) != 2 {
+		t.Errorf("missing key file: %v, %v", got, err)
+	}
+}
+
+func TestNetworks(t *testing.T) {
+	if n, ok := NetworkByName("arbitrum"); !ok || n.ChainID != 42161 || n.TxURL("0x01") != "https://arbiscan.io/tx/0x01" {
+		t.Errorf("arbitrum = %+v", n)
+	}
+	if n, _ := NetworkByName("local"); n.TxURL("0x01") != "" || n.PriceFeed != "" {
+		t.Errorf("local = %+v", n)
+	}
+	if _, ok := NetworkByName("dogechain"); ok {
+		t.Error("unknown network found")
+	}
+	if got := redact("https://eth-mainnet.g.alchemy.com/v2/abc"); got != "eth-mainnet.g.alchemy.com" {
+		t.Errorf("redact = %s", got)
+	}
+}
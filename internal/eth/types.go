@@ -0,0 +1,165 @@
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	hashPattern    = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+	addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+)
+
+// ValidHash reports whether s is a 0x-prefixed 32-byte hex hash.
+func ValidHash(s string) bool {
+	return hashPattern.MatchString(s)
+}
+
+// ValidAddress reports whether s is a 0x-prefixed 20-byte hex address. The
+// checksum of mixed-case addresses isn't verified.
+func ValidAddress(s string) bool {
+	return addressPattern.MatchString(s)
+}
+
+// Uint64 is a JSON-RPC quantity that fits in 64 bits, such as a block number
+// or an amount of gas.
+type Uint64 uint64
+
+func (u *Uint64) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("quantity must be a hex string: %s", data)
+	}
+	digits, err := quantityDigits(s)
+	if err != nil {
+		return err
+	}
+	v, err := strconv.ParseUint(digits, 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid quantity %q: %w", s, err)
+	}
+	*u = Uint64(v)
+	return nil
+}
+
+// Big is a JSON-RPC quantity of any size, such as a value in wei.
+type Big struct {
+	big.Int
+}
+
+func (b *Big) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("quantity must be a hex string: %s", data)
+	}
+	digits, err := quantityDigits(s)
+	if err != nil {
+		return err
+	}
+	if _, ok := b.SetString(digits, 16); !ok {
+		return fmt.Errorf("invalid quantity %q", s)
+	}
+	return nil
+}
+
+// quantityDigits strips the 0x prefix of a quantity.
+func quantityDigits(s string) (string, error) {
+	digits, ok := strings.CutPrefix(s, "0x")
+	if !ok || digits == "" {
+		return "", fmt.Errorf("invalid quantity %q", s)
+	}
+	return digits, nil
+}
+
+// Transaction is the result of eth_getTransactionByHash. BlockNumber and
+// TransactionIndex are nil while the transaction is pending, and To is empty
+// for contract creations.
+type Transaction struct {
+	Hash                 string  `json:"hash"`
+	Type                 Uint64  `json:"type"`
+	BlockHash            string  `json:"blockHash"`
+	BlockNumber          *Uint64 `json:"blockNumber"`
+	TransactionIndex     *Uint64 `json:"transactionIndex"`
+	From                 string  `json:"from"`
+	To                   string  `json:"to"`
+	Value                Big     `json:"value"`
+	Nonce                Uint64  `json:"nonce"`
+	Gas                  Uint64  `json:"gas"`
+	GasPrice             *Big    `json:"gasPrice"`
+	MaxFeePerGas         *Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *Big    `json:"maxPriorityFeePerGas"`
+	Input                string  `json:"input"`
+}
+
+// Receipt is the result of eth_getTransactionReceipt. Status is 1 for
+// success and 0 for a reverted transaction.
+type Receipt struct {
+	Status            Uint64 `json:"status"`
+	BlockNumber       Uint64 `json:"blockNumber"`
+	GasUsed           Uint64 `json:"gasUsed"`
+	CumulativeGasUsed Uint64 `json:"cumulativeGasUsed"`
+	EffectiveGasPrice *Big   `json:"effectiveGasPrice"`
+	ContractAddress   string `json:"contractAddress"`
+	Logs              []Log  `json:"logs"`
+}
+
+// Log is an event emitted by a contract.
+type Log struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	LogIndex        Uint64   `json:"logIndex"`
+	BlockNumber     *Uint64  `json:"blockNumber"`
+	TransactionHash string   `json:"transactionHash"`
+}
+
+// Block is the result of eth_getBlockByNumber with full transactions.
+// BaseFeePerGas is nil before the London fork.
+type Block struct {
+	Number        Uint64        `json:"number"`
+	Hash          string        `json:"hash"`
+	ParentHash    string        `json:"parentHash"`
+	Timestamp     Uint64        `json:"timestamp"`
+	Miner         string        `json:"miner"`
+	GasUsed       Uint64        `json:"gasUsed"`
+	GasLimit      Uint64        `json:"gasLimit"`
+	BaseFeePerGas *Big          `json:"baseFeePerGas"`
+	Transactions  []Transaction `json:"transactions"`
+}
+
+// etherDecimals is the decimals of ETH: one ether is 10^18 wei.
+const etherDecimals = 18
+
+// FormatUnits formats an integer amount of the smallest unit as a decimal
+// with decimals places, trailing zeros trimmed: FormatUnits(1500000, 6) is
+// "1.5".
+func FormatUnits(v *big.Int, decimals int) string {
+	s := new(big.Int).Abs(v).String()
+	if decimals > 0 {
+		if len(s) <= decimals {
+			s = strings.Repeat("0", decimals-len(s)+1) + s
+		}
+		whole, frac := s[:len(s)-decimals], strings.TrimRight(s[len(s)-decimals:], "0")
+		s = whole
+		if frac != "" {
+			s += "." + frac
+		}
+	}
+	if v.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// toFloat converts an amount of the smallest unit to a float of whole units.
+func toFloat(v *big.Int, decimals int) float64 {
+	f, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(v),
+		new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)),
+	).Float64()
+	return f
+}
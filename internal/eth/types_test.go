@@ -0,0 +1,64 @@
+package eth
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestQuantities(t *testing.T) {
+	var tx struct {
+		Block Uint64 `json:"block"`
+		Value Big    `json:"value"`
+	}
+	// More wei than fits in an int64.
+	if err := json.Unmarshal([]byte(`{"block":"0x116c197","value":"0x1bc16d674ec800000"}`), &tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Block != 18268567 {
+		t.Errorf("block = %d", tx.Block)
+	}
+	if tx.Value.String() != "32000000000000000000" {
+		t.Errorf("value = %s", tx.Value.String())
+	}
+
+	for _, bad := range []string{`"116c197"`, `"0x"`, `"0xzz"`, `12`} {
+		var u Uint64
+		if err := json.Unmarshal([]byte(bad), &u); err == nil {
+			t.Errorf("%s accepted", bad)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		v        string
+		decimals int
+		want     string
+	}{
+		{"1500000", 6, "1.5"},
+		{"1", 18, "0.000000000000000001"},
+		{"567000000000000000", 18, "0.567"},
+		{"0", 18, "0"},
+		{"-2500000", 6, "-2.5"},
+		{"42", 0, "42"},
+		{"18000000000", 9, "18"},
+	}
+	for _, tt := range tests {
+		v, _ := new(big.Int).SetString(tt.v, 10)
+		if got := FormatUnits(v, tt.decimals); got != tt.want {
+			t.Errorf("FormatUnits(%s, %d) = %s, want %s", tt.v, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestValidHash(t *testing.T) {
+	if !ValidHash("0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b") {
+		t.Error("valid hash rejected")
+	}
+	for _, bad := range []string{"", "0x88df", "88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b", "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944g"} {
+		if ValidHash(bad) {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// indexSymbols are the broad index ETFs low-impact releases are tagged with;
+// high and medium impact releases affect every symbol.
+var indexSymbols = []string{"SPY", "QQQ", "IWM", "DIA"}
+
+// Calendar merges the scheduled events with the releases and earnings stored
+// in the database.
+type Calendar struct {
+	db   *pgxpool.Pool
+	repo *repository.Queries
+}
+
+func NewCalendar(db *pgxpool.Pool) *Calendar {
+	return &Calendar{
+		db:   db,
+		repo: repository.New(db),
+	}
+}
+
+// Between returns every event from from through to, in order.
+func (c *Calendar) Between(ctx context.Context, from, to time.Time) ([]Event, error) {
+	start := pgtype.Date{Time: from, Valid: true}
+	end := pgtype.Date{Time: to, Valid: true}
+
+	releases, err := c.repo.GetUpcomingReleases(ctx, repository.GetUpcomingReleasesParams{
+		ReleaseDate:   start,
+		ReleaseDate_2: end,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list economic releases: %w", err)
+	}
+	earnings, err := c.repo.ListEarningsEvents(ctx, repository.ListEarningsEventsParams{
+		ReportDate:   start,
+		ReportDate_2: end,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list earnings: %w", err)
+	}
+	return merge(Scheduled(from, to), releases, earnings), nil
+}
+
+// Before returns the events affecting symbol from today through expiry.
+func (c *Calendar) Before(ctx context.Context, symbol string, expiry time.Time) ([]Event, error) {
+	events, err := c.Between(ctx, time.Now().In(NewYork), expiry)
+	if err != nil {
+		return nil, err
+	}
+	return Filter(events, symbol, nil), nil
+}
+
+func merge(scheduled []Event, releases []repository.EconomicRelease, earnings []repository.EarningsEvent) []Event {
+	events := make([]Event, 0, len(scheduled)+len(releases)+len(earnings))
+	events = append(events, scheduled...)
+	for _, r := range releases {
+		events = append(events, releaseEvent(r))
+	}
+	for _, r := range earnings {
+		events = append(events, earningsEvent(r))
+	}
+	Sort(events)
+	return events
+}
+
+func releaseEvent(r repository.EconomicRelease) Event {
+	e := Event{
+		Kind:   KindEconomic,
+		Title:  r.ReleaseName,
+		Date:   day(r.ReleaseDate.Time.Date()),
+		Impact: r.Impact,
+	}
+	if r.ReleaseTime.Valid {
+		e.Time = time.Duration(r.ReleaseTime.Microseconds) * time.Microsecond
+	}
+	if r.Impact == ImpactLow {
+		e.Symbols = indexSymbols
+	} else {
+		e.MarketWide = true
+	}
+	return e
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestMerge(t *testing.T) {
+	date := func(s string) pgtype.Date {
+		d, _ := time.Parse("2006-01-02", s)
+		return pgtype.Date{Time: d, Valid: true}
+	}
+	releases := []repository.EconomicRelease{
+		{ReleaseName: "Consumer Price Index", ReleaseDate: date("2026-10-14"), Impact: "High",
+			ReleaseTime: pgtype.Time{Microseconds: int64(8*time.Hour+30*time.Minute) / 1e3, Valid: true}},
+		{ReleaseName: "State Employment and Unemployment", ReleaseDate: date("2026-10-16"), Impact: "Low"},
+	}
+	earnings := []repository.EarningsEvent{
+		{Symbol: "AAPL", ReportDate: date("2026-10-29"), Session: SessionAfterClose, FiscalPeriod: "Q4 2026",
+			EpsEstimate: pgtype.Float8{Float64: 1.78, Valid: true}},
+	}
+	events := merge(Scheduled(day(2026, time.October, 1), day(2026, time.October, 31)), releases, earnings)
+
+	var titles []string
+	for _, e := range events {
+		titles = append(titles, e.Title)
+	}
+	want := []string{
+		"Consumer Price Index",
+		"State Employment and Unemployment",
+		"Monthly OPEX",
+		"VIX Oct expiration",
+		"FOMC rate decision",
+		"AAPL earnings (Q4 2026), after the close, EPS est. 1.78",
+	}
+	if strings.Join(titles, "|") != strings.Join(want, "|") {
+		t.Fatalf("titles = %q\nwant %q", titles, want)
+	}
+
+	// AAPL sees the macro events and its own earnings, but not the regional
+	// release or the VIX expiration.
+	var aapl []string
+	for _, e := range Filter(events, "AAPL", nil) {
+		aapl = append(aapl, string(e.Kind))
+	}
+	if got := strings.Join(aapl, ","); got != "economic,opex,fomc,earnings" {
+		t.Errorf("AAPL events = %s", got)
+	}
+	if n := len(Filter(events, "SPY", []Kind{KindEconomic})); n != 2 {
+		t.Errorf("SPY economic events = %d, want 2", n)
+	}
+
+	raw, err := json.Marshal(events[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(raw); !strings.Contains(s, `"time":"08:30"`) || !strings.Contains(s, `"at":"2026-10-14T08:30:00-04:00"`) {
+		t.Errorf("JSON = %s", s)
+	}
+}
@@ -0,0 +1,8 @@
+// Package events builds the market event calendar: FRED economic releases,
+// FOMC decisions, monthly and quarterly option expirations, VIX expirations
+// and imported earnings dates, each tagged with the symbols it affects.
+//
+// Expirations are computed from the exchange rules and the NYSE holiday
+// calendar, FOMC dates come from the Fed's published schedule, and releases
+// and earnings are read from the database.
+package events
@@ -0,0 +1,218 @@
+package events
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Earnings sessions.
+const (
+	SessionBeforeOpen = "bmo"
+	SessionAfterClose = "amc"
+)
+
+// Earnings is a scheduled earnings report.
+type Earnings struct {
+	Symbol string
+	Date   time.Time
+	// Session is SessionBeforeOpen, SessionAfterClose or empty when unknown.
+	Session      string
+	FiscalPeriod string
+	// EPSEstimate is nil when there is no estimate.
+	EPSEstimate *float64
+	Source      string
+}
+
+var sessionAliases = map[string]string{
+	"bmo":               SessionBeforeOpen,
+	"before open":       SessionBeforeOpen,
+	"pre-market":        SessionBeforeOpen,
+	"premarket":         SessionBeforeOpen,
+	"amc":               SessionAfterClose,
+	"after close":       SessionAfterClose,
+	"post-market":       SessionAfterClose,
+	"postmarket":        SessionAfterClose,
+	"":                  "",
+	"unknown":           "",
+	"time not supplied": "",
+}
+
+// ParseEarningsCSV reads earnings dates from a CSV with a header row. The
+// symbol (or ticker) and date (or report_date) columns are required; session
+// (or time: bmo, amc, "before open", "after close"), fiscal_period,
+// eps_estimate and source are optional. Later rows for the same symbol and
+// date replace earlier ones.
+func ParseEarningsCSV(r io.Reader) ([]Earnings, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, field := range header {
+		name := strings.ToLower(strings.TrimSpace(field))
+		switch name {
+		case "ticker":
+			name = "symbol"
+		case "report_date":
+			name = "date"
+		case "time":
+			name = "session"
+		case "eps", "estimate":
+			name = "eps_estimate"
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"symbol", "date"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv has no %s column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	index := make(map[string]int)
+	var rows []Earnings
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		e := Earnings{
+			Symbol:       strings.ToUpper(field(record, "symbol")),
+			FiscalPeriod: field(record, "fiscal_period"),
+			Source:       field(record, "source"),
+		}
+		if e.Symbol == "" {
+			continue
+		}
+		if !universe.ValidSymbol(e.Symbol) {
+			return nil, fmt.Errorf("line %d: invalid symbol %q", line, e.Symbol)
+		}
+		if e.Date, err = time.ParseInLocation("2006-01-02", field(record, "date"), NewYork); err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, field(record, "date"))
+		}
+		session, ok := sessionAliases[strings.ToLower(field(record, "session"))]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown session %q", line, field(record, "session"))
+		}
+		e.Session = session
+		if raw := field(record, "eps_estimate"); raw != "" {
+			v, err := strconv.ParseFloat(strings.TrimPrefix(raw, "$"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid eps estimate %q", line, raw)
+			}
+			e.EPSEstimate = &v
+		}
+
+		key := e.Symbol + " " + e.Date.Format("2006-01-02")
+		if i, ok := index[key]; ok {
+			rows[i] = e
+			continue
+		}
+		index[key] = len(rows)
+		rows = append(rows, e)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no earnings found in csv")
+	}
+	return rows, nil
+}
+
+// ImportEarnings upserts rows and returns the number written. A row with a
+// fiscal period replaces any other date stored for that symbol and period,
+// so a rescheduled report doesn't show up twice.
+func (c *Calendar) ImportEarnings(ctx context.Context, rows []Earnings) (int, error) {
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no earnings to import")
+	}
+
+	tx, err := c.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin import: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	q := c.repo.WithTx(tx)
+
+	for _, e := range rows {
+		date := pgtype.Date{Time: e.Date, Valid: true}
+		if e.FiscalPeriod != "" {
+			err := q.DeleteRescheduledEarnings(ctx, repository.DeleteRescheduledEarningsParams{
+				Symbol:       e.Symbol,
+				FiscalPeriod: e.FiscalPeriod,
+				ReportDate:   date,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("clear rescheduled %s: %w", e.Symbol, err)
+			}
+		}
+		params := repository.UpsertEarningsEventParams{
+			Symbol:       e.Symbol,
+			ReportDate:   date,
+			Session:      e.Session,
+			FiscalPeriod: e.FiscalPeriod,
+			Source:       e.Source,
+		}
+		if e.EPSEstimate != nil {
+			params.EpsEstimate = pgtype.Float8{Float64: *e.EPSEstimate, Valid: true}
+		}
+		if err := q.UpsertEarningsEvent(ctx, params); err != nil {
+			return 0, fmt.Errorf("upsert %s: %w", e.Symbol, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit import: %w", err)
+	}
+	return len(rows), nil
+}
+
+// earningsEvent returns the calendar entry of a stored report. Companies
+// don't publish an exact time, so the session only goes into the title.
+func earningsEvent(r repository.EarningsEvent) Event {
+	e := Event{
+		Kind:    KindEarnings,
+		Title:   r.Symbol + " earnings",
+		Date:    day(r.ReportDate.Time.Date()),
+		Impact:  ImpactHigh,
+		Symbols: []string{r.Symbol},
+	}
+	if r.FiscalPeriod != "" {
+		e.Title += " (" + r.FiscalPeriod + ")"
+	}
+	switch r.Session {
+	case SessionBeforeOpen:
+		e.Title += ", before the open"
+	case SessionAfterClose:
+		e.Title += ", after the close"
+	}
+	if r.EpsEstimate.Valid {
+		e.Title += fmt.Sprintf(", EPS est. %.2f", r.EpsEstimate.Float64)
+	}
+	return e
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseEarningsCSV(t *testing.T) {
+	rows, err := ParseEarningsCSV(strings.NewReader(`Ticker,Report_Date,Time,Fiscal_Period,EPS
+aapl,2026-10-29,After Close,Q4 2026,$1.78
+NVDA,2026-11-18,amc,,
+MSFT,2026-10-28,,Q1 2027,3.10
+AAPL,2026-10-29,amc,Q4 2026,1.80
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	// The second AAPL row replaces the first.
+	if a := rows[0]; a.Symbol != "AAPL" || a.Session != SessionAfterClose || a.FiscalPeriod != "Q4 2026" || *a.EPSEstimate != 1.80 {
+		t.Errorf("AAPL = %+v", a)
+	}
+	if n := rows[1]; n.EPSEstimate != nil || n.Date.Format("2006-01-02") != "2026-11-18" {
+		t.Errorf("NVDA = %+v", n)
+	}
+	if m := rows[2]; m.Session != "" {
+		t.Errorf("MSFT session = %q", m.Session)
+	}
+
+	for _, bad := range []string{
+		"date\n2026-10-29\n",
+		"symbol,date\nAAPL,10/29/2026\n",
+		"symbol,date,session\nAAPL,2026-10-29,lunch\n",
+		"symbol,date,eps\nAAPL,2026-10-29,n/a\n",
+		"symbol,date\nNOT A SYMBOL,2026-10-29\n",
+		"symbol,date\n",
+	} {
+		if _, err := ParseEarningsCSV(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseEarningsCSV(%q) succeeded", bad)
+		}
+	}
+}
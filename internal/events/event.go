@@ -0,0 +1,161 @@
+package events
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind is the source of an event.
+type Kind string
+
+const (
+	KindEconomic Kind = "economic"
+	KindFOMC     Kind = "fomc"
+	KindOPEX     Kind = "opex"
+	KindVIX      Kind = "vix_expiration"
+	KindEarnings Kind = "earnings"
+)
+
+// Kinds lists every kind in display order.
+var Kinds = []Kind{KindFOMC, KindEconomic, KindOPEX, KindVIX, KindEarnings}
+
+// Impact levels, as used by the economic calendar.
+const (
+	ImpactHigh   = "High"
+	ImpactMedium = "Medium"
+	ImpactLow    = "Low"
+)
+
+// Event is one entry of the calendar.
+type Event struct {
+	Kind  Kind
+	Title string
+	// Date is the day of the event, at midnight New York time.
+	Date time.Time
+	// Time is when the event happens after midnight New York time; zero when
+	// it has no fixed time.
+	Time   time.Duration
+	Impact string
+	// Symbols are the symbols the event affects. MarketWide events affect
+	// every symbol.
+	Symbols    []string
+	MarketWide bool
+}
+
+// At returns the time of the event, or its date when it has no fixed time.
+func (e Event) At() time.Time {
+	return e.Date.Add(e.Time)
+}
+
+// Affects reports whether the event matters to symbol.
+func (e Event) Affects(symbol string) bool {
+	if e.MarketWide {
+		return true
+	}
+	symbol = strings.ToUpper(symbol)
+	for _, s := range e.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Kind       Kind     `json:"kind"`
+		Title      string   `json:"title"`
+		Date       string   `json:"date"`
+		Time       string   `json:"time,omitempty"`
+		At         string   `json:"at"`
+		Impact     string   `json:"impact"`
+		Symbols    []string `json:"symbols"`
+		MarketWide bool     `json:"market_wide"`
+	}{
+		Kind:       e.Kind,
+		Title:      e.Title,
+		Date:       e.Date.Format("2006-01-02"),
+		At:         e.At().Format(time.RFC3339),
+		Impact:     e.Impact,
+		Symbols:    e.Symbols,
+		MarketWide: e.MarketWide,
+	}
+	if e.Time > 0 {
+		v.Time = e.At().Format("15:04")
+	}
+	if v.Symbols == nil {
+		v.Symbols = []string{}
+	}
+	return json.Marshal(v)
+}
+
+// Sort orders events by time, then by kind in Kinds order.
+func Sort(events []Event) {
+	rank := make(map[Kind]int, len(Kinds))
+	for i, k := range Kinds {
+		rank[k] = i
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if a, b := events[i].At(), events[j].At(); !a.Equal(b) {
+			return a.Before(b)
+		}
+		return rank[events[i].Kind] < rank[events[j].Kind]
+	})
+}
+
+// Filter returns the events affecting symbol whose kind is in kinds. An empty
+// symbol or kinds matches everything.
+func Filter(events []Event, symbol string, kinds []Kind) []Event {
+	filtered := make([]Event, 0, len(events))
+	for _, e := range events {
+		if symbol != "" && !e.Affects(symbol) {
+			continue
+		}
+		if len(kinds) > 0 && !containsKind(kinds, e.Kind) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+func containsKind(kinds []Kind, kind Kind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Clock returns the event's time as "15:04 ET", or "" when it has none.
+func (e Event) Clock() string {
+	if e.Time == 0 {
+		return ""
+	}
+	return e.At().Format("15:04") + " ET"
+}
+
+// Valid reports whether k is one of Kinds.
+func (k Kind) Valid() bool {
+	return containsKind(Kinds, k)
+}
+
+// Label is the display name of the kind.
+func (k Kind) Label() string {
+	switch k {
+	case KindEconomic:
+		return "Economic"
+	case KindFOMC:
+		return "FOMC"
+	case KindOPEX:
+		return "OPEX"
+	case KindVIX:
+		return "VIX"
+	case KindEarnings:
+		return "Earnings"
+	}
+	return string(k)
+}
@@ -0,0 +1,42 @@
+package events
+
+import "time"
+
+// fomcMeetings are the scheduled FOMC meetings from federalreserve.gov
+// (monetarypolicy/fomccalendars.htm), as the date of each meeting's second
+// day. Add the next year's dates when the Fed publishes them.
+var fomcMeetings = []string{
+	"2025-01-29", "2025-03-19", "2025-05-07", "2025-06-18",
+	"2025-07-30", "2025-09-17", "2025-10-29", "2025-12-10",
+	"2026-01-28", "2026-03-18", "2026-04-29", "2026-06-17",
+	"2026-07-29", "2026-09-16", "2026-10-28", "2026-12-09",
+}
+
+// fomcDecisionTime is when the statement is released, after midnight ET.
+const fomcDecisionTime = 14 * time.Hour
+
+// fomcDecisions returns an event for the rate decision of each scheduled
+// meeting. Meetings in the last month of a quarter also publish the Summary
+// of Economic Projections.
+func fomcDecisions() []Event {
+	events := make([]Event, 0, len(fomcMeetings))
+	for _, s := range fomcMeetings {
+		d, err := time.ParseInLocation("2006-01-02", s, NewYork)
+		if err != nil {
+			panic("events: bad FOMC date " + s)
+		}
+		title := "FOMC rate decision"
+		if d.Month()%3 == 0 {
+			title = "FOMC rate decision and projections"
+		}
+		events = append(events, Event{
+			Kind:       KindFOMC,
+			Title:      title,
+			Date:       d,
+			Time:       fomcDecisionTime,
+			Impact:     ImpactHigh,
+			MarketWide: true,
+		})
+	}
+	return events
+}
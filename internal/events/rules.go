@@ -0,0 +1,176 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// NewYork is the time zone of every event date; UTC when tzdata is missing.
+var NewYork = loadNewYork()
+
+func loadNewYork() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// vixSymbols are the products settled on, or tracking, VIX futures.
+var vixSymbols = []string{"VIX", "VXX", "UVXY", "SVXY", "VIXY"}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, NewYork)
+}
+
+// nthWeekday returns the nth weekday of month, counting from the end when n
+// is negative.
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	if n < 0 {
+		d := day(year, month+1, 0)
+		for d.Weekday() != weekday {
+			d = d.AddDate(0, 0, -1)
+		}
+		return d.AddDate(0, 0, 7*(n+1))
+	}
+	d := day(year, month, 1)
+	for d.Weekday() != weekday {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d.AddDate(0, 0, 7*(n-1))
+}
+
+// easter returns Easter Sunday of year (anonymous Gregorian algorithm).
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	return day(year, time.Month(month), (h+l-7*m+114)%31+1)
+}
+
+// observed moves a fixed-date holiday falling on a weekend to the Friday
+// before or the Monday after.
+func observed(d time.Time) time.Time {
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case time.Sunday:
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// MarketHolidays returns the NYSE full-day holidays of year.
+func MarketHolidays(year int) []time.Time {
+	holidays := []time.Time{
+		nthWeekday(year, time.January, time.Monday, 3),    // Martin Luther King Jr. Day
+		nthWeekday(year, time.February, time.Monday, 3),   // Washington's Birthday
+		easter(year).AddDate(0, 0, -2),                    // Good Friday
+		nthWeekday(year, time.May, time.Monday, -1),       // Memorial Day
+		observed(day(year, time.July, 4)),                 // Independence Day
+		nthWeekday(year, time.September, time.Monday, 1),  // Labor Day
+		nthWeekday(year, time.November, time.Thursday, 4), // Thanksgiving
+		observed(day(year, time.December, 25)),            // Christmas
+	}
+	// New Year's Day on a Saturday isn't made up on the Friday before.
+	if newYear := day(year, time.January, 1); newYear.Weekday() != time.Saturday {
+		holidays = append(holidays, observed(newYear))
+	}
+	if year >= 2022 {
+		holidays = append(holidays, observed(day(year, time.June, 19))) // Juneteenth
+	}
+	return holidays
+}
+
+// IsTradingDay reports whether the NYSE is open on d's date.
+func IsTradingDay(d time.Time) bool {
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	y, m, dd := d.Date()
+	for _, h := range MarketHolidays(y) {
+		if hy, hm, hd := h.Date(); hy == y && hm == m && hd == dd {
+			return false
+		}
+	}
+	return true
+}
+
+// previousTradingDay returns the last trading day before d.
+func previousTradingDay(d time.Time) time.Time {
+	d = d.AddDate(0, 0, -1)
+	for !IsTradingDay(d) {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+// MonthlyExpiration returns the standard equity and index option expiration
+// of month: its third Friday, or the trading day before when that Friday is a
+// holiday.
+func MonthlyExpiration(year int, month time.Month) time.Time {
+	d := nthWeekday(year, month, time.Friday, 3)
+	if !IsTradingDay(d) {
+		d = previousTradingDay(d)
+	}
+	return d
+}
+
+// VIXExpiration returns the expiration of month's VIX futures and options:
+// the Wednesday 30 days before the third Friday of the following month, both
+// moved to the trading day before when they fall on a holiday.
+func VIXExpiration(year int, month time.Month) time.Time {
+	friday := nthWeekday(year, month+1, time.Friday, 3)
+	if !IsTradingDay(friday) {
+		friday = previousTradingDay(friday)
+	}
+	d := friday.AddDate(0, 0, -30)
+	if !IsTradingDay(d) {
+		d = previousTradingDay(d)
+	}
+	return d
+}
+
+// Scheduled returns the FOMC decisions, option expirations and VIX
+// expirations from from through to, by date.
+func Scheduled(from, to time.Time) []Event {
+	from = day(from.In(NewYork).Date())
+	to = day(to.In(NewYork).Date())
+	within := func(d time.Time) bool { return !d.Before(from) && !d.After(to) }
+
+	var events []Event
+	for m := day(from.Year(), from.Month(), 1); !m.After(to); m = m.AddDate(0, 1, 0) {
+		if d := MonthlyExpiration(m.Year(), m.Month()); within(d) {
+			e := Event{Kind: KindOPEX, Title: "Monthly OPEX", Date: d, Impact: ImpactMedium, MarketWide: true}
+			if m.Month()%3 == 0 {
+				e.Title = "Quarterly OPEX (triple witching)"
+				e.Impact = ImpactHigh
+			}
+			events = append(events, e)
+		}
+		if d := VIXExpiration(m.Year(), m.Month()); within(d) {
+			events = append(events, Event{
+				Kind:    KindVIX,
+				Title:   fmt.Sprintf("VIX %s expiration", m.Format("Jan")),
+				Date:    d,
+				Impact:  ImpactMedium,
+				Symbols: vixSymbols,
+			})
+		}
+	}
+	for _, e := range fomcDecisions() {
+		if within(e.Date) {
+			events = append(events, e)
+		}
+	}
+
+	Sort(events)
+	return events
+}
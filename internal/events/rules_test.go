@@ -0,0 +1,83 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarketHolidays(t *testing.T) {
+	var got []string
+	for _, h := range MarketHolidays(2026) {
+		got = append(got, h.Format("2006-01-02"))
+	}
+	want := map[string]bool{
+		"2026-01-01": true, "2026-01-19": true, "2026-02-16": true, "2026-04-03": true,
+		"2026-05-25": true, "2026-06-19": true, "2026-07-03": true, "2026-09-07": true,
+		"2026-11-26": true, "2026-12-25": true,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("holidays = %v", got)
+	}
+	for _, d := range got {
+		if !want[d] {
+			t.Errorf("unexpected holiday %s", d)
+		}
+	}
+
+	// New Year's Day 2022 fell on a Saturday and wasn't observed.
+	if !IsTradingDay(day(2021, time.December, 31)) {
+		t.Error("2021-12-31 should be a trading day")
+	}
+}
+
+func TestExpirations(t *testing.T) {
+	cases := []struct {
+		name string
+		got  time.Time
+		want string
+	}{
+		{"October 2026 OPEX", MonthlyExpiration(2026, time.October), "2026-10-16"},
+		// Juneteenth and Good Friday move the expiration to Thursday.
+		{"June 2026 OPEX", MonthlyExpiration(2026, time.June), "2026-06-18"},
+		{"April 2025 OPEX", MonthlyExpiration(2025, time.April), "2025-04-17"},
+		{"October 2026 VIX", VIXExpiration(2026, time.October), "2026-10-21"},
+		// 30 days before Thursday 2025-04-17, as April's third Friday was Good Friday.
+		{"March 2025 VIX", VIXExpiration(2025, time.March), "2025-03-18"},
+		{"December 2026 VIX", VIXExpiration(2026, time.December), "2026-12-16"},
+	}
+	for _, c := range cases {
+		if got := c.got.Format("2006-01-02"); got != c.want {
+			t.Errorf("%s = %s, want %s", c.name, got, c.want)
+		}
+	}
+}
+
+func TestScheduled(t *testing.T) {
+	events := Scheduled(day(2026, time.October, 1), day(2026, time.October, 31))
+
+	var got []string
+	for _, e := range events {
+		got = append(got, e.Date.Format("01-02")+" "+string(e.Kind))
+	}
+	want := []string{"10-16 opex", "10-21 vix_expiration", "10-28 fomc"}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if fomc := events[2]; fomc.Time != 14*time.Hour || !fomc.Affects("AAPL") {
+		t.Errorf("FOMC event = %+v", fomc)
+	}
+	if vix := events[1]; vix.Affects("SPY") || !vix.Affects("uvxy") {
+		t.Errorf("VIX expiration symbols = %v", vix.Symbols)
+	}
+
+	// September's expiration is the quarterly one.
+	sep := Scheduled(day(2026, time.September, 18), day(2026, time.September, 18))
+	if len(sep) != 1 || sep[0].Impact != ImpactHigh {
+		t.Errorf("September OPEX = %+v", sep)
+	}
+}
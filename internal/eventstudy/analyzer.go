@@ -0,0 +1,144 @@
+package eventstudy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/macro"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/arnabmitra/eth-proxy/internal/zscore"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Query selects the releases to study and the symbol to study them on.
+type Query struct {
+	Symbol string
+	// Impact defaults to High.
+	Impact string
+	// ReleaseID limits the study to one release type; zero studies all.
+	ReleaseID int32
+	From      time.Time
+	// Window defaults to DefaultWindow.
+	Window int
+}
+
+// Analyzer runs event studies on the stored releases and GEX history.
+type Analyzer struct {
+	repo   *repository.Queries
+	macro  *macro.Store
+	scorer *zscore.Scorer
+	zscore zscore.Config
+	loc    *time.Location
+}
+
+// NewAnalyzer returns an Analyzer that scores GEX with cfg.
+func NewAnalyzer(db *pgxpool.Pool, cfg zscore.Config) *Analyzer {
+	repo := repository.New(db)
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.UTC
+	}
+	return &Analyzer{
+		repo:   repo,
+		macro:  macro.NewStore(db),
+		scorer: zscore.NewScorer(repo),
+		zscore: cfg,
+		loc:    loc,
+	}
+}
+
+// Run studies every release of q.Impact since q.From that the symbol's GEX
+// history covers.
+func (a *Analyzer) Run(ctx context.Context, q Query) (*Report, error) {
+	if q.Impact == "" {
+		q.Impact = "High"
+	}
+	if q.Window <= 0 {
+		q.Window = DefaultWindow
+	}
+
+	releases, err := a.repo.ListReleasesBetween(ctx, repository.ListReleasesBetweenParams{
+		Impact:   q.Impact,
+		FromDate: pgtype.Date{Time: q.From, Valid: true},
+		ToDate:   pgtype.Date{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list releases: %w", err)
+	}
+	// Closes start early enough to fill the window of the first release.
+	closes, err := a.closes(ctx, q.Symbol, q.From.AddDate(0, 0, -2*q.Window-maxGapDays))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Symbol:          q.Symbol,
+		VolatilityIndex: VolatilityIndexes[q.Symbol],
+		Impact:          q.Impact,
+		From:            q.From.Format(time.DateOnly),
+		Window:          q.Window,
+		Events:          []Event{},
+	}
+	var vol []macro.Point
+	if report.VolatilityIndex != "" {
+		vol, err = a.macro.History(ctx, report.VolatilityIndex, q.From.AddDate(0, 0, -maxGapDays-1))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range releases {
+		if q.ReleaseID != 0 && r.ReleaseID != q.ReleaseID {
+			continue
+		}
+		if e, ok := NewEvent(r, closes, vol, q.Window); ok {
+			report.Events = append(report.Events, e)
+		}
+	}
+	report.Summaries = Summarize(report.Events, q.Window)
+	sort.SliceStable(report.Events, func(i, j int) bool {
+		return report.Events[i].ReleaseDate > report.Events[j].ReleaseDate
+	})
+	return report, nil
+}
+
+// closes returns the daily closes of symbol since from with the Z-score of
+// each close's day.
+func (a *Analyzer) closes(ctx context.Context, symbol string, from time.Time) ([]Close, error) {
+	rows, err := a.repo.ListDailyGEXCloses(ctx, repository.ListDailyGEXClosesParams{
+		Symbol:   symbol,
+		FromTime: from,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list daily closes for %s: %w", symbol, err)
+	}
+	points, err := a.scorer.History(ctx, a.zscore, symbol, from)
+	if err != nil {
+		return nil, err
+	}
+	// The last score of each day is the score of its close.
+	scores := make(map[string]float64, len(rows))
+	for _, p := range points {
+		scores[p.Time.In(a.loc).Format(time.DateOnly)] = p.Score
+	}
+
+	closes := make([]Close, 0, len(rows))
+	for _, row := range rows {
+		c := Close{Date: row.RecordedAt.In(a.loc)}
+		if f, err := row.GexValue.Float64Value(); err == nil {
+			c.GEX = f.Float64
+		}
+		if row.SpotPrice.Valid {
+			c.Spot, _ = strconv.ParseFloat(row.SpotPrice.String, 64)
+		}
+		if z, ok := scores[c.Date.Format(time.DateOnly)]; ok {
+			c.ZScore = &z
+		}
+		closes = append(closes, c)
+	}
+	return closes, nil
+}
@@ -0,0 +1,56 @@
+package eventstudy
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+var csvHeader = []string{
+	"symbol", "release_id", "release_name", "release_date", "release_time", "surprise",
+	"move_pct", "implied_move_pct", "move_ratio",
+	"offset", "date", "spot", "gex", "zscore",
+}
+
+// WriteCSV writes the events of report with one row per day of each event's
+// window; the event's columns repeat on each of its days.
+func WriteCSV(w io.Writer, report *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range report.Events {
+		event := []string{
+			report.Symbol,
+			strconv.Itoa(int(e.ReleaseID)),
+			e.ReleaseName,
+			e.ReleaseDate,
+			e.ReleaseTime,
+			formatFloat(e.Surprise),
+			formatFloat(e.Move),
+			formatFloat(e.ImpliedMove),
+			formatFloat(e.MoveRatio),
+		}
+		for _, d := range e.Days {
+			row := append(event[:len(event):len(event)],
+				strconv.Itoa(d.Offset),
+				d.Date,
+				strconv.FormatFloat(d.Spot, 'f', -1, 64),
+				strconv.FormatFloat(d.GEX, 'f', -1, 64),
+				formatFloat(d.ZScore),
+			)
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 4, 64)
+}
@@ -0,0 +1,10 @@
+// Package eventstudy measures how an index's GEX behaves around economic
+// releases. Each past release of an impact becomes an event: the symbol's
+// daily GEX and Z-score for a few trading days either side, the close-to-close
+// move into the release day and the move its volatility index priced in.
+// Events are summarized per release type.
+//
+// Releases come from economic_releases, GEX and spot from the daily closes of
+// gex_history, Z-scores from internal/zscore and volatility index closes from
+// the macro observations.
+package eventstudy
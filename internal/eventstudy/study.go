@@ -0,0 +1,293 @@
+package eventstudy
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/macro"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+)
+
+// Window bounds, in trading days either side of a release.
+const (
+	DefaultWindow = 3
+	MaxWindow     = 10
+)
+
+const (
+	// tradingDays annualizes volatility index closes into daily moves.
+	tradingDays = 252
+	// maxGapDays is how far the closes around a release may be from its date,
+	// enough for a long weekend. Releases further from the stored closes fell
+	// in a gap of the GEX history and are left out.
+	maxGapDays = 4
+)
+
+// VolatilityIndexes maps index symbols to the FRED series of the volatility
+// index that prices their options.
+var VolatilityIndexes = map[string]string{
+	"SPY": macro.SeriesVIX,
+	"SPX": macro.SeriesVIX,
+	"QQQ": "VXNCLS",
+	"NDX": "VXNCLS",
+	"IWM": "RVXCLS",
+}
+
+// Close is a symbol's last snapshot of a New York trading day. Date is in
+// New York time.
+type Close struct {
+	Date   time.Time
+	GEX    float64
+	Spot   float64
+	ZScore *float64
+}
+
+// Day is one trading day of an event's window. Offset 0 is the release day.
+type Day struct {
+	Offset int      `json:"offset"`
+	Date   string   `json:"date"`
+	Spot   float64  `json:"spot"`
+	GEX    float64  `json:"gex"`
+	ZScore *float64 `json:"zscore"`
+}
+
+// Event is one release and the symbol around it.
+type Event struct {
+	ReleaseID   int32  `json:"release_id"`
+	ReleaseName string `json:"release_name"`
+	ReleaseDate string `json:"release_date"`
+	// ReleaseTime is "15:04" Eastern, empty when the release has no fixed time.
+	ReleaseTime string `json:"release_time,omitempty"`
+	// Surprise is the actual minus the consensus, once both are known.
+	Surprise *float64 `json:"surprise"`
+	Days     []Day    `json:"days"`
+
+	// Move is the change of the spot price from the close before the release
+	// to the close of the release day, in percent.
+	Move *float64 `json:"move"`
+	// ImpliedMove is the one-day move the volatility index priced at the
+	// close before, index / sqrt(252), in percent. MoveRatio is |Move| over it.
+	ImpliedMove *float64 `json:"implied_move"`
+	MoveRatio   *float64 `json:"move_ratio"`
+
+	// GEXBefore and ZBefore are from the close before the release.
+	GEXBefore *float64 `json:"gex_before"`
+	ZBefore   *float64 `json:"zscore_before"`
+}
+
+// PathPoint averages GEX and Z-score over the events with a close at Offset.
+type PathPoint struct {
+	Offset int      `json:"offset"`
+	GEX    *float64 `json:"gex"`
+	ZScore *float64 `json:"zscore"`
+	Events int      `json:"events"`
+}
+
+// Summary aggregates the events of one release type.
+type Summary struct {
+	ReleaseID   int32  `json:"release_id"`
+	ReleaseName string `json:"release_name"`
+	Events      int    `json:"events"`
+
+	AvgAbsMove     *float64 `json:"avg_abs_move"`
+	AvgImpliedMove *float64 `json:"avg_implied_move"`
+	AvgMoveRatio   *float64 `json:"avg_move_ratio"`
+	// ExceededPct is the share of events, in percent, that moved more than
+	// implied.
+	ExceededPct *float64 `json:"exceeded_pct"`
+
+	// NegativeGamma counts the events that started with negative GEX; the
+	// average moves are split by the sign of GEX before the release.
+	NegativeGamma           int      `json:"negative_gamma"`
+	AvgAbsMoveNegativeGamma *float64 `json:"avg_abs_move_negative_gamma"`
+	AvgAbsMovePositiveGamma *float64 `json:"avg_abs_move_positive_gamma"`
+
+	Path []PathPoint `json:"path"`
+}
+
+// Report is the event studies of one symbol.
+type Report struct {
+	Symbol string `json:"symbol"`
+	// VolatilityIndex is the series implied moves come from, empty when the
+	// symbol has none.
+	VolatilityIndex string    `json:"volatility_index,omitempty"`
+	Impact          string    `json:"impact"`
+	From            string    `json:"from"`
+	Window          int       `json:"window"`
+	Summaries       []Summary `json:"summaries"`
+	// Events are newest first.
+	Events []Event `json:"events"`
+}
+
+// NewEvent studies closes, oldest first, around release. Releases come out at
+// or before the 16:00 close, so the first close on or after the release date
+// is the release day and the close before it the last one ahead of the
+// release. Offsets count stored closes. vol is the volatility index, oldest
+// first, and may be empty. It reports false when the closes don't cover the
+// release.
+func NewEvent(release repository.EconomicRelease, closes []Close, vol []macro.Point, window int) (Event, bool) {
+	date := release.ReleaseDate.Time
+	day := sort.Search(len(closes), func(i int) bool {
+		return !dateOf(closes[i].Date).Before(date)
+	})
+	if day == 0 || day == len(closes) {
+		return Event{}, false
+	}
+	before := closes[day-1]
+	if dateOf(closes[day].Date).Sub(date) > maxGapDays*24*time.Hour ||
+		date.Sub(dateOf(before.Date)) > (maxGapDays+1)*24*time.Hour {
+		return Event{}, false
+	}
+
+	e := Event{
+		ReleaseID:   release.ReleaseID,
+		ReleaseName: release.ReleaseName,
+		ReleaseDate: date.Format(time.DateOnly),
+		GEXBefore:   ptr(before.GEX),
+		ZBefore:     before.ZScore,
+	}
+	if release.ReleaseTime.Valid {
+		e.ReleaseTime = time.Time{}.Add(time.Duration(release.ReleaseTime.Microseconds) * time.Microsecond).Format("15:04")
+	}
+	if release.Actual.Valid && release.Consensus.Valid {
+		e.Surprise = ptr(release.Actual.Float64 - release.Consensus.Float64)
+	}
+	for i := max(day-window, 0); i <= min(day+window, len(closes)-1); i++ {
+		c := closes[i]
+		e.Days = append(e.Days, Day{
+			Offset: i - day,
+			Date:   dateOf(c.Date).Format(time.DateOnly),
+			Spot:   c.Spot,
+			GEX:    c.GEX,
+			ZScore: c.ZScore,
+		})
+	}
+
+	if before.Spot > 0 && closes[day].Spot > 0 {
+		e.Move = ptr(100 * (closes[day].Spot/before.Spot - 1))
+	}
+	if v, ok := volatilityAt(vol, dateOf(before.Date)); ok && v > 0 {
+		e.ImpliedMove = ptr(v / math.Sqrt(tradingDays))
+		if e.Move != nil {
+			e.MoveRatio = ptr(math.Abs(*e.Move) / *e.ImpliedMove)
+		}
+	}
+	return e, true
+}
+
+// volatilityAt returns the last close of vol on or shortly before date.
+func volatilityAt(vol []macro.Point, date time.Time) (float64, bool) {
+	i := sort.Search(len(vol), func(i int) bool {
+		return vol[i].Date.After(date)
+	})
+	if i == 0 || date.Sub(vol[i-1].Date) > maxGapDays*24*time.Hour {
+		return 0, false
+	}
+	return vol[i-1].Value, true
+}
+
+// Summarize aggregates events per release type, ordered by name.
+func Summarize(events []Event, window int) []Summary {
+	byRelease := make(map[int32][]Event)
+	for _, e := range events {
+		byRelease[e.ReleaseID] = append(byRelease[e.ReleaseID], e)
+	}
+
+	summaries := make([]Summary, 0, len(byRelease))
+	for id, group := range byRelease {
+		s := Summary{
+			ReleaseID:   id,
+			ReleaseName: group[0].ReleaseName,
+			Events:      len(group),
+		}
+		var moves, implied, ratios, negative, positive []float64
+		exceeded := 0
+		for _, e := range group {
+			if e.Move != nil {
+				move := math.Abs(*e.Move)
+				moves = append(moves, move)
+				if e.GEXBefore != nil && *e.GEXBefore < 0 {
+					negative = append(negative, move)
+				} else {
+					positive = append(positive, move)
+				}
+			}
+			if e.GEXBefore != nil && *e.GEXBefore < 0 {
+				s.NegativeGamma++
+			}
+			if e.ImpliedMove != nil {
+				implied = append(implied, *e.ImpliedMove)
+			}
+			if e.MoveRatio != nil {
+				ratios = append(ratios, *e.MoveRatio)
+				if *e.MoveRatio > 1 {
+					exceeded++
+				}
+			}
+		}
+		s.AvgAbsMove = mean(moves)
+		s.AvgImpliedMove = mean(implied)
+		s.AvgMoveRatio = mean(ratios)
+		if len(ratios) > 0 {
+			s.ExceededPct = ptr(100 * float64(exceeded) / float64(len(ratios)))
+		}
+		s.AvgAbsMoveNegativeGamma = mean(negative)
+		s.AvgAbsMovePositiveGamma = mean(positive)
+		s.Path = path(group, window)
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].ReleaseName != summaries[j].ReleaseName {
+			return summaries[i].ReleaseName < summaries[j].ReleaseName
+		}
+		return summaries[i].ReleaseID < summaries[j].ReleaseID
+	})
+	return summaries
+}
+
+// path averages GEX and Z-score at each offset of the window.
+func path(events []Event, window int) []PathPoint {
+	points := make([]PathPoint, 0, 2*window+1)
+	for offset := -window; offset <= window; offset++ {
+		var gex, z []float64
+		for _, e := range events {
+			for _, d := range e.Days {
+				if d.Offset != offset {
+					continue
+				}
+				gex = append(gex, d.GEX)
+				if d.ZScore != nil {
+					z = append(z, *d.ZScore)
+				}
+			}
+		}
+		points = append(points, PathPoint{
+			Offset: offset,
+			GEX:    mean(gex),
+			ZScore: mean(z),
+			Events: len(gex),
+		})
+	}
+	return points
+}
+
+// dateOf is the calendar date of t, as midnight UTC like pgtype.Date.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func mean(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return ptr(sum / float64(len(values)))
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
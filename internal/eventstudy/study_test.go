@@ -0,0 +1,175 @@
+package eventstudy
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/macro"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// weekCloses are SPY closes from Monday 2026-03-09 to Tuesday 2026-03-17.
+func weekCloses() []Close {
+	z := 1.5
+	return []Close{
+		{Date: day("2026-03-09"), GEX: 2e9, Spot: 600},
+		{Date: day("2026-03-10"), GEX: -1e9, Spot: 606, ZScore: &z},
+		{Date: day("2026-03-11"), GEX: -2e9, Spot: 594},
+		{Date: day("2026-03-12"), GEX: 1e9, Spot: 597},
+		{Date: day("2026-03-13"), GEX: 3e9, Spot: 600},
+		{Date: day("2026-03-16"), GEX: 4e9, Spot: 603},
+		{Date: day("2026-03-17"), GEX: 5e9, Spot: 603},
+	}
+}
+
+func release(id int32, name, date string) repository.EconomicRelease {
+	return repository.EconomicRelease{
+		ReleaseID:   id,
+		ReleaseName: name,
+		ReleaseDate: pgtype.Date{Time: day(date), Valid: true},
+		Impact:      "High",
+		ReleaseTime: pgtype.Time{Microseconds: int64(8*time.Hour+30*time.Minute) / 1000, Valid: true},
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	vol := []macro.Point{
+		{Date: day("2026-03-09"), Value: 20},
+		{Date: day("2026-03-10"), Value: 15.874},
+	}
+	r := release(10, "Consumer Price Index", "2026-03-11")
+	r.Actual = pgtype.Float8{Float64: 0.4, Valid: true}
+	r.Consensus = pgtype.Float8{Float64: 0.3, Valid: true}
+
+	e, ok := NewEvent(r, weekCloses(), vol, 2)
+	if !ok {
+		t.Fatal("event not covered by the closes")
+	}
+	if e.ReleaseTime != "08:30" || e.ReleaseDate != "2026-03-11" {
+		t.Errorf("release = %s %s", e.ReleaseDate, e.ReleaseTime)
+	}
+	if e.Surprise == nil || math.Abs(*e.Surprise-0.1) > 1e-9 {
+		t.Errorf("surprise = %v", e.Surprise)
+	}
+	if len(e.Days) != 5 || e.Days[0].Offset != -2 || e.Days[0].Date != "2026-03-09" || e.Days[4].Date != "2026-03-13" {
+		t.Fatalf("days = %+v", e.Days)
+	}
+	// 606 to 594 is a 1.98% drop; a VIX of 15.874 prices a 1% day.
+	if e.Move == nil || math.Abs(*e.Move+1.9802) > 1e-3 {
+		t.Errorf("move = %v", e.Move)
+	}
+	if e.ImpliedMove == nil || math.Abs(*e.ImpliedMove-1) > 1e-3 {
+		t.Errorf("implied move = %v", e.ImpliedMove)
+	}
+	if e.MoveRatio == nil || math.Abs(*e.MoveRatio-1.9802) > 1e-2 {
+		t.Errorf("move ratio = %v", e.MoveRatio)
+	}
+	if *e.GEXBefore != -1e9 || e.ZBefore == nil || *e.ZBefore != 1.5 {
+		t.Errorf("before = %v, %v", *e.GEXBefore, e.ZBefore)
+	}
+}
+
+func TestNewEventEdges(t *testing.T) {
+	closes := weekCloses()
+	tests := []struct {
+		date string
+		ok   bool
+		day  string
+	}{
+		{"2026-03-09", false, ""},          // no close before the release
+		{"2026-03-18", false, ""},          // released after the last close
+		{"2026-03-14", true, "2026-03-16"}, // Saturday: Monday reacts
+		{"2026-03-03", false, ""},          // a week before the first close
+	}
+	for _, tt := range tests {
+		e, ok := NewEvent(release(10, "CPI", tt.date), closes, nil, 1)
+		if ok != tt.ok {
+			t.Errorf("%s: ok = %v, want %v", tt.date, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		for _, d := range e.Days {
+			if d.Offset == 0 && d.Date != tt.day {
+				t.Errorf("%s: release day = %s, want %s", tt.date, d.Date, tt.day)
+			}
+		}
+		if e.ImpliedMove != nil {
+			t.Errorf("%s: implied move without a volatility index", tt.date)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	closes := weekCloses()
+	vol := []macro.Point{{Date: day("2026-03-09"), Value: 20}}
+	var events []Event
+	for _, r := range []repository.EconomicRelease{
+		release(10, "Consumer Price Index", "2026-03-10"),
+		release(10, "Consumer Price Index", "2026-03-12"),
+		release(50, "Employment Situation", "2026-03-13"),
+	} {
+		e, ok := NewEvent(r, closes, vol, 1)
+		if !ok {
+			t.Fatalf("%s not covered", r.ReleaseDate.Time)
+		}
+		events = append(events, e)
+	}
+
+	summaries := Summarize(events, 1)
+	if len(summaries) != 2 || summaries[0].ReleaseName != "Consumer Price Index" {
+		t.Fatalf("summaries = %+v", summaries)
+	}
+	cpi := summaries[0]
+	if cpi.Events != 2 || cpi.NegativeGamma != 1 {
+		t.Errorf("events = %d, negative gamma = %d", cpi.Events, cpi.NegativeGamma)
+	}
+	// 600 -> 606 is +1% with positive GEX before, 594 -> 597 +0.505% with
+	// negative GEX. A VIX of 20 prices 1.26%, more than either moved.
+	if math.Abs(*cpi.AvgAbsMove-0.7525) > 1e-3 {
+		t.Errorf("avg move = %v", *cpi.AvgAbsMove)
+	}
+	if math.Abs(*cpi.AvgAbsMovePositiveGamma-1) > 1e-3 || math.Abs(*cpi.AvgAbsMoveNegativeGamma-0.505) > 1e-3 {
+		t.Errorf("moves by gamma = %v, %v", *cpi.AvgAbsMovePositiveGamma, *cpi.AvgAbsMoveNegativeGamma)
+	}
+	if cpi.ExceededPct == nil || *cpi.ExceededPct != 0 {
+		t.Errorf("exceeded = %v", cpi.ExceededPct)
+	}
+	if math.Abs(*cpi.AvgImpliedMove-1.2599) > 1e-3 {
+		t.Errorf("avg implied move = %v", *cpi.AvgImpliedMove)
+	}
+	if len(cpi.Path) != 3 || cpi.Path[1].Events != 2 || *cpi.Path[1].GEX != 0 || *cpi.Path[1].ZScore != 1.5 {
+		t.Errorf("path = %+v", cpi.Path)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	e, _ := NewEvent(release(10, "Consumer Price Index", "2026-03-11"), weekCloses(), nil, 1)
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, &Report{Symbol: "SPY", Events: []Event{e}}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || len(rows[1]) != len(csvHeader) {
+		t.Fatalf("rows = %v", rows)
+	}
+	if got := rows[2]; got[0] != "SPY" || got[2] != "Consumer Price Index" || got[9] != "0" || got[10] != "2026-03-11" || got[7] != "" {
+		t.Errorf("release day row = %v", got)
+	}
+}
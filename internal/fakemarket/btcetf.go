@@ -0,0 +1,103 @@
+package fakemarket
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+// fakeETF is a spot bitcoin ETF with a typical daily flow in US$ millions;
+// GBTC bleeds, the rest take in money with noise around it.
+type fakeETF struct {
+	ticker string
+	drift  float64
+	noise  float64
+}
+
+var fakeETFs = []fakeETF{
+	{"IBIT", 120, 250},
+	{"FBTC", 40, 120},
+	{"BITB", 8, 30},
+	{"ARKB", 6, 40},
+	{"BTCO", 1, 8},
+	{"EZBC", 1, 6},
+	{"BRRR", 0.5, 5},
+	{"HODL", 2, 10},
+	{"BTCW", 0.2, 3},
+	{"GBTC", -60, 120},
+}
+
+// etfLaunch is the first trading day of the spot bitcoin ETFs.
+var etfLaunch = time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)
+
+// flow is the fund's flow on day in US$ millions, and false when the fund
+// reported none, as small funds often don't.
+func (f fakeETF) flow(day time.Time) (float64, bool) {
+	h := hash(f.ticker + day.Format("2006-01-02"))
+	if f.noise < 10 && h%3 == 0 {
+		return 0, false
+	}
+	noise := float64(h%2001)/1000 - 1
+	days := day.Sub(etfLaunch).Hours() / 24
+	return round(f.drift*(1+0.5*math.Sin(days/40))+f.noise*noise, 1), true
+}
+
+// btcETFFlows serves the flows of every trading day since launch up to
+// yesterday as a Farside-style wide CSV: a title row, a column per fund, a
+// Total column, outflows in parentheses and a totals footer.
+func (s *Server) btcETFFlows(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/csv")
+	cw := csv.NewWriter(w)
+
+	header := []string{"Date"}
+	for _, f := range fakeETFs {
+		header = append(header, f.ticker)
+	}
+	header = append(header, "Total")
+	cw.Write([]string{"Bitcoin ETF Flow (US$m)"})
+	cw.Write(header)
+
+	totals := make([]float64, len(fakeETFs)+1)
+	now := s.Now().In(newYork)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for day := etfLaunch; day.Before(today); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		row := []string{day.Format("02 Jan 2006")}
+		total := 0.0
+		for i, f := range fakeETFs {
+			v, ok := f.flow(day)
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, millionsText(v))
+			totals[i] += v
+			total += v
+		}
+		totals[len(fakeETFs)] += total
+		cw.Write(append(row, millionsText(total)))
+	}
+
+	footer := []string{"Total"}
+	for _, v := range totals {
+		footer = append(footer, millionsText(v))
+	}
+	cw.Write(footer)
+	cw.Flush()
+}
+
+// millionsText writes v the way the trackers do, e.g. "1,234.5" or "(12.3)".
+func millionsText(v float64) string {
+	s := fmt.Sprintf("%.1f", math.Abs(v))
+	for i := len(s) - 5; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	if v < 0 {
+		return "(" + s + ")"
+	}
+	return s
+}
@@ -0,0 +1,286 @@
+package fakemarket
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+)
+
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+// ethRPC answers the JSON-RPC methods the app uses with transactions derived
+// from the requested hash.
+func (s *Server) ethRPC(w http.ResponseWriter, r *http.Request) {
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, rpcError(nil, -32700, "parse error"))
+		return
+	}
+
+	switch req.Method {
+	case "eth_chainId":
+		// Mainnet, so the mainnet price feed below is the one asked for.
+		writeJSON(w, rpcResult(req.ID, "0x1"))
+	case "eth_blockNumber":
+		writeJSON(w, rpcResult(req.ID, s.blockNumber()))
+	case "eth_getTransactionByHash":
+		var hash string
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params[0], &hash)
+		}
+		writeJSON(w, rpcResult(req.ID, s.transaction(hash)))
+	case "eth_getTransactionReceipt":
+		var hash string
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params[0], &hash)
+		}
+		writeJSON(w, rpcResult(req.ID, s.receipt(hash)))
+	case "eth_call":
+		var call struct {
+			To   string `json:"to"`
+			Data string `json:"data"`
+		}
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params[0], &call)
+		}
+		to := strings.ToLower(call.To)
+		switch {
+		case to == chainlinkETHUSD && call.Data == latestAnswer:
+			writeJSON(w, rpcResult(req.ID, fmt.Sprintf("0x%064x", s.ethUSD())))
+		case to == fakeUSDC && strings.HasPrefix(call.Data, balanceOf) && len(call.Data) == 74:
+			holder := "0x" + call.Data[34:]
+			writeJSON(w, rpcResult(req.ID, fmt.Sprintf("0x%064x", uint64(hash("usdc"+holder)%1000000)*10000)))
+		case to == ensRegistry:
+			// No names are registered.
+			writeJSON(w, rpcResult(req.ID, fmt.Sprintf("0x%064x", 0)))
+		case isContract(to):
+			writeJSON(w, rpcError(req.ID, 3, "execution reverted"))
+		default:
+			writeJSON(w, rpcResult(req.ID, "0x"))
+		}
+	case "eth_getBlockByNumber":
+		var id string
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params[0], &id)
+		}
+		latest := s.latestBlock()
+		n := latest
+		if id != "latest" {
+			if _, err := fmt.Sscanf(id, "0x%x", &n); err != nil {
+				writeJSON(w, rpcError(req.ID, -32602, "invalid block number"))
+				return
+			}
+		}
+		if n > latest {
+			writeJSON(w, rpcResult(req.ID, nil))
+			return
+		}
+		writeJSON(w, rpcResult(req.ID, s.block(n)))
+	case "eth_getBlockByHash":
+		var id string
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params[0], &id)
+		}
+		var n uint64
+		if _, err := fmt.Sscanf(id, "0xb10c%x", &n); err != nil || len(id) != 66 || n > s.latestBlock() {
+			writeJSON(w, rpcResult(req.ID, nil))
+			return
+		}
+		writeJSON(w, rpcResult(req.ID, s.block(n)))
+	case "eth_getBalance", "eth_getTransactionCount", "eth_getCode":
+		var addr string
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params[0], &addr)
+		}
+		addr = strings.ToLower(addr)
+		switch req.Method {
+		case "eth_getBalance":
+			writeJSON(w, rpcResult(req.ID, fmt.Sprintf("0x%x", uint64(hash("balance"+addr)%10000)*100000000000000)))
+		case "eth_getTransactionCount":
+			writeJSON(w, rpcResult(req.ID, fmt.Sprintf("0x%x", hash("nonce"+addr)%2000)))
+		default:
+			code := "0x"
+			if isContract(addr) {
+				code = "0x6080604052"
+			}
+			writeJSON(w, rpcResult(req.ID, code))
+		}
+	case "eth_getLogs":
+		// Token transfers only exist inside the receipts of lookups.
+		writeJSON(w, rpcResult(req.ID, []interface{}{}))
+	default:
+		writeJSON(w, rpcError(req.ID, -32601, "the method "+req.Method+" does not exist/is not available"))
+	}
+}
+
+func (s *Server) blockNumber() string {
+	return fmt.Sprintf("0x%x", s.latestBlock())
+}
+
+// mergeBlock and mergeTime are the first proof-of-stake block and its time.
+const (
+	mergeBlock = 15537393
+	mergeTime  = 1663224162
+)
+
+// latestBlock advances roughly one block every 12 seconds since the merge.
+func (s *Server) latestBlock() uint64 {
+	return uint64(mergeBlock + (s.Now().Unix()-mergeTime)/12)
+}
+
+// blockTxs is how many transactions every block holds.
+const blockTxs = 8
+
+// block returns block n with its transactions, each from one of a small
+// set of senders so an address page finds some of them.
+func (s *Server) block(n uint64) map[string]interface{} {
+	blockHash := fmt.Sprintf("0xb10c%060x", n)
+	txs := make([]interface{}, 0, blockTxs)
+	var gasUsed uint64
+	for i := 0; i < blockTxs; i++ {
+		tx := s.transaction(fmt.Sprintf("0x%064x", hash(fmt.Sprintf("tx-%d-%d", n, i)))).(map[string]interface{})
+		tx["blockNumber"] = fmt.Sprintf("0x%x", n)
+		tx["blockHash"] = blockHash
+		tx["transactionIndex"] = fmt.Sprintf("0x%x", i)
+		tx["from"] = fmt.Sprintf("0x%040x", hash(fmt.Sprintf("sender-%d", hash(fmt.Sprint(n, i))%20)))
+		txs = append(txs, tx)
+		var gas uint64
+		fmt.Sscanf(tx["gas"].(string), "0x%x", &gas)
+		gasUsed += gas
+	}
+	return map[string]interface{}{
+		"number":        fmt.Sprintf("0x%x", n),
+		"hash":          blockHash,
+		"parentHash":    fmt.Sprintf("0xb10c%060x", n-1),
+		"timestamp":     fmt.Sprintf("0x%x", mergeTime+int64(n-mergeBlock)*12),
+		"miner":         fmt.Sprintf("0x%040x", hash(fmt.Sprint("builder", n%7))),
+		"gasUsed":       fmt.Sprintf("0x%x", gasUsed),
+		"gasLimit":      "0x1c9c380",
+		"baseFeePerGas": fmt.Sprintf("0x%x", 1000000000+uint64(hash(fmt.Sprint("basefee", n))%30)*1000000000),
+		"transactions":  txs,
+	}
+}
+
+const (
+	chainlinkETHUSD = "0x5f4ec3df9cbd43714fe2740f5e3616155c5b8419"
+	// latestAnswer is the selector of the price feed's latestAnswer().
+	latestAnswer = "0x50d25bcd"
+	fakeUSDC     = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	ensRegistry  = "0x00000000000c2e074ec69a0dfb2997ba6c7d2e1e"
+	// balanceOf is the selector of balanceOf(address).
+	balanceOf = "0x70a08231"
+	// erc20Transfer is the selector of transfer(address,uint256) and
+	// transferTopic the topic of its Transfer event.
+	erc20Transfer = "0xa9059cbb"
+	transferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+)
+
+// ethUSD is the Chainlink answer, with 8 decimals: around $2,500, moving
+// through the day.
+func (s *Server) ethUSD() uint64 {
+	minutes := float64(s.Now().Unix() / 60)
+	return uint64((2500 + 150*math.Sin(minutes/97)) * 1e8)
+}
+
+// isContract reports whether the fake chain has code at addr.
+func isContract(addr string) bool {
+	return addr == fakeUSDC || addr == chainlinkETHUSD || addr == ensRegistry
+}
+
+// isTokenTransfer reports whether the transaction of txHash sends USDC
+// rather than ETH; a third of them do.
+func isTokenTransfer(txHash string) bool {
+	return hash(txHash)%3 == 0
+}
+
+func (s *Server) transaction(txHash string) interface{} {
+	if len(txHash) != 66 {
+		return nil
+	}
+	h := hash(txHash)
+	to := fmt.Sprintf("0x%040x", hash("to"+txHash))
+	input := "0x"
+	value := uint64(h%1000) * 1000000000000000
+	if isTokenTransfer(txHash) {
+		input = fmt.Sprintf("%s%064s%064x", erc20Transfer, to[2:], tokenAmount(txHash))
+		to, value = fakeUSDC, 0
+	}
+	return map[string]interface{}{
+		"blockHash":        fmt.Sprintf("0x%064x", hash("block"+txHash)),
+		"blockNumber":      fmt.Sprintf("0x%x", 18000000+h%1000000),
+		"from":             fmt.Sprintf("0x%040x", hash("from"+txHash)),
+		"gas":              fmt.Sprintf("0x%x", 21000+h%100000),
+		"gasPrice":         fmt.Sprintf("0x%x", 1000000000+uint64(h%50)*1000000000),
+		"hash":             txHash,
+		"input":            input,
+		"nonce":            fmt.Sprintf("0x%x", h%500),
+		"to":               to,
+		"transactionIndex": fmt.Sprintf("0x%x", h%200),
+		"value":            fmt.Sprintf("0x%x", value),
+		"v":                "0x1",
+		"r":                fmt.Sprintf("0x%064x", hash("r"+txHash)),
+		"s":                fmt.Sprintf("0x%064x", hash("s"+txHash)),
+	}
+}
+
+// tokenAmount is the USDC sent by a token transfer, in its 6 decimals.
+func tokenAmount(txHash string) uint64 {
+	return uint64(hash("amount"+txHash)%100000) * 10000
+}
+
+// receipt matches transaction: every transaction is mined and succeeds,
+// using all of its gas for plain transfers and 80% of it for token ones.
+func (s *Server) receipt(txHash string) interface{} {
+	tx, ok := s.transaction(txHash).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	h := hash(txHash)
+	gas := uint64(21000 + h%100000)
+	logs := []interface{}{}
+	if isTokenTransfer(txHash) {
+		gas = gas * 8 / 10
+		input := tx["input"].(string)
+		logs = append(logs, map[string]interface{}{
+			"address":         fakeUSDC,
+			"topics":          []string{transferTopic, fmt.Sprintf("0x%064s", tx["from"].(string)[2:]), "0x" + input[10:74]},
+			"data":            "0x" + input[74:],
+			"logIndex":        "0x0",
+			"blockNumber":     tx["blockNumber"],
+			"transactionHash": txHash,
+		})
+	}
+	return map[string]interface{}{
+		"transactionHash":   txHash,
+		"blockHash":         tx["blockHash"],
+		"blockNumber":       tx["blockNumber"],
+		"from":              tx["from"],
+		"to":                tx["to"],
+		"status":            "0x1",
+		"gasUsed":           fmt.Sprintf("0x%x", gas),
+		"cumulativeGasUsed": fmt.Sprintf("0x%x", gas+uint64(h%5000000)),
+		"effectiveGasPrice": tx["gasPrice"],
+		"contractAddress":   nil,
+		"logs":              logs,
+		"type":              "0x0",
+	}
+}
+
+func rpcResult(id json.RawMessage, result interface{}) map[string]interface{} {
+	return map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": result}
+}
+
+func rpcError(id json.RawMessage, code int, msg string) map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error":   map[string]interface{}{"code": code, "message": msg},
+	}
+}
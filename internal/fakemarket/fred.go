@@ -0,0 +1,273 @@
+package fakemarket
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// fakeRelease schedules a FRED release on the same day every month, or on
+// the first Friday when day is 0. Each release publishes the previous month's
+// observation of series, if it has one.
+type fakeRelease struct {
+	id     int
+	name   string
+	day    int
+	series string
+}
+
+var fakeReleases = []fakeRelease{
+	{50, "Employment Situation", 0, "PAYEMS"},
+	{192, "Job Openings and Labor Turnover Survey", 5, "JTSJOL"},
+	{10, "Consumer Price Index", 12, "CPIAUCSL"},
+	{46, "Producer Price Index", 13, "PPIFIS"},
+	{9, "Advance Monthly Sales for Retail and Food Services", 15, "RSAFS"},
+	{53, "Gross Domestic Product", 27, ""},
+	{54, "Personal Income and Outlays", 28, ""},
+}
+
+// date returns the day rel is published in the month starting at m.
+func (rel fakeRelease) date(m time.Time) time.Time {
+	if rel.day != 0 {
+		return m.AddDate(0, 0, rel.day-1)
+	}
+	d := m
+	for d.Weekday() != time.Friday {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// level is the value of series for the month starting at m: a slow trend
+// with some month-to-month noise.
+func (rel fakeRelease) level(m time.Time) float64 {
+	months := float64(m.Year()*12 + int(m.Month()))
+	base := 100 + float64(hash(rel.series)%900)
+	return base * (1 + 0.002*months/12 + 0.003*math.Sin(months*1.7))
+}
+
+// fakeMacroSeries is a daily series, or a weekly one published on Fridays,
+// that swings around base like the macro dashboard's series.
+type fakeMacroSeries struct {
+	id        string
+	base      float64
+	amplitude float64
+	weekly    bool
+}
+
+var fakeMacro = []fakeMacroSeries{
+	{"DGS2", 3.9, 0.4, false},
+	{"DGS10", 4.2, 0.3, false},
+	{"T10Y2Y", 0.3, 0.35, false},
+	{"DFF", 4.1, 0.05, false},
+	{"VIXCLS", 18, 6, false},
+	{"VXNCLS", 22, 7, false},
+	{"RVXCLS", 24, 7, false},
+	{"NFCI", -0.45, 0.15, true},
+}
+
+// value is the series on day d: a slow cycle with a little daily noise.
+func (ms fakeMacroSeries) value(d time.Time) float64 {
+	days := float64(d.Unix() / 86400)
+	phase := float64(hash(ms.id) % 100)
+	return ms.base + ms.amplitude*(math.Sin(days/41+phase)+0.15*math.Sin(days*2.3))
+}
+
+// observations returns the latest limit observations before asOf, newest
+// first: weekdays, or Fridays for weekly series.
+func (ms fakeMacroSeries) observations(asOf time.Time, limit int) []time.Time {
+	var days []time.Time
+	for d := asOf.AddDate(0, 0, -1); len(days) < limit; d = d.AddDate(0, 0, -1) {
+		if ms.weekly && d.Weekday() != time.Friday {
+			continue
+		}
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		days = append(days, d)
+	}
+	return days
+}
+
+func (s *Server) fredReleaseDates(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	now := s.Now().In(newYork)
+	start, err := parseDate(q.Get("realtime_start"))
+	if err != nil {
+		start = now.AddDate(0, 0, -7)
+	}
+	end, err := parseDate(q.Get("realtime_end"))
+	if err != nil {
+		end = now.AddDate(0, 0, 30)
+	}
+
+	type releaseDate struct {
+		ReleaseID   int    `json:"release_id"`
+		ReleaseName string `json:"release_name"`
+		Date        string `json:"date"`
+	}
+	var dates []releaseDate
+	for m := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, newYork); !m.After(end); m = m.AddDate(0, 1, 0) {
+		for _, rel := range fakeReleases {
+			d := rel.date(m)
+			if d.Before(start) || d.After(end) {
+				continue
+			}
+			dates = append(dates, releaseDate{rel.id, rel.name, d.Format("2006-01-02")})
+		}
+	}
+	sort.SliceStable(dates, func(i, j int) bool { return dates[i].Date < dates[j].Date })
+
+	writeJSON(w, map[string]interface{}{
+		"realtime_start": start.Format("2006-01-02"),
+		"realtime_end":   end.Format("2006-01-02"),
+		"order_by":       "release_date",
+		"sort_order":     "asc",
+		"count":          len(dates),
+		"offset":         0,
+		"limit":          1000,
+		"release_dates":  dates,
+	})
+}
+
+func (s *Server) fredSeriesObservations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	for _, ms := range fakeMacro {
+		if ms.id == q.Get("series_id") {
+			s.fredMacroObservations(w, r, ms)
+			return
+		}
+	}
+	var rel fakeRelease
+	for _, candidate := range fakeReleases {
+		if candidate.series != "" && candidate.series == q.Get("series_id") {
+			rel = candidate
+		}
+	}
+	if rel.series == "" {
+		http.Error(w, `{"error_code":400,"error_message":"Bad Request.  The series does not exist."}`, http.StatusBadRequest)
+		return
+	}
+	asOf, err := parseDate(q.Get("realtime_end"))
+	if err != nil {
+		asOf = s.Now().In(newYork)
+	}
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+
+	// The newest observation is last month's once this month's release is
+	// out, and the month before otherwise.
+	month := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, newYork)
+	latest := month.AddDate(0, -1, 0)
+	if asOf.Before(rel.date(month)) {
+		latest = month.AddDate(0, -2, 0)
+	}
+
+	type observation struct {
+		RealtimeStart string `json:"realtime_start"`
+		RealtimeEnd   string `json:"realtime_end"`
+		Date          string `json:"date"`
+		Value         string `json:"value"`
+	}
+	observations := make([]observation, 0, limit)
+	for m := latest; len(observations) < limit; m = m.AddDate(0, -1, 0) {
+		value := rel.level(m)
+		switch q.Get("units") {
+		case "chg":
+			value -= rel.level(m.AddDate(0, -1, 0))
+		case "pch":
+			value = 100 * (value/rel.level(m.AddDate(0, -1, 0)) - 1)
+		}
+		observations = append(observations, observation{
+			RealtimeStart: asOf.Format("2006-01-02"),
+			RealtimeEnd:   asOf.Format("2006-01-02"),
+			Date:          m.Format("2006-01-02"),
+			Value:         strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64),
+		})
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"realtime_start": asOf.Format("2006-01-02"),
+		"realtime_end":   asOf.Format("2006-01-02"),
+		"units":          q.Get("units"),
+		"sort_order":     "desc",
+		"count":          len(observations),
+		"limit":          limit,
+		"observations":   observations,
+	})
+}
+
+func (s *Server) fredMacroObservations(w http.ResponseWriter, r *http.Request, ms fakeMacroSeries) {
+	now := s.Now().In(newYork)
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+
+	type observation struct {
+		Date  string `json:"date"`
+		Value string `json:"value"`
+	}
+	observations := make([]observation, 0, limit)
+	for _, d := range ms.observations(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, newYork), limit) {
+		observations = append(observations, observation{
+			Date:  d.Format("2006-01-02"),
+			Value: strconv.FormatFloat(round(ms.value(d), 2), 'f', -1, 64),
+		})
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"realtime_start": now.Format("2006-01-02"),
+		"realtime_end":   now.Format("2006-01-02"),
+		"sort_order":     "desc",
+		"count":          len(observations),
+		"limit":          limit,
+		"observations":   observations,
+	})
+}
+
+func (s *Server) fredReleases(w http.ResponseWriter, r *http.Request) {
+	type release struct {
+		ID           int    `json:"id"`
+		Name         string `json:"name"`
+		PressRelease bool   `json:"press_release"`
+	}
+	releases := make([]release, 0, len(fakeReleases))
+	for _, rel := range fakeReleases {
+		releases = append(releases, release{rel.id, rel.name, true})
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Name < releases[j].Name })
+
+	writeJSON(w, map[string]interface{}{
+		"order_by": "name",
+		"count":    len(releases),
+		"offset":   0,
+		"limit":    1000,
+		"releases": releases,
+	})
+}
+
+func (s *Server) fredReleaseSeries(w http.ResponseWriter, r *http.Request) {
+	type series struct {
+		ID         string `json:"id"`
+		Title      string `json:"title"`
+		Frequency  string `json:"frequency_short"`
+		Units      string `json:"units_short"`
+		Popularity int    `json:"popularity"`
+	}
+	id, _ := strconv.Atoi(r.URL.Query().Get("release_id"))
+	seriess := []series{}
+	for _, rel := range fakeReleases {
+		if rel.id == id && rel.series != "" {
+			seriess = append(seriess, series{rel.series, rel.name, "M", "Index", 90})
+		}
+	}
+	writeJSON(w, map[string]interface{}{
+		"count":   len(seriess),
+		"seriess": seriess,
+	})
+}
@@ -0,0 +1,212 @@
+// Package fakemarket serves deterministic stand-ins for the Alpaca,
+// Public.com, FRED and Ethereum JSON-RPC APIs and a bitcoin ETF flows CSV,
+// so the application can be run and exercised end to end without credentials
+// or network access. Prices, chains and releases are derived from the symbol
+// and the clock, so the same request always gets the same answer on a given
+// day.
+package fakemarket
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/handler/gex"
+)
+
+var newYork = mustLoadLocation("America/New_York")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// Server generates the fake market. The zero value is not usable; call New.
+type Server struct {
+	// Now anchors expirations and release dates.
+	Now func() time.Time
+	// Expiries is how many weekly expirations each symbol lists.
+	Expiries int
+	// StrikesPerSide is how many strikes are listed above and below spot.
+	StrikesPerSide int
+	// AccountID is the Public.com account the fake session belongs to.
+	AccountID string
+}
+
+func New() *Server {
+	return &Server{
+		Now:            time.Now,
+		Expiries:       8,
+		StrikesPerSide: 15,
+		AccountID:      "FAKE0001",
+	}
+}
+
+// Handler serves every fake provider from one mux. Alpaca data and trading,
+// Public.com and FRED use their real paths; the Ethereum node answers on /rpc
+// and the bitcoin ETF flows are at /btc-etf/flows.csv.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v2/stocks/snapshots", s.alpacaStockSnapshots)
+	mux.HandleFunc("GET /v1beta1/options/snapshots/{symbol}", s.alpacaOptionSnapshots)
+	mux.HandleFunc("GET /v2/options/contracts", s.alpacaOptionContracts)
+
+	mux.HandleFunc("POST /userapiauthservice/personal/access-tokens", s.publicAccessToken)
+	mux.HandleFunc("GET /userapigateway/trading/accounts", s.publicAccounts)
+	mux.HandleFunc("POST /userapigateway/marketdata/{account}/quotes", s.publicQuotes)
+	mux.HandleFunc("POST /userapigateway/marketdata/{account}/option-chain", s.publicOptionChain)
+	mux.HandleFunc("POST /userapigateway/marketdata/{account}/option-expirations", s.publicExpirations)
+	mux.HandleFunc("GET /userapigateway/option-details/{account}/greeks", s.publicGreeks)
+
+	mux.HandleFunc("GET /fred/releases/dates", s.fredReleaseDates)
+	mux.HandleFunc("GET /fred/series/observations", s.fredSeriesObservations)
+	mux.HandleFunc("GET /fred/releases", s.fredReleases)
+	mux.HandleFunc("GET /fred/release/series", s.fredReleaseSeries)
+
+	mux.HandleFunc("POST /rpc", s.ethRPC)
+
+	mux.HandleFunc("GET /btc-etf/flows.csv", s.btcETFFlows)
+
+	return mux
+}
+
+// Spot is the underlying price for symbol: stable per symbol, drifting a
+// little from day to day.
+func (s *Server) Spot(symbol string) float64 {
+	base := 20 + float64(hash(symbol)%98000)/100
+	day := float64(s.Now().In(newYork).YearDay())
+	drift := 1 + 0.01*math.Sin(day+float64(hash(symbol)%7))
+	return math.Round(base*drift*100) / 100
+}
+
+// ExpiryDates lists the next weekly (Friday) expirations, including today if
+// it is a Friday.
+func (s *Server) ExpiryDates() []time.Time {
+	now := s.Now().In(newYork)
+	d := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, newYork)
+	for d.Weekday() != time.Friday {
+		d = d.AddDate(0, 0, 1)
+	}
+	dates := make([]time.Time, s.Expiries)
+	for i := range dates {
+		dates[i] = d.AddDate(0, 0, 7*i)
+	}
+	return dates
+}
+
+// Contract is one generated option.
+type Contract struct {
+	OCC        string
+	Underlying string
+	Call       bool
+	Strike     float64
+	Expiry     time.Time
+	OI         int
+	IV         float64
+	Gamma      float64
+	Delta      float64
+	Bid, Ask   float64
+}
+
+func (c Contract) typeName() string {
+	if c.Call {
+		return "call"
+	}
+	return "put"
+}
+
+// Chain generates the contracts listed for symbol on expiry.
+func (s *Server) Chain(symbol string, expiry time.Time) []Contract {
+	spot := s.Spot(symbol)
+	step := strikeStep(spot)
+	atm := math.Round(spot/step) * step
+
+	expiresAt := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 16, 0, 0, 0, newYork)
+	days := math.Max(expiresAt.Sub(s.Now()).Hours()/24, 0.5)
+	t := days / 365
+
+	var contracts []Contract
+	for i := -s.StrikesPerSide; i <= s.StrikesPerSide; i++ {
+		strike := atm + float64(i)*step
+		if strike <= 0 {
+			continue
+		}
+		moneyness := math.Log(strike / spot)
+		iv := 0.18 + 0.6*moneyness*moneyness + 0.05*math.Max(-moneyness, 0)
+		gamma := gex.EstimateGamma(spot, strike, iv, days)
+		d1 := (-moneyness + (0.05+0.5*iv*iv)*t) / (iv * math.Sqrt(t))
+
+		for _, call := range []bool{true, false} {
+			c := Contract{
+				OCC:        occSymbol(symbol, expiry, call, strike),
+				Underlying: symbol,
+				Call:       call,
+				Strike:     strike,
+				Expiry:     expiry,
+				IV:         round(iv, 4),
+				Gamma:      round(gamma, 6),
+			}
+			// Open interest clusters at round strikes near the money, with
+			// puts heavier below spot and calls heavier above.
+			weight := math.Exp(-40 * moneyness * moneyness)
+			if (call && strike > spot) || (!call && strike < spot) {
+				weight *= 1.5
+			}
+			if math.Mod(strike, step*5) == 0 {
+				weight *= 2
+			}
+			c.OI = int(weight*float64(500+hash(c.OCC)%4500)) + 1
+
+			price := gex.BlackScholesPrice(spot, strike, iv, t, 0.05, call)
+			c.Bid = round(math.Max(price*0.97, 0.01), 2)
+			c.Ask = round(math.Max(price*1.03, 0.02), 2)
+			if call {
+				c.Delta = round(gex.N(d1), 4)
+			} else {
+				c.Delta = round(gex.N(d1)-1, 4)
+			}
+			contracts = append(contracts, c)
+		}
+	}
+	return contracts
+}
+
+func strikeStep(spot float64) float64 {
+	switch {
+	case spot < 50:
+		return 1
+	case spot < 200:
+		return 2.5
+	case spot < 1000:
+		return 5
+	default:
+		return 10
+	}
+}
+
+// occSymbol builds an OCC option symbol, e.g. SPY261023C00590000.
+func occSymbol(underlying string, expiry time.Time, call bool, strike float64) string {
+	side := "P"
+	if call {
+		side = "C"
+	}
+	return fmt.Sprintf("%s%s%s%08d", strings.ToUpper(underlying), expiry.Format("060102"), side, int(math.Round(strike*1000)))
+}
+
+func hash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
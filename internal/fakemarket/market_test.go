@@ -0,0 +1,304 @@
+package fakemarket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/btcetf"
+	"github.com/arnabmitra/eth-proxy/internal/eth"
+	"github.com/arnabmitra/eth-proxy/internal/fred"
+	"github.com/arnabmitra/eth-proxy/internal/handler/gex"
+	"github.com/arnabmitra/eth-proxy/internal/public"
+)
+
+func newTestMarket(t *testing.T) (*Server, string) {
+	t.Helper()
+	m := New()
+	// Expiry lookups use the real date, so the market runs on the real clock.
+	m.Expiries = 3
+	srv := httptest.NewServer(m.Handler())
+	t.Cleanup(srv.Close)
+	return m, srv.URL
+}
+
+func TestChainIsDeterministic(t *testing.T) {
+	m, _ := newTestMarket(t)
+	expiry := m.ExpiryDates()[0]
+	if expiry.Weekday() != time.Friday {
+		t.Fatalf("expiry %s is not a Friday", expiry)
+	}
+
+	a, b := m.Chain("SPY", expiry), m.Chain("SPY", expiry)
+	if len(a) != 2*(2*m.StrikesPerSide+1) {
+		t.Fatalf("got %d contracts", len(a))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("contract %d differs between calls: %+v vs %+v", i, a[i], b[i])
+		}
+		if a[i].Gamma <= 0 || a[i].OI <= 0 {
+			t.Errorf("contract %s has gamma %v, OI %d", a[i].OCC, a[i].Gamma, a[i].OI)
+		}
+	}
+	if c, ok := m.lookupOCC(a[3].OCC); !ok || c != a[3] {
+		t.Errorf("lookupOCC(%s) = %+v, %v", a[3].OCC, c, ok)
+	}
+}
+
+func TestAlpacaPathEndToEnd(t *testing.T) {
+	m, url := newTestMarket(t)
+	t.Setenv("PUBLIC_PERSONAL_SECRET", "")
+	t.Setenv("PUBLIC_SECRET_KEY", "")
+	t.Setenv("ALPACA_API_BASE_URL", "")
+	t.Setenv("ALPACA_DATA_BASE_URL", url)
+	t.Setenv("ALPACA_LIVE_BASE_URL", url)
+	t.Setenv("ALPACA_PAPER_BASE_URL", url)
+	ctx := context.Background()
+
+	dates, err := gex.GetExpirationDates(ctx, "key", "secret", "QQQ")
+	if err != nil {
+		t.Fatalf("GetExpirationDates: %v", err)
+	}
+	if len(dates) != m.Expiries {
+		t.Fatalf("got %d expirations, want %d", len(dates), m.Expiries)
+	}
+
+	spot, err := gex.GetSpotPrice(ctx, "key", "secret", "QQQ")
+	if err != nil {
+		t.Fatalf("GetSpotPrice: %v", err)
+	}
+	if spot != m.Spot("QQQ") {
+		t.Errorf("spot = %v, want %v", spot, m.Spot("QQQ"))
+	}
+
+	options, _, warning, err := gex.FetchOptionsChain(ctx, "QQQ", dates[0], "key", "secret")
+	if err != nil {
+		t.Fatalf("FetchOptionsChain: %v", err)
+	}
+	if warning != "" {
+		t.Errorf("unexpected warning %q", warning)
+	}
+	if len(options) != 2*(2*m.StrikesPerSide+1) {
+		t.Errorf("got %d options", len(options))
+	}
+}
+
+func TestPublicPathEndToEnd(t *testing.T) {
+	m, url := newTestMarket(t)
+	c := public.NewClient("secret", "")
+	c.BaseURL = url
+	ctx := context.Background()
+
+	price, err := c.GetSpotPrice(ctx, "NVDA")
+	if err != nil {
+		t.Fatalf("GetSpotPrice: %v", err)
+	}
+	if price != m.Spot("NVDA") {
+		t.Errorf("price = %v, want %v", price, m.Spot("NVDA"))
+	}
+	if c.AccountID() != m.AccountID {
+		t.Errorf("account = %q, want %q", c.AccountID(), m.AccountID)
+	}
+
+	expiry := m.ExpiryDates()[1]
+	chain, _, err := c.GetOptionChain(ctx, "NVDA", expiry.Format("2006-01-02"))
+	if err != nil {
+		t.Fatalf("GetOptionChain: %v", err)
+	}
+	if len(chain.Calls) != 2*m.StrikesPerSide+1 || len(chain.Puts) != len(chain.Calls) {
+		t.Errorf("got %d calls, %d puts", len(chain.Calls), len(chain.Puts))
+	}
+
+	osi := m.Chain("NVDA", expiry)[0].OCC
+	greeks, err := c.GetGreeks(ctx, []string{osi})
+	if err != nil {
+		t.Fatalf("GetGreeks: %v", err)
+	}
+	if greeks[osi] <= 0 {
+		t.Errorf("gamma for %s = %v", osi, greeks[osi])
+	}
+}
+
+func TestFREDReleases(t *testing.T) {
+	_, url := newTestMarket(t)
+	c := fred.NewClient("key")
+	c.BaseURL = url + "/fred"
+
+	tracked := map[int]fred.TrackedRelease{10: {Impact: "High"}, 50: {Impact: "High"}}
+	releases, err := c.GetFilteredReleases(context.Background(), 45, tracked)
+	if err != nil {
+		t.Fatalf("GetFilteredReleases: %v", err)
+	}
+	seen := make(map[int]bool)
+	for _, r := range releases {
+		seen[r.ReleaseID] = true
+	}
+	// CPI and the jobs report are both monthly, so a 45 day window has them.
+	if !seen[10] || !seen[50] {
+		t.Errorf("expected CPI and Employment Situation, got %+v", releases)
+	}
+}
+
+func TestFREDReleaseValues(t *testing.T) {
+	_, url := newTestMarket(t)
+	c := fred.NewClient("key")
+	c.BaseURL = url + "/fred"
+	ctx := context.Background()
+
+	// The fake CPI comes out on the 12th with the previous month's value.
+	release := time.Date(2026, 9, 12, 0, 0, 0, 0, time.UTC)
+	v, err := c.GetReleaseValues(ctx, "CPIAUCSL", "pch", release)
+	if err != nil {
+		t.Fatalf("GetReleaseValues: %v", err)
+	}
+	if v.Actual == nil || v.Prior == nil || v.Period.Format("2006-01-02") != "2026-08-01" {
+		t.Fatalf("release day values = %+v", v)
+	}
+
+	// The day before, August isn't out: July is the latest, i.e. the prior.
+	before, err := c.GetReleaseValues(ctx, "CPIAUCSL", "pch", release.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("GetReleaseValues: %v", err)
+	}
+	if before.Actual != nil || before.Prior == nil || *before.Prior != *v.Prior {
+		t.Errorf("values before the release = %+v, want prior %v only", before, *v.Prior)
+	}
+}
+
+func TestFREDMacroSeries(t *testing.T) {
+	_, url := newTestMarket(t)
+	c := fred.NewClient("key")
+	c.BaseURL = url + "/fred"
+
+	daily, err := c.GetSeriesObservations(context.Background(), "DGS10", "", time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("GetSeriesObservations: %v", err)
+	}
+	if len(daily) != 10 || !daily[0].Date.After(daily[9].Date) {
+		t.Fatalf("observations = %+v", daily)
+	}
+	for _, o := range daily {
+		if wd := o.Date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			t.Errorf("weekend observation %s", o.Date)
+		}
+	}
+
+	weekly, err := c.GetSeriesObservations(context.Background(), "NFCI", "", time.Time{}, 3)
+	if err != nil {
+		t.Fatalf("GetSeriesObservations: %v", err)
+	}
+	if len(weekly) != 3 || weekly[0].Date.Sub(weekly[1].Date) != 7*24*time.Hour {
+		t.Errorf("weekly observations = %+v", weekly)
+	}
+}
+
+func TestEthLookup(t *testing.T) {
+	_, url := newTestMarket(t)
+	c := eth.NewClient(eth.Mainnet, []string{url + "/rpc"}, http.DefaultClient)
+	if err := c.Verify(context.Background()); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	var plain, token bool
+	for i := 0; i < 12 && !(plain && token); i++ {
+		d, err := c.Lookup(context.Background(), fmt.Sprintf("0x%064x", i+1))
+		if err != nil {
+			t.Fatalf("Lookup: %v", err)
+		}
+		if d.Status != eth.StatusSuccess || d.GasUsed == 0 || d.Fee == "" || d.FeeUSD == nil || *d.ETHUSD < 2000 {
+			t.Errorf("details = %+v", d)
+		}
+		if len(d.Transfers) == 0 {
+			plain = true
+			continue
+		}
+		token = true
+		if tr := d.Transfers[0]; tr.Symbol != "USDC" || tr.From != d.From || d.Call == nil || d.Call.Name != "transfer" || d.Call.Args[0].Value != tr.To {
+			t.Errorf("token transfer %+v, call %+v", tr, d.Call)
+		}
+	}
+	if !plain || !token {
+		t.Errorf("plain transfers %v, token transfers %v", plain, token)
+	}
+
+	if _, err := c.Lookup(context.Background(), "0x01"); !errors.Is(err, eth.ErrNotFound) {
+		t.Errorf("short hash: %v", err)
+	}
+}
+
+func TestEthExplorer(t *testing.T) {
+	_, url := newTestMarket(t)
+	c := eth.NewClient(eth.Mainnet, []string{url + "/rpc"}, http.DefaultClient)
+	ctx := context.Background()
+
+	latest, err := c.Block(ctx, "latest")
+	if err != nil {
+		t.Fatalf("Block: %v", err)
+	}
+	if len(latest.Transactions) != 8 || latest.BaseFee == "" || latest.GasUsed == 0 {
+		t.Errorf("latest block = %+v", latest)
+	}
+	if b, err := c.Block(ctx, latest.ParentHash); err != nil || b.Number != latest.Number-1 {
+		t.Errorf("parent = %+v, %v", b, err)
+	}
+	if _, err := c.Block(ctx, fmt.Sprint(latest.Number+100)); !errors.Is(err, eth.ErrNotFound) {
+		t.Errorf("future block: %v", err)
+	}
+
+	sender := latest.Transactions[0].From
+	d, err := c.Address(ctx, sender)
+	if err != nil {
+		t.Fatalf("Address: %v", err)
+	}
+	if d.Contract || d.Balance == "" || len(d.Transactions) == 0 || len(d.Warnings) != 0 {
+		t.Errorf("address = %+v", d)
+	}
+	for _, tx := range d.Transactions {
+		if tx.From != sender && tx.To != sender {
+			t.Errorf("unrelated transaction %+v", tx)
+		}
+	}
+	if _, err := c.Address(ctx, "nobody.eth"); !errors.Is(err, eth.ErrNotFound) {
+		t.Errorf("unregistered name: %v", err)
+	}
+}
+
+func TestBTCETFFlows(t *testing.T) {
+	m, url := newTestMarket(t)
+	m.Now = func() time.Time { return time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC) }
+
+	src := &btcetf.Source{Location: url + "/btc-etf/flows.csv", HTTPClient: http.DefaultClient}
+	flows, err := src.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	// 15 trading days from the 11th to the 31st of January.
+	days := make(map[string]bool)
+	var gbtc float64
+	for _, f := range flows {
+		days[f.Date.Format("2006-01-02")] = true
+		if f.Ticker == "GBTC" {
+			gbtc += f.USD
+		}
+		if f.Ticker == "TOTAL" {
+			t.Errorf("total column parsed as a fund: %+v", f)
+		}
+	}
+	if len(days) != 15 || !days["2024-01-11"] || !days["2024-01-31"] || days["2024-01-13"] {
+		t.Errorf("days = %v", days)
+	}
+	if gbtc >= 0 {
+		t.Errorf("GBTC took in %.0f", gbtc)
+	}
+
+	again, err := src.Fetch(context.Background())
+	if err != nil || len(again) != len(flows) || again[len(again)-1] != flows[len(flows)-1] {
+		t.Errorf("second fetch differs: %d flows, %v", len(again), err)
+	}
+}
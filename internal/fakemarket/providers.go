@@ -0,0 +1,251 @@
+package fakemarket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func parseDate(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", s, newYork)
+}
+
+func decimal(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Alpaca
+
+func (s *Server) alpacaStockSnapshots(w http.ResponseWriter, r *http.Request) {
+	now := s.Now().UTC().Format(time.RFC3339Nano)
+	snapshots := make(map[string]interface{})
+	for _, symbol := range strings.Split(r.URL.Query().Get("symbols"), ",") {
+		if symbol == "" {
+			continue
+		}
+		spot := s.Spot(symbol)
+		snapshots[symbol] = map[string]interface{}{
+			"latestTrade": map[string]interface{}{"t": now, "p": spot, "s": 100, "x": "V"},
+			"latestQuote": map[string]interface{}{
+				"t": now, "bp": round(spot-0.01, 2), "bs": 3, "ap": round(spot+0.01, 2), "as": 4,
+			},
+		}
+	}
+	writeJSON(w, snapshots)
+}
+
+func (s *Server) alpacaOptionSnapshots(w http.ResponseWriter, r *http.Request) {
+	symbol := r.PathValue("symbol")
+	now := s.Now().UTC().Format(time.RFC3339Nano)
+
+	expiries := s.ExpiryDates()
+	if exp := r.URL.Query().Get("expiration_date"); exp != "" {
+		d, err := parseDate(exp)
+		if err != nil {
+			http.Error(w, `{"message":"invalid expiration_date"}`, http.StatusUnprocessableEntity)
+			return
+		}
+		expiries = []time.Time{d}
+	}
+
+	snapshots := make(map[string]interface{})
+	for _, expiry := range expiries {
+		for _, c := range s.Chain(symbol, expiry) {
+			snapshots[c.OCC] = map[string]interface{}{
+				"latestQuote":       map[string]interface{}{"t": now, "bp": c.Bid, "bs": 10, "ap": c.Ask, "as": 10},
+				"impliedVolatility": c.IV,
+				"greeks":            map[string]interface{}{"delta": c.Delta, "gamma": c.Gamma},
+			}
+		}
+	}
+	writeJSON(w, map[string]interface{}{"snapshots": snapshots, "next_page_token": nil})
+}
+
+func (s *Server) alpacaOptionContracts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var contracts []map[string]interface{}
+	for _, symbol := range strings.Split(q.Get("underlying_symbols"), ",") {
+		if symbol == "" {
+			continue
+		}
+		for _, expiry := range s.matchingExpiries(q.Get("expiration_date"), q.Get("expiration_date_gte")) {
+			for _, c := range s.Chain(symbol, expiry) {
+				contracts = append(contracts, map[string]interface{}{
+					"id":                  fmt.Sprintf("%08x", hash(c.OCC)),
+					"symbol":              c.OCC,
+					"name":                c.OCC,
+					"status":              "active",
+					"tradable":            true,
+					"expiration_date":     expiry.Format("2006-01-02"),
+					"underlying_symbol":   symbol,
+					"underlying_asset_id": fmt.Sprintf("%08x", hash(symbol)),
+					"type":                c.typeName(),
+					"style":               "american",
+					"strike_price":        decimal(c.Strike),
+					"multiplier":          "100",
+					"size":                "100",
+					"open_interest":       strconv.Itoa(c.OI),
+				})
+			}
+		}
+	}
+	writeJSON(w, map[string]interface{}{"option_contracts": contracts, "next_page_token": nil})
+}
+
+// matchingExpiries narrows the listed expiries to an exact date or to those
+// on or after gte.
+func (s *Server) matchingExpiries(exact, gte string) []time.Time {
+	var out []time.Time
+	for _, d := range s.ExpiryDates() {
+		day := d.Format("2006-01-02")
+		if exact != "" && day != exact {
+			continue
+		}
+		if gte != "" && day < gte {
+			continue
+		}
+		out = append(out, d)
+	}
+	if exact != "" && len(out) == 0 {
+		if d, err := parseDate(exact); err == nil {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// Public.com
+
+type publicInstrument struct {
+	Symbol string `json:"symbol"`
+	Type   string `json:"type"`
+}
+
+func (s *Server) publicAccessToken(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{"accessToken": "fake-access-token"})
+}
+
+func (s *Server) publicAccounts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"accounts": []map[string]string{{"accountId": s.AccountID}},
+	})
+}
+
+func (s *Server) publicQuotes(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Instruments []publicInstrument `json:"instruments"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	quotes := make([]map[string]interface{}, 0, len(req.Instruments))
+	for _, in := range req.Instruments {
+		quotes = append(quotes, map[string]interface{}{
+			"instrument": in,
+			"last":       decimal(s.Spot(in.Symbol)),
+		})
+	}
+	writeJSON(w, map[string]interface{}{"quotes": quotes})
+}
+
+func (s *Server) publicOptionChain(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Instrument     publicInstrument `json:"instrument"`
+		ExpirationDate string           `json:"expirationDate"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	expiry, err := parseDate(req.ExpirationDate)
+	if err != nil {
+		http.Error(w, "invalid expirationDate", http.StatusBadRequest)
+		return
+	}
+
+	calls := []map[string]interface{}{}
+	puts := []map[string]interface{}{}
+	for _, c := range s.Chain(req.Instrument.Symbol, expiry) {
+		entry := map[string]interface{}{
+			"instrument":   publicInstrument{Symbol: c.OCC, Type: "OPTION"},
+			"openInterest": c.OI,
+			"bid":          decimal(c.Bid),
+			"ask":          decimal(c.Ask),
+			"optionDetails": map[string]interface{}{
+				"strikePrice": decimal(c.Strike),
+				"greeks": map[string]string{
+					"gamma": decimal(c.Gamma),
+					"delta": decimal(c.Delta),
+				},
+			},
+		}
+		if c.Call {
+			calls = append(calls, entry)
+		} else {
+			puts = append(puts, entry)
+		}
+	}
+	writeJSON(w, map[string]interface{}{
+		"baseSymbol": req.Instrument.Symbol,
+		"calls":      calls,
+		"puts":       puts,
+	})
+}
+
+func (s *Server) publicExpirations(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Instrument publicInstrument `json:"instrument"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dates := make([]string, 0, s.Expiries)
+	for _, d := range s.ExpiryDates() {
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	writeJSON(w, map[string]interface{}{"baseSymbol": req.Instrument.Symbol, "expirations": dates})
+}
+
+func (s *Server) publicGreeks(w http.ResponseWriter, r *http.Request) {
+	var greeks []map[string]interface{}
+	for _, osi := range strings.Split(r.URL.Query().Get("osiSymbols"), ",") {
+		c, ok := s.lookupOCC(osi)
+		if !ok {
+			continue
+		}
+		greeks = append(greeks, map[string]interface{}{
+			"symbol": osi,
+			"greeks": map[string]string{"gamma": decimal(c.Gamma), "delta": decimal(c.Delta)},
+		})
+	}
+	writeJSON(w, map[string]interface{}{"greeks": greeks})
+}
+
+// lookupOCC regenerates the contract behind an OCC symbol.
+func (s *Server) lookupOCC(osi string) (Contract, bool) {
+	// Root, then YYMMDD, C/P and an 8 digit strike.
+	if len(osi) < 16 {
+		return Contract{}, false
+	}
+	root := osi[:len(osi)-15]
+	expiry, err := time.ParseInLocation("060102", osi[len(root):len(root)+6], newYork)
+	if err != nil {
+		return Contract{}, false
+	}
+	for _, c := range s.Chain(root, expiry) {
+		if c.OCC == osi {
+			return c, true
+		}
+	}
+	return Contract{}, false
+}
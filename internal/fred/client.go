@@ -1,9 +1,11 @@
 package fred
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 )
 
@@ -13,15 +15,24 @@ const (
 
 // Client for FRED API
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
+	apiKey string
+
+	// BaseURL defaults to the public FRED API; FRED_API_BASE_URL overrides
+	// it, e.g. to point at cmd/fakemarket. HTTPClient can be swapped in tests.
+	BaseURL    string
+	HTTPClient *http.Client
 }
 
 // NewClient creates a new FRED API client
 func NewClient(apiKey string) *Client {
+	url := os.Getenv("FRED_API_BASE_URL")
+	if url == "" {
+		url = baseURL
+	}
 	return &Client{
-		apiKey: apiKey,
-		httpClient: &http.Client{
+		apiKey:  apiKey,
+		BaseURL: url,
+		HTTPClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
@@ -47,14 +58,19 @@ type ReleaseDate struct {
 }
 
 // GetUpcomingReleases fetches releases from past 7 days to next N days
-func (c *Client) GetUpcomingReleases(days int) (*ReleaseDatesResponse, error) {
+func (c *Client) GetUpcomingReleases(ctx context.Context, days int) (*ReleaseDatesResponse, error) {
 	startDate := time.Now().AddDate(0, 0, -7).Format("2006-01-02") // Look back 7 days
 	endDate := time.Now().AddDate(0, 0, days).Format("2006-01-02")
 
 	url := fmt.Sprintf("%s/releases/dates?realtime_start=%s&realtime_end=%s&api_key=%s&file_type=json&limit=1000",
-		baseURL, startDate, endDate, c.apiKey)
+		c.BaseURL, startDate, endDate, c.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
 
-	resp, err := http.Get(url)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch releases: %w", err)
 	}
@@ -78,46 +94,42 @@ type FilteredRelease struct {
 	ReleaseName string
 	Date        time.Time
 	Impact      string // High, Medium, Low
+
+	// ReleaseTime is when the release is published, after midnight Eastern
+	// time; zero when it has no fixed time.
+	ReleaseTime time.Duration
+	// SeriesID is the headline series the release publishes, in Units (a
+	// FRED units code such as "pch"); empty when we don't track one.
+	SeriesID string
+	Units    string
+}
+
+// TrackedRelease is a release we collect, with what we know about it beyond
+// its FRED name. The tracked releases are configured in the database.
+type TrackedRelease struct {
+	Impact string // High, Medium, Low
+	// ReleaseTime is when it is published, after midnight Eastern time; zero
+	// when it has no fixed time.
+	ReleaseTime time.Duration
+	Category    string
+	// SeriesID is the headline series, in Units; RelatedSeries are other
+	// series the release publishes that are worth watching.
+	SeriesID      string
+	Units         string
+	RelatedSeries []string
 }
 
-// GetFilteredReleases returns only the releases we care about with impact levels
-func (c *Client) GetFilteredReleases(days int) ([]FilteredRelease, error) {
-	response, err := c.GetUpcomingReleases(days)
+// GetFilteredReleases returns only the tracked releases, with their impact levels
+func (c *Client) GetFilteredReleases(ctx context.Context, days int, tracked map[int]TrackedRelease) ([]FilteredRelease, error) {
+	response, err := c.GetUpcomingReleases(ctx, days)
 	if err != nil {
 		return nil, err
 	}
 
 	filtered := make([]FilteredRelease, 0)
 
-	// Map of release IDs we care about with their impact levels
-	importantReleases := map[int]string{
-		// High Impact - Market Moving
-		10:  "High",   // Consumer Price Index (CPI)
-		50:  "High",   // Employment Situation (NFP + Unemployment)
-		9:   "High",   // Advance Monthly Sales for Retail and Food Services
-		192: "High",   // Job Openings and Labor Turnover Survey (JOLTS)
-		436: "High",   // Monthly Retail Trade and Food Services
-		
-		// Medium Impact - Important Economic Indicators
-		46:  "Medium", // Producer Price Index (PPI)
-		479: "Medium", // Consumer Expenditure Surveys
-		11:  "Medium", // Employment Cost Index
-		386: "Medium", // GDPNow (Atlanta Fed GDP forecast)
-		296: "Medium", // Housing Vacancies and Homeownership
-		92:  "Medium", // Selected Real Retail Sales Series
-		
-		// Low Impact - Regional/Supplementary Data
-		112: "Low",    // State Employment and Unemployment
-		113: "Low",    // Metropolitan Area Employment and Unemployment
-		308: "Low",    // State and Metro Area Employment, Hours, and Earnings
-		477: "Low",    // Monthly State Retail Sales
-		
-		// Note: FOMC Press Release (ID 101) excluded - it's daily data, not the actual meeting
-		// Actual FOMC meetings should be added manually or from Fed calendar
-	}
-
 	for _, release := range response.ReleaseDates {
-		if impact, ok := importantReleases[release.ReleaseID]; ok {
+		if info, ok := tracked[release.ReleaseID]; ok {
 			releaseDate, err := time.Parse("2006-01-02", release.Date)
 			if err != nil {
 				continue
@@ -127,7 +139,10 @@ func (c *Client) GetFilteredReleases(days int) ([]FilteredRelease, error) {
 				ReleaseID:   release.ReleaseID,
 				ReleaseName: release.ReleaseName,
 				Date:        releaseDate,
-				Impact:      impact,
+				Impact:      info.Impact,
+				ReleaseTime: info.ReleaseTime,
+				SeriesID:    info.SeriesID,
+				Units:       info.Units,
 			})
 		}
 	}
@@ -1,54 +1,166 @@
 package fred
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/httpreplay"
 )
 
+// testTracked are some of the releases the migration seeds.
+var testTracked = map[int]TrackedRelease{
+	10:  {Impact: "High", ReleaseTime: 8*time.Hour + 30*time.Minute, SeriesID: "CPIAUCSL", Units: "pch"},
+	50:  {Impact: "High", ReleaseTime: 8*time.Hour + 30*time.Minute, SeriesID: "PAYEMS", Units: "chg"},
+	192: {Impact: "High", ReleaseTime: 10 * time.Hour},
+	46:  {Impact: "Medium", ReleaseTime: 8*time.Hour + 30*time.Minute},
+}
+
+// replayClient returns a client answered from the fred_release_dates
+// cassette. HTTPREPLAY=record with FRED_API_KEY set records it again.
+func replayClient(t *testing.T) *Client {
+	t.Helper()
+	rec := httpreplay.ForTest(t, "fred_release_dates")
+	// The requested window is relative to today.
+	rec.IgnoreParams = []string{"realtime_start", "realtime_end"}
+
+	client := NewClient(os.Getenv("FRED_API_KEY"))
+	client.BaseURL = baseURL
+	client.HTTPClient = rec.Client()
+	return client
+}
+
 func TestFREDClient(t *testing.T) {
-	// Get API key from environment
-	apiKey := "f5991f935f3de996990f99823bdd172b"
-	
-	client := NewClient(apiKey)
-	
-	// Test fetching upcoming releases
-	response, err := client.GetUpcomingReleases(30)
+	response, err := replayClient(t).GetUpcomingReleases(context.Background(), 30)
 	if err != nil {
 		t.Fatalf("Failed to fetch releases: %v", err)
 	}
-	
-	fmt.Printf("Found %d total releases in next 30 days\n", response.Count)
-	fmt.Printf("Sample releases:\n")
-	for i, release := range response.ReleaseDates {
-		if i >= 5 {
-			break
+	if response.Count == 0 || len(response.ReleaseDates) == 0 {
+		t.Fatalf("expected releases from the recording, got %+v", response)
+	}
+	for _, release := range response.ReleaseDates {
+		if release.ReleaseID == 0 || release.ReleaseName == "" || release.Date == "" {
+			t.Errorf("incomplete release date %+v", release)
 		}
-		fmt.Printf("  - [%d] %s on %s\n", release.ReleaseID, release.ReleaseName, release.Date)
 	}
 }
 
 func TestFilteredReleases(t *testing.T) {
-	apiKey := "f5991f935f3de996990f99823bdd172b"
-	
-	client := NewClient(apiKey)
-	
-	// Test filtered releases for next 60 days to get more results
-	filtered, err := client.GetFilteredReleases(60)
+	filtered, err := replayClient(t).GetFilteredReleases(context.Background(), 60, testTracked)
 	if err != nil {
 		t.Fatalf("Failed to fetch filtered releases: %v", err)
 	}
-	
-	fmt.Printf("\nFiltered to %d important releases in next 60 days:\n", len(filtered))
+	if len(filtered) == 0 {
+		t.Fatal("Expected at least some filtered releases")
+	}
 	for _, release := range filtered {
-		fmt.Printf("  - [%s] %s on %s (ID: %d)\n", 
-			release.Impact, 
-			release.ReleaseName, 
-			release.Date.Format("Jan 02, 2006"),
-			release.ReleaseID)
-	}
-	
-	// Verify we got some high-impact releases
+		if _, ok := testTracked[release.ReleaseID]; !ok {
+			t.Errorf("untracked release %d kept", release.ReleaseID)
+		}
+	}
+}
+
+func TestGetUpcomingReleasesHonorsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewClient("unused").GetUpcomingReleases(ctx, 30)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGetFilteredReleasesReplay(t *testing.T) {
+	filtered, err := replayClient(t).GetFilteredReleases(context.Background(), 30, testTracked)
+	if err != nil {
+		t.Fatalf("GetFilteredReleases: %v", err)
+	}
 	if len(filtered) == 0 {
-		t.Error("Expected at least some filtered releases")
+		t.Fatal("expected releases from the recording")
+	}
+	for _, r := range filtered {
+		if r.Impact == "" || r.Date.IsZero() {
+			t.Errorf("incomplete release %+v", r)
+		}
+	}
+}
+
+func TestGetSeriesObservations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/series/observations" || q.Get("series_id") != "PAYEMS" || q.Get("units") != "chg" ||
+			q.Get("realtime_end") != "2026-10-02" || q.Get("sort_order") != "desc" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, `{"units":"chg","count":3,"observations":[
+			{"date":"2026-09-01","value":"."},
+			{"date":"2026-08-01","value":"142"},
+			{"date":"2026-07-01","value":"89"}]}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient("key")
+	client.BaseURL = srv.URL
+	obs, err := client.GetSeriesObservations(context.Background(), "PAYEMS", "chg", time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), 3)
+	if err != nil {
+		t.Fatalf("GetSeriesObservations: %v", err)
+	}
+	// The missing September value is skipped.
+	if len(obs) != 2 || obs[0].Value != 142 || obs[0].Date.Format("2006-01-02") != "2026-08-01" {
+		t.Errorf("observations = %+v", obs)
+	}
+}
+
+func TestGetReleasesPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/releases" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		// Serve a catalogue of three releases, two per page.
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			fmt.Fprint(w, `{"count":3,"offset":0,"releases":[{"id":53,"name":"Gross Domestic Product"},{"id":54,"name":"Personal Income and Outlays"}]}`)
+		case "2":
+			fmt.Fprint(w, `{"count":3,"offset":2,"releases":[{"id":10,"name":"Consumer Price Index","press_release":true}]}`)
+		default:
+			t.Errorf("unexpected offset %s", r.URL.Query().Get("offset"))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient("key")
+	client.BaseURL = srv.URL
+	releases, err := client.GetReleases(context.Background())
+	if err != nil {
+		t.Fatalf("GetReleases: %v", err)
+	}
+	if len(releases) != 3 || releases[2].ID != 10 || !releases[2].PressRelease {
+		t.Errorf("releases = %+v", releases)
+	}
+}
+
+func TestGetReleaseSeries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/release/series" || q.Get("release_id") != "54" || q.Get("order_by") != "popularity" || q.Get("limit") != "5" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, `{"count":1,"seriess":[{"id":"PCEPI","title":"Personal Consumption Expenditures: Chain-type Price Index","frequency_short":"M","units_short":"Index 2017=100","seasonal_adjustment_short":"SA","popularity":85}]}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient("key")
+	client.BaseURL = srv.URL
+	series, err := client.GetReleaseSeries(context.Background(), 54, 5)
+	if err != nil {
+		t.Fatalf("GetReleaseSeries: %v", err)
+	}
+	if len(series) != 1 || series[0].ID != "PCEPI" || series[0].Frequency != "M" || series[0].Popularity != 85 {
+		t.Errorf("series = %+v", series)
 	}
 }
@@ -0,0 +1,135 @@
+package fred
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ObservationsResponse represents the FRED API response for series observations
+type ObservationsResponse struct {
+	RealtimeStart string             `json:"realtime_start"`
+	RealtimeEnd   string             `json:"realtime_end"`
+	Units         string             `json:"units"`
+	Count         int                `json:"count"`
+	Observations  []ObservationValue `json:"observations"`
+}
+
+// ObservationValue is an observation as FRED returns it; missing values are "."
+type ObservationValue struct {
+	Date  string `json:"date"`
+	Value string `json:"value"`
+}
+
+// Observation is one value of a series, dated by the period it covers.
+type Observation struct {
+	Date  time.Time
+	Value float64
+}
+
+// GetSeriesObservations returns the latest limit observations of seriesID,
+// newest first, as they were known on asOf. A zero asOf means today. units is
+// a FRED units code such as "pch" (percent change); empty means levels.
+// Missing values are skipped.
+func (c *Client) GetSeriesObservations(ctx context.Context, seriesID, units string, asOf time.Time, limit int) ([]Observation, error) {
+	params := url.Values{
+		"series_id":  {seriesID},
+		"api_key":    {c.apiKey},
+		"file_type":  {"json"},
+		"sort_order": {"desc"},
+		"limit":      {strconv.Itoa(limit)},
+	}
+	if units != "" {
+		params.Set("units", units)
+	}
+	if !asOf.IsZero() {
+		params.Set("realtime_start", asOf.Format("2006-01-02"))
+		params.Set("realtime_end", asOf.Format("2006-01-02"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/series/observations?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch observations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d for series %s", resp.StatusCode, seriesID)
+	}
+
+	var result ObservationsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	observations := make([]Observation, 0, len(result.Observations))
+	for _, o := range result.Observations {
+		value, err := strconv.ParseFloat(o.Value, 64)
+		if err != nil {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", o.Date)
+		if err != nil {
+			continue
+		}
+		observations = append(observations, Observation{Date: date, Value: value})
+	}
+	return observations, nil
+}
+
+// ReleaseValues are the headline values of a release. Actual is nil until the
+// release is published; Period is the observation date of Actual.
+type ReleaseValues struct {
+	Period time.Time
+	Actual *float64
+	Prior  *float64
+}
+
+// GetReleaseValues returns the actual and prior values a release of seriesID
+// on date published. The series as known on the release date is compared with
+// the day before: a new observation is the actual and the one before it the
+// prior. Until that happens, and for future releases, only the prior (the
+// latest value) is set.
+func (c *Client) GetReleaseValues(ctx context.Context, seriesID, units string, date time.Time) (ReleaseValues, error) {
+	var values ReleaseValues
+
+	if date.Format("2006-01-02") > time.Now().Format("2006-01-02") {
+		latest, err := c.GetSeriesObservations(ctx, seriesID, units, time.Time{}, 1)
+		if err != nil || len(latest) == 0 {
+			return values, err
+		}
+		values.Prior = &latest[0].Value
+		return values, nil
+	}
+
+	after, err := c.GetSeriesObservations(ctx, seriesID, units, date, 2)
+	if err != nil {
+		return values, err
+	}
+	before, err := c.GetSeriesObservations(ctx, seriesID, units, date.AddDate(0, 0, -1), 1)
+	if err != nil {
+		return values, err
+	}
+	if len(after) == 0 {
+		return values, nil
+	}
+	if len(before) == 0 || after[0].Date.After(before[0].Date) {
+		values.Period = after[0].Date
+		values.Actual = &after[0].Value
+		if len(after) > 1 {
+			values.Prior = &after[1].Value
+		}
+		return values, nil
+	}
+	values.Prior = &after[0].Value
+	return values, nil
+}
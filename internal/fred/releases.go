@@ -0,0 +1,109 @@
+package fred
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ReleasesResponse represents the FRED API response for the release catalogue
+type ReleasesResponse struct {
+	Count    int       `json:"count"`
+	Offset   int       `json:"offset"`
+	Limit    int       `json:"limit"`
+	Releases []Release `json:"releases"`
+}
+
+// Release is an entry of the FRED release catalogue
+type Release struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	PressRelease bool   `json:"press_release"`
+	Link         string `json:"link,omitempty"`
+	Notes        string `json:"notes,omitempty"`
+}
+
+// ReleaseSeriesResponse represents the FRED API response for a release's series
+type ReleaseSeriesResponse struct {
+	Count  int      `json:"count"`
+	Series []Series `json:"seriess"`
+}
+
+// Series describes a FRED series
+type Series struct {
+	ID             string `json:"id"`
+	Title          string `json:"title"`
+	Frequency      string `json:"frequency_short"`
+	Units          string `json:"units_short"`
+	SeasonalAdjust string `json:"seasonal_adjustment_short"`
+	ObservationEnd string `json:"observation_end"`
+	Popularity     int    `json:"popularity"`
+}
+
+// GetReleases returns the whole FRED release catalogue, by name.
+func (c *Client) GetReleases(ctx context.Context) ([]Release, error) {
+	var releases []Release
+	// The catalogue is a few hundred releases; page through it in case it
+	// outgrows one request.
+	for offset := 0; ; {
+		params := url.Values{
+			"api_key":   {c.apiKey},
+			"file_type": {"json"},
+			"order_by":  {"name"},
+			"limit":     {"1000"},
+			"offset":    {strconv.Itoa(offset)},
+		}
+		var page ReleasesResponse
+		if err := c.get(ctx, "/releases", params, &page); err != nil {
+			return nil, err
+		}
+		releases = append(releases, page.Releases...)
+		offset += len(page.Releases)
+		if len(page.Releases) == 0 || offset >= page.Count {
+			return releases, nil
+		}
+	}
+}
+
+// GetReleaseSeries returns up to limit series of a release, most popular
+// first, to help pick the headline and related series to track.
+func (c *Client) GetReleaseSeries(ctx context.Context, releaseID, limit int) ([]Series, error) {
+	params := url.Values{
+		"release_id": {strconv.Itoa(releaseID)},
+		"api_key":    {c.apiKey},
+		"file_type":  {"json"},
+		"order_by":   {"popularity"},
+		"sort_order": {"desc"},
+		"limit":      {strconv.Itoa(limit)},
+	}
+	var result ReleaseSeriesResponse
+	if err := c.get(ctx, "/release/series", params, &result); err != nil {
+		return nil, err
+	}
+	return result.Series, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, params url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned status %d for %s", resp.StatusCode, path)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
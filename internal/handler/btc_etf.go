@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/btcetf"
+)
+
+const (
+	defaultBTCETFDays = 90
+	maxBTCETFDays     = 5 * 365
+)
+
+// BTCETFHandler serves the spot bitcoin ETF flows: per-fund flows and
+// holdings, the daily totals and the cumulative flows since launch.
+type BTCETFHandler struct {
+	logger *slog.Logger
+	tmpl   *template.Template
+	store  *btcetf.Store
+}
+
+func NewBTCETFHandler(logger *slog.Logger, tmpl *template.Template, store *btcetf.Store) *BTCETFHandler {
+	return &BTCETFHandler{
+		logger: logger,
+		tmpl:   tmpl,
+		store:  store,
+	}
+}
+
+// report reads the report with the daily flows of ?days= (default 90).
+func (h *BTCETFHandler) report(w http.ResponseWriter, r *http.Request) (*btcetf.Report, bool) {
+	days, ok := btcETFDays(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	report, err := h.store.Report(ctx, days)
+	if err != nil {
+		h.logger.Error("Failed to load BTC ETF flows", slog.Any("error", err))
+		http.Error(w, "Failed to load BTC ETF flows", http.StatusInternalServerError)
+		return nil, false
+	}
+	return report, true
+}
+
+// GetFlows returns the report as JSON.
+func (h *BTCETFHandler) GetFlows(w http.ResponseWriter, r *http.Request) {
+	report, ok := h.report(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *BTCETFHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report, ok := h.report(w, r)
+	if !ok {
+		return
+	}
+	err := h.tmpl.ExecuteTemplate(w, "btc_etf.html", map[string]interface{}{
+		"Report": report,
+		"Days":   r.URL.Query().Get("days"),
+	})
+	if err != nil {
+		h.logger.Error("Failed to render BTC ETF flows", slog.Any("error", err))
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+func btcETFDays(w http.ResponseWriter, r *http.Request) (int, bool) {
+	s := r.URL.Query().Get("days")
+	if s == "" {
+		return defaultBTCETFDays, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > maxBTCETFDays {
+		http.Error(w, "days must be between 1 and "+strconv.Itoa(maxBTCETFDays), http.StatusBadRequest)
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/btcetf"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+)
+
+func TestBTCETFTemplateRenders(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	holdings := 805000.0
+	report := btcetf.NewReport([]repository.BtcEtfFund{
+		{Ticker: "IBIT", Name: "iShares Bitcoin Trust", Issuer: "BlackRock"},
+		{Ticker: "GBTC", Name: "Grayscale Bitcoin Trust", Issuer: "Grayscale"},
+	}, []btcetf.Flow{
+		{Ticker: "IBIT", Date: day.AddDate(0, 0, -1), USD: 250e6},
+		{Ticker: "GBTC", Date: day.AddDate(0, 0, -1), USD: -40e6},
+		{Ticker: "IBIT", Date: day, USD: 1.2e9, HoldingsBTC: &holdings},
+	}, 90)
+
+	for _, page := range []map[string]interface{}{
+		{"Report": report, "Days": ""},
+		{"Report": btcetf.NewReport(nil, nil, 90), "Days": "30"},
+	} {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, "btc_etf.html", page); err != nil {
+			t.Fatalf("render: %v", err)
+		}
+		out := buf.String()
+		if page["Days"] == "30" {
+			if !strings.Contains(out, "No flows yet") {
+				t.Error("empty page lacks its notice")
+			}
+			continue
+		}
+		for _, want := range []string{"as of 2026-10-16", "&#43;$1.20B", "-$40.0M", "805,000 BTC", "2 days of inflows", "Fri 16 Oct 2026", `"cumulative_usd":1410000000`} {
+			if !strings.Contains(out, want) {
+				t.Errorf("page is missing %q", want)
+			}
+		}
+		if strings.Contains(out, "theblock.co") {
+			t.Error("page still embeds the third-party chart")
+		}
+	}
+}
+
+func TestBTCETFDays(t *testing.T) {
+	for query, want := range map[string]int{"": defaultBTCETFDays, "?days=30": 30, "?days=0": 0, "?days=9999": 0, "?days=x": 0} {
+		w := httptest.NewRecorder()
+		days, ok := btcETFDays(w, httptest.NewRequest(http.MethodGet, "/btc-etf"+query, nil))
+		if days != want || ok != (want != 0) {
+			t.Errorf("%q: days %d, ok %v", query, days, ok)
+		}
+		if !ok && w.Code != http.StatusBadRequest {
+			t.Errorf("%q: status %d", query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/events"
+	"github.com/arnabmitra/eth-proxy/internal/ics"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// feedHistoryDays is how far back feeds keep releases, so recent ones
+	// stay in calendars with their actual values.
+	feedHistoryDays = 30
+	// feedRefresh is how often subscribers are asked to poll a feed.
+	feedRefresh = time.Hour
+	// releaseDuration makes timed releases show as a block in calendar apps.
+	releaseDuration = 30 * time.Minute
+	// uidDomain keeps event UIDs stable whichever host serves the feed.
+	uidDomain = "gextracker.site"
+)
+
+// CalendarFeedHandler serves the economic calendar and watchlist option
+// expiries as iCalendar feeds.
+type CalendarFeedHandler struct {
+	logger    *slog.Logger
+	queries   *repository.Queries
+	universes *universe.Store
+}
+
+func NewCalendarFeedHandler(logger *slog.Logger, db *pgxpool.Pool, universes *universe.Store) *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		logger:    logger,
+		queries:   repository.New(db),
+		universes: universes,
+	}
+}
+
+// EconomicCalendar serves the tracked FRED releases of the past 30 days and
+// the coming months, optionally only those with an impact in ?impact=
+// (comma-separated).
+func (h *CalendarFeedHandler) EconomicCalendar(w http.ResponseWriter, r *http.Request) {
+	impacts, ok := feedImpacts(w, r)
+	if !ok {
+		return
+	}
+
+	releases, err := h.releaseEvents(r.Context(), impacts, siteURL(r))
+	if err != nil {
+		h.logger.Error("Failed to build economic calendar feed", slog.Any("error", err))
+		http.Error(w, "Failed to build calendar", http.StatusInternalServerError)
+		return
+	}
+
+	h.write(w, "economic-calendar.ics", ics.Calendar{
+		Name:        "Economic Calendar",
+		Description: "U.S. economic releases tracked by GEX Tracker, with impact",
+		Refresh:     feedRefresh,
+		Events:      releases,
+	})
+}
+
+// Watchlist serves /calendar/<slug>.ics: the option expiries of the members
+// of watchlist slug together with the economic releases, filtered like
+// EconomicCalendar.
+func (h *CalendarFeedHandler) Watchlist(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/calendar/")
+	slug, ok := strings.CutSuffix(name, ".ics")
+	if !ok || slug == "" {
+		http.NotFound(w, r)
+		return
+	}
+	impacts, ok := feedImpacts(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	u, err := h.universes.Get(ctx, slug)
+	if errors.Is(err, universe.ErrNotFound) || (err == nil && u.Kind != universe.KindWatchlist) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to load watchlist", slog.String("slug", slug), slog.Any("error", err))
+		http.Error(w, "Failed to build calendar", http.StatusInternalServerError)
+		return
+	}
+	symbols, err := h.universes.Symbols(ctx, slug)
+	if err != nil {
+		h.logger.Error("Failed to load watchlist symbols", slog.String("slug", slug), slog.Any("error", err))
+		http.Error(w, "Failed to build calendar", http.StatusInternalServerError)
+		return
+	}
+
+	base := siteURL(r)
+	feed, err := h.releaseEvents(ctx, impacts, base)
+	if err != nil {
+		h.logger.Error("Failed to build watchlist feed", slog.String("slug", slug), slog.Any("error", err))
+		http.Error(w, "Failed to build calendar", http.StatusInternalServerError)
+		return
+	}
+	expiries, err := h.queries.ListOptionExpiryDatesForSymbols(ctx, symbols)
+	if err != nil {
+		h.logger.Error("Failed to load option expiries", slog.String("slug", slug), slog.Any("error", err))
+		http.Error(w, "Failed to build calendar", http.StatusInternalServerError)
+		return
+	}
+	today := time.Now().In(events.NewYork)
+	feed = append(feed, expiryFeedEvents(slug, expiries, today, today.AddDate(0, 0, maxEventDays), impacts, base)...)
+	sort.SliceStable(feed, func(i, j int) bool { return feed[i].Start.Before(feed[j].Start) })
+
+	h.write(w, slug+".ics", ics.Calendar{
+		Name:        u.Name + " calendar",
+		Description: "Option expiries of " + u.Name + " and U.S. economic releases, from GEX Tracker",
+		Refresh:     feedRefresh,
+		Events:      feed,
+	})
+}
+
+func (h *CalendarFeedHandler) releaseEvents(ctx context.Context, impacts map[string]bool, base string) ([]ics.Event, error) {
+	today := time.Now().In(events.NewYork)
+	rows, err := h.queries.GetUpcomingReleases(ctx, repository.GetUpcomingReleasesParams{
+		ReleaseDate:   pgtype.Date{Time: today.AddDate(0, 0, -feedHistoryDays), Valid: true},
+		ReleaseDate_2: pgtype.Date{Time: today.AddDate(0, 0, maxEventDays), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list releases: %w", err)
+	}
+
+	feed := make([]ics.Event, 0, len(rows))
+	for _, row := range rows {
+		if len(impacts) > 0 && !impacts[row.Impact] {
+			continue
+		}
+		feed = append(feed, releaseFeedEvent(row, base))
+	}
+	return feed, nil
+}
+
+func (h *CalendarFeedHandler) write(w http.ResponseWriter, filename string, cal ics.Calendar) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "public, max-age=900")
+	if err := ics.Write(w, cal, time.Now()); err != nil {
+		h.logger.Error("Failed to write calendar feed", slog.String("feed", filename), slog.Any("error", err))
+	}
+}
+
+// feedImpacts parses ?impact=, e.g. "High,Medium". An empty result keeps
+// every release.
+func feedImpacts(w http.ResponseWriter, r *http.Request) (map[string]bool, bool) {
+	impacts := map[string]bool{}
+	for _, s := range strings.Split(strings.Join(r.URL.Query()["impact"], ","), ",") {
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "":
+		case "high":
+			impacts[events.ImpactHigh] = true
+		case "medium":
+			impacts[events.ImpactMedium] = true
+		case "low":
+			impacts[events.ImpactLow] = true
+		default:
+			http.Error(w, "impact must be High, Medium or Low", http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	return impacts, true
+}
+
+// releaseFeedEvent is the calendar entry of an economic release. Releases
+// without a known time of day are all-day events.
+func releaseFeedEvent(r repository.EconomicRelease, base string) ics.Event {
+	d := r.ReleaseDate.Time
+	e := ics.Event{
+		UID:        fmt.Sprintf("economic-%d-%s@%s", r.ReleaseID, d.Format("20060102"), uidDomain),
+		Summary:    fmt.Sprintf("[%s] %s", r.Impact, r.ReleaseName),
+		URL:        base + "/economic-calendar",
+		Categories: []string{"Economic", r.Impact},
+		Start:      time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, events.NewYork),
+		AllDay:     !r.ReleaseTime.Valid,
+		Updated:    r.UpdatedAt,
+	}
+	if r.ReleaseTime.Valid {
+		e.Start = e.Start.Add(time.Duration(r.ReleaseTime.Microseconds) * time.Microsecond)
+		e.Duration = releaseDuration
+	}
+
+	lines := []string{r.Impact + " impact FRED release"}
+	var values []string
+	for _, v := range []struct {
+		label string
+		value *float64
+	}{
+		{"Actual", float8Ptr(r.Actual)},
+		{"Consensus", float8Ptr(r.Consensus)},
+		{"Prior", float8Ptr(r.Prior)},
+	} {
+		if v.value != nil {
+			values = append(values, fmt.Sprintf("%s: %g", v.label, *v.value))
+		}
+	}
+	if len(values) > 0 {
+		lines = append(lines, strings.Join(values, " | "))
+	}
+	if r.SeriesID.Valid {
+		lines = append(lines, fmt.Sprintf("Series: %s (%s)", r.SeriesID.String, r.Units.String))
+	}
+	e.Description = strings.Join(lines, "\n")
+	return e
+}
+
+// expiryFeedEvents turns the stored expiry dates of a watchlist's symbols
+// into one all-day event per expiration date from from through to, listing
+// the symbols expiring that day. Monthly expirations are Medium and
+// quarterly ones High impact; other expiries are Low.
+func expiryFeedEvents(slug string, rows []repository.OptionExpiryDate, from, to time.Time, impacts map[string]bool, base string) []ics.Event {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, events.NewYork)
+	type expiry struct {
+		symbols []string
+		updated time.Time
+	}
+	byDate := map[string]*expiry{}
+	for _, row := range rows {
+		var dates []string
+		if err := json.Unmarshal(row.ExpiryDates, &dates); err != nil {
+			continue
+		}
+		for _, s := range dates {
+			d, err := time.ParseInLocation("2006-01-02", s, events.NewYork)
+			if err != nil || d.Before(from) || d.After(to) {
+				continue
+			}
+			x := byDate[s]
+			if x == nil {
+				x = &expiry{}
+				byDate[s] = x
+			}
+			x.symbols = append(x.symbols, row.Symbol)
+			if row.UpdatedAt.After(x.updated) {
+				x.updated = row.UpdatedAt
+			}
+		}
+	}
+
+	feed := make([]ics.Event, 0, len(byDate))
+	for s, x := range byDate {
+		d, _ := time.ParseInLocation("2006-01-02", s, events.NewYork)
+		title, impact := "Options expire", events.ImpactLow
+		if d.Equal(events.MonthlyExpiration(d.Year(), d.Month())) {
+			title, impact = "Monthly OPEX", events.ImpactMedium
+			if d.Month()%3 == 0 {
+				title, impact = "Quarterly OPEX", events.ImpactHigh
+			}
+		}
+		if len(impacts) > 0 && !impacts[impact] {
+			continue
+		}
+		feed = append(feed, ics.Event{
+			UID:         fmt.Sprintf("expiry-%s-%s@%s", slug, d.Format("20060102"), uidDomain),
+			Summary:     fmt.Sprintf("[%s] %s: %s", impact, title, strings.Join(x.symbols, ", ")),
+			Description: fmt.Sprintf("%s for %d %s symbols", title, len(x.symbols), slug),
+			URL:         fmt.Sprintf("%s/gex?symbol=%s&expiration=%s", base, x.symbols[0], s),
+			Categories:  []string{"Options", impact},
+			Start:       d,
+			AllDay:      true,
+			Updated:     x.updated,
+		})
+	}
+	sort.Slice(feed, func(i, j int) bool { return feed[i].Start.Before(feed[j].Start) })
+	return feed
+}
+
+// siteURL is the scheme and host the request was made to, honoring a TLS
+// terminating proxy.
+func siteURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
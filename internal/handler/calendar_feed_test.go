@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/events"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestReleaseFeedEvent(t *testing.T) {
+	e := releaseFeedEvent(repository.EconomicRelease{
+		ReleaseID:   10,
+		ReleaseName: "Consumer Price Index",
+		ReleaseDate: pgtype.Date{Time: time.Date(2026, 11, 12, 0, 0, 0, 0, time.UTC), Valid: true},
+		Impact:      "High",
+		ReleaseTime: pgtype.Time{Microseconds: int64(8*time.Hour+30*time.Minute) / 1e3, Valid: true},
+		SeriesID:    pgtype.Text{String: "CPIAUCSL", Valid: true},
+		Units:       pgtype.Text{String: "pc1", Valid: true},
+		Actual:      pgtype.Float8{Float64: 3.1, Valid: true},
+		Prior:       pgtype.Float8{Float64: 3, Valid: true},
+	}, "https://gextracker.site")
+
+	if e.UID != "economic-10-20261112@gextracker.site" || e.Summary != "[High] Consumer Price Index" {
+		t.Errorf("event = %+v", e)
+	}
+	if e.AllDay || !e.Start.Equal(time.Date(2026, 11, 12, 8, 30, 0, 0, events.NewYork)) {
+		t.Errorf("start = %v, all day %v", e.Start, e.AllDay)
+	}
+	if !strings.Contains(e.Description, "Actual: 3.1 | Prior: 3") || !strings.Contains(e.Description, "CPIAUCSL (pc1)") {
+		t.Errorf("description = %q", e.Description)
+	}
+
+	// Releases without a time are all-day.
+	e = releaseFeedEvent(repository.EconomicRelease{ReleaseID: 17, ReleaseName: "Industrial Production", Impact: "Low",
+		ReleaseDate: pgtype.Date{Time: time.Date(2026, 11, 17, 0, 0, 0, 0, time.UTC), Valid: true}}, "")
+	if !e.AllDay || e.Start.Day() != 17 {
+		t.Errorf("event = %+v", e)
+	}
+}
+
+func TestExpiryFeedEvents(t *testing.T) {
+	rows := []repository.OptionExpiryDate{
+		{Symbol: "AAPL", ExpiryDates: []byte(`["2026-10-16","2026-10-23","2026-11-20","2026-12-18","2027-06-18"]`)},
+		{Symbol: "SPY", ExpiryDates: []byte(`["2026-10-19","2026-10-23","2026-12-18"]`)},
+	}
+	from := time.Date(2026, 10, 18, 15, 0, 0, 0, events.NewYork)
+
+	feed := expiryFeedEvents("core", rows, from, from.AddDate(0, 0, 90), nil, "https://gextracker.site")
+	var got []string
+	for _, e := range feed {
+		got = append(got, e.Start.Format("01-02")+" "+e.Summary)
+	}
+	want := []string{
+		"10-19 [Low] Options expire: SPY",
+		"10-23 [Low] Options expire: AAPL, SPY",
+		"11-20 [Medium] Monthly OPEX: AAPL",
+		"12-18 [High] Quarterly OPEX: AAPL, SPY",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("feed =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if feed[1].UID != "expiry-core-20261023@gextracker.site" || !feed[1].AllDay {
+		t.Errorf("event = %+v", feed[1])
+	}
+
+	high := expiryFeedEvents("core", rows, from, from.AddDate(0, 0, 90), map[string]bool{"High": true}, "")
+	if len(high) != 1 || high[0].Start.Month() != time.December {
+		t.Errorf("high impact = %+v", high)
+	}
+}
+
+func TestFeedImpacts(t *testing.T) {
+	w := httptest.NewRecorder()
+	impacts, ok := feedImpacts(w, httptest.NewRequest("GET", "/economic-calendar.ics?impact=high,Medium", nil))
+	if !ok || len(impacts) != 2 || !impacts["High"] || !impacts["Medium"] {
+		t.Errorf("impacts = %v", impacts)
+	}
+
+	w = httptest.NewRecorder()
+	if _, ok := feedImpacts(w, httptest.NewRequest("GET", "/economic-calendar.ics?impact=urgent", nil)); ok || w.Code != 400 {
+		t.Errorf("expected a 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CollectorStatusHandler serves the admin view of the GEX collector's run
+// ledger: recent runs, symbols that are failing and how fresh each symbol's
+// stored data is.
+type CollectorStatusHandler struct {
+	logger  *slog.Logger
+	tmpl    *template.Template
+	queries *repository.Queries
+}
+
+func NewCollectorStatusHandler(logger *slog.Logger, tmpl *template.Template, db *pgxpool.Pool) *CollectorStatusHandler {
+	return &CollectorStatusHandler{
+		logger:  logger,
+		tmpl:    tmpl,
+		queries: repository.New(db),
+	}
+}
+
+func (h *CollectorStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.tmpl.ExecuteTemplate(w, "collector_status.html", nil)
+	if err != nil {
+		h.logger.Error("Failed to render collector status", slog.Any("error", err))
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+type CollectorRunView struct {
+	ID               string  `json:"id"`
+	Trigger          string  `json:"trigger"`
+	StartedAt        string  `json:"started_at"`
+	FinishedAt       string  `json:"finished_at,omitempty"`
+	DurationSeconds  float64 `json:"duration_seconds"`
+	SymbolsAttempted int32   `json:"symbols_attempted"`
+	Successes        int32   `json:"successes"`
+	Failures         int32   `json:"failures"`
+}
+
+type CollectorAttemptView struct {
+	Symbol     string `json:"symbol"`
+	Tier       string `json:"tier"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	Provider   string `json:"provider,omitempty"`
+	StartedAt  string `json:"started_at"`
+	DurationMs int32  `json:"duration_ms"`
+}
+
+type FailingSymbolView struct {
+	Symbol        string `json:"symbol"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+	Provider      string `json:"provider,omitempty"`
+	LastAttemptAt string `json:"last_attempt_at"`
+	FailureCount  int64  `json:"failure_count"`
+}
+
+type SymbolFreshnessView struct {
+	Symbol         string  `json:"symbol"`
+	LastRecordedAt string  `json:"last_recorded_at"`
+	AgeMinutes     float64 `json:"age_minutes"`
+}
+
+// GetStatus returns the collector status as JSON. ?limit= caps the number of
+// runs (default 20) and ?run=<id> adds the per-symbol attempts of that run.
+func (h *CollectorStatusHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	limit := int32(20)
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 && v <= 200 {
+		limit = int32(v)
+	}
+
+	runs, err := h.queries.ListCollectorRuns(ctx, limit)
+	if err != nil {
+		h.logger.Error("Failed to fetch collector runs", slog.Any("error", err))
+		http.Error(w, "Failed to fetch collector runs", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	failing, err := h.queries.GetFailingCollectorSymbols(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		h.logger.Error("Failed to fetch failing symbols", slog.Any("error", err))
+		http.Error(w, "Failed to fetch failing symbols", http.StatusInternalServerError)
+		return
+	}
+
+	freshness, err := h.queries.GetGEXFreshness(ctx)
+	if err != nil {
+		h.logger.Error("Failed to fetch data freshness", slog.Any("error", err))
+		http.Error(w, "Failed to fetch data freshness", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"runs":      collectorRunViews(runs, now),
+		"failing":   failingSymbolViews(failing),
+		"freshness": freshnessViews(freshness, now),
+	}
+
+	if runParam := r.URL.Query().Get("run"); runParam != "" {
+		runID, err := uuid.Parse(runParam)
+		if err != nil {
+			http.Error(w, "Invalid run id", http.StatusBadRequest)
+			return
+		}
+		attempts, err := h.queries.ListCollectorRunSymbols(ctx, runID)
+		if err != nil {
+			h.logger.Error("Failed to fetch run attempts", slog.Any("error", err))
+			http.Error(w, "Failed to fetch run attempts", http.StatusInternalServerError)
+			return
+		}
+		response["run_id"] = runID.String()
+		response["attempts"] = attemptViews(attempts)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func collectorRunViews(runs []repository.CollectorRun, now time.Time) []CollectorRunView {
+	views := make([]CollectorRunView, 0, len(runs))
+	for _, run := range runs {
+		view := CollectorRunView{
+			ID:               run.ID.String(),
+			Trigger:          run.Trigger,
+			StartedAt:        run.StartedAt.Format(time.RFC3339),
+			SymbolsAttempted: run.SymbolsAttempted,
+			Successes:        run.Successes,
+			Failures:         run.Failures,
+		}
+		end := now
+		if run.FinishedAt.Valid {
+			end = run.FinishedAt.Time
+			view.FinishedAt = end.Format(time.RFC3339)
+		}
+		view.DurationSeconds = end.Sub(run.StartedAt).Seconds()
+		views = append(views, view)
+	}
+	return views
+}
+
+func attemptViews(attempts []repository.CollectorRunSymbol) []CollectorAttemptView {
+	views := make([]CollectorAttemptView, 0, len(attempts))
+	for _, a := range attempts {
+		views = append(views, CollectorAttemptView{
+			Symbol:     a.Symbol,
+			Tier:       a.Tier,
+			Status:     a.Status,
+			Error:      a.Error.String,
+			Provider:   a.Provider.String,
+			StartedAt:  a.StartedAt.Format(time.RFC3339),
+			DurationMs: a.DurationMs,
+		})
+	}
+	return views
+}
+
+func failingSymbolViews(rows []repository.GetFailingCollectorSymbolsRow) []FailingSymbolView {
+	views := make([]FailingSymbolView, 0, len(rows))
+	for _, row := range rows {
+		views = append(views, FailingSymbolView{
+			Symbol:        row.Symbol,
+			Status:        row.Status,
+			Error:         row.Error.String,
+			Provider:      row.Provider.String,
+			LastAttemptAt: row.LastAttemptAt.Format(time.RFC3339),
+			FailureCount:  row.FailureCount,
+		})
+	}
+	return views
+}
+
+func freshnessViews(rows []repository.GetGEXFreshnessRow, now time.Time) []SymbolFreshnessView {
+	views := make([]SymbolFreshnessView, 0, len(rows))
+	for _, row := range rows {
+		views = append(views, SymbolFreshnessView{
+			Symbol:         row.Symbol,
+			LastRecordedAt: row.LastRecordedAt.Format(time.RFC3339),
+			AgeMinutes:     now.Sub(row.LastRecordedAt).Minutes(),
+		})
+	}
+	return views
+}
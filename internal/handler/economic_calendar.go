@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -40,6 +41,62 @@ type ReleaseView struct {
 	ReleaseName string `json:"release_name"`
 	ReleaseDate string `json:"release_date"`
 	Impact      string `json:"impact"`
+
+	// ReleaseTime is "15:04" Eastern and ReleaseAt the full RFC 3339 time,
+	// both empty when the release has no fixed time.
+	ReleaseTime string `json:"release_time,omitempty"`
+	ReleaseAt   string `json:"release_at,omitempty"`
+
+	// SeriesID is the headline series, with values in Units (a FRED units
+	// code: "pch" is percent change, "chg" change, "lin" levels). Period is
+	// the observation date of Actual.
+	SeriesID        string   `json:"series_id,omitempty"`
+	Units           string   `json:"units,omitempty"`
+	Period          string   `json:"period,omitempty"`
+	Actual          *float64 `json:"actual"`
+	Prior           *float64 `json:"prior"`
+	Consensus       *float64 `json:"consensus"`
+	ConsensusSource string   `json:"consensus_source,omitempty"`
+	// Surprise is Actual minus Consensus, once both are known.
+	Surprise *float64 `json:"surprise"`
+}
+
+func newReleaseView(r repository.EconomicRelease, loc *time.Location) ReleaseView {
+	v := ReleaseView{
+		ID:              r.ID.String(),
+		ReleaseID:       r.ReleaseID,
+		ReleaseName:     r.ReleaseName,
+		ReleaseDate:     r.ReleaseDate.Time.Format("2006-01-02"),
+		Impact:          r.Impact,
+		SeriesID:        r.SeriesID.String,
+		Units:           r.Units.String,
+		Actual:          float8Ptr(r.Actual),
+		Prior:           float8Ptr(r.Prior),
+		Consensus:       float8Ptr(r.Consensus),
+		ConsensusSource: r.ConsensusSource.String,
+	}
+	if r.ReleaseTime.Valid {
+		d := r.ReleaseDate.Time
+		at := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc).
+			Add(time.Duration(r.ReleaseTime.Microseconds) * time.Microsecond)
+		v.ReleaseTime = at.Format("15:04")
+		v.ReleaseAt = at.Format(time.RFC3339)
+	}
+	if r.Period.Valid {
+		v.Period = r.Period.Time.Format("2006-01-02")
+	}
+	if v.Actual != nil && v.Consensus != nil {
+		surprise := *v.Actual - *v.Consensus
+		v.Surprise = &surprise
+	}
+	return v
+}
+
+func float8Ptr(f pgtype.Float8) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float64
 }
 
 func (h *EconomicCalendarHandler) GetThisWeek(w http.ResponseWriter, r *http.Request) {
@@ -53,15 +110,13 @@ func (h *EconomicCalendarHandler) GetThisWeek(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.UTC
+	}
 	views := make([]ReleaseView, 0, len(releases))
 	for _, r := range releases {
-		views = append(views, ReleaseView{
-			ID:          r.ID.String(),
-			ReleaseID:   r.ReleaseID,
-			ReleaseName: r.ReleaseName,
-			ReleaseDate: r.ReleaseDate.Time.Format("2006-01-02"),
-			Impact:      r.Impact,
-		})
+		views = append(views, newReleaseView(r, loc))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
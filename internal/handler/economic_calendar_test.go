@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestNewReleaseView(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	r := repository.EconomicRelease{
+		ReleaseID:   10,
+		ReleaseName: "Consumer Price Index",
+		ReleaseDate: pgtype.Date{Time: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), Valid: true},
+		Impact:      "High",
+		ReleaseTime: pgtype.Time{Microseconds: (8*3600 + 30*60) * 1e6, Valid: true},
+		SeriesID:    pgtype.Text{String: "CPIAUCSL", Valid: true},
+		Units:       pgtype.Text{String: "pch", Valid: true},
+		Period:      pgtype.Date{Time: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		Actual:      pgtype.Float8{Float64: 0.4, Valid: true},
+		Prior:       pgtype.Float8{Float64: 0.2, Valid: true},
+		Consensus:   pgtype.Float8{Float64: 0.3, Valid: true},
+	}
+
+	v := newReleaseView(r, loc)
+	if v.ReleaseTime != "08:30" || v.ReleaseAt != "2026-10-14T08:30:00-04:00" {
+		t.Errorf("release time = %q, %q", v.ReleaseTime, v.ReleaseAt)
+	}
+	if v.Period != "2026-09-01" || *v.Prior != 0.2 {
+		t.Errorf("view = %+v", v)
+	}
+	if v.Surprise == nil || *v.Surprise < 0.0999 || *v.Surprise > 0.1001 {
+		t.Errorf("surprise = %v, want 0.1", v.Surprise)
+	}
+
+	// Without a consensus there is no surprise, and no time without one set.
+	r.Consensus = pgtype.Float8{}
+	r.ReleaseTime = pgtype.Time{}
+	if v := newReleaseView(r, loc); v.Surprise != nil || v.ReleaseTime != "" || v.ReleaseAt != "" {
+		t.Errorf("view without consensus or time = %+v", v)
+	}
+}
+
+func TestParseConsensusCSV(t *testing.T) {
+	rows, err := parseConsensusCSV(strings.NewReader(`# consensus for CPI week
+Release_ID, Date, Consensus, Source
+10, 2026-10-14, 0.3%, Bloomberg
+46, 2026-10-15, ,
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if rows[0].ReleaseID != 10 || rows[0].ReleaseDate != "2026-10-14" || *rows[0].Value != 0.3 || rows[0].Source != "Bloomberg" {
+		t.Errorf("row 0 = %+v", rows[0])
+	}
+	if rows[1].Value != nil {
+		t.Errorf("blank consensus should clear, got %v", *rows[1].Value)
+	}
+
+	for _, bad := range []string{
+		"release_id,release_date\n10,2026-10-14\n",
+		"release_id,release_date,consensus\nCPI,2026-10-14,0.3\n",
+		"release_id,release_date,consensus\n10,10/14/2026,0.3\n",
+		"release_id,release_date,consensus\n10,2026-10-14,high\n",
+		"release_id,release_date,consensus\n",
+	} {
+		if _, err := parseConsensusCSV(strings.NewReader(bad)); err == nil {
+			t.Errorf("parseConsensusCSV(%q) succeeded", bad)
+		}
+	}
+}
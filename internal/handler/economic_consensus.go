@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Consensus is the expected value of a release, in its series' units. A nil
+// Value clears the stored consensus.
+type Consensus struct {
+	ReleaseID   int32    `json:"release_id"`
+	ReleaseDate string   `json:"release_date"`
+	Value       *float64 `json:"consensus"`
+	Source      string   `json:"source"`
+}
+
+func (c Consensus) date() (time.Time, error) {
+	d, err := time.Parse("2006-01-02", c.ReleaseDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid release date %q", c.ReleaseDate)
+	}
+	return d, nil
+}
+
+// parseConsensusCSV reads consensus values from a CSV with a header row. The
+// release_id, release_date (or date) and consensus columns are required;
+// source is optional. A blank consensus clears the stored value.
+func parseConsensusCSV(r io.Reader) ([]Consensus, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, field := range header {
+		name := strings.ToLower(strings.TrimSpace(field))
+		if name == "date" {
+			name = "release_date"
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"release_id", "release_date", "consensus"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv has no %s column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []Consensus
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		id, err := strconv.ParseInt(field(record, "release_id"), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid release id %q", line, field(record, "release_id"))
+		}
+		c := Consensus{
+			ReleaseID:   int32(id),
+			ReleaseDate: field(record, "release_date"),
+			Source:      field(record, "source"),
+		}
+		if _, err := c.date(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if raw := strings.TrimSuffix(field(record, "consensus"), "%"); raw != "" {
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid consensus %q", line, raw)
+			}
+			c.Value = &v
+		}
+		rows = append(rows, c)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no consensus values found in csv")
+	}
+	return rows, nil
+}
+
+// setConsensus stores c and reports whether its release exists.
+func (h *EconomicCalendarHandler) setConsensus(ctx context.Context, c Consensus) (bool, error) {
+	date, err := c.date()
+	if err != nil {
+		return false, err
+	}
+	params := repository.SetEconomicReleaseConsensusParams{
+		ReleaseID:       c.ReleaseID,
+		ReleaseDate:     pgtype.Date{Time: date, Valid: true},
+		ConsensusSource: pgtype.Text{String: c.Source, Valid: c.Source != ""},
+	}
+	if c.Value != nil {
+		params.Consensus = pgtype.Float8{Float64: *c.Value, Valid: true}
+	}
+	n, err := h.queries.SetEconomicReleaseConsensus(ctx, params)
+	return n > 0, err
+}
+
+// SetConsensus stores the consensus of one release from a JSON body such as
+// {"release_id": 10, "release_date": "2026-10-14", "consensus": 0.3,
+// "source": "Bloomberg"}. A null consensus clears it.
+func (h *EconomicCalendarHandler) SetConsensus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var c Consensus
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&c); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if _, err := c.date(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	found, err := h.setConsensus(r.Context(), c)
+	if err != nil {
+		h.logger.Error("Failed to store consensus", slog.Int("release_id", int(c.ReleaseID)), slog.Any("error", err))
+		http.Error(w, "Failed to store consensus", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "Unknown release", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c)
+}
+
+// ImportConsensus stores consensus values from a CSV request body, or from
+// the "file" field of a multipart form. See parseConsensusCSV for the
+// columns. Rows for releases that aren't on the calendar are returned as
+// unmatched.
+func (h *EconomicCalendarHandler) ImportConsensus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Missing file field", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	rows, err := parseConsensusCSV(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	imported := 0
+	unmatched := make([]Consensus, 0)
+	for _, c := range rows {
+		found, err := h.setConsensus(r.Context(), c)
+		if err != nil {
+			h.logger.Error("Consensus import failed", slog.Int("release_id", int(c.ReleaseID)), slog.Any("error", err))
+			http.Error(w, "Failed to import consensus", http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			unmatched = append(unmatched, c)
+			continue
+		}
+		imported++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"imported":  imported,
+		"unmatched": unmatched,
+	})
+}
@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/eth"
+)
+
+// EthHandler serves the Ethereum transaction lookup at /eth-tx, the address
+// and block pages under /eth/ and their JSON APIs.
+type EthHandler struct {
+	logger  *slog.Logger
+	tmpl    *template.Template
+	clients map[string]*eth.Client
+	// networks are the configured ones, in the order of eth.Networks.
+	networks []eth.Network
+}
+
+// NewEthHandler returns the handler for the networks of clients. Lookups on
+// a network without a client fail with 503.
+func NewEthHandler(logger *slog.Logger, tmpl *template.Template, clients ...*eth.Client) *EthHandler {
+	h := &EthHandler{
+		logger:  logger,
+		tmpl:    tmpl,
+		clients: make(map[string]*eth.Client),
+	}
+	for _, c := range clients {
+		h.clients[c.Network().Name] = c
+	}
+	for _, n := range eth.Networks {
+		if h.clients[n.Name] != nil {
+			h.networks = append(h.networks, n)
+		}
+	}
+	return h
+}
+
+type ethTxPage struct {
+	TxHash   string
+	Network  string
+	Networks []eth.Network
+	Details  *eth.TxDetails
+	Error    string
+}
+
+type ethAddressPage struct {
+	Query    string
+	Network  string
+	Networks []eth.Network
+	Details  *eth.AddressDetails
+	Error    string
+}
+
+type ethBlockPage struct {
+	Query    string
+	Network  string
+	Networks []eth.Network
+	Details  *eth.BlockDetails
+	Error    string
+}
+
+// ServeHTTP renders the lookup page, with the transaction of ?hash= on
+// ?network= when given. The form posts txhash and network and gets the
+// "transaction-details" fragment back, with any error in it so htmx swaps
+// it in.
+func (h *EthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		page := h.page(r.URL.Query().Get("network"), r.URL.Query().Get("hash"))
+		if page.TxHash != "" {
+			page.Details, _, page.Error = h.lookup(r.Context(), page.Network, page.TxHash)
+		}
+		h.render(w, "eth.html", page)
+	case http.MethodPost:
+		page := h.page(r.FormValue("network"), r.FormValue("txhash"))
+		if target := searchTarget(page.Network, page.TxHash); target != "" {
+			w.Header().Set("HX-Redirect", target)
+			return
+		}
+		page.Details, _, page.Error = h.lookup(r.Context(), page.Network, page.TxHash)
+		h.render(w, "transaction-details", page)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetTransaction returns the transaction of ?hash= as JSON.
+func (h *EthHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	details, status, msg := h.lookup(r.Context(), networkOrMainnet(q.Get("network")), strings.TrimSpace(q.Get("hash")))
+	if details == nil {
+		http.Error(w, msg, status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(details)
+}
+
+// searchTarget is the page for a search that isn't a transaction hash: an
+// address, an ENS name or a block number. It is empty otherwise.
+func searchTarget(network, q string) string {
+	var path string
+	switch {
+	case eth.ValidHash(q):
+		return ""
+	case eth.ValidAddress(q) || eth.IsENSName(q):
+		path = "/eth/address/"
+	case eth.ValidBlockID(q):
+		path = "/eth/block/"
+	default:
+		return ""
+	}
+	return path + url.PathEscape(q) + "?network=" + url.QueryEscape(network)
+}
+
+// Search redirects the search box of the address and block pages, ?q= on
+// ?network=, to the page for what was entered; hashes and anything
+// unrecognized go to /eth-tx, which explains what it accepts.
+func (h *EthHandler) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	network := networkOrMainnet(r.URL.Query().Get("network"))
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	target := searchTarget(network, q)
+	if target == "" {
+		target = "/eth-tx?network=" + url.QueryEscape(network) + "&hash=" + url.QueryEscape(q)
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// Address renders /eth/address/<address or ENS name>?network=.
+func (h *EthHandler) Address(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	page := ethAddressPage{
+		Query:    strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/eth/address/")),
+		Network:  networkOrMainnet(r.URL.Query().Get("network")),
+		Networks: h.networks,
+	}
+	status := http.StatusOK
+	if page.Query != "" {
+		page.Details, status, page.Error = h.lookupAddress(r.Context(), page.Network, page.Query)
+	}
+	h.renderStatus(w, "eth_address.html", status, page)
+}
+
+// GetAddress returns the address or ENS name of ?address= as JSON.
+func (h *EthHandler) GetAddress(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	details, status, msg := h.lookupAddress(r.Context(), networkOrMainnet(q.Get("network")), strings.TrimSpace(q.Get("address")))
+	if details == nil {
+		http.Error(w, msg, status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(details)
+}
+
+// Block renders /eth/block/<number, hash or latest>?network=.
+func (h *EthHandler) Block(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	page := ethBlockPage{
+		Query:    strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/eth/block/")),
+		Network:  networkOrMainnet(r.URL.Query().Get("network")),
+		Networks: h.networks,
+	}
+	status := http.StatusOK
+	if page.Query != "" {
+		page.Details, status, page.Error = h.lookupBlock(r.Context(), page.Network, page.Query)
+	}
+	h.renderStatus(w, "eth_block.html", status, page)
+}
+
+// GetBlock returns the block of ?block= as JSON.
+func (h *EthHandler) GetBlock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	details, status, msg := h.lookupBlock(r.Context(), networkOrMainnet(q.Get("network")), strings.TrimSpace(q.Get("block")))
+	if details == nil {
+		http.Error(w, msg, status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(details)
+}
+
+func (h *EthHandler) page(network, hash string) ethTxPage {
+	return ethTxPage{
+		TxHash:   strings.TrimSpace(hash),
+		Network:  networkOrMainnet(network),
+		Networks: h.networks,
+	}
+}
+
+func networkOrMainnet(name string) string {
+	if name = strings.TrimSpace(name); name != "" {
+		return name
+	}
+	return eth.Mainnet.Name
+}
+
+// lookup returns the transaction, or the status and message of why there is
+// none.
+func (h *EthHandler) lookup(ctx context.Context, network, hash string) (*eth.TxDetails, int, string) {
+	n, ok := eth.NetworkByName(network)
+	if !ok {
+		return nil, http.StatusBadRequest, "Unknown network " + network
+	}
+	if !eth.ValidHash(hash) {
+		return nil, http.StatusBadRequest, "Enter a transaction hash: 0x followed by 64 hex digits"
+	}
+	client := h.clients[n.Name]
+	if client == nil {
+		return nil, http.StatusServiceUnavailable, "No RPC endpoint configured for " + n.Label
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	details, err := client.Lookup(ctx, hash)
+	if err != nil {
+		status, msg := h.failure(err, n, "Transaction", hash)
+		return nil, status, msg
+	}
+	return details, http.StatusOK, ""
+}
+
+// lookupAddress is lookup for an address or ENS name.
+func (h *EthHandler) lookupAddress(ctx context.Context, network, query string) (*eth.AddressDetails, int, string) {
+	n, ok := eth.NetworkByName(network)
+	if !ok {
+		return nil, http.StatusBadRequest, "Unknown network " + network
+	}
+	if !eth.ValidAddress(query) && !eth.IsENSName(query) {
+		return nil, http.StatusBadRequest, "Enter an address (0x followed by 40 hex digits) or an ENS name"
+	}
+	client := h.clients[n.Name]
+	if client == nil {
+		return nil, http.StatusServiceUnavailable, "No RPC endpoint configured for " + n.Label
+	}
+
+	// Scanning the latest blocks takes a few dozen calls.
+	ctx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
+	details, err := client.Address(ctx, query)
+	if err != nil {
+		status, msg := h.failure(err, n, "Address", query)
+		return nil, status, msg
+	}
+	return details, http.StatusOK, ""
+}
+
+// lookupBlock is lookup for a block number, hash or "latest".
+func (h *EthHandler) lookupBlock(ctx context.Context, network, id string) (*eth.BlockDetails, int, string) {
+	n, ok := eth.NetworkByName(network)
+	if !ok {
+		return nil, http.StatusBadRequest, "Unknown network " + network
+	}
+	if !eth.ValidBlockID(id) {
+		return nil, http.StatusBadRequest, "Enter a block number, a block hash or latest"
+	}
+	client := h.clients[n.Name]
+	if client == nil {
+		return nil, http.StatusServiceUnavailable, "No RPC endpoint configured for " + n.Label
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	details, err := client.Block(ctx, id)
+	if err != nil {
+		status, msg := h.failure(err, n, "Block", id)
+		return nil, status, msg
+	}
+	return details, http.StatusOK, ""
+}
+
+// failure returns the status and message for a failed lookup of what.
+func (h *EthHandler) failure(err error, n eth.Network, what, id string) (int, string) {
+	switch {
+	case errors.Is(err, eth.ErrNotFound):
+		return http.StatusNotFound, what + " not found on " + n.Label
+	case errors.Is(err, eth.ErrNoENS):
+		return http.StatusBadRequest, "ENS names can't be resolved on " + n.Label
+	}
+	h.logger.Error("Failed to fetch "+strings.ToLower(what), slog.String("network", n.Name), slog.String("id", id), slog.Any("error", err))
+	return http.StatusBadGateway, "Failed to fetch " + strings.ToLower(what) + " details"
+}
+
+func (h *EthHandler) render(w http.ResponseWriter, name string, page ethTxPage) {
+	h.renderStatus(w, name, http.StatusOK, page)
+}
+
+// renderStatus renders the page with status; the template is rendered to a
+// buffer first so a failure can still become a 500.
+func (h *EthHandler) renderStatus(w http.ResponseWriter, name string, status int, page interface{}) {
+	var buf bytes.Buffer
+	if err := h.tmpl.ExecuteTemplate(&buf, name, page); err != nil {
+		h.logger.Error("Failed to render Ethereum page", slog.String("template", name), slog.Any("error", err))
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
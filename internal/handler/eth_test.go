@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/eth"
+)
+
+func TestEthTemplateRenders(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+	h := NewEthHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), tmpl)
+
+	price, fee := 2500.0, 0.945
+	d := &eth.TxDetails{
+		Hash:        "0x" + strings.Repeat("ab", 32),
+		Network:     "base",
+		ExplorerURL: "https://basescan.org/tx/0x" + strings.Repeat("ab", 32),
+		Status:      eth.StatusSuccess,
+		BlockNumber: 18268567,
+		From:        "0x00000000000000000000000000000000000a11ce",
+		To:          "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		Value:       "0",
+		GasLimit:    60000,
+		GasUsed:     50000,
+		GasPrice:    "18",
+		Fee:         "0.0009",
+		FeeUSD:      &fee,
+		ETHUSD:      &price,
+		Call: &eth.Call{Selector: "0xa9059cbb", Name: "transfer", Signature: "transfer(address,uint256)", Args: []eth.Arg{
+			{Name: "to", Type: "address", Value: "0x0000000000000000000000000000000000000b0b"},
+			{Name: "amount", Type: "uint256", Value: "2500000"},
+		}},
+		Transfers: []eth.Transfer{{Standard: "ERC-20", Token: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Amount: "2.5"}},
+	}
+
+	rec := httptest.NewRecorder()
+	h.render(rec, "transaction-details", ethTxPage{TxHash: d.Hash, Details: d})
+	out := rec.Body.String()
+	for _, want := range []string{"18268567", "transfer(address,uint256)", "2500000", "2.5 USDC", "$0.95", "/api/eth/tx?network=base&hash=" + d.Hash, "https://basescan.org/tx/"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("fragment is missing %q", want)
+		}
+	}
+}
+
+func TestEthLookupErrors(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+	h := NewEthHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), tmpl)
+
+	hash := "0x" + strings.Repeat("ab", 32)
+	tests := []struct {
+		network string
+		hash    string
+		status  int
+	}{
+		{"", "0x1234", http.StatusBadRequest},
+		{"", hash, http.StatusServiceUnavailable},
+		{"sepolia", hash, http.StatusServiceUnavailable},
+		{"dogechain", hash, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		target := "/api/eth/tx?network=" + tt.network + "&hash=" + url.QueryEscape(tt.hash)
+		h.GetTransaction(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: status %d, want %d", tt.network, tt.hash, rec.Code, tt.status)
+		}
+	}
+
+	// The form gets the error back inside the fragment.
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/eth-tx", strings.NewReader("txhash=nope"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "64 hex digits") {
+		t.Errorf("form error: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestEthExplorerTemplatesRender(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+	h := NewEthHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), tmpl)
+
+	alice := "0x00000000000000000000000000000000000a11ce"
+	hash := "0x" + strings.Repeat("ab", 32)
+	price, usd := 2500.0, 3750.0
+	rec := httptest.NewRecorder()
+	h.renderStatus(rec, "eth_address.html", http.StatusOK, ethAddressPage{
+		Query:   "alice.eth",
+		Network: "mainnet",
+		Details: &eth.AddressDetails{
+			Network: "mainnet", Address: alice, Name: "alice.eth", Balance: "1.5", BalanceUSD: &usd, ETHUSD: &price, Nonce: 7,
+			Tokens:       []eth.TokenBalance{{Symbol: "USDC", Balance: "2.5"}},
+			Transactions: []eth.TxSummary{{Hash: hash, Block: 100, From: alice, To: "0xb0b", Value: "0.5", Method: "transfer"}},
+			ScannedFrom:  81, ScannedTo: 100,
+			Transfers: []eth.Transfer{{Standard: "ERC-20", Symbol: "USDC", Amount: "2.5", From: alice, To: "0xb0b", Block: 99, TxHash: hash}},
+			Warnings:  []string{"ENS name"},
+		},
+	})
+	out := rec.Body.String()
+	for _, want := range []string{"alice.eth", "1.5 ETH", "$3,750.00", "2.5 <span class=\"text-gray-400\">USDC", "Out", "Blocks 81 to 100", "2.5 USDC", "Couldn't load: ENS name.", "/eth/block/99?network=mainnet"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("address page is missing %q", want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.renderStatus(rec, "eth_block.html", http.StatusOK, ethBlockPage{
+		Query:   "100",
+		Network: "mainnet",
+		Details: &eth.BlockDetails{
+			Network: "mainnet", Number: 100, Hash: hash, ParentHash: hash, Time: time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC),
+			GasUsed: 15000000, GasLimit: 30000000, BaseFee: "12.5", BurntFees: "0.1875",
+			Transactions: []eth.TxSummary{{Hash: hash, From: alice, Value: "1"}},
+		},
+	})
+	out = rec.Body.String()
+	for _, want := range []string{"Block 100", "2023-11-14 22:13:20 UTC", "(50.0%)", "12.5 gwei", "0.1875 ETH", "Contract creation"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("block page is missing %q", want)
+		}
+	}
+}
+
+func TestEthSearch(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+	h := NewEthHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), tmpl)
+
+	hash := "0x" + strings.Repeat("ab", 32)
+	tests := []struct {
+		q    string
+		want string
+	}{
+		{"vitalik.eth", "/eth/address/vitalik.eth?network=base"},
+		{"0x00000000000000000000000000000000000a11ce", "/eth/address/0x00000000000000000000000000000000000a11ce?network=base"},
+		{"18000000", "/eth/block/18000000?network=base"},
+		{hash, "/eth-tx?network=base&hash=" + hash},
+		{"nonsense", "/eth-tx?network=base&hash=nonsense"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.Search(rec, httptest.NewRequest(http.MethodGet, "/eth/search?network=base&q="+url.QueryEscape(tt.q), nil))
+		if got := rec.Header().Get("Location"); rec.Code != http.StatusSeeOther || got != tt.want {
+			t.Errorf("%s: %d to %s, want %s", tt.q, rec.Code, got, tt.want)
+		}
+	}
+
+	// The pages keep their status on errors.
+	for target, status := range map[string]int{
+		"/eth/address/":                  http.StatusOK,
+		"/eth/address/nope":              http.StatusBadRequest,
+		"/eth/address/vitalik.eth":       http.StatusServiceUnavailable,
+		"/eth/block/latest?network=doge": http.StatusBadRequest,
+		"/eth/block/12":                  http.StatusServiceUnavailable,
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if strings.HasPrefix(target, "/eth/address/") {
+			h.Address(rec, req)
+		} else {
+			h.Block(rec, req)
+		}
+		if rec.Code != status {
+			t.Errorf("%s: status %d, want %d", target, rec.Code, status)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/events"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+)
+
+const (
+	defaultEventDays = 30
+	maxEventDays     = 180
+)
+
+// EventCalendarHandler serves the unified event calendar: economic releases,
+// FOMC decisions, option and VIX expirations and earnings.
+type EventCalendarHandler struct {
+	logger   *slog.Logger
+	tmpl     *template.Template
+	calendar *events.Calendar
+}
+
+func NewEventCalendarHandler(logger *slog.Logger, tmpl *template.Template, calendar *events.Calendar) *EventCalendarHandler {
+	return &EventCalendarHandler{
+		logger:   logger,
+		tmpl:     tmpl,
+		calendar: calendar,
+	}
+}
+
+// EventList is a filtered window of the calendar.
+type EventList struct {
+	From   string         `json:"from"`
+	To     string         `json:"to"`
+	Symbol string         `json:"symbol,omitempty"`
+	Kinds  []events.Kind  `json:"kinds,omitempty"`
+	Events []events.Event `json:"events"`
+	Count  int            `json:"count"`
+}
+
+// Selected reports whether the list was filtered to kind.
+func (l *EventList) Selected(kind events.Kind) bool {
+	for _, k := range l.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// eventList reads the calendar from ?from= (default today) through ?to=, or
+// for ?days= days, keeping the events that affect ?symbol= and whose kind is
+// in ?kind= (repeated or comma-separated).
+func (h *EventCalendarHandler) eventList(w http.ResponseWriter, r *http.Request) (*EventList, bool) {
+	q := r.URL.Query()
+
+	from := time.Now().In(events.NewYork)
+	if s := q.Get("from"); s != "" {
+		d, err := time.ParseInLocation("2006-01-02", s, events.NewYork)
+		if err != nil {
+			http.Error(w, "Invalid from date, use YYYY-MM-DD", http.StatusBadRequest)
+			return nil, false
+		}
+		from = d
+	}
+	days := defaultEventDays
+	if s := q.Get("days"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxEventDays {
+			http.Error(w, "days must be between 1 and "+strconv.Itoa(maxEventDays), http.StatusBadRequest)
+			return nil, false
+		}
+		days = n
+	}
+	to := from.AddDate(0, 0, days)
+	if s := q.Get("to"); s != "" {
+		d, err := time.ParseInLocation("2006-01-02", s, events.NewYork)
+		if err != nil || d.Before(from) || d.After(from.AddDate(0, 0, maxEventDays)) {
+			http.Error(w, "Invalid to date", http.StatusBadRequest)
+			return nil, false
+		}
+		to = d
+	}
+
+	symbol := strings.ToUpper(strings.TrimSpace(q.Get("symbol")))
+	if symbol != "" && !universe.ValidSymbol(symbol) {
+		http.Error(w, "Invalid symbol", http.StatusBadRequest)
+		return nil, false
+	}
+	var kinds []events.Kind
+	for _, k := range strings.Split(strings.Join(q["kind"], ","), ",") {
+		if k = strings.TrimSpace(k); k == "" {
+			continue
+		}
+		kind := events.Kind(k)
+		if !kind.Valid() {
+			http.Error(w, "Unknown event kind "+k, http.StatusBadRequest)
+			return nil, false
+		}
+		kinds = append(kinds, kind)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	all, err := h.calendar.Between(ctx, from, to)
+	if err != nil {
+		h.logger.Error("Failed to load event calendar", slog.Any("error", err))
+		http.Error(w, "Failed to load events", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	list := events.Filter(all, symbol, kinds)
+	return &EventList{
+		From:   from.Format("2006-01-02"),
+		To:     to.Format("2006-01-02"),
+		Symbol: symbol,
+		Kinds:  kinds,
+		Events: list,
+		Count:  len(list),
+	}, true
+}
+
+// GetEvents returns the calendar as JSON; see eventList for the parameters.
+func (h *EventCalendarHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
+	list, ok := h.eventList(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func (h *EventCalendarHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	list, ok := h.eventList(w, r)
+	if !ok {
+		return
+	}
+	err := h.tmpl.ExecuteTemplate(w, "event_calendar.html", map[string]interface{}{
+		"List":  list,
+		"Kinds": events.Kinds,
+		"Days":  r.URL.Query().Get("days"),
+	})
+	if err != nil {
+		h.logger.Error("Failed to render event calendar", slog.Any("error", err))
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// ImportEarnings stores earnings dates from a CSV request body, or from the
+// "file" field of a multipart form. See events.ParseEarningsCSV for the
+// columns.
+func (h *EventCalendarHandler) ImportEarnings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Missing file field", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	rows, err := events.ParseEarningsCSV(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.calendar.ImportEarnings(r.Context(), rows)
+	if err != nil {
+		h.logger.Error("Earnings import failed", slog.Any("error", err))
+		http.Error(w, "Failed to import earnings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"imported": n,
+	})
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/events"
+)
+
+func TestEventCalendarTemplateRenders(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, events.NewYork)
+	list := &EventList{
+		From:   "2026-10-01",
+		To:     "2026-10-31",
+		Symbol: "UVXY",
+		Kinds:  []events.Kind{events.KindVIX},
+		Events: events.Filter(events.Scheduled(from, from.AddDate(0, 0, 30)), "UVXY", []events.Kind{events.KindVIX}),
+	}
+	list.Count = len(list.Events)
+
+	var buf bytes.Buffer
+	err := tmpl.ExecuteTemplate(&buf, "event_calendar.html", map[string]interface{}{
+		"List":  list,
+		"Kinds": events.Kinds,
+		"Days":  "30",
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"1 events affecting UVXY", "VIX Oct expiration", "Wed, Oct 21", `value="vix_expiration" checked`, "badge-vix_expiration"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+}
+
+func TestGEXChartShowsEventsBeforeExpiry(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+
+	from := time.Date(2026, 10, 19, 0, 0, 0, 0, events.NewYork)
+	var buf bytes.Buffer
+	err := tmpl.ExecuteTemplate(&buf, "gex_chart.html", map[string]interface{}{
+		"Symbol":            "SPY",
+		"Expiration":        "2026-10-30",
+		"SpotPrice":         660.0,
+		"ChartData":         []StrikeGEX{{660, 1e6}},
+		"GammaFlipLevel":    650.0,
+		"TotalGEX":          1e6,
+		"TotalGEXFormatted": formatCurrency(1e6),
+		"Events":            events.Filter(events.Scheduled(from, from.AddDate(0, 0, 11)), "SPY", nil),
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Events Before This Expiry", "FOMC rate decision", "Wed Oct 28, 14:00 ET"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("chart is missing %q", want)
+		}
+	}
+	// The VIX expiration on the 21st falls in the window but doesn't affect SPY.
+	if strings.Contains(out, "VIX Oct expiration") {
+		t.Error("chart lists the VIX expiration for SPY")
+	}
+}
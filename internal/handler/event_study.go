@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/eventstudy"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+)
+
+const (
+	defaultStudySymbol = "SPY"
+	defaultStudyDays   = 365
+	maxStudyDays       = 5 * 365
+)
+
+// EventStudyHandler serves the GEX event studies around economic releases
+// shown on the economic calendar.
+type EventStudyHandler struct {
+	logger   *slog.Logger
+	analyzer *eventstudy.Analyzer
+}
+
+func NewEventStudyHandler(logger *slog.Logger, analyzer *eventstudy.Analyzer) *EventStudyHandler {
+	return &EventStudyHandler{
+		logger:   logger,
+		analyzer: analyzer,
+	}
+}
+
+// GetStudies returns the event studies as JSON, or as CSV with ?format=csv.
+// ?symbol= defaults to SPY, ?impact= to High, ?days= of releases to a year
+// and ?window= to three trading days either side; ?release_id= limits the
+// study to one release type.
+func (h *EventStudyHandler) GetStudies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q, errMsg := parseStudyQuery(r, time.Now())
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	report, err := h.analyzer.Run(ctx, q)
+	if err != nil {
+		h.logger.Error("Failed to run event studies", slog.String("symbol", q.Symbol), slog.Any("error", err))
+		http.Error(w, "Failed to run event studies", http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="event-studies-`+strings.ToLower(q.Symbol)+`.csv"`)
+		if err := eventstudy.WriteCSV(w, report); err != nil {
+			h.logger.Error("Failed to write event studies", slog.Any("error", err))
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseStudyQuery reads the study parameters, returning a message for the
+// first invalid one.
+func parseStudyQuery(r *http.Request, now time.Time) (eventstudy.Query, string) {
+	v := r.URL.Query()
+	q := eventstudy.Query{
+		Symbol: strings.ToUpper(strings.TrimSpace(v.Get("symbol"))),
+		Window: eventstudy.DefaultWindow,
+	}
+	if q.Symbol == "" {
+		q.Symbol = defaultStudySymbol
+	}
+	if !universe.ValidSymbol(q.Symbol) {
+		return q, "invalid symbol"
+	}
+
+	switch strings.ToLower(v.Get("impact")) {
+	case "", "high":
+		q.Impact = "High"
+	case "medium":
+		q.Impact = "Medium"
+	case "low":
+		q.Impact = "Low"
+	default:
+		return q, "impact must be High, Medium or Low"
+	}
+
+	if s := v.Get("release_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || id <= 0 {
+			return q, "invalid release_id"
+		}
+		q.ReleaseID = int32(id)
+	}
+
+	days := defaultStudyDays
+	if s := v.Get("days"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxStudyDays {
+			return q, "days must be between 1 and " + strconv.Itoa(maxStudyDays)
+		}
+		days = n
+	}
+	q.From = now.AddDate(0, 0, -days)
+
+	if s := v.Get("window"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > eventstudy.MaxWindow {
+			return q, "window must be between 1 and " + strconv.Itoa(eventstudy.MaxWindow)
+		}
+		q.Window = n
+	}
+	return q, ""
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseStudyQuery(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	q, msg := parseStudyQuery(httptest.NewRequest("GET", "/api/economic-calendar/studies", nil), now)
+	if msg != "" {
+		t.Fatalf("defaults rejected: %s", msg)
+	}
+	if q.Symbol != "SPY" || q.Impact != "High" || q.ReleaseID != 0 || q.Window != 3 || !q.From.Equal(now.AddDate(0, 0, -365)) {
+		t.Errorf("defaults = %+v", q)
+	}
+
+	q, msg = parseStudyQuery(httptest.NewRequest("GET", "/api/economic-calendar/studies?symbol=qqq&impact=medium&release_id=10&days=90&window=5", nil), now)
+	if msg != "" {
+		t.Fatalf("valid query rejected: %s", msg)
+	}
+	if q.Symbol != "QQQ" || q.Impact != "Medium" || q.ReleaseID != 10 || q.Window != 5 || !q.From.Equal(now.AddDate(0, 0, -90)) {
+		t.Errorf("query = %+v", q)
+	}
+
+	for _, query := range []string{
+		"symbol=not-a-symbol",
+		"impact=extreme",
+		"release_id=-1",
+		"days=0",
+		"days=5000",
+		"window=11",
+	} {
+		if _, msg := parseStudyQuery(httptest.NewRequest("GET", "/api/economic-calendar/studies?"+query, nil), now); msg == "" {
+			t.Errorf("%s accepted", query)
+		}
+	}
+}
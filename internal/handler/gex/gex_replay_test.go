@@ -0,0 +1,92 @@
+package gex
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/arnabmitra/eth-proxy/internal/httpreplay"
+	"github.com/arnabmitra/eth-proxy/internal/outbound"
+)
+
+// replayAlpaca routes provider traffic through the named cassette and turns
+// Public.com off so the Alpaca path is the one exercised.
+func replayAlpaca(t *testing.T, cassette string) (*httpreplay.Recorder, string, string) {
+	t.Helper()
+	t.Setenv("PUBLIC_PERSONAL_SECRET", "")
+	t.Setenv("PUBLIC_SECRET_KEY", "")
+	t.Setenv("ALPACA_API_BASE_URL", "")
+
+	rec := httpreplay.ForTest(t, cassette)
+	t.Cleanup(outbound.SetBaseTransport(rec))
+
+	key, secret := GetAlpacaConfig()
+	if key == "" {
+		key, secret = "test-key", "test-secret"
+	}
+	return rec, key, secret
+}
+
+func TestFetchOptionsChainReplay(t *testing.T) {
+	_, key, secret := replayAlpaca(t, "alpaca_spy_chain")
+	ctx := context.Background()
+
+	spot, err := GetSpotPrice(ctx, key, secret, "SPY")
+	if err != nil {
+		t.Fatalf("GetSpotPrice: %v", err)
+	}
+	if spot <= 0 {
+		t.Fatalf("spot = %v", spot)
+	}
+
+	options, body, _, err := FetchOptionsChain(ctx, "SPY", "2026-10-23", key, secret)
+	if err != nil {
+		t.Fatalf("FetchOptionsChain: %v", err)
+	}
+	if len(options) == 0 {
+		t.Fatal("expected options from the recorded chain")
+	}
+
+	var resp Response
+	if err := json.Unmarshal([]byte(*body), &resp); err != nil {
+		t.Fatalf("decode stored chain: %v", err)
+	}
+	if resp.Provider != "alpaca" {
+		t.Errorf("provider = %q, want alpaca", resp.Provider)
+	}
+
+	withGamma := 0
+	for _, o := range options {
+		if o.ExpirationDate != "2026-10-23" {
+			t.Fatalf("option %+v has the wrong expiry", o)
+		}
+		if o.Greeks.Gamma > 0 {
+			withGamma++
+		}
+	}
+	if withGamma != len(options) {
+		t.Errorf("%d of %d options have gamma", withGamma, len(options))
+	}
+	if TotalGEX(CalculateGEXPerStrike(options, spot)) == 0 {
+		t.Error("expected non-zero total GEX")
+	}
+}
+
+func TestGetExpirationDatesReplay(t *testing.T) {
+	rec, key, secret := replayAlpaca(t, "alpaca_spy_expirations")
+	// The lower bound is today's date, which differs from the recording.
+	rec.IgnoreParams = []string{"expiration_date_gte"}
+
+	dates, err := GetExpirationDates(context.Background(), key, secret, "SPY")
+	if err != nil {
+		t.Fatalf("GetExpirationDates: %v", err)
+	}
+	if len(dates) == 0 {
+		t.Fatal("expected expiration dates")
+	}
+	for i := 1; i < len(dates); i++ {
+		if dates[i] <= dates[i-1] {
+			t.Fatalf("dates not sorted and unique: %v", dates)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package gex
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"gonum.org/v1/plot"
@@ -13,6 +14,7 @@ import (
 	"github.com/alpacahq/alpaca-trade-api-go/v3/alpaca"
 	"github.com/alpacahq/alpaca-trade-api-go/v3/marketdata"
 	"cloud.google.com/go/civil"
+	"github.com/arnabmitra/eth-proxy/internal/outbound"
 	"github.com/arnabmitra/eth-proxy/internal/public"
 	"os"
 	"strings"
@@ -144,6 +146,51 @@ func GetPublicConfig() (string, string) {
 	return secret, accountID
 }
 
+// publicClient returns the shared Public.com session, or nil when no
+// Public.com secret is configured. The session caches its token and account
+// ID, so callers no longer authenticate per request.
+func publicClient() *public.Client {
+	secret, accountID := GetPublicConfig()
+	if secret == "" {
+		return nil
+	}
+	return public.Shared(secret, accountID)
+}
+
+// alpacaURL returns the Alpaca endpoint named by env, or def. The overrides
+// let the app run against cmd/fakemarket.
+func alpacaURL(env, def string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	return def
+}
+
+// newMarketDataClient returns an Alpaca market data client whose requests go
+// through the shared "alpaca" outbound limiter. The SDK's own 429 retry loop
+// is disabled so retries aren't stacked on top of each other. The SDK builds
+// requests without a context, so ctx is applied at the transport instead.
+func newMarketDataClient(ctx context.Context, apiKey, apiSecret string) *marketdata.Client {
+	return marketdata.NewClient(marketdata.ClientOpts{
+		APIKey:     apiKey,
+		APISecret:  apiSecret,
+		BaseURL:    alpacaURL("ALPACA_DATA_BASE_URL", "https://data.alpaca.markets"),
+		RetryLimit: -1,
+		HTTPClient: outbound.WithContext(ctx, outbound.HTTPClient(outbound.For("alpaca"), 10*time.Second)),
+	})
+}
+
+// newTradingClient is the trading API counterpart of newMarketDataClient.
+func newTradingClient(ctx context.Context, apiKey, apiSecret, baseURL string) *alpaca.Client {
+	return alpaca.NewClient(alpaca.ClientOpts{
+		APIKey:     apiKey,
+		APISecret:  apiSecret,
+		BaseURL:    baseURL,
+		RetryLimit: -1,
+		HTTPClient: outbound.WithContext(ctx, outbound.HTTPClient(outbound.For("alpaca"), 10*time.Second)),
+	})
+}
+
 // Option represents an individual option in the chain
 type Option struct {
 	Strike       float64 `json:"strike"`
@@ -162,40 +209,31 @@ type Response struct {
 		Option []Option `json:"option"`
 	} `json:"options"`
 	Warning string `json:"warning,omitempty"`
+	// Provider records which data source served the chain ("public", "alpaca" or "alpaca-paper").
+	Provider string `json:"provider,omitempty"`
 }
 
-func GetSpotPrice(apiKey, apiSecret, symbol string) (float64, error) {
+func GetSpotPrice(ctx context.Context, apiKey, apiSecret, symbol string) (float64, error) {
 	// Try Public.com first if secret is available
-	publicSecret, publicAccountID := GetPublicConfig()
-	if publicSecret != "" {
+	if client := publicClient(); client != nil {
 		fmt.Printf("Attempting to fetch spot price from Public.com for %s...\n", symbol)
-		client := public.NewClient(publicSecret, publicAccountID)
-		if err := client.Authenticate(); err == nil {
-			if err := client.FetchAccountID(); err == nil {
-				price, err := client.GetSpotPrice(symbol)
-				if err == nil {
-					fmt.Printf("Successfully fetched spot price from Public.com: %.2f\n", price)
-					return price, nil
-				}
-				fmt.Printf("Public.com spot price fetch failed: %v\n", err)
-			} else {
-				fmt.Printf("Public.com account ID fetch failed: %v\n", err)
-			}
-		} else {
-			fmt.Printf("Public.com authentication failed: %v\n", err)
+		price, err := client.GetSpotPrice(ctx, symbol)
+		if err == nil {
+			fmt.Printf("Successfully fetched spot price from Public.com: %.2f\n", price)
+			return price, nil
 		}
+		if ctx.Err() != nil {
+			return 0, err
+		}
+		fmt.Printf("Public.com spot price fetch failed: %v\n", err)
 		fmt.Println("Falling back to Alpaca for spot price.")
 	}
 
-	mdClient := marketdata.NewClient(marketdata.ClientOpts{
-		APIKey:    apiKey,
-		APISecret: apiSecret,
-		BaseURL:   "https://data.alpaca.markets",
-	})
+	mdClient := newMarketDataClient(ctx, apiKey, apiSecret)
 
 	snapshot, err := mdClient.GetSnapshot(symbol, marketdata.GetSnapshotRequest{})
 	if err != nil {
-		return 0, fmt.Errorf("error getting snapshot: %v", err)
+		return 0, fmt.Errorf("error getting snapshot: %w", err)
 	}
 
 	if snapshot == nil {
@@ -220,78 +258,57 @@ func GetSpotPrice(apiKey, apiSecret, symbol string) (float64, error) {
 }
 
 // FetchOptionsChain fetches the options chain for the given symbol and expiration date using Alpaca
-func FetchOptionsChain(symbol, expiration string, apiKey, apiSecret string) ([]Option, *string, string, error) {
-	publicSecret, publicAccountID := GetPublicConfig()
-	if publicSecret != "" {
+func FetchOptionsChain(ctx context.Context, symbol, expiration string, apiKey, apiSecret string) ([]Option, *string, string, error) {
+	if client := publicClient(); client != nil {
 		fmt.Printf("Attempting to fetch options chain from Public.com for %s (%s)...\n", symbol, expiration)
-		client := public.NewClient(publicSecret, publicAccountID)
-		if err := client.Authenticate(); err == nil {
-			if err := client.FetchAccountID(); err == nil {
-				chain, rawBody, err := client.GetOptionChain(symbol, expiration)
-				if err == nil {
-					// Write full response to file for debugging
-					_ = os.WriteFile("public_debug_response.json", rawBody, 0644)
-					fmt.Printf("DEBUG: Wrote full Public.com response to public_debug_response.json (Size: %d bytes)\n", len(rawBody))
-					
-					fmt.Printf("Successfully fetched option chain from Public.com for %s (%d calls, %d puts)\n", 
-						symbol, len(chain.Calls), len(chain.Puts))
-					
-					var options []Option
-					
-					// Helper to process contracts
-					process := func(contracts []public.OptionContract, side string) {
-						for _, c := range contracts {
-							strike, _ := c.OptionDetails.StrikePrice.Float64()
-							oi, _ := c.OpenInterest.Int64()
-							opt := Option{
-								Strike:         strike,
-								OptionType:     side,
-								OpenInterest:   int(oi),
-								ExpirationDate: expiration,
-								ExpirationType: "AMERICAN",
-							}
-							if c.OptionDetails.Greeks != nil {
-								gamma, _ := c.OptionDetails.Greeks.Gamma.Float64()
-								opt.Greeks.Gamma = gamma
-							}
-							options = append(options, opt)
-						}
+		chain, _, err := client.GetOptionChain(ctx, symbol, expiration)
+		if err == nil {
+			fmt.Printf("Successfully fetched option chain from Public.com for %s (%d calls, %d puts)\n", 
+				symbol, len(chain.Calls), len(chain.Puts))
+			
+			var options []Option
+			
+			// Helper to process contracts
+			process := func(contracts []public.OptionContract, side string) {
+				for _, c := range contracts {
+					strike, _ := c.OptionDetails.StrikePrice.Float64()
+					oi, _ := c.OpenInterest.Int64()
+					opt := Option{
+						Strike:         strike,
+						OptionType:     side,
+						OpenInterest:   int(oi),
+						ExpirationDate: expiration,
+						ExpirationType: "AMERICAN",
 					}
-
-					process(chain.Calls, "CALL")
-					process(chain.Puts, "PUT")
-
-					resp := Response{}
-					resp.Options.Option = options
-					jsonData, _ := json.Marshal(resp)
-					bodyStr := string(jsonData)
-					return options, &bodyStr, "", nil
-				} else {
-					fmt.Printf("Public.com GetOptionChain failed: %v\n", err)
+					if c.OptionDetails.Greeks != nil {
+						gamma, _ := c.OptionDetails.Greeks.Gamma.Float64()
+						opt.Greeks.Gamma = gamma
+					}
+					options = append(options, opt)
 				}
-			} else {
-				fmt.Printf("Public.com account ID fetch failed: %v\n", err)
 			}
-		} else {
-			fmt.Printf("Public.com authentication failed: %v\n", err)
+
+			process(chain.Calls, "CALL")
+			process(chain.Puts, "PUT")
+
+			resp := Response{Provider: "public"}
+			resp.Options.Option = options
+			jsonData, _ := json.Marshal(resp)
+			bodyStr := string(jsonData)
+			return options, &bodyStr, "", nil
+		}
+		if ctx.Err() != nil {
+			return nil, nil, "", err
 		}
+		fmt.Printf("Public.com GetOptionChain failed: %v\n", err)
 		fmt.Println("Falling back to Alpaca for options chain.")
 	}
 
 	// Try Live API first for contracts
-	liveBaseUrl := "https://api.alpaca.markets"
+	liveBaseUrl := alpacaURL("ALPACA_LIVE_BASE_URL", "https://api.alpaca.markets")
 	
-	mdClient := marketdata.NewClient(marketdata.ClientOpts{
-		APIKey:    apiKey,
-		APISecret: apiSecret,
-		BaseURL:   "https://data.alpaca.markets",
-	})
-
-	tradeClient := alpaca.NewClient(alpaca.ClientOpts{
-		APIKey:    apiKey,
-		APISecret: apiSecret,
-		BaseURL:   liveBaseUrl,
-	})
+	mdClient := newMarketDataClient(ctx, apiKey, apiSecret)
+	tradeClient := newTradingClient(ctx, apiKey, apiSecret, liveBaseUrl)
 
 	expDate, err := civil.ParseDate(expiration)
 	if err != nil {
@@ -306,14 +323,13 @@ func FetchOptionsChain(symbol, expiration string, apiKey, apiSecret string) ([]O
 	})
 
 	isPaperFallback := false
+	if ctx.Err() != nil {
+		return nil, nil, "", ctx.Err()
+	}
 	if err != nil || len(contracts) == 0 {
 		fmt.Printf("Live API returned 0 contracts for %s on %s, falling back to Paper API\n", symbol, expiration)
-		paperBaseUrl := "https://paper-api.alpaca.markets"
-		tradeClient = alpaca.NewClient(alpaca.ClientOpts{
-			APIKey:    apiKey,
-			APISecret: apiSecret,
-			BaseURL:   paperBaseUrl,
-		})
+		paperBaseUrl := alpacaURL("ALPACA_PAPER_BASE_URL", "https://paper-api.alpaca.markets")
+		tradeClient = newTradingClient(ctx, apiKey, apiSecret, paperBaseUrl)
 		contracts, err = tradeClient.GetOptionContracts(alpaca.GetOptionContractsRequest{
 			UnderlyingSymbols: symbol,
 			ExpirationDate:    expDate,
@@ -325,7 +341,7 @@ func FetchOptionsChain(symbol, expiration string, apiKey, apiSecret string) ([]O
 	}
 
 	if err != nil {
-		return nil, nil, "", fmt.Errorf("error getting contracts: %v", err)
+		return nil, nil, "", fmt.Errorf("error getting contracts: %w", err)
 	}
 
 	if len(contracts) == 0 {
@@ -337,7 +353,7 @@ func FetchOptionsChain(symbol, expiration string, apiKey, apiSecret string) ([]O
 		ExpirationDate: expDate,
 	})
 	if err != nil {
-		return nil, nil, "", fmt.Errorf("error getting option chain snapshots: %v", err)
+		return nil, nil, "", fmt.Errorf("error getting option chain snapshots: %w", err)
 	}
 
 	// Check underlying spread for warning
@@ -365,7 +381,10 @@ func FetchOptionsChain(symbol, expiration string, apiKey, apiSecret string) ([]O
 	}
 
 	// Get spot price for estimation if needed
-	spotPrice, err := GetSpotPrice(apiKey, apiSecret, symbol)
+	spotPrice, err := GetSpotPrice(ctx, apiKey, apiSecret, symbol)
+	if ctx.Err() != nil {
+		return nil, nil, "", ctx.Err()
+	}
 	if err != nil {
 		fmt.Printf("Warning: failed to get spot price for gamma estimation: %v\n", err)
 	}
@@ -432,7 +451,10 @@ func FetchOptionsChain(symbol, expiration string, apiKey, apiSecret string) ([]O
 	fmt.Printf("Matched %d options with provided Greeks, estimated %d more, out of %d contracts\n", greeksFound, greeksEstimated, len(contracts))
 
 	// Wrap in Response struct for JSON compatibility
-	resp := Response{}
+	resp := Response{Provider: "alpaca"}
+	if isPaperFallback {
+		resp.Provider = "alpaca-paper"
+	}
 	resp.Options.Option = options
 	resp.Warning = warning
 	jsonData, err := json.Marshal(resp)
@@ -444,35 +466,28 @@ func FetchOptionsChain(symbol, expiration string, apiKey, apiSecret string) ([]O
 	return options, &bodyStr, warning, nil
 }
 
-func GetExpirationDates(apiKey, apiSecret, symbol string) ([]string, error) {
-	publicSecret, publicAccountID := GetPublicConfig()
-	if publicSecret != "" {
+func GetExpirationDates(ctx context.Context, apiKey, apiSecret, symbol string) ([]string, error) {
+	if client := publicClient(); client != nil {
 		fmt.Printf("Attempting to fetch expiration dates from Public.com for %s...\n", symbol)
-		client := public.NewClient(publicSecret, publicAccountID)
-		if err := client.Authenticate(); err == nil {
-			if err := client.FetchAccountID(); err == nil {
-				dates, err := client.GetExpirations(symbol)
-				if err == nil {
-					fmt.Printf("Successfully fetched %d expiration dates from Public.com\n", len(dates))
-					sort.Strings(dates)
-					return dates, nil
-				}
-				fmt.Printf("Public.com expiration fetch failed: %v\n", err)
-			}
+		dates, err := client.GetExpirations(ctx, symbol)
+		if err == nil {
+			fmt.Printf("Successfully fetched %d expiration dates from Public.com\n", len(dates))
+			sort.Strings(dates)
+			return dates, nil
 		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		fmt.Printf("Public.com expiration fetch failed: %v\n", err)
 		fmt.Println("Falling back to Alpaca for expiration dates.")
 	}
 
 	baseUrl := os.Getenv("ALPACA_API_BASE_URL")
 	if baseUrl == "" {
-		baseUrl = "https://api.alpaca.markets"
+		baseUrl = alpacaURL("ALPACA_LIVE_BASE_URL", "https://api.alpaca.markets")
 	}
 
-	tradeClient := alpaca.NewClient(alpaca.ClientOpts{
-		APIKey:    apiKey,
-		APISecret: apiSecret,
-		BaseURL:   baseUrl,
-	})
+	tradeClient := newTradingClient(ctx, apiKey, apiSecret, baseUrl)
 
 	loc, _ := time.LoadLocation("America/New_York")
 	nowNY := time.Now().In(loc)
@@ -486,7 +501,7 @@ func GetExpirationDates(apiKey, apiSecret, symbol string) ([]string, error) {
 		Status:            alpaca.OptionStatusActive,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error getting options contracts: %v", err)
+		return nil, fmt.Errorf("error getting options contracts: %w", err)
 	}
 
 	fmt.Printf("Alpaca returned %d active contracts for %s\n", len(contracts), symbol)
@@ -528,6 +543,68 @@ func CalculateGEXPerStrike(options []Option, spotPrice float64) map[float64]floa
 	return gexByStrike
 }
 
+// TotalGEX sums the per-strike exposure into the single value stored in gex_history.
+func TotalGEX(gexByStrike map[float64]float64) float64 {
+	total := 0.0
+	for _, gexValue := range gexByStrike {
+		total += gexValue
+	}
+	return total
+}
+
+// Levels are the strike levels recorded with each gex_history snapshot. A
+// zero level is unknown.
+type Levels struct {
+	Flip     float64
+	CallWall float64
+	PutWall  float64
+}
+
+// CalculateLevels returns the gamma flip level and the call and put walls of
+// options at spotPrice.
+func CalculateLevels(options []Option, spotPrice float64) Levels {
+	var levels Levels
+	if spotPrice <= 0 || len(options) == 0 {
+		return levels
+	}
+	levels.Flip = CalculateGammaFlipLevel(CalculateGEXPerStrike(options, spotPrice))
+	levels.CallWall, levels.PutWall = CalculateWalls(options, spotPrice)
+	return levels
+}
+
+// CalculateWalls returns the call wall and put wall of options: the strikes
+// with the largest call and put gamma exposure. Either is zero if there is no
+// open interest on that side.
+func CalculateWalls(options []Option, spotPrice float64) (callWall, putWall float64) {
+	callGEX := make(map[float64]float64)
+	putGEX := make(map[float64]float64)
+	for _, option := range options {
+		if option.OpenInterest <= 0 || option.Greeks.Gamma == 0 {
+			continue
+		}
+		gex := float64(option.OpenInterest) * abs(option.Greeks.Gamma) * 100 * spotPrice
+		switch strings.ToLower(option.OptionType) {
+		case "call":
+			callGEX[option.Strike] += gex
+		case "put":
+			putGEX[option.Strike] += gex
+		}
+	}
+	return largestStrike(callGEX), largestStrike(putGEX)
+}
+
+// largestStrike returns the strike with the largest value, preferring the
+// lower strike on ties so the result doesn't depend on map order.
+func largestStrike(byStrike map[float64]float64) float64 {
+	best, bestValue := 0.0, 0.0
+	for strike, value := range byStrike {
+		if value > bestValue || (value == bestValue && value > 0 && strike < best) {
+			best, bestValue = strike, value
+		}
+	}
+	return best
+}
+
 func CalculateGammaFlipLevel(gexByStrike map[float64]float64) float64 {
 	if len(gexByStrike) == 0 {
 		return 0
@@ -191,3 +191,30 @@ func TestCalculateGammaFlipLevelRealSPYData(t *testing.T) {
 		t.Errorf("Gamma flip level = %.2f, expected between 677.00 and 679.00 based on real data", result)
 	}
 }
+
+func TestCalculateWalls(t *testing.T) {
+	option := func(strike float64, kind string, oi int, gamma float64) Option {
+		o := Option{Strike: strike, OptionType: kind, OpenInterest: oi}
+		o.Greeks.Gamma = gamma
+		return o
+	}
+	options := []Option{
+		option(580, "put", 9000, 0.01),
+		option(590, "put", 4000, 0.02),
+		option(590, "put", 2000, 0.02), // a second expiry at the same strike
+		option(600, "call", 1000, 0.03),
+		option(610, "call", 5000, 0.02),
+		option(620, "call", 100000, 0), // no gamma
+		option(630, "CALL", 20000, 0.002),
+	}
+
+	callWall, putWall := CalculateWalls(options, 600)
+	if callWall != 610 || putWall != 590 {
+		t.Errorf("CalculateWalls = %v, %v; want 610, 590", callWall, putWall)
+	}
+
+	callWall, putWall = CalculateWalls(options[:3], 600)
+	if callWall != 0 || putWall != 590 {
+		t.Errorf("puts only: CalculateWalls = %v, %v; want 0, 590", callWall, putWall)
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/cache"
+	"github.com/arnabmitra/eth-proxy/internal/handler/gex"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+)
+
+// cachedChain is an option chain with the spot price it was fetched at.
+type cachedChain struct {
+	Options []gex.Option `json:"options"`
+	Warning string       `json:"warning,omitempty"`
+	Spot    float64      `json:"spot"`
+}
+
+// SetCache replaces the handler's provider cache, by default in memory.
+func (h *GEXHandler) SetCache(c *cache.Cache) {
+	h.cache = c
+}
+
+// spotPrice returns symbol's last trade price, cached for the spot TTL.
+func (h *GEXHandler) spotPrice(ctx context.Context, symbol string) (float64, error) {
+	return cache.Fetch(ctx, h.cache, cache.KindSpot, symbol, func(ctx context.Context) (float64, error) {
+		apiKey, apiSecret := gex.GetAlpacaConfig()
+		return gex.GetSpotPrice(ctx, apiKey, apiSecret, symbol)
+	})
+}
+
+// optionChain returns the chain of symbol expiring on expiration, cached for
+// kind's TTL. A chain the collector stored within that TTL is used as is;
+// otherwise it is fetched and stored, which also records it in gex_history.
+func (h *GEXHandler) optionChain(ctx context.Context, symbol, expiration string, kind cache.Kind) (cachedChain, error) {
+	key := symbol + ":" + expiration
+	return cache.Fetch(ctx, h.cache, kind, key, func(ctx context.Context) (cachedChain, error) {
+		expiryDate, err := stringToPgDate(expiration)
+		if err != nil {
+			return cachedChain{}, err
+		}
+		stored, err := h.repo.GetOptionChainBySymbolAndExpiry(ctx, repository.GetOptionChainBySymbolAndExpiryParams{
+			Symbol:     symbol,
+			ExpiryDate: expiryDate,
+		})
+		if err == nil && time.Since(stored.UpdatedAt) <= h.cache.TTL(kind) {
+			var response gex.Response
+			spot, spotErr := strconv.ParseFloat(stored.SpotPrice, 64)
+			if json.Unmarshal(stored.OptionChain, &response) == nil && spotErr == nil {
+				return cachedChain{Options: response.Options.Option, Warning: response.Warning, Spot: spot}, nil
+			}
+		}
+
+		price, err := h.spotPrice(ctx, symbol)
+		if err != nil {
+			return cachedChain{}, fmt.Errorf("error fetching price: %w", err)
+		}
+		apiKey, apiSecret := gex.GetAlpacaConfig()
+		options, jsonOption, warning, err := gex.FetchOptionsChain(ctx, symbol, expiration, apiKey, apiSecret)
+		if err != nil {
+			return cachedChain{}, err
+		}
+		h.logger.Info("Fetched options chain", "count", len(options), "symbol", symbol, "expiration", expiration)
+
+		// Only store if we actually got options
+		if len(options) > 0 && jsonOption != nil {
+			totalGEX := 0.0
+			for _, gexValue := range gex.CalculateGEXPerStrike(options, price) {
+				totalGEX += gexValue
+			}
+			if err := h.StoreOptionChain(ctx, options, symbol, *jsonOption, fmt.Sprintf("%.2f", price), fmt.Sprintf("%.2f", totalGEX)); err != nil {
+				h.logger.Error("failed to store option chain", "error", err, "symbol", symbol, "expiration", expiration)
+			}
+		}
+		return cachedChain{Options: options, Warning: warning, Spot: price}, nil
+	})
+}
@@ -0,0 +1,392 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+)
+
+// maxCompareSymbols caps the comparison page so the chart and matrix stay
+// readable.
+const maxCompareSymbols = 8
+
+// minCorrelationPairs is the fewest overlapping changes a correlation is
+// reported for.
+const minCorrelationPairs = 5
+
+// compareWindow is a selectable span of the comparison page.
+type compareWindow struct {
+	// tradingDays keeps the last N New York trading days with data; zero
+	// keeps everything fetched.
+	tradingDays int
+	fetch       time.Duration
+	// bucket is the sampling interval every symbol is aligned to; zero means
+	// one sample per trading day.
+	bucket time.Duration
+}
+
+var compareWindows = map[string]compareWindow{
+	"1d": {tradingDays: 1, fetch: 5 * 24 * time.Hour, bucket: 15 * time.Minute},
+	"5d": {tradingDays: 5, fetch: 10 * 24 * time.Hour, bucket: time.Hour},
+	"1m": {fetch: 31 * 24 * time.Hour},
+}
+
+// Correlation is one cell of the comparison matrix. It is invalid when there
+// were too few overlapping changes or one side never moved.
+type Correlation struct {
+	Value float64
+	Valid bool
+}
+
+func (c Correlation) MarshalJSON() ([]byte, error) {
+	if !c.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(math.Round(c.Value*1000) / 1000)
+}
+
+// Color returns the cell background: green for positive correlation, red
+// for negative, stronger the larger it is.
+func (c Correlation) Color() template.CSS {
+	if !c.Valid {
+		return template.CSS("transparent")
+	}
+	alpha := 0.1 + 0.7*math.Min(math.Abs(c.Value), 1)
+	if c.Value >= 0 {
+		return template.CSS(fmt.Sprintf("rgba(16, 185, 129, %.2f)", alpha))
+	}
+	return template.CSS(fmt.Sprintf("rgba(239, 68, 68, %.2f)", alpha))
+}
+
+// CompareSeries is one symbol's samples, aligned with GEXComparison.Times.
+// Missing samples are null.
+type CompareSeries struct {
+	Symbol string     `json:"symbol"`
+	GEX    []*float64 `json:"gex"`
+	// Normalized is GEX divided by the symbol's largest absolute GEX in the
+	// window, so every line runs between -1 and 1.
+	Normalized []*float64 `json:"normalized"`
+	Spot       []*float64 `json:"spot"`
+}
+
+// CompareRegime is a symbol's latest gamma regime in the window.
+type CompareRegime struct {
+	Symbol          string    `json:"symbol"`
+	AsOf            time.Time `json:"as_of"`
+	GEX             float64   `json:"gex"`
+	Spot            float64   `json:"spot"`
+	FlipLevel       float64   `json:"flip_level,omitempty"`
+	FlipDistancePct float64   `json:"flip_distance_pct,omitempty"`
+	// Regime is RegimeAboveFlip, RegimeBelowFlip or "" without a flip level.
+	Regime string `json:"regime"`
+	// GEXChangePct is the change from the first to the last sample in the
+	// window, relative to the first.
+	GEXChangePct float64 `json:"gex_change_pct"`
+}
+
+// Positive reports whether dealers are net long gamma.
+func (r CompareRegime) Positive() bool { return r.GEX >= 0 }
+
+// GEXComparison is the data behind the comparison page and API.
+type GEXComparison struct {
+	Symbols []string        `json:"symbols"`
+	Window  string          `json:"window"`
+	Times   []time.Time     `json:"times"`
+	Series  []CompareSeries `json:"series"`
+	// Correlation[i][j] correlates the GEX changes of Symbols[i] with the
+	// price changes of Symbols[j] between consecutive samples.
+	Correlation [][]Correlation `json:"correlation"`
+	Regimes     []CompareRegime `json:"regimes"`
+}
+
+type compareSample struct {
+	at   time.Time
+	gex  float64
+	spot float64
+	flip float64
+}
+
+// buildComparison aligns rows, ordered by symbol then time, into w's buckets
+// and computes the series, correlation matrix and regimes of symbols.
+func buildComparison(symbols []string, rows []repository.ListGEXHistoryForSymbolsRow, w compareWindow, loc *time.Location) GEXComparison {
+	day := func(t time.Time) string { return t.In(loc).Format(time.DateOnly) }
+	bucket := func(t time.Time) time.Time {
+		if w.bucket == 0 {
+			t = t.In(loc)
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		return t.Truncate(w.bucket)
+	}
+
+	// Keep the last tradingDays days that any symbol has data for.
+	var cutoff string
+	if w.tradingDays > 0 {
+		days := make(map[string]bool)
+		for _, row := range rows {
+			days[day(row.RecordedAt)] = true
+		}
+		sorted := make([]string, 0, len(days))
+		for d := range days {
+			sorted = append(sorted, d)
+		}
+		sort.Strings(sorted)
+		if len(sorted) > w.tradingDays {
+			cutoff = sorted[len(sorted)-w.tradingDays]
+		}
+	}
+
+	// The last sample of each symbol in each bucket, plus the first and last
+	// in the window for the regime table.
+	buckets := make(map[string]map[time.Time]compareSample)
+	first := make(map[string]compareSample)
+	last := make(map[string]compareSample)
+	allTimes := make(map[time.Time]bool)
+	for _, row := range rows {
+		if cutoff != "" && day(row.RecordedAt) < cutoff {
+			continue
+		}
+		gexValue, _ := row.GexValue.Float64Value()
+		smp := compareSample{at: row.RecordedAt, gex: gexValue.Float64}
+		if spot := spotLevel(row.SpotPrice); spot != nil {
+			smp.spot = *spot
+		}
+		if flip := numericLevel(row.FlipLevel); flip != nil {
+			smp.flip = *flip
+		}
+
+		if buckets[row.Symbol] == nil {
+			buckets[row.Symbol] = make(map[time.Time]compareSample)
+			first[row.Symbol] = smp
+		}
+		key := bucket(row.RecordedAt)
+		buckets[row.Symbol][key] = smp
+		last[row.Symbol] = smp
+		allTimes[key] = true
+	}
+
+	c := GEXComparison{Symbols: symbols}
+	for t := range allTimes {
+		c.Times = append(c.Times, t)
+	}
+	sort.Slice(c.Times, func(i, j int) bool { return c.Times[i].Before(c.Times[j]) })
+
+	gexChanges := make([][]*float64, len(symbols))
+	priceChanges := make([][]*float64, len(symbols))
+	for i, symbol := range symbols {
+		s := CompareSeries{
+			Symbol:     symbol,
+			GEX:        make([]*float64, len(c.Times)),
+			Normalized: make([]*float64, len(c.Times)),
+			Spot:       make([]*float64, len(c.Times)),
+		}
+		maxAbs := 0.0
+		for k, t := range c.Times {
+			smp, ok := buckets[symbol][t]
+			if !ok {
+				continue
+			}
+			s.GEX[k] = &smp.gex
+			if smp.spot > 0 {
+				s.Spot[k] = &smp.spot
+			}
+			maxAbs = math.Max(maxAbs, math.Abs(smp.gex))
+		}
+		for k, v := range s.GEX {
+			if v != nil && maxAbs > 0 {
+				n := *v / maxAbs
+				s.Normalized[k] = &n
+			}
+		}
+		c.Series = append(c.Series, s)
+
+		gexChanges[i] = make([]*float64, len(c.Times))
+		priceChanges[i] = make([]*float64, len(c.Times))
+		for k := 1; k < len(c.Times); k++ {
+			if s.GEX[k] != nil && s.GEX[k-1] != nil {
+				d := *s.GEX[k] - *s.GEX[k-1]
+				gexChanges[i][k] = &d
+			}
+			if s.Spot[k] != nil && s.Spot[k-1] != nil {
+				r := *s.Spot[k] / *s.Spot[k-1] - 1
+				priceChanges[i][k] = &r
+			}
+		}
+
+		if smp, ok := last[symbol]; ok {
+			regime := CompareRegime{Symbol: symbol, AsOf: smp.at, GEX: smp.gex, Spot: smp.spot}
+			if smp.flip > 0 && smp.spot > 0 {
+				regime.FlipLevel = smp.flip
+				regime.FlipDistancePct = (smp.spot - smp.flip) / smp.spot * 100
+				regime.Regime = RegimeBelowFlip
+				if smp.spot >= smp.flip {
+					regime.Regime = RegimeAboveFlip
+				}
+			}
+			if start := first[symbol].gex; start != 0 {
+				regime.GEXChangePct = (smp.gex - start) / math.Abs(start) * 100
+			}
+			c.Regimes = append(c.Regimes, regime)
+		}
+	}
+
+	c.Correlation = make([][]Correlation, len(symbols))
+	for i := range symbols {
+		c.Correlation[i] = make([]Correlation, len(symbols))
+		for j := range symbols {
+			c.Correlation[i][j] = correlate(gexChanges[i], priceChanges[j])
+		}
+	}
+	return c
+}
+
+// correlate returns the Pearson correlation of xs and ys over the positions
+// where both are set.
+func correlate(xs, ys []*float64) Correlation {
+	var n, sx, sy, sxx, syy, sxy float64
+	for k := range xs {
+		if xs[k] == nil || ys[k] == nil {
+			continue
+		}
+		x, y := *xs[k], *ys[k]
+		n++
+		sx += x
+		sy += y
+		sxx += x * x
+		syy += y * y
+		sxy += x * y
+	}
+	if n < minCorrelationPairs {
+		return Correlation{}
+	}
+	cov := sxy - sx*sy/n
+	vx := sxx - sx*sx/n
+	vy := syy - sy*sy/n
+	if vx <= 0 || vy <= 0 {
+		return Correlation{}
+	}
+	return Correlation{Value: math.Max(-1, math.Min(1, cov/math.Sqrt(vx*vy))), Valid: true}
+}
+
+// compareSymbols reads ?symbols= (comma-separated or repeated), upper-cased,
+// de-duplicated and validated.
+func compareSymbols(values []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, v := range values {
+		for _, s := range strings.Split(v, ",") {
+			s = strings.ToUpper(strings.TrimSpace(s))
+			if s == "" || seen[s] {
+				continue
+			}
+			if !universe.ValidSymbol(s) {
+				return nil, fmt.Errorf("invalid symbol %q", s)
+			}
+			seen[s] = true
+			symbols = append(symbols, s)
+		}
+	}
+	if len(symbols) > maxCompareSymbols {
+		return nil, fmt.Errorf("compare at most %d symbols", maxCompareSymbols)
+	}
+	return symbols, nil
+}
+
+func (h *GEXHandler) gexComparison(w http.ResponseWriter, r *http.Request) (*GEXComparison, bool) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	symbols, err := compareSymbols(q["symbols"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if len(symbols) == 0 {
+		symbols, err = h.universes.Symbols(ctx, universe.Mag7)
+		if err != nil {
+			h.logger.Error("failed to load default comparison symbols", "error", err)
+			http.Error(w, "Failed to load comparison", http.StatusInternalServerError)
+			return nil, false
+		}
+		symbols = symbols[:min(len(symbols), maxCompareSymbols)]
+	}
+
+	windowKey := strings.ToLower(q.Get("window"))
+	if windowKey == "" {
+		windowKey = "5d"
+	}
+	window, ok := compareWindows[windowKey]
+	if !ok {
+		http.Error(w, "window must be 1d, 5d or 1m", http.StatusBadRequest)
+		return nil, false
+	}
+
+	rows, err := h.repo.ListGEXHistoryForSymbols(ctx, repository.ListGEXHistoryForSymbolsParams{
+		Symbols:  symbols,
+		FromTime: time.Now().Add(-window.fetch),
+	})
+	if err != nil {
+		h.logger.Error("failed to fetch GEX history for comparison", "symbols", symbols, "error", err)
+		http.Error(w, "Failed to load comparison", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.UTC
+	}
+	c := buildComparison(symbols, rows, window, loc)
+	c.Window = windowKey
+	return &c, true
+}
+
+// HandleCompareAPI returns normalized GEX series, the GEX-vs-price change
+// correlation matrix and the latest regimes of ?symbols= over ?window=.
+func (h *GEXHandler) HandleCompareAPI(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.gexComparison(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c)
+}
+
+// HandleComparePage renders the multi-symbol comparison page.
+func (h *GEXHandler) HandleComparePage(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.gexComparison(w, r)
+	if !ok {
+		return
+	}
+
+	labels := make([]string, len(c.Times))
+	layout := "Jan 2 15:04"
+	if compareWindows[c.Window].bucket == 0 {
+		layout = "Jan 2"
+	}
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.UTC
+	}
+	for i, t := range c.Times {
+		labels[i] = t.In(loc).Format(layout)
+	}
+
+	data := map[string]interface{}{
+		"Comparison":  c,
+		"SymbolsText": strings.Join(c.Symbols, ", "),
+		"Window":      c.Window,
+		"Labels":      labels,
+		"MaxSymbols":  maxCompareSymbols,
+	}
+	if err := h.tmpl.ExecuteTemplate(w, "gex_compare.html", data); err != nil {
+		h.logger.Error("failed to render template", "error", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
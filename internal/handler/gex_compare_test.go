@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func compareRow(symbol string, at time.Time, gexValue, spot, flip float64) repository.ListGEXHistoryForSymbolsRow {
+	row := repository.ListGEXHistoryForSymbolsRow{
+		Symbol:     symbol,
+		RecordedAt: at,
+		SpotPrice:  pgtype.Text{String: strconv.FormatFloat(spot, 'f', -1, 64), Valid: true},
+	}
+	row.GexValue.Scan(strconv.FormatFloat(gexValue, 'f', -1, 64))
+	if flip > 0 {
+		row.FlipLevel.Scan(strconv.FormatFloat(flip, 'f', -1, 64))
+	}
+	return row
+}
+
+func TestBuildComparison(t *testing.T) {
+	loc, _ := time.LoadLocation("America/New_York")
+	day := func(d, hour int) time.Time { return time.Date(2026, 10, d, hour, 0, 0, 0, loc) }
+
+	// AAA's GEX rises with its own price every day; BBB only has three days
+	// so none of its correlations have enough pairs.
+	var rows []repository.ListGEXHistoryForSymbolsRow
+	spots := []float64{100, 101, 103, 102, 106, 105, 108, 110}
+	for i, spot := range spots {
+		if i > 0 {
+			// An earlier snapshot on the same day is superseded by the later one.
+			rows = append(rows, compareRow("AAA", day(5+i, 10), -1, 1, 0))
+		}
+		rows = append(rows, compareRow("AAA", day(5+i, 15), (spot-104)*1e6, spot, 104))
+	}
+	rows = append(rows,
+		compareRow("BBB", day(5, 15), 2e6, 50, 0),
+		compareRow("BBB", day(7, 15), 4e6, 51, 0),
+		compareRow("BBB", day(8, 15), 3e6, 49, 0),
+	)
+
+	c := buildComparison([]string{"AAA", "BBB"}, rows, compareWindows["1m"], loc)
+
+	if len(c.Times) != len(spots) || !c.Times[0].Equal(time.Date(2026, 10, 5, 0, 0, 0, 0, loc)) {
+		t.Fatalf("times = %v", c.Times)
+	}
+	aaa, bbb := c.Series[0], c.Series[1]
+	// AAA's largest absolute GEX is 6e6, on the last day.
+	if *aaa.GEX[0] != -4e6 || *aaa.Normalized[len(spots)-1] != 1 || *aaa.Normalized[3] != -2.0/6 {
+		t.Errorf("AAA series isn't normalized to its largest GEX: %v", aaa.Normalized)
+	}
+	if bbb.GEX[1] != nil || *bbb.Normalized[2] != 1 || *bbb.Spot[3] != 49 {
+		t.Error("BBB isn't aligned to the shared times")
+	}
+
+	if cell := c.Correlation[0][0]; !cell.Valid || cell.Value < 0.9 {
+		t.Errorf("AAA GEX vs AAA price = %+v, want strongly positive", cell)
+	}
+	if c.Correlation[1][0].Valid || c.Correlation[0][1].Valid {
+		t.Error("correlations with too few pairs should be invalid")
+	}
+
+	want := []CompareRegime{
+		{Symbol: "AAA", AsOf: day(12, 15), GEX: 6e6, Spot: 110, FlipLevel: 104, FlipDistancePct: (110.0 - 104) / 110 * 100, Regime: RegimeAboveFlip, GEXChangePct: 250},
+		{Symbol: "BBB", AsOf: day(8, 15), GEX: 3e6, Spot: 49, GEXChangePct: 50},
+	}
+	if !reflect.DeepEqual(c.Regimes, want) {
+		t.Errorf("regimes = %+v\nwant %+v", c.Regimes, want)
+	}
+
+	oneDay := buildComparison([]string{"AAA"}, rows, compareWindows["1d"], loc)
+	if len(oneDay.Times) != 2 || !oneDay.Times[0].Equal(day(12, 10)) || !oneDay.Times[1].Equal(day(12, 15)) {
+		t.Errorf("1d times = %v, want the two snapshots of the last day", oneDay.Times)
+	}
+}
+
+func TestCorrelationJSON(t *testing.T) {
+	for _, tt := range []struct {
+		c    Correlation
+		want string
+	}{
+		{Correlation{}, "null"},
+		{Correlation{Value: -0.12345, Valid: true}, "-0.123"},
+	} {
+		got, err := tt.c.MarshalJSON()
+		if err != nil || string(got) != tt.want {
+			t.Errorf("MarshalJSON(%+v) = %s, %v; want %s", tt.c, got, err, tt.want)
+		}
+	}
+}
+
+func TestCompareSymbols(t *testing.T) {
+	got, err := compareSymbols([]string{" spy, qqq", "SPY", "nvda"})
+	if err != nil || !reflect.DeepEqual(got, []string{"SPY", "QQQ", "NVDA"}) {
+		t.Errorf("compareSymbols = %v, %v", got, err)
+	}
+	if _, err := compareSymbols([]string{"SPY,<script>"}); err == nil {
+		t.Error("expected an invalid symbol to be rejected")
+	}
+	if _, err := compareSymbols([]string{"A,B,C,D,E,F,G,H,I"}); err == nil {
+		t.Errorf("expected more than %d symbols to be rejected", maxCompareSymbols)
+	}
+}
+
+func TestCompareTemplateRenders(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+	loc, _ := time.LoadLocation("America/New_York")
+
+	var rows []repository.ListGEXHistoryForSymbolsRow
+	for i, spot := range []float64{100, 102, 101, 104, 103, 107, 106} {
+		at := time.Date(2026, 10, 5+i, 15, 0, 0, 0, loc)
+		rows = append(rows, compareRow("SPY", at, spot*1e6, spot, 101))
+	}
+	c := buildComparison([]string{"SPY", "QQQ"}, rows, compareWindows["1m"], loc)
+	c.Window = "1m"
+	data := map[string]interface{}{
+		"Comparison":  &c,
+		"SymbolsText": "SPY, QQQ",
+		"Window":      c.Window,
+		"Labels":      []string{"Oct 5", "Oct 6", "Oct 7", "Oct 8", "Oct 9", "Oct 10", "Oct 11"},
+		"MaxSymbols":  maxCompareSymbols,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "gex_compare.html", data); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "ZgotmplZ") {
+		t.Error("template output contains a sanitized value")
+	}
+	if !strings.Contains(out, "background-color: rgba(16, 185, 129") {
+		t.Error("the SPY diagonal cell is missing its colour")
+	}
+	if !strings.Contains(out, `"symbol":"QQQ"`) {
+		t.Error("chart series aren't embedded as JSON")
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"math"
@@ -23,7 +24,10 @@ import (
 
 	"log/slog"
 
+	"github.com/arnabmitra/eth-proxy/internal/cache"
+	"github.com/arnabmitra/eth-proxy/internal/events"
 	"github.com/arnabmitra/eth-proxy/internal/handler/gex"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
 )
 
 func formatCurrency(val float64) string {
@@ -35,16 +39,35 @@ func formatCurrency(val float64) string {
 }
 
 type GEXHandler struct {
-	logger *slog.Logger
-	tmpl   *template.Template
-	repo   *repository.Queries
+	logger    *slog.Logger
+	tmpl      *template.Template
+	repo      *repository.Queries
+	universes *universe.Store
+
+	// snapshotTTL and refreshes govern the precomputed all-expiry snapshots
+	// behind the MAG7, universe grid and all-expiry pages.
+	snapshotTTL time.Duration
+	refreshes   refreshGroup
+
+	// cache holds spot prices, chains and computed GEX shared between
+	// requests (and instances, when backed by Redis).
+	cache *cache.Cache
+
+	// events lists what happens before an expiry on the chart page.
+	events *events.Calendar
 }
 
 func NewGEXHandler(logger *slog.Logger, tmpl *template.Template, db *pgxpool.Pool) *GEXHandler {
 	return &GEXHandler{
-		logger: logger,
-		tmpl:   tmpl,
-		repo:   repository.New(db),
+		logger:    logger,
+		tmpl:      tmpl,
+		repo:      repository.New(db),
+		universes: universe.NewStore(db),
+
+		snapshotTTL: DefaultSnapshotTTL,
+		refreshes:   refreshGroup{retryAfter: snapshotRetryAfter},
+		cache:       cache.New(cache.NewMemory(), cache.DefaultPolicy()),
+		events:      events.NewCalendar(db),
 	}
 }
 
@@ -59,9 +82,9 @@ func (h *GEXHandler) CalculateGEXForAllExpiries(ctx context.Context, symbol stri
 
 	if err != nil || expiryDates == nil || len(expiryDates) == 0 {
 
-		expiryDates, err = gex.GetExpirationDates(apiKey, apiSecret, symbol)
+		expiryDates, err = gex.GetExpirationDates(ctx, apiKey, apiSecret, symbol)
 		if err != nil {
-			return nil, "", fmt.Errorf("cannot get expiration dates: %v", err)
+			return nil, "", fmt.Errorf("cannot get expiration dates: %w", err)
 		}
 
 		expirationDatesJSON, err := json.MarshalIndent(expiryDates, "", "  ")
@@ -75,9 +98,9 @@ func (h *GEXHandler) CalculateGEXForAllExpiries(ctx context.Context, symbol stri
 	}
 
 	// Get current price
-	price, err := gex.GetSpotPrice(apiKey, apiSecret, symbol)
+	price, err := h.spotPrice(ctx, symbol)
 	if err != nil {
-		return nil, "", fmt.Errorf("error fetching price: %v", err)
+		return nil, "", fmt.Errorf("error fetching price: %w", err)
 	}
 
 	// Initialize combined GEX map
@@ -94,52 +117,15 @@ func (h *GEXHandler) CalculateGEXForAllExpiries(ctx context.Context, symbol stri
 
 	// Process each expiry date
 	for _, expiryDate := range expiryDates {
-		var options []gex.Option
-		var warning string
-
-		expirationDatePgType, err := stringToPgDate(expiryDate)
-		if err != nil {
-			continue
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
 		}
 
-		// Try to get cached options data first
-		expiry, err := h.repo.GetOptionChainBySymbolAndExpiry(ctx, repository.GetOptionChainBySymbolAndExpiryParams{
-			Symbol:     symbol,
-			ExpiryDate: expirationDatePgType,
-		})
-
-		// Use 4 hour cache time for better performance
-		cacheTime := 4 * time.Hour
-		if err == nil && time.Since(expiry.UpdatedAt) <= cacheTime {
-			// Use cached data
-			var response gex.Response
-			err = json.Unmarshal(expiry.OptionChain, &response)
-			if err == nil {
-				options = response.Options.Option
-				warning = response.Warning
-			}
-		} else {
-			// Fetch fresh data
-			optionsFromApi, jsonOption, warningFromApi, errFromApi := gex.FetchOptionsChain(symbol, expiryDate, apiKey, apiSecret)
-			if errFromApi != nil || jsonOption == nil {
-				continue // Skip this expiry if there's an error
-			}
-			// Use the fresh data we just fetched
-			options = optionsFromApi
-			warning = warningFromApi
-
-			gexByStrike := gex.CalculateGEXPerStrike(options, price)
-
-			// Calculate total GEX (sum of all strikes)
-			totalGEX := 0.0
-			for _, gexValue := range gexByStrike {
-				totalGEX += gexValue
-			}
-			err = h.StoreOptionChain(ctx, optionsFromApi, symbol, *jsonOption, fmt.Sprintf("%.2f", price), fmt.Sprintf("%.2f", totalGEX))
-			if err != nil {
-				continue
-			}
+		chain, err := h.optionChain(ctx, symbol, expiryDate, cache.KindAllExpiryChain)
+		if err != nil {
+			continue // Skip this expiry if there's an error
 		}
+		options, warning := chain.Options, chain.Warning
 
 		if warning != "" && !strings.Contains(combinedWarning, warning) {
 			if combinedWarning != "" {
@@ -161,27 +147,31 @@ func (h *GEXHandler) CalculateGEXForAllExpiries(ctx context.Context, symbol stri
 func (h *GEXHandler) AllGEXHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodPost {
 		symbol := strings.ToUpper(strings.TrimSpace(r.FormValue("symbol")))
-
-		apiKey := os.Getenv("ALPACA_API_KEY")
-		apiSecret := os.Getenv("ALPACA_API_SECRET")
-		if apiKey == "" || apiSecret == "" {
-			http.Error(w, "ALPACA_API_KEY or ALPACA_API_SECRET environment variable is not set", http.StatusInternalServerError)
+		if !universe.ValidSymbol(symbol) {
+			http.Error(w, "Invalid symbol", http.StatusBadRequest)
 			return
 		}
 
-		// Calculate GEX for all expiry dates
-		gexByStrike, warning, err := h.CalculateGEXForAllExpiries(r.Context(), symbol)
+		// Serve the precomputed snapshot; a symbol nobody has asked for yet
+		// is calculated once in the background while this request waits.
+		snapshot, err := h.awaitSnapshot(r.Context(), symbol)
 		if err != nil {
+			h.logger.Error("failed to load GEX snapshot", "symbol", symbol, "error", err)
 			http.Error(w, fmt.Sprintf("Error calculating GEX for all expiries: %v", err), http.StatusInternalServerError)
 			return
 		}
-
-		// Get current price
-		price, err := gex.GetSpotPrice(apiKey, apiSecret, symbol)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Error fetching price: %v", err), http.StatusInternalServerError)
+		if snapshot == nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if err := h.tmpl.ExecuteTemplate(w, "all_gex_chart.html", map[string]interface{}{
+				"Symbol":  symbol,
+				"Pending": true,
+			}); err != nil {
+				h.renderError(w, fmt.Sprintf("Error rendering template: %v", err))
+			}
 			return
 		}
+		gexByStrike := snapshot.GEXByStrike()
+		price := snapshot.SpotPrice
 
 		// Process GEX data
 		strikePrices := make([]float64, 0, len(gexByStrike))
@@ -229,21 +219,8 @@ func (h *GEXHandler) AllGEXHandler(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		// Prepare chart data for D3.js
-		chartData := make([]map[string]interface{}, 0)
-		for strike, gexValue := range gexByStrike {
-			if gexValue != 0 {
-				chartData = append(chartData, map[string]interface{}{
-					"strike": strike,
-					"gex":    gexValue,
-				})
-			}
-		}
-
-		// Sort by strike price
-		sort.Slice(chartData, func(i, j int) bool {
-			return chartData[i]["strike"].(float64) < chartData[j]["strike"].(float64)
-		})
+		// Chart data for D3.js, already sorted by strike
+		chartData := snapshot.Strikes
 
 		// Calculate gamma flip level
 		gammaFlipLevel := gex.CalculateGammaFlipLevel(gexByStrike)
@@ -275,7 +252,9 @@ func (h *GEXHandler) AllGEXHandler(w http.ResponseWriter, r *http.Request) {
 			"GammaFlipLevel":    gammaFlipLevel,
 			"TotalGEX":          totalGEX,
 			"TotalGEXFormatted": totalGEXFormatted,
-			"Warning":           warning,
+			"Warning":           snapshot.Warning,
+			"Updated":           snapshot.Updated(),
+			"Stale":             snapshot.Stale,
 		})
 		if err != nil {
 			h.renderError(w, fmt.Sprintf("Error rendering template: %v", err))
@@ -306,7 +285,7 @@ func (h *GEXHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		expiryDates, err := h.GetExpiryDates(r.Context(), symbol)
 
 		if err != nil || len(expiryDates) == 0 {
-			expirationDates, err := gex.GetExpirationDates(apiKey, apiSecret, symbol)
+			expirationDates, err := gex.GetExpirationDates(r.Context(), apiKey, apiSecret, symbol)
 			if err != nil {
 				return
 			}
@@ -339,73 +318,18 @@ func (h *GEXHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		expirationDatePgType, err := stringToPgDate(expiration)
-		if err != nil {
+		if _, err := stringToPgDate(expiration); err != nil {
 			h.logger.Error("failed to parse expiration date", "error", err, "expiration", expiration)
 			http.Error(w, fmt.Sprintf("Invalid expiration date: %v", err), http.StatusBadRequest)
 			return
 		}
-		var options []gex.Option
-		var jsonOption *string
-		var price float64
-		var warning string
-		expiry, err := h.repo.GetOptionChainBySymbolAndExpiry(r.Context(), repository.GetOptionChainBySymbolAndExpiryParams{Symbol: symbol, ExpiryDate: expirationDatePgType})
-		
-		if err == nil && time.Since(expiry.UpdatedAt) <= 1*time.Minute {
-			var response gex.Response
-			err = json.Unmarshal(expiry.OptionChain, &response)
-			if err != nil {
-				http.Error(w, fmt.Sprintf("Error unmarshalling options chain: %v", err), http.StatusInternalServerError)
-				return
-			}
-			options = response.Options.Option
-			warning = response.Warning
-			priceFloat, err := strconv.ParseFloat(expiry.SpotPrice, 64)
-			if err != nil {
-				http.Error(w, fmt.Sprintf("Error converting spot price to float64: %v", err), http.StatusInternalServerError)
-				return
-			}
-			price = priceFloat
-		} else {
-			//always get the spot price
-			price, err = gex.GetSpotPrice(apiKey, apiSecret, symbol)
-			if err != nil {
-				http.Error(w, fmt.Sprintf("Error fetching price: %v", err), http.StatusInternalServerError)
-				return
-			}
-			options, jsonOption, warning, err = gex.FetchOptionsChain(symbol, expiration, apiKey, apiSecret)
-
-			if err != nil {
-				h.logger.Error("failed to fetch options chain", "error", err, "symbol", symbol, "expiration", expiration)
-				http.Error(w, fmt.Sprintf("Error fetching options chain: %v", err), http.StatusInternalServerError)
-				return
-			}
-			h.logger.Info("Fetched options chain", "count", len(options), "symbol", symbol, "expiration", expiration)
-
-			// Calculate GEX for the nearest expiry
-			gexByStrike := gex.CalculateGEXPerStrike(options, price)
-			h.logger.Info("Calculated GEX per strike", "count", len(gexByStrike), "symbol", symbol)
-
-			// Only store if we actually got options
-			if len(options) > 0 {
-				// Calculate total GEX (sum of all strikes)
-				totalGEX := 0.0
-				for _, gexValue := range gexByStrike {
-					totalGEX += gexValue
-				}
-				err = h.StoreOptionChain(r.Context(), options, symbol, *jsonOption, fmt.Sprintf("%.2f", price), fmt.Sprintf("%.2f", totalGEX))
-				if err != nil {
-					h.logger.Error("failed to store option chain", "error", err)
-					return
-				}
-			}
-		}
-		//print the options chain
-		fmt.Println("Options Chain:")
-		for _, option := range options {
-			fmt.Printf("Strike: %.2f, Type: %s, Expiration: %s, Open Interest: %d, Expiration Type : %s\n",
-				option.Strike, option.OptionType, option.ExpirationDate, option.OpenInterest, option.ExpirationType)
+		chain, err := h.optionChain(r.Context(), symbol, expiration, cache.KindChain)
+		if err != nil {
+			h.logProviderError(r.Context(), "failed to fetch options chain", err, "symbol", symbol, "expiration", expiration)
+			http.Error(w, fmt.Sprintf("Error fetching options chain: %v", err), http.StatusInternalServerError)
+			return
 		}
+		options, price, warning := chain.Options, chain.Spot, chain.Warning
 
 		gexByStrike := gex.CalculateGEXPerStrike(options, price)
 
@@ -414,11 +338,6 @@ func (h *GEXHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			strikePrices = append(strikePrices, strike)
 		}
 		sort.Float64s(strikePrices)
-		// Print GEX per strike in sorted order
-		fmt.Println("Gamma Exposure (GEX) per Strike Price (Sorted):")
-		for _, strike := range strikePrices {
-			fmt.Printf("Strike: %.2f, GEX: %.2f\n", strike, gexByStrike[strike])
-		}
 		// Create a slice of structs to hold strike prices and GEX values
 		type GEXEntry struct {
 			Strike float64
@@ -443,12 +362,8 @@ func (h *GEXHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			gexEntries = gexEntries[:20]
 		}
 
-		// // Print the sorted and limited GEX data
-
-		fmt.Println("Top 20 Gamma Exposure (GEX) per Strike Price (Sorted by GEX):")
-		for _, entry := range gexEntries {
-			fmt.Printf("Strike: %.2f, GEX: %.2f\n", entry.Strike, entry.GEX)
-		}
+		h.logger.Debug("computed GEX per strike", "symbol", symbol, "expiration", expiration,
+			"options", len(options), "strikes", len(strikePrices))
 
 		// Prepare data for template
 		gexData := make([]map[string]string, len(gexEntries))
@@ -538,6 +453,7 @@ func (h *GEXHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			"TotalGEXFormatted": totalGEXFormatted,
 			"RegimeSummary":     regimeSummary,
 			"Warning":           warning,
+			"Events":            h.eventsBefore(r.Context(), symbol, expiration),
 		})
 		if err != nil {
 			h.renderError(w, fmt.Sprintf("Error fetching options chain: %v", err))
@@ -554,6 +470,39 @@ func (h *GEXHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// logProviderError logs a failed provider call. Calls cut short because the
+// client went away or the request timed out are logged at info level so they
+// aren't mistaken for provider failures.
+func (h *GEXHandler) logProviderError(ctx context.Context, msg string, err error, args ...any) {
+	if ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		h.logger.Info(msg+": request canceled", append(args, "reason", ctx.Err())...)
+		return
+	}
+	h.logger.Error(msg, append(args, "error", err)...)
+}
+
+// eventsBefore returns the medium and high impact events affecting symbol
+// from today through expiration. The chart renders without them when the
+// calendar can't be read.
+func (h *GEXHandler) eventsBefore(ctx context.Context, symbol, expiration string) []events.Event {
+	expiry, err := time.ParseInLocation("2006-01-02", expiration, events.NewYork)
+	if err != nil {
+		return nil
+	}
+	all, err := h.events.Before(ctx, symbol, expiry)
+	if err != nil {
+		h.logger.Error("failed to load events before expiry", "symbol", symbol, "expiration", expiration, "error", err)
+		return nil
+	}
+	notable := make([]events.Event, 0, len(all))
+	for _, e := range all {
+		if e.Impact != events.ImpactLow {
+			notable = append(notable, e)
+		}
+	}
+	return notable
+}
+
 func (h *GEXHandler) renderError(w http.ResponseWriter, errMsg string) {
 	err := h.tmpl.ExecuteTemplate(w, "error.html", map[string]interface{}{
 		"Error": errMsg,
@@ -598,6 +547,7 @@ func (h *GEXHandler) StoreOptionChain(ctx context.Context, options []gex.Option,
 	} else {
 		fmt.Println("GexValue set successfully:", gexValue)
 	}
+	flip, callWall, putWall := strikeLevels(options, price)
 	recordedAt := time.Now()
 	_, err = h.repo.InsertGEXHistory(ctx, repository.InsertGEXHistoryParams{
 		ID:          uuid.New(),
@@ -608,6 +558,9 @@ func (h *GEXHandler) StoreOptionChain(ctx context.Context, options []gex.Option,
 		GexValue:    gexValue,
 		RecordedAt:  recordedAt,
 		SpotPrice:   pgtype.Text{String: price, Valid: true},
+		FlipLevel:   flip,
+		CallWall:    callWall,
+		PutWall:     putWall,
 	})
 	if err != nil {
 		h.logger.Error("failed to insert GEX history", "error", err)
@@ -616,6 +569,27 @@ func (h *GEXHandler) StoreOptionChain(ctx context.Context, options []gex.Option,
 	return nil
 }
 
+// strikeLevels returns the gamma flip level and call and put walls of
+// options at the given spot price. Levels that can't be computed, because the
+// price doesn't parse or there are no options, are NULL.
+func strikeLevels(options []gex.Option, price string) (flip, callWall, putWall pgtype.Numeric) {
+	spot, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return
+	}
+	levels := gex.CalculateLevels(options, spot)
+	return levelNumeric(levels.Flip), levelNumeric(levels.CallWall), levelNumeric(levels.PutWall)
+}
+
+// levelNumeric converts a strike level to a numeric, NULL when it is zero.
+func levelNumeric(level float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	if level > 0 {
+		n.Scan(strconv.FormatFloat(level, 'f', 2, 64))
+	}
+	return n
+}
+
 func stringToPgDate(dateStr string) (pgtype.Date, error) {
 	// Parse string to time.Time
 	t, err := time.Parse("2006-01-02", dateStr)
@@ -656,7 +630,7 @@ func (h *GEXHandler) GetExpiryDates(ctx context.Context, symbol string) ([]strin
 
 	var dates []string
 
-	if expiryDates.UpdatedAt.Before(time.Now().Add(-1 * 24 * time.Hour)) {
+	if time.Since(expiryDates.UpdatedAt) > h.cache.TTL(cache.KindExpiries) {
 		return dates, nil
 	}
 	err = json.Unmarshal(expiryDates.ExpiryDates, &dates)
@@ -684,7 +658,7 @@ func (h *GEXHandler) GetExpiryDatesHandler(w http.ResponseWriter, r *http.Reques
 			return
 		}
 
-		expirationDates, err := gex.GetExpirationDates(apiKey, apiSecret, symbol)
+		expirationDates, err := gex.GetExpirationDates(r.Context(), apiKey, apiSecret, symbol)
 		if err != nil {
 			h.logger.Error("failed to get expiration dates from Alpaca", "error", err, "symbol", symbol)
 			http.Error(w, fmt.Sprintf("Error fetching expiration dates: %v", err), http.StatusInternalServerError)
@@ -855,133 +829,93 @@ type GexHistoryRecord struct {
 	TopGexByStrike []GexStrikeValue `json:"top_gex_by_strike"`
 }
 
-func (h *GEXHandler) MAG7GEXHandler(w http.ResponseWriter, r *http.Request) {
-	mag7 := []string{"AAPL", "MSFT", "GOOG", "AMZN", "NVDA", "TSLA", "META"}
-	type Mag7Chart struct {
-		Symbol            string
-		SpotPrice         float64
-		ChartData         []map[string]interface{}
-		TotalGEXFormatted string
-	}
-
-	apiKey := os.Getenv("ALPACA_API_KEY")
-		apiSecret := os.Getenv("ALPACA_API_SECRET")
-
-	// Channel to collect results
-	type result struct {
-		chart Mag7Chart
-		err   error
-		index int
-	}
-	results := make(chan result, len(mag7))
-
-	// Launch goroutines for concurrent fetching
-	for idx, symbol := range mag7 {
-		go func(sym string, position int) {
-			// Retry logic for rate limiting
-			var gexByStrike map[float64]float64
-			var price float64
-			var err error
-
-			maxRetries := 3
-			for attempt := 0; attempt < maxRetries; attempt++ {
-				gexByStrike, _, err = h.CalculateGEXForAllExpiries(r.Context(), sym)
-				if err == nil {
-					break
-				}
-
-				// Check if it's a rate limit error
-				if attempt < maxRetries-1 {
-					// Exponential backoff: 1s, 2s, 4s
-					backoff := time.Duration(1<<uint(attempt)) * time.Second
-					h.logger.Warn("retrying after error", "symbol", sym, "attempt", attempt+1, "backoff", backoff, "error", err)
-					time.Sleep(backoff)
-				}
-			}
-
-			if err != nil {
-				h.logger.Error("failed to calculate GEX after retries", "error", err, "symbol", sym)
-				results <- result{err: err, index: position}
-				return
-			}
-
-			// Get spot price with retry
-			for attempt := 0; attempt < maxRetries; attempt++ {
-				price, err = gex.GetSpotPrice(apiKey, apiSecret, sym)
-				if err == nil {
-					break
-				}
+// maxGridSymbols caps the universe grid page; every chart needs a full
+// multi-expiry calculation.
+const maxGridSymbols = 12
 
-				if attempt < maxRetries-1 {
-					backoff := time.Duration(1<<uint(attempt)) * time.Second
-					h.logger.Warn("retrying price fetch", "symbol", sym, "attempt", attempt+1, "backoff", backoff, "error", err)
-					time.Sleep(backoff)
-				}
-			}
-
-			if err != nil {
-				h.logger.Error("failed to get spot price after retries", "error", err, "symbol", sym)
-				results <- result{err: err, index: position}
-				return
-			}
-
-			// Calculate total GEX
-			totalGEX := 0.0
-			for _, gexValue := range gexByStrike {
-				totalGEX += gexValue
-			}
+func (h *GEXHandler) MAG7GEXHandler(w http.ResponseWriter, r *http.Request) {
+	h.renderUniverseGrid(w, r, universe.Mag7)
+}
 
-			// Prepare chart data
-			chartData := make([]map[string]interface{}, 0)
-			for strike, gexValue := range gexByStrike {
-				if gexValue != 0 {
-					chartData = append(chartData, map[string]interface{}{
-						"strike": strike,
-						"gex":    gexValue,
-					})
-				}
-			}
+// UniverseGridHandler renders the MAG7-style chart grid for the universe
+// named by ?universe=, limited to its first maxGridSymbols members.
+func (h *GEXHandler) UniverseGridHandler(w http.ResponseWriter, r *http.Request) {
+	slug := r.URL.Query().Get("universe")
+	if slug == "" {
+		slug = universe.Mag7
+	}
+	h.renderUniverseGrid(w, r, slug)
+}
 
-			// Sort by strike price
-			sort.Slice(chartData, func(i, j int) bool {
-				return chartData[i]["strike"].(float64) < chartData[j]["strike"].(float64)
-			})
-
-			results <- result{
-				chart: Mag7Chart{
-					Symbol:            sym,
-					SpotPrice:         price,
-					ChartData:         chartData,
-					TotalGEXFormatted: formatCurrency(totalGEX),
-				},
-				index: position,
-			}
-		}(symbol, idx)
+func (h *GEXHandler) renderUniverseGrid(w http.ResponseWriter, r *http.Request, slug string) {
+	u, err := h.repo.GetUniverseBySlug(r.Context(), slug)
+	if err != nil {
+		h.logger.Error("failed to load universe", "error", err, "universe", slug)
+		http.NotFound(w, r)
+		return
+	}
+	symbols, err := h.universes.Symbols(r.Context(), slug)
+	if err != nil {
+		h.renderError(w, fmt.Sprintf("Error loading universe %s: %v", slug, err))
+		return
+	}
+	truncated := len(symbols) > maxGridSymbols
+	if truncated {
+		symbols = symbols[:maxGridSymbols]
+	}
 
-		// Small stagger to avoid thundering herd on API
-		time.Sleep(50 * time.Millisecond)
+	// Charts come from the precomputed snapshots; missing or stale ones are
+	// refreshed in the background rather than on this request.
+	snapshots, err := h.snapshots(r.Context(), symbols)
+	if err != nil {
+		h.logger.Error("failed to load GEX snapshots", "error", err, "universe", slug)
+		h.renderError(w, fmt.Sprintf("Error loading GEX for %s: %v", slug, err))
+		return
 	}
 
-	// Collect results
-	chartsByIndex := make(map[int]Mag7Chart)
-	for range mag7 {
-		res := <-results
-		if res.err == nil {
-			chartsByIndex[res.index] = res.chart
-		}
+	type Mag7Chart struct {
+		Symbol            string
+		SpotPrice         float64
+		ChartData         []StrikeGEX
+		TotalGEXFormatted string
+		Updated           string
+		Stale             bool
 	}
-	close(results)
 
-	// Build ordered charts array
-	charts := make([]Mag7Chart, 0, len(chartsByIndex))
-	for i := 0; i < len(mag7); i++ {
-		if chart, ok := chartsByIndex[i]; ok {
-			charts = append(charts, chart)
+	charts := make([]Mag7Chart, 0, len(snapshots))
+	var pending []string
+	var oldest *GEXSnapshot
+	for _, symbol := range symbols {
+		snapshot, ok := snapshots[symbol]
+		if !ok {
+			pending = append(pending, symbol)
+			continue
+		}
+		if oldest == nil || snapshot.ComputedAt.Before(oldest.ComputedAt) {
+			oldest = snapshot
 		}
+		charts = append(charts, Mag7Chart{
+			Symbol:            symbol,
+			SpotPrice:         snapshot.SpotPrice,
+			ChartData:         snapshot.Strikes,
+			TotalGEXFormatted: formatCurrency(snapshot.TotalGEX()),
+			Updated:           snapshot.Updated(),
+			Stale:             snapshot.Stale,
+		})
+	}
+	var updated string
+	if oldest != nil {
+		updated = oldest.Updated()
 	}
 
-	err := h.tmpl.ExecuteTemplate(w, "mag7_gex.html", map[string]interface{}{
-		"Charts": charts,
+	err = h.tmpl.ExecuteTemplate(w, "mag7_gex.html", map[string]interface{}{
+		"Charts":    charts,
+		"Pending":   pending,
+		"Updated":   updated,
+		"Universe":  u.Slug,
+		"Title":     u.Name,
+		"Truncated": truncated,
+		"Limit":     maxGridSymbols,
 	})
 	if err != nil {
 		h.renderError(w, fmt.Sprintf("Error rendering template: %v", err))
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arnabmitra/eth-proxy/internal/zscore"
+)
+
+// Scanner sort orders. zscore_abs_desc is the default.
+const (
+	SortZScoreAbsDesc   = "zscore_abs_desc"
+	SortGEXAsc          = "gex_asc"
+	SortGEXDesc         = "gex_desc"
+	SortChangePctDesc   = "change_pct_desc"
+	SortChangePctAsc    = "change_pct_asc"
+	SortFlipDistanceAsc = "flip_distance_asc"
+)
+
+var scannerSorts = map[string]bool{
+	SortZScoreAbsDesc:   true,
+	SortGEXAsc:          true,
+	SortGEXDesc:         true,
+	SortChangePctDesc:   true,
+	SortChangePctAsc:    true,
+	SortFlipDistanceAsc: true,
+}
+
+// Market cap tiers, by market cap in USD.
+const (
+	CapMega  = "mega"  // $200B and up
+	CapLarge = "large" // $10B to $200B
+	CapMid   = "mid"   // $2B to $10B
+	CapSmall = "small" // under $2B
+)
+
+// CapTier returns the tier of a market cap, or "" if it is unknown.
+func CapTier(marketCap int64) string {
+	switch {
+	case marketCap <= 0:
+		return ""
+	case marketCap >= 200e9:
+		return CapMega
+	case marketCap >= 10e9:
+		return CapLarge
+	case marketCap >= 2e9:
+		return CapMid
+	default:
+		return CapSmall
+	}
+}
+
+// Gamma regimes: spot above the flip level means dealers are long gamma and
+// tend to damp moves, below it they are short gamma and tend to chase them.
+const (
+	RegimeAboveFlip = "above_flip"
+	RegimeBelowFlip = "below_flip"
+)
+
+const (
+	defaultScannerPerPage = 25
+	maxScannerPerPage     = 100
+)
+
+// ScannerFilter is the set of filters, sort and page the scanner was asked
+// for. The zero value shows every item, sorted by the default order.
+type ScannerFilter struct {
+	Sectors []string
+	CapTier string
+	GEXSign string // "positive" or "negative"
+	MinAbsZ float64
+	// MinPrice and MaxPrice bound the spot price; zero means no bound.
+	MinPrice float64
+	MaxPrice float64
+	Regime   string
+	// MaxFlipDistancePct keeps items whose spot is within this many percent
+	// of the flip level; zero means no limit.
+	MaxFlipDistancePct float64
+
+	Sort    string
+	Page    int
+	PerPage int
+	// Z selects how Z-scores are computed; zero fields use the server default.
+	Z zscore.Config
+}
+
+// ParseScannerFilter reads a filter from query parameters. Unknown or
+// malformed values are ignored rather than rejected so that old links and
+// saved views keep working.
+func ParseScannerFilter(q url.Values) ScannerFilter {
+	f := ScannerFilter{
+		CapTier: q.Get("cap"),
+		GEXSign: q.Get("gex"),
+		Regime:  q.Get("regime"),
+		Sort:    q.Get("sort"),
+	}
+	for _, v := range q["sector"] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				f.Sectors = append(f.Sectors, s)
+			}
+		}
+	}
+
+	switch f.CapTier {
+	case CapMega, CapLarge, CapMid, CapSmall:
+	default:
+		f.CapTier = ""
+	}
+	switch f.GEXSign {
+	case "positive", "negative":
+	default:
+		f.GEXSign = ""
+	}
+	switch f.Regime {
+	case RegimeAboveFlip, RegimeBelowFlip:
+	default:
+		f.Regime = ""
+	}
+	if !scannerSorts[f.Sort] {
+		f.Sort = SortZScoreAbsDesc
+	}
+
+	f.MinAbsZ = parsePositive(q.Get("min_z"))
+	f.MinPrice = parsePositive(q.Get("min_price"))
+	f.MaxPrice = parsePositive(q.Get("max_price"))
+	f.MaxFlipDistancePct = parsePositive(q.Get("near_flip"))
+
+	f.Z, _ = zscore.Parse(q)
+
+	f.Page, _ = strconv.Atoi(q.Get("page"))
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	f.PerPage, _ = strconv.Atoi(q.Get("per_page"))
+	if f.PerPage < 1 {
+		f.PerPage = defaultScannerPerPage
+	}
+	if f.PerPage > maxScannerPerPage {
+		f.PerPage = maxScannerPerPage
+	}
+	return f
+}
+
+func parsePositive(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
+
+// Values encodes the filter and sort as query parameters, leaving out
+// defaults and the page so the result can be saved as a view.
+func (f ScannerFilter) Values() url.Values {
+	q := url.Values{}
+	if len(f.Sectors) > 0 {
+		q.Set("sector", strings.Join(f.Sectors, ","))
+	}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	setFloat := func(key string, value float64) {
+		if value > 0 {
+			q.Set(key, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+	}
+	set("cap", f.CapTier)
+	set("gex", f.GEXSign)
+	set("regime", f.Regime)
+	setFloat("min_z", f.MinAbsZ)
+	setFloat("min_price", f.MinPrice)
+	setFloat("max_price", f.MaxPrice)
+	setFloat("near_flip", f.MaxFlipDistancePct)
+	if f.Sort != SortZScoreAbsDesc {
+		set("sort", f.Sort)
+	}
+	if f.PerPage != defaultScannerPerPage {
+		q.Set("per_page", strconv.Itoa(f.PerPage))
+	}
+	for key, values := range f.Z.Values() {
+		q[key] = values
+	}
+	return q
+}
+
+// Active reports whether any filter is set.
+func (f ScannerFilter) Active() bool {
+	return len(f.Sectors) > 0 || f.CapTier != "" || f.GEXSign != "" || f.MinAbsZ > 0 ||
+		f.MinPrice > 0 || f.MaxPrice > 0 || f.Regime != "" || f.MaxFlipDistancePct > 0
+}
+
+// HasSector reports whether sector is one of the selected sectors.
+func (f ScannerFilter) HasSector(sector string) bool {
+	for _, s := range f.Sectors {
+		if strings.EqualFold(s, sector) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether item passes every filter. Filters on data an item
+// doesn't have, such as the sector of a symbol without metadata or the
+// regime of a row without a flip level, exclude it.
+func (f ScannerFilter) Match(item GEXScanItem) bool {
+	if len(f.Sectors) > 0 && !f.HasSector(item.Sector) {
+		return false
+	}
+	if f.CapTier != "" && CapTier(item.MarketCap) != f.CapTier {
+		return false
+	}
+	switch f.GEXSign {
+	case "positive":
+		if item.CurrentGEX <= 0 {
+			return false
+		}
+	case "negative":
+		if item.CurrentGEX >= 0 {
+			return false
+		}
+	}
+	if f.MinAbsZ > 0 && math.Abs(item.ZScore) < f.MinAbsZ {
+		return false
+	}
+	if f.MinPrice > 0 && item.CurrentPrice < f.MinPrice {
+		return false
+	}
+	if f.MaxPrice > 0 && item.CurrentPrice > f.MaxPrice {
+		return false
+	}
+	if f.Regime != "" && item.Regime() != f.Regime {
+		return false
+	}
+	if f.MaxFlipDistancePct > 0 && (item.FlipLevel <= 0 || math.Abs(item.FlipDistancePct) > f.MaxFlipDistancePct) {
+		return false
+	}
+	return true
+}
+
+// Apply filters and sorts items in place and returns the ones that match.
+func (f ScannerFilter) Apply(items []GEXScanItem) []GEXScanItem {
+	matched := items[:0]
+	for _, item := range items {
+		if f.Match(item) {
+			matched = append(matched, item)
+		}
+	}
+	sortScanItems(matched, f.Sort)
+	return matched
+}
+
+func sortScanItems(items []GEXScanItem, order string) {
+	var less func(a, b GEXScanItem) bool
+	switch order {
+	case SortGEXAsc:
+		less = func(a, b GEXScanItem) bool { return a.CurrentGEX < b.CurrentGEX }
+	case SortGEXDesc:
+		less = func(a, b GEXScanItem) bool { return a.CurrentGEX > b.CurrentGEX }
+	case SortChangePctDesc:
+		less = func(a, b GEXScanItem) bool { return a.GEXChangePct > b.GEXChangePct }
+	case SortChangePctAsc:
+		less = func(a, b GEXScanItem) bool { return a.GEXChangePct < b.GEXChangePct }
+	case SortFlipDistanceAsc:
+		// Items without a flip level go last.
+		less = func(a, b GEXScanItem) bool {
+			if (a.FlipLevel > 0) != (b.FlipLevel > 0) {
+				return a.FlipLevel > 0
+			}
+			return math.Abs(a.FlipDistancePct) < math.Abs(b.FlipDistancePct)
+		}
+	default:
+		less = func(a, b GEXScanItem) bool { return math.Abs(a.ZScore) > math.Abs(b.ZScore) }
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if less(items[i], items[j]) {
+			return true
+		}
+		if less(items[j], items[i]) {
+			return false
+		}
+		return items[i].Symbol < items[j].Symbol
+	})
+}
+
+// ScannerPage is one page of scanner results.
+type ScannerPage struct {
+	Items      []GEXScanItem
+	Page       int
+	PerPage    int
+	TotalItems int
+	TotalPages int
+	// First and Last are the 1-based positions of the items on the page.
+	First int
+	Last  int
+}
+
+// HasPrev and HasNext report whether there are pages before and after this one.
+func (p ScannerPage) HasPrev() bool { return p.Page > 1 }
+func (p ScannerPage) HasNext() bool { return p.Page < p.TotalPages }
+
+// paginate returns the requested page of items. Pages past the end are
+// clamped to the last page.
+func paginate(items []GEXScanItem, page, perPage int) ScannerPage {
+	p := ScannerPage{
+		PerPage:    perPage,
+		TotalItems: len(items),
+		TotalPages: (len(items) + perPage - 1) / perPage,
+	}
+	if p.TotalPages == 0 {
+		p.TotalPages = 1
+	}
+	p.Page = min(max(page, 1), p.TotalPages)
+
+	start := (p.Page - 1) * perPage
+	end := min(start+perPage, len(items))
+	p.Items = items[start:end]
+	if len(p.Items) > 0 {
+		p.First, p.Last = start+1, end
+	}
+	return p
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arnabmitra/eth-proxy/internal/zscore"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func scanItems() []GEXScanItem {
+	items := []GEXScanItem{
+		{Symbol: "AAPL", CurrentGEX: 5e9, GEXChangePct: 12, CurrentPrice: 230, ZScore: 2.5, Sector: "Information Technology", MarketCap: 3.4e12},
+		{Symbol: "JPM", CurrentGEX: -1e9, GEXChangePct: -30, CurrentPrice: 240, ZScore: -3.1, Sector: "Financials", MarketCap: 650e9},
+		{Symbol: "XOM", CurrentGEX: 2e8, GEXChangePct: 4, CurrentPrice: 118, ZScore: 0.4, Sector: "Energy", MarketCap: 480e9},
+		{Symbol: "RIOT", CurrentGEX: -5e7, GEXChangePct: 55, CurrentPrice: 12, ZScore: 1.8, Sector: "Financials", MarketCap: 4e9},
+		{Symbol: "ZM", CurrentGEX: 1e7, GEXChangePct: -2, CurrentPrice: 80, ZScore: -0.2},
+	}
+	flips := map[string]string{"AAPL": "225", "JPM": "250", "XOM": "117.5", "RIOT": "13"}
+	for i := range items {
+		if flip, ok := flips[items[i].Symbol]; ok {
+			var n pgtype.Numeric
+			n.Scan(flip)
+			items[i].setFlipLevel(n)
+		}
+	}
+	return items
+}
+
+func symbols(items []GEXScanItem) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.Symbol)
+	}
+	return out
+}
+
+func TestParseScannerFilter(t *testing.T) {
+	q, _ := url.ParseQuery("sector=Energy,Financials&cap=large&gex=negative&min_z=2&min_price=10&max_price=-5" +
+		"&regime=sideways&near_flip=1.5&sort=bogus&page=3&per_page=500&z_method=mad&z_lookback=60&z_norm=bogus")
+	f := ParseScannerFilter(q)
+
+	want := ScannerFilter{
+		Sectors:            []string{"Energy", "Financials"},
+		CapTier:            CapLarge,
+		GEXSign:            "negative",
+		MinAbsZ:            2,
+		MinPrice:           10,
+		MaxFlipDistancePct: 1.5,
+		Sort:               SortZScoreAbsDesc,
+		Page:               3,
+		PerPage:            maxScannerPerPage,
+		Z:                  zscore.Config{Method: zscore.MethodMAD, LookbackDays: 60},
+	}
+	if !reflect.DeepEqual(f, want) {
+		t.Errorf("ParseScannerFilter =\n%+v\nwant\n%+v", f, want)
+	}
+
+	// Values round-trips everything except the page.
+	back := ParseScannerFilter(f.Values())
+	want.Page = 1
+	if !reflect.DeepEqual(back, want) {
+		t.Errorf("round trip =\n%+v\nwant\n%+v", back, want)
+	}
+	if got := ParseScannerFilter(url.Values{}).Values().Encode(); got != "" {
+		t.Errorf("default filter encodes as %q, want empty", got)
+	}
+}
+
+func TestScannerFilterApply(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"JPM", "AAPL", "RIOT", "XOM", "ZM"}},
+		{"sector=financials", []string{"JPM", "RIOT"}},
+		{"cap=mega", []string{"JPM", "AAPL", "XOM"}},
+		{"cap=mid", []string{"RIOT"}},
+		{"gex=positive", []string{"AAPL", "XOM", "ZM"}},
+		{"min_z=2", []string{"JPM", "AAPL"}},
+		{"min_price=50&max_price=235", []string{"AAPL", "XOM", "ZM"}},
+		{"regime=below_flip", []string{"JPM", "RIOT"}},
+		{"near_flip=3", []string{"AAPL", "XOM"}},
+		{"sort=gex_asc", []string{"JPM", "RIOT", "ZM", "XOM", "AAPL"}},
+		{"sort=change_pct_desc", []string{"RIOT", "AAPL", "XOM", "ZM", "JPM"}},
+		{"sort=change_pct_asc", []string{"JPM", "ZM", "XOM", "AAPL", "RIOT"}},
+		{"sort=flip_distance_asc", []string{"XOM", "AAPL", "JPM", "RIOT", "ZM"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			got := symbols(ParseScannerFilter(q).Apply(scanItems()))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply(%s) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := scanItems()
+
+	p := paginate(items, 2, 2)
+	if got := symbols(p.Items); !reflect.DeepEqual(got, []string{"XOM", "RIOT"}) {
+		t.Errorf("page 2 = %v", got)
+	}
+	if p.TotalPages != 3 || p.First != 3 || p.Last != 4 || !p.HasPrev() || !p.HasNext() {
+		t.Errorf("unexpected page %+v", p)
+	}
+
+	p = paginate(items, 9, 2)
+	if p.Page != 3 || len(p.Items) != 1 || p.HasNext() {
+		t.Errorf("page past the end = %+v", p)
+	}
+
+	p = paginate(nil, 1, 25)
+	if p.Page != 1 || p.TotalPages != 1 || len(p.Items) != 0 || p.First != 0 {
+		t.Errorf("empty page = %+v", p)
+	}
+}
+
+func TestScannerTemplatesRender(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+
+	q, _ := url.ParseQuery("sector=Financials&sort=flip_distance_asc&per_page=1")
+	filter := ParseScannerFilter(q)
+	items := filter.Apply(scanItems())
+	page := paginate(items, 2, filter.PerPage)
+	links := scannerLinks{filter: filter, universe: "sp500"}
+	data := map[string]interface{}{
+		"Items":       page.Items,
+		"Page":        page,
+		"Filter":      filter,
+		"PrevURL":     links.page(page.Page - 1),
+		"NextURL":     links.page(page.Page + 1),
+		"SortURLs":    links.sorts(),
+		"LastUpdated": "now",
+		"Sort":        filter.Sort,
+		"Sectors":     []string{"Energy", "Financials"},
+		"Universe":    "sp500",
+		"View":        "",
+		"ZScore":      zscore.Config{Method: zscore.MethodEWMA, LookbackDays: 60, Normalize: zscore.NormNone, HalfLifeDays: 10},
+	}
+
+	for _, name := range []string{"gex_scanner.html", "gex_scanner_table.html"} {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		out := buf.String()
+		if !strings.Contains(out, "RIOT") || strings.Contains(out, "JPM") {
+			t.Errorf("%s does not show only the second page", name)
+		}
+		if !strings.Contains(out, "Z-scores: ewma (half-life 10d), 60d") {
+			t.Errorf("%s does not show the Z-score method", name)
+		}
+		if !strings.Contains(out, "Showing 2&ndash;2 of 2") {
+			t.Errorf("%s is missing the result count", name)
+		}
+		if !strings.Contains(out, "/gex-scanner?per_page=1&amp;sector=Financials&amp;sort=flip_distance_asc&amp;universe=sp500") {
+			t.Errorf("%s pager links drop the filters", name)
+		}
+	}
+}
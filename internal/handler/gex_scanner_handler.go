@@ -1,39 +1,66 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"html/template"
 	"log/slog"
-	"math"
 	"net/http"
 	"sort"
 	"strconv"
 	"time"
 
 	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+	"github.com/arnabmitra/eth-proxy/internal/zscore"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type GEXScannerHandler struct {
-	logger         *slog.Logger
-	tmpl           *template.Template
-	repo           *repository.Queries
-	allowedSymbols map[string]bool
+	logger          *slog.Logger
+	tmpl            *template.Template
+	repo            *repository.Queries
+	universes       *universe.Store
+	defaultUniverse string
+	scorer          *zscore.Scorer
+	zscore          zscore.Config
 }
 
-func NewGEXScannerHandler(logger *slog.Logger, tmpl *template.Template, db *pgxpool.Pool, allowedSymbols []string) *GEXScannerHandler {
-	allowed := make(map[string]bool)
-	for _, s := range allowedSymbols {
-		allowed[s] = true
-	}
+// NewGEXScannerHandler scans the members of defaultUniverse unless the
+// request picks another one with ?universe=. Z-scores use zscoreDefaults
+// unless the request overrides them with the z_* parameters.
+func NewGEXScannerHandler(logger *slog.Logger, tmpl *template.Template, db *pgxpool.Pool, universes *universe.Store, defaultUniverse string, zscoreDefaults zscore.Config) *GEXScannerHandler {
+	repo := repository.New(db)
 	return &GEXScannerHandler{
-		logger:         logger,
-		tmpl:           tmpl,
-		repo:           repository.New(db),
-		allowedSymbols: allowed,
+		logger:          logger,
+		tmpl:            tmpl,
+		repo:            repo,
+		universes:       universes,
+		defaultUniverse: defaultUniverse,
+		scorer:          zscore.NewScorer(repo),
+		zscore:          zscoreDefaults.WithDefaults(zscore.Default()),
 	}
 }
 
+// allowedSymbols loads the members of the universe slug, or of the default
+// universe if slug is empty.
+func (h *GEXScannerHandler) allowedSymbols(ctx context.Context, slug string) (string, map[string]bool, error) {
+	if slug == "" {
+		slug = h.defaultUniverse
+	}
+	symbols, err := h.universes.Symbols(ctx, slug)
+	if err != nil {
+		return slug, nil, err
+	}
+	allowed := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		allowed[s] = true
+	}
+	return slug, allowed, nil
+}
+
 type GEXScanItem struct {
 	Symbol       string
 	CurrentGEX   float64
@@ -44,19 +71,132 @@ type GEXScanItem struct {
 	ExpiryDate   string
 	Direction    string // "up" or "down"
 	ZScore       float64
+	// ZMethod describes how ZScore was computed; empty when it wasn't.
+	ZMethod string
+	// FlipLevel is zero when the row was stored without one.
+	FlipLevel       float64
+	FlipDistancePct float64 // (price - flip) / price, in percent
+	Name            string
+	Sector          string
+	Industry        string
+	MarketCap       int64
+}
+
+// Regime returns RegimeAboveFlip or RegimeBelowFlip, or "" when the flip
+// level or price is unknown.
+func (i GEXScanItem) Regime() string {
+	if i.FlipLevel <= 0 || i.CurrentPrice <= 0 {
+		return ""
+	}
+	if i.CurrentPrice >= i.FlipLevel {
+		return RegimeAboveFlip
+	}
+	return RegimeBelowFlip
+}
+
+// CapTier returns the market cap tier of the item's symbol.
+func (i GEXScanItem) CapTier() string {
+	return CapTier(i.MarketCap)
+}
+
+func (i *GEXScanItem) setFlipLevel(level pgtype.Numeric) {
+	flip, _ := level.Float64Value()
+	if !flip.Valid || flip.Float64 <= 0 {
+		return
+	}
+	i.FlipLevel = flip.Float64
+	if i.CurrentPrice > 0 {
+		i.FlipDistancePct = (i.CurrentPrice - i.FlipLevel) / i.CurrentPrice * 100
+	}
 }
 
 func (h *GEXScannerHandler) HandleGEXScanner(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	now := time.Now()
-	loc, _ := time.LoadLocation("America/New_York")
-	nowInET := now.In(loc)
 
-	sortParam := r.URL.Query().Get("sort")
-	if sortParam == "" {
-		sortParam = "zscore_abs_desc"
+	owner := scannerVisitor(w, r)
+	query, view, err := h.requestQuery(ctx, r, owner)
+	if errors.Is(err, errViewNotFound) {
+		http.Error(w, "Unknown saved view", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to load scanner view", "view", view, "error", err)
+		http.Error(w, "Failed to load GEX scanner data", http.StatusInternalServerError)
+		return
+	}
+	filter := ParseScannerFilter(query)
+	zcfg := filter.Z.WithDefaults(h.zscore)
+
+	universeSlug, allowed, err := h.allowedSymbols(ctx, query.Get("universe"))
+	if errors.Is(err, universe.ErrNotFound) {
+		http.Error(w, "Unknown universe", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to load universe", "universe", universeSlug, "error", err)
+		http.Error(w, "Failed to load GEX scanner data", http.StatusInternalServerError)
+		return
+	}
+
+	items, sectors, err := h.loadScanItems(ctx, now, allowed, zcfg)
+	if err != nil {
+		h.logger.Error("failed to get GEX data", "error", err)
+		http.Error(w, "Failed to load GEX scanner data", http.StatusInternalServerError)
+		return
+	}
+
+	page := paginate(filter.Apply(items), filter.Page, filter.PerPage)
+
+	universes, err := h.universes.List(ctx)
+	if err != nil {
+		h.logger.Error("failed to list universes", "error", err)
 	}
 
+	views, err := h.repo.ListScannerViews(ctx, owner)
+	if err != nil {
+		h.logger.Error("failed to list scanner views", "error", err)
+	}
+
+	links := scannerLinks{filter: filter, universe: universeSlug}
+	data := map[string]interface{}{
+		"Items":       page.Items,
+		"Page":        page,
+		"Filter":      filter,
+		"PrevURL":     links.page(page.Page - 1),
+		"NextURL":     links.page(page.Page + 1),
+		"SortURLs":    links.sorts(),
+		"LastUpdated": now.Format("Jan 02, 2006 3:04 PM MST"),
+		"Sort":        filter.Sort,
+		"Sectors":     sectors,
+		"Universe":    universeSlug,
+		"Universes":   universes,
+		"Views":       views,
+		"View":        view,
+		"ZScore":      zcfg,
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		err = h.tmpl.ExecuteTemplate(w, "gex_scanner_table.html", data)
+	} else {
+		err = h.tmpl.ExecuteTemplate(w, "gex_scanner.html", data)
+	}
+
+	if err != nil {
+		h.logger.Error("failed to render template", "error", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// loadScanItems returns the latest GEX change of every symbol in allowed,
+// with Z-scores computed by zcfg, flip levels and symbol metadata filled in,
+// and the list of known sectors. During market hours changes are measured over the last hour;
+// otherwise, or if nothing was collected in that hour, between the last two
+// collections.
+func (h *GEXScannerHandler) loadScanItems(ctx context.Context, now time.Time, allowed map[string]bool, zcfg zscore.Config) ([]GEXScanItem, []string, error) {
+	loc, _ := time.LoadLocation("America/New_York")
+	nowInET := now.In(loc)
+
 	var items []GEXScanItem
 	var err error
 
@@ -78,7 +218,7 @@ func (h *GEXScannerHandler) HandleGEXScanner(w http.ResponseWriter, r *http.Requ
 		})
 		err = err_
 		if err == nil {
-			items = h.processGEXChangeResults(results)
+			items = h.processGEXChangeResults(results, allowed)
 		}
 
 		// If no items found during market hours (e.g. just opened or delay), fall back to latest changes
@@ -86,7 +226,7 @@ func (h *GEXScannerHandler) HandleGEXScanner(w http.ResponseWriter, r *http.Requ
 			results, err_ := h.repo.GetLatestGEXChanges(ctx)
 			err = err_
 			if err == nil {
-				items = h.processLatestGEXChangesResults(results)
+				items = h.processLatestGEXChangesResults(results, allowed)
 			}
 		}
 	} else {
@@ -94,94 +234,137 @@ func (h *GEXScannerHandler) HandleGEXScanner(w http.ResponseWriter, r *http.Requ
 		results, err_ := h.repo.GetLatestGEXChanges(ctx)
 		err = err_
 		if err == nil {
-			items = h.processLatestGEXChangesResults(results)
+			items = h.processLatestGEXChangesResults(results, allowed)
 		}
 	}
 
 	if err != nil {
-		h.logger.Error("failed to get GEX data", "error", err)
-		http.Error(w, "Failed to load GEX scanner data", http.StatusInternalServerError)
-		return
+		return nil, nil, err
 	}
 
-	// Fetch anomalies/z-scores
-	anomalies, err := h.repo.GetGEXAnomalies(ctx)
+	scores, err := h.scorer.Latest(ctx, zcfg, now)
 	if err != nil {
-		h.logger.Error("failed to fetch GEX anomalies", "error", err)
-	} else {
-		// Create a map for fast lookup
-		zScoreMap := make(map[string]float64)
-		for _, anomaly := range anomalies {
-			z, _ := anomaly.ZScore.Float64Value()
-			zScoreMap[anomaly.Symbol] = z.Float64
-		}
-
-		// Enhance items with ZScore
-		for i := range items {
-			if z, ok := zScoreMap[items[i].Symbol]; ok {
-				items[i].ZScore = z
-			}
+		h.logger.Error("failed to compute GEX z-scores", "method", zcfg.String(), "error", err)
+	}
+	for i := range items {
+		if z, ok := scores[items[i].Symbol]; ok {
+			items[i].ZScore = z.Score
+			items[i].ZMethod = z.Method
 		}
 	}
 
-	// Sort items
-	if sortParam == "gex_asc" {
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].CurrentGEX < items[j].CurrentGEX
-		})
-	} else if sortParam == "gex_desc" {
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].CurrentGEX > items[j].CurrentGEX
-		})
-	} else if sortParam == "zscore_abs_desc" {
-		sort.Slice(items, func(i, j int) bool {
-			return math.Abs(items[i].ZScore) > math.Abs(items[j].ZScore)
-		})
+	metadata, err := h.repo.ListSymbolMetadata(ctx)
+	if err != nil {
+		h.logger.Error("failed to load symbol metadata", "error", err)
 	}
+	sectors := applySymbolMetadata(items, metadata)
+	return items, sectors, nil
+}
 
-	data := map[string]interface{}{
-		"Items":       items,
-		"LastUpdated": now.Format("Jan 02, 2006 3:04 PM MST"),
-		"Sort":        sortParam,
+// applySymbolMetadata fills in the name, sector, industry and market cap of
+// items and returns the sorted list of known sectors for the filter form.
+func applySymbolMetadata(items []GEXScanItem, metadata []repository.SymbolMetadatum) []string {
+	bySymbol := make(map[string]repository.SymbolMetadatum, len(metadata))
+	seen := make(map[string]bool)
+	var sectors []string
+	for _, m := range metadata {
+		bySymbol[m.Symbol] = m
+		if m.Sector != "" && !seen[m.Sector] {
+			seen[m.Sector] = true
+			sectors = append(sectors, m.Sector)
+		}
 	}
+	sort.Strings(sectors)
+
+	for i := range items {
+		if m, ok := bySymbol[items[i].Symbol]; ok {
+			items[i].Name = m.Name
+			items[i].Sector = m.Sector
+			items[i].Industry = m.Industry
+			items[i].MarketCap = m.MarketCap.Int64
+		}
+	}
+	return sectors
+}
 
-	if r.Header.Get("HX-Request") == "true" {
-		err = h.tmpl.ExecuteTemplate(w, "gex_scanner_table.html", data)
-	} else {
-		err = h.tmpl.ExecuteTemplate(w, "gex_scanner.html", data)
+// scannerLinks builds scanner URLs that keep the current universe and filters.
+type scannerLinks struct {
+	filter   ScannerFilter
+	universe string
+}
+
+func (l scannerLinks) query(page int) string {
+	q := l.filter.Values()
+	q.Set("universe", l.universe)
+	if page > 1 {
+		q.Set("page", strconv.Itoa(page))
 	}
+	return q.Encode()
+}
 
-	if err != nil {
-		h.logger.Error("failed to render template", "error", err)
-		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+func (l scannerLinks) page(page int) string {
+	return "/gex-scanner?" + l.query(page)
+}
+
+// sorts returns the first-page URL for each sort order.
+func (l scannerLinks) sorts() map[string]string {
+	urls := make(map[string]string, len(scannerSorts))
+	for order := range scannerSorts {
+		f := l.filter
+		f.Sort = order
+		urls[order] = scannerLinks{filter: f, universe: l.universe}.page(1)
 	}
+	return urls
 }
 
+// HandleZScoreHistory returns the Z-score of every snapshot of ?symbol= over
+// the last ?days= (default 7, at most 90), computed with the z_* parameters
+// layered over the server default. Each point carries the method used.
 func (h *GEXScannerHandler) HandleZScoreHistory(w http.ResponseWriter, r *http.Request) {
-	symbol := r.URL.Query().Get("symbol")
+	q := r.URL.Query()
+	symbol := q.Get("symbol")
 	if symbol == "" {
 		http.Error(w, "Symbol is required", http.StatusBadRequest)
 		return
 	}
 
-	results, err := h.repo.GetHistoricalGEXAnomalies(r.Context(), symbol)
+	zcfg, err := zscore.Parse(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	zcfg = zcfg.WithDefaults(h.zscore)
+
+	days := 7
+	if v := q.Get("days"); v != "" {
+		days, err = strconv.Atoi(v)
+		if err != nil || days < 1 || days > 90 {
+			http.Error(w, "days must be 1 to 90", http.StatusBadRequest)
+			return
+		}
+	}
+
+	results, err := h.scorer.History(r.Context(), zcfg, symbol, time.Now().AddDate(0, 0, -days))
 	if err != nil {
-		h.logger.Error("failed to fetch historical anomalies", "symbol", symbol, "error", err)
+		h.logger.Error("failed to compute z-score history", "symbol", symbol, "method", zcfg.String(), "error", err)
 		http.Error(w, "Internal error", http.StatusInternalServerError)
 		return
 	}
 
 	type HistoryPoint struct {
-		Time   string  `json:"time"`
-		ZScore float64 `json:"zscore"`
+		Time         string  `json:"time"`
+		ZScore       float64 `json:"zscore"`
+		Observations int     `json:"observations"`
+		Method       string  `json:"method"`
 	}
 
 	points := make([]HistoryPoint, 0, len(results))
-	for _, r := range results {
-		z, _ := r.ZScore.Float64Value()
+	for _, p := range results {
 		points = append(points, HistoryPoint{
-			Time:   r.RecordedAt.Format(time.RFC3339),
-			ZScore: z.Float64,
+			Time:         p.Time.Format(time.RFC3339),
+			ZScore:       p.Score,
+			Observations: p.Observations,
+			Method:       p.Method,
 		})
 	}
 
@@ -189,10 +372,10 @@ func (h *GEXScannerHandler) HandleZScoreHistory(w http.ResponseWriter, r *http.R
 	json.NewEncoder(w).Encode(points)
 }
 
-func (h *GEXScannerHandler) processGEXChangeResults(results []repository.GetGEXChangeForSymbolsRow) []GEXScanItem {
+func (h *GEXScannerHandler) processGEXChangeResults(results []repository.GetGEXChangeForSymbolsRow, allowed map[string]bool) []GEXScanItem {
 	items := make([]GEXScanItem, 0, len(results))
 	for _, result := range results {
-		if !h.allowedSymbols[result.Symbol] {
+		if !allowed[result.Symbol] {
 			continue
 		}
 
@@ -230,14 +413,15 @@ func (h *GEXScannerHandler) processGEXChangeResults(results []repository.GetGEXC
 			ExpiryDate:   expiryDate,
 			Direction:    direction,
 		})
+		items[len(items)-1].setFlipLevel(result.FlipLevel)
 	}
 	return items
 }
 
-func (h *GEXScannerHandler) processLatestGEXChangesResults(results []repository.GetLatestGEXChangesRow) []GEXScanItem {
+func (h *GEXScannerHandler) processLatestGEXChangesResults(results []repository.GetLatestGEXChangesRow, allowed map[string]bool) []GEXScanItem {
 	items := make([]GEXScanItem, 0, len(results))
 	for _, result := range results {
-		if !h.allowedSymbols[result.Symbol] {
+		if !allowed[result.Symbol] {
 			continue
 		}
 
@@ -275,6 +459,7 @@ func (h *GEXScannerHandler) processLatestGEXChangesResults(results []repository.
 			ExpiryDate:   expiryDate,
 			Direction:    direction,
 		})
+		items[len(items)-1].setFlipLevel(result.FlipLevel)
 	}
 	return items
 }
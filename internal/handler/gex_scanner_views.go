@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// scannerVisitorCookie identifies a browser so saved views can be kept per
+// user without accounts.
+const scannerVisitorCookie = "gex_scanner_visitor"
+
+var errViewNotFound = errors.New("scanner view not found")
+
+// scannerVisitor returns the visitor id from the request cookie, setting a
+// new one if there isn't a valid one.
+func scannerVisitor(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(scannerVisitorCookie); err == nil {
+		if id, err := uuid.Parse(c.Value); err == nil {
+			return id.String()
+		}
+	}
+	id := uuid.New().String()
+	http.SetCookie(w, &http.Cookie{
+		Name:     scannerVisitorCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
+
+// requestQuery returns the scanner query for r. With ?view= the saved view's
+// query is used and only the page is taken from the request.
+func (h *GEXScannerHandler) requestQuery(ctx context.Context, r *http.Request, owner string) (url.Values, string, error) {
+	q := r.URL.Query()
+	name := q.Get("view")
+	if name == "" {
+		return q, "", nil
+	}
+
+	view, err := h.repo.GetScannerView(ctx, repository.GetScannerViewParams{Owner: owner, Name: name})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, name, fmt.Errorf("%w: %s", errViewNotFound, name)
+	}
+	if err != nil {
+		return nil, name, err
+	}
+	saved, err := url.ParseQuery(view.Query)
+	if err != nil {
+		return nil, name, fmt.Errorf("parse view %s: %w", name, err)
+	}
+	if page := q.Get("page"); page != "" {
+		saved.Set("page", page)
+	}
+	return saved, name, nil
+}
+
+// ScannerViewResponse is a saved view as returned by the views API.
+type ScannerViewResponse struct {
+	Name      string `json:"name"`
+	Query     string `json:"query"`
+	URL       string `json:"url"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// HandleViews manages the caller's saved scanner views. GET lists them, POST
+// saves the filters in the form under ?name= (or the "name" field) and
+// DELETE removes ?name=.
+func (h *GEXScannerHandler) HandleViews(w http.ResponseWriter, r *http.Request) {
+	owner := scannerVisitor(w, r)
+	switch r.Method {
+	case http.MethodGet:
+		h.listViews(w, r, owner)
+	case http.MethodPost:
+		h.saveView(w, r, owner)
+	case http.MethodDelete:
+		h.deleteView(w, r, owner)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *GEXScannerHandler) listViews(w http.ResponseWriter, r *http.Request, owner string) {
+	views, err := h.repo.ListScannerViews(r.Context(), owner)
+	if err != nil {
+		h.logger.Error("failed to list scanner views", "error", err)
+		http.Error(w, "Failed to list views", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]ScannerViewResponse, 0, len(views))
+	for _, v := range views {
+		resp = append(resp, ScannerViewResponse{
+			Name:      v.Name,
+			Query:     v.Query,
+			URL:       "/gex-scanner?view=" + url.QueryEscape(v.Name),
+			UpdatedAt: v.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"views": resp,
+		"count": len(resp),
+	})
+}
+
+func (h *GEXScannerHandler) saveView(w http.ResponseWriter, r *http.Request, owner string) {
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	name, err := viewName(r.Form.Get("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	q := ParseScannerFilter(r.Form).Values()
+	if universe := r.Form.Get("universe"); universe != "" {
+		q.Set("universe", universe)
+	}
+	if _, err := h.repo.UpsertScannerView(r.Context(), repository.UpsertScannerViewParams{
+		Owner: owner,
+		Name:  name,
+		Query: q.Encode(),
+	}); err != nil {
+		h.logger.Error("failed to save scanner view", "view", name, "error", err)
+		http.Error(w, "Failed to save view", http.StatusInternalServerError)
+		return
+	}
+
+	target := "/gex-scanner?view=" + url.QueryEscape(name)
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", target)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+func (h *GEXScannerHandler) deleteView(w http.ResponseWriter, r *http.Request, owner string) {
+	name, err := viewName(r.URL.Query().Get("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.repo.DeleteScannerView(r.Context(), repository.DeleteScannerViewParams{Owner: owner, Name: name})
+	if err != nil {
+		h.logger.Error("failed to delete scanner view", "view", name, "error", err)
+		http.Error(w, "Failed to delete view", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "Unknown saved view", http.StatusNotFound)
+		return
+	}
+
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/gex-scanner")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func viewName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("name is required")
+	}
+	if len(name) > 100 {
+		return "", fmt.Errorf("name must be at most 100 characters")
+	}
+	return name, nil
+}
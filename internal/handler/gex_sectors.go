@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+	"github.com/arnabmitra/eth-proxy/internal/zscore"
+)
+
+// unclassified groups symbols without a sector or industry.
+const unclassified = "Unclassified"
+
+// Heatmap colour metrics.
+const (
+	HeatAvgZScore     = "avg_z"
+	HeatPositiveShare = "positive_share"
+	HeatTotalGEX      = "total_gex"
+)
+
+// SectorAggregate is the GEX rollup of a group of symbols.
+type SectorAggregate struct {
+	Name      string  `json:"name"`
+	Symbols   int     `json:"symbols"`
+	TotalGEX  float64 `json:"total_gex"`
+	GEXChange float64 `json:"gex_change"`
+	// PositiveShare is the fraction of symbols with positive GEX, 0 to 1.
+	PositiveShare float64 `json:"positive_share"`
+	AvgZScore     float64 `json:"avg_zscore"`
+	// Top lists up to five members with the largest absolute GEX.
+	Top []string `json:"top"`
+	// Heat is the colour metric scaled to -1..1.
+	Heat float64 `json:"heat"`
+
+	positive int
+	zSum     float64
+	members  []GEXScanItem
+}
+
+func (a *SectorAggregate) add(item GEXScanItem) {
+	a.Symbols++
+	a.TotalGEX += item.CurrentGEX
+	a.GEXChange += item.GEXChange
+	a.zSum += item.ZScore
+	if item.CurrentGEX > 0 {
+		a.positive++
+	}
+	a.members = append(a.members, item)
+}
+
+func (a *SectorAggregate) finish() {
+	if a.Symbols == 0 {
+		return
+	}
+	a.PositiveShare = float64(a.positive) / float64(a.Symbols)
+	a.AvgZScore = a.zSum / float64(a.Symbols)
+
+	sort.SliceStable(a.members, func(i, j int) bool {
+		return math.Abs(a.members[i].CurrentGEX) > math.Abs(a.members[j].CurrentGEX)
+	})
+	a.Top = make([]string, 0, 5)
+	for _, m := range a.members[:min(5, len(a.members))] {
+		a.Top = append(a.Top, m.Symbol)
+	}
+}
+
+// PositivePct returns PositiveShare as a percentage.
+func (a SectorAggregate) PositivePct() float64 {
+	return a.PositiveShare * 100
+}
+
+// HeatColor returns the tile background for Heat: green for positive,
+// red for negative, stronger the further from zero. It is built only from
+// numbers, so it is safe to mark as CSS.
+func (a SectorAggregate) HeatColor() template.CSS {
+	alpha := 0.15 + 0.7*math.Min(math.Abs(a.Heat), 1)
+	if a.Heat >= 0 {
+		return template.CSS(fmt.Sprintf("rgba(16, 185, 129, %.2f)", alpha))
+	}
+	return template.CSS(fmt.Sprintf("rgba(239, 68, 68, %.2f)", alpha))
+}
+
+// aggregateSectors groups items by sector, or by industry when byIndustry is
+// set, and also returns the rollup of all items. Groups are ordered by total
+// GEX, largest first.
+func aggregateSectors(items []GEXScanItem, byIndustry bool) ([]SectorAggregate, SectorAggregate) {
+	groups := make(map[string]*SectorAggregate)
+	var all SectorAggregate
+	for _, item := range items {
+		name := item.Sector
+		if byIndustry {
+			name = item.Industry
+		}
+		if name == "" {
+			name = unclassified
+		}
+		g, ok := groups[name]
+		if !ok {
+			g = &SectorAggregate{Name: name}
+			groups[name] = g
+		}
+		g.add(item)
+		all.add(item)
+	}
+
+	out := make([]SectorAggregate, 0, len(groups))
+	for _, g := range groups {
+		g.finish()
+		out = append(out, *g)
+	}
+	all.finish()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TotalGEX != out[j].TotalGEX {
+			return out[i].TotalGEX > out[j].TotalGEX
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, all
+}
+
+// setHeat fills in Heat for each group from metric. Average Z-scores are
+// scaled so ±3σ is full strength, positive share so 50% is neutral, and
+// total GEX relative to the largest group.
+func setHeat(groups []SectorAggregate, metric string) {
+	maxGEX := 0.0
+	for _, g := range groups {
+		maxGEX = math.Max(maxGEX, math.Abs(g.TotalGEX))
+	}
+	for i := range groups {
+		g := &groups[i]
+		switch metric {
+		case HeatPositiveShare:
+			g.Heat = (g.PositiveShare - 0.5) * 2
+		case HeatTotalGEX:
+			if maxGEX > 0 {
+				g.Heat = g.TotalGEX / maxGEX
+			}
+		default:
+			g.Heat = math.Max(-1, math.Min(1, g.AvgZScore/3))
+		}
+	}
+}
+
+// sectorRollup is the data behind the sector page and API.
+type sectorRollup struct {
+	Universe string
+	By       string
+	Metric   string
+	Groups   []SectorAggregate
+	Index    SectorAggregate
+	ZScore   zscore.Config
+	AsOf     time.Time
+}
+
+func (h *GEXScannerHandler) sectorRollup(w http.ResponseWriter, r *http.Request) (*sectorRollup, bool) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	by := q.Get("by")
+	if by != "industry" {
+		by = "sector"
+	}
+	metric := q.Get("metric")
+	switch metric {
+	case HeatPositiveShare, HeatTotalGEX:
+	default:
+		metric = HeatAvgZScore
+	}
+
+	slug, allowed, err := h.allowedSymbols(ctx, q.Get("universe"))
+	if errors.Is(err, universe.ErrNotFound) {
+		http.Error(w, "Unknown universe", http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("failed to load universe", "universe", slug, "error", err)
+		http.Error(w, "Failed to load sector data", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	zcfg, _ := zscore.Parse(q)
+	zcfg = zcfg.WithDefaults(h.zscore)
+	now := time.Now()
+	items, _, err := h.loadScanItems(ctx, now, allowed, zcfg)
+	if err != nil {
+		h.logger.Error("failed to get GEX data", "error", err)
+		http.Error(w, "Failed to load sector data", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	groups, index := aggregateSectors(items, by == "industry")
+	setHeat(groups, metric)
+	return &sectorRollup{
+		Universe: slug,
+		By:       by,
+		Metric:   metric,
+		Groups:   groups,
+		Index:    index,
+		ZScore:   zcfg,
+		AsOf:     now,
+	}, true
+}
+
+// HandleSectorAPI returns aggregate GEX, positive-gamma share and average
+// Z-score per sector (or ?by=industry) and for the whole universe.
+func (h *GEXScannerHandler) HandleSectorAPI(w http.ResponseWriter, r *http.Request) {
+	rollup, ok := h.sectorRollup(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"universe": rollup.Universe,
+		"by":       rollup.By,
+		"metric":   rollup.Metric,
+		"as_of":    rollup.AsOf.Format(time.RFC3339),
+		"zscore":   rollup.ZScore,
+		"method":   rollup.ZScore.String(),
+		"index":    rollup.Index,
+		"groups":   rollup.Groups,
+	})
+}
+
+// HandleSectorHeatmap renders the sector heatmap page.
+func (h *GEXScannerHandler) HandleSectorHeatmap(w http.ResponseWriter, r *http.Request) {
+	rollup, ok := h.sectorRollup(w, r)
+	if !ok {
+		return
+	}
+
+	universes, err := h.universes.List(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list universes", "error", err)
+	}
+	universeName := rollup.Universe
+	for _, u := range universes {
+		if u.Slug == rollup.Universe {
+			universeName = u.Name
+		}
+	}
+
+	// Link sector tiles to the scanner filtered to that sector.
+	scannerURLs := make(map[string]string, len(rollup.Groups))
+	if rollup.By == "sector" {
+		for _, g := range rollup.Groups {
+			if g.Name == unclassified {
+				continue
+			}
+			scannerURLs[g.Name] = "/gex-scanner?" + url.Values{"universe": {rollup.Universe}, "sector": {g.Name}}.Encode()
+		}
+	}
+
+	data := map[string]interface{}{
+		"Groups":       rollup.Groups,
+		"Index":        rollup.Index,
+		"By":           rollup.By,
+		"Metric":       rollup.Metric,
+		"Universe":     rollup.Universe,
+		"UniverseName": universeName,
+		"Universes":    universes,
+		"ScannerURLs":  scannerURLs,
+		"ZMethod":      rollup.ZScore.String(),
+		"LastUpdated":  rollup.AsOf.Format("Jan 02, 2006 3:04 PM MST"),
+	}
+	if err := h.tmpl.ExecuteTemplate(w, "gex_sectors.html", data); err != nil {
+		h.logger.Error("failed to render template", "error", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
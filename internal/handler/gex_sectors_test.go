@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAggregateSectors(t *testing.T) {
+	items := scanItems()
+	items[0].Industry = "Technology Hardware"
+
+	groups, index := aggregateSectors(items, false)
+
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	if want := []string{"Information Technology", "Energy", unclassified, "Financials"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("groups = %v, want %v", names, want)
+	}
+
+	fin := groups[3]
+	if fin.Symbols != 2 || fin.TotalGEX != -1.05e9 || fin.PositiveShare != 0 {
+		t.Errorf("financials = %+v", fin)
+	}
+	if math.Abs(fin.AvgZScore-(-0.65)) > 1e-9 {
+		t.Errorf("financials avg Z = %v, want -0.65", fin.AvgZScore)
+	}
+	if !reflect.DeepEqual(fin.Top, []string{"JPM", "RIOT"}) {
+		t.Errorf("financials top = %v", fin.Top)
+	}
+
+	if index.Symbols != 5 || index.PositiveShare != 0.6 || len(index.Top) != 5 || index.Top[0] != "AAPL" {
+		t.Errorf("index = %+v", index)
+	}
+
+	byIndustry, _ := aggregateSectors(items, true)
+	if len(byIndustry) != 2 || byIndustry[0].Name != "Technology Hardware" || byIndustry[1].Symbols != 4 {
+		t.Errorf("by industry = %+v", byIndustry)
+	}
+}
+
+func TestSetHeat(t *testing.T) {
+	groups := []SectorAggregate{
+		{Name: "a", TotalGEX: 4e9, PositiveShare: 1, AvgZScore: 6},
+		{Name: "b", TotalGEX: -2e9, PositiveShare: 0.25, AvgZScore: -1.5},
+	}
+
+	for _, tt := range []struct {
+		metric string
+		want   []float64
+	}{
+		{HeatAvgZScore, []float64{1, -0.5}},
+		{HeatPositiveShare, []float64{1, -0.5}},
+		{HeatTotalGEX, []float64{1, -0.5}},
+	} {
+		setHeat(groups, tt.metric)
+		if got := []float64{groups[0].Heat, groups[1].Heat}; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s heat = %v, want %v", tt.metric, got, tt.want)
+		}
+	}
+}
+
+func TestSectorTemplateRenders(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+
+	groups, index := aggregateSectors(scanItems(), false)
+	setHeat(groups, HeatAvgZScore)
+	data := map[string]interface{}{
+		"Groups":       groups,
+		"Index":        index,
+		"By":           "sector",
+		"Metric":       HeatAvgZScore,
+		"Universe":     "sp500",
+		"UniverseName": "S&P 500",
+		"ScannerURLs":  map[string]string{"Financials": "/gex-scanner?sector=Financials&universe=sp500"},
+		"LastUpdated":  "now",
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "gex_sectors.html", data); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "ZgotmplZ") {
+		t.Error("template output contains a sanitized value")
+	}
+	if !strings.Contains(out, "background-color: rgba(") {
+		t.Error("tiles are missing their heat colour")
+	}
+	if !strings.Contains(out, `href="/gex-scanner?sector=Financials&amp;universe=sp500"`) {
+		t.Error("sector tile doesn't link to the scanner")
+	}
+}
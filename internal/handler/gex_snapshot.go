@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/arnabmitra/eth-proxy/internal/cache"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+)
+
+// DefaultSnapshotTTL is how long an all-expiry snapshot is served before a
+// view triggers a background refresh.
+const DefaultSnapshotTTL = 5 * time.Minute
+
+const (
+	// snapshotRefreshTimeout bounds one symbol's all-expiry calculation.
+	snapshotRefreshTimeout = 2 * time.Minute
+	// snapshotRetryAfter is how long a symbol whose refresh failed is left
+	// alone, so an unknown ticker can't turn page views into provider calls.
+	snapshotRetryAfter = time.Minute
+	// snapshotWait is how long the all-expiry page waits for a symbol that
+	// has no snapshot yet before telling the visitor to come back.
+	snapshotWait = 10 * time.Second
+)
+
+// StrikeGEX is the net GEX of one strike across all expiries.
+type StrikeGEX struct {
+	Strike float64 `json:"strike"`
+	GEX    float64 `json:"gex"`
+}
+
+// GEXSnapshot is a precomputed all-expiry GEX profile of a symbol.
+type GEXSnapshot struct {
+	Symbol    string
+	SpotPrice float64
+	// Strikes holds the non-zero strikes, ordered by strike.
+	Strikes    []StrikeGEX
+	Warning    string
+	ComputedAt time.Time
+	// Stale is set when the snapshot is older than the TTL; a refresh is
+	// already running.
+	Stale bool
+}
+
+func newGEXSnapshot(symbol string, spot float64, gexByStrike map[float64]float64, warning string, computedAt time.Time) *GEXSnapshot {
+	s := &GEXSnapshot{Symbol: symbol, SpotPrice: spot, Warning: warning, ComputedAt: computedAt}
+	for strike, gexValue := range gexByStrike {
+		if gexValue != 0 {
+			s.Strikes = append(s.Strikes, StrikeGEX{Strike: strike, GEX: gexValue})
+		}
+	}
+	sort.Slice(s.Strikes, func(i, j int) bool { return s.Strikes[i].Strike < s.Strikes[j].Strike })
+	return s
+}
+
+// GEXByStrike returns the strikes as a map, as CalculateGEXForAllExpiries
+// does.
+func (s *GEXSnapshot) GEXByStrike() map[float64]float64 {
+	m := make(map[float64]float64, len(s.Strikes))
+	for _, st := range s.Strikes {
+		m[st.Strike] = st.GEX
+	}
+	return m
+}
+
+func (s *GEXSnapshot) TotalGEX() float64 {
+	total := 0.0
+	for _, st := range s.Strikes {
+		total += st.GEX
+	}
+	return total
+}
+
+// Updated describes the snapshot's age, e.g. "3 minutes ago".
+func (s *GEXSnapshot) Updated() string {
+	return humanize.Time(s.ComputedAt)
+}
+
+// refreshGroup runs at most one refresh per key at a time. Callers that
+// arrive while one is running get its done channel instead of starting
+// another, and a key whose refresh failed isn't retried for retryAfter, so
+// snapshot refreshes don't multiply with traffic.
+type refreshGroup struct {
+	retryAfter time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]chan struct{}
+	failed   map[string]time.Time
+}
+
+func (g *refreshGroup) do(key string, fn func() error) <-chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if done, ok := g.inflight[key]; ok {
+		return done
+	}
+	if at, ok := g.failed[key]; ok && time.Since(at) < g.retryAfter {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+	if g.inflight == nil {
+		g.inflight = make(map[string]chan struct{})
+		g.failed = make(map[string]time.Time)
+	}
+	done := make(chan struct{})
+	g.inflight[key] = done
+	go func() {
+		err := fn()
+		g.mu.Lock()
+		delete(g.inflight, key)
+		if err != nil {
+			g.failed[key] = time.Now()
+		} else {
+			delete(g.failed, key)
+		}
+		g.mu.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+// SetSnapshotTTL changes how long snapshots are served before revalidating.
+func (h *GEXHandler) SetSnapshotTTL(ttl time.Duration) {
+	h.snapshotTTL = ttl
+}
+
+// SnapshotTTL returns how long snapshots are served before revalidating.
+func (h *GEXHandler) SnapshotTTL() time.Duration {
+	return h.snapshotTTL
+}
+
+// RefreshSnapshot recalculates and stores symbol's all-expiry snapshot,
+// joining a refresh already in progress, and waits for it to finish.
+func (h *GEXHandler) RefreshSnapshot(ctx context.Context, symbol string) error {
+	select {
+	case <-h.revalidate(symbol):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// revalidate starts a background refresh of symbol's snapshot unless one is
+// already running, and returns a channel closed when it finishes. The
+// refresh outlives the request that triggered it.
+func (h *GEXHandler) revalidate(symbol string) <-chan struct{} {
+	return h.refreshes.do(symbol, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), snapshotRefreshTimeout)
+		defer cancel()
+
+		s, err := h.computeSnapshot(ctx, symbol)
+		if err != nil {
+			h.logProviderError(ctx, "failed to refresh GEX snapshot", err, "symbol", symbol)
+			return err
+		}
+		if err := h.storeSnapshot(ctx, s); err != nil {
+			h.logger.Error("failed to store GEX snapshot", "symbol", symbol, "error", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// computeSnapshot calculates symbol's all-expiry GEX. The result is cached
+// briefly so instances sharing a Redis cache don't each recalculate it.
+func (h *GEXHandler) computeSnapshot(ctx context.Context, symbol string) (*GEXSnapshot, error) {
+	return cache.Fetch(ctx, h.cache, cache.KindGEX, symbol, func(ctx context.Context) (*GEXSnapshot, error) {
+		gexByStrike, warning, err := h.CalculateGEXForAllExpiries(ctx, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("calculate GEX: %w", err)
+		}
+		price, err := h.spotPrice(ctx, symbol)
+		if err != nil {
+			return nil, fmt.Errorf("get spot price: %w", err)
+		}
+		return newGEXSnapshot(symbol, price, gexByStrike, warning, time.Now()), nil
+	})
+}
+
+func (h *GEXHandler) storeSnapshot(ctx context.Context, s *GEXSnapshot) error {
+	strikes, err := json.Marshal(s.Strikes)
+	if err != nil {
+		return err
+	}
+	return h.repo.UpsertGEXSnapshot(ctx, repository.UpsertGEXSnapshotParams{
+		Symbol:     s.Symbol,
+		SpotPrice:  s.SpotPrice,
+		Strikes:    strikes,
+		Warning:    s.Warning,
+		ComputedAt: s.ComputedAt,
+	})
+}
+
+// snapshots returns the stored snapshots of symbols, starting a background
+// refresh of any that are missing or older than the TTL. Stale snapshots are
+// still returned; a later view gets the refreshed one.
+func (h *GEXHandler) snapshots(ctx context.Context, symbols []string) (map[string]*GEXSnapshot, error) {
+	rows, err := h.repo.ListGEXSnapshots(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make(map[string]*GEXSnapshot, len(rows))
+	for _, row := range rows {
+		s := &GEXSnapshot{Symbol: row.Symbol, SpotPrice: row.SpotPrice, Warning: row.Warning, ComputedAt: row.ComputedAt}
+		if err := json.Unmarshal(row.Strikes, &s.Strikes); err != nil {
+			h.logger.Error("failed to decode GEX snapshot", "symbol", row.Symbol, "error", err)
+			continue
+		}
+		s.Stale = time.Since(s.ComputedAt) > h.snapshotTTL
+		snapshots[row.Symbol] = s
+	}
+
+	for _, symbol := range symbols {
+		if s, ok := snapshots[symbol]; !ok || s.Stale {
+			h.revalidate(symbol)
+		}
+	}
+	return snapshots, nil
+}
+
+// awaitSnapshot returns symbol's snapshot, waiting up to snapshotWait for
+// one that hasn't been calculated yet. It returns nil if the wait runs out.
+func (h *GEXHandler) awaitSnapshot(ctx context.Context, symbol string) (*GEXSnapshot, error) {
+	snapshots, err := h.snapshots(ctx, []string{symbol})
+	if err != nil || snapshots[symbol] != nil {
+		return snapshots[symbol], err
+	}
+
+	timer := time.NewTimer(snapshotWait)
+	defer timer.Stop()
+	select {
+	case <-h.revalidate(symbol):
+	case <-timer.C:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	snapshots, err = h.snapshots(ctx, []string{symbol})
+	if err != nil {
+		return nil, err
+	}
+	return snapshots[symbol], nil
+}
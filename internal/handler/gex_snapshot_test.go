@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewGEXSnapshot(t *testing.T) {
+	s := newGEXSnapshot("SPY", 500, map[float64]float64{510: 2e6, 490: -5e5, 500: 0, 495: 1e6}, "", time.Now())
+
+	want := []StrikeGEX{{490, -5e5}, {495, 1e6}, {510, 2e6}}
+	if !reflect.DeepEqual(s.Strikes, want) {
+		t.Errorf("strikes = %v, want %v", s.Strikes, want)
+	}
+	if s.TotalGEX() != 2.5e6 {
+		t.Errorf("total = %v, want 2.5e6", s.TotalGEX())
+	}
+	if m := s.GEXByStrike(); len(m) != 3 || m[495] != 1e6 {
+		t.Errorf("GEXByStrike = %v", m)
+	}
+}
+
+func TestRefreshGroup(t *testing.T) {
+	g := refreshGroup{retryAfter: time.Hour}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-g.do("SPY", func() error {
+				calls.Add(1)
+				<-release
+				return nil
+			})
+		}()
+	}
+	// Let every caller join before the refresh finishes.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("refresh ran %d times for concurrent callers, want 1", n)
+	}
+
+	// A finished refresh doesn't block the next one.
+	<-g.do("SPY", func() error { calls.Add(1); return nil })
+	if n := calls.Load(); n != 2 {
+		t.Errorf("refresh ran %d times, want 2", n)
+	}
+
+	// A failed refresh isn't retried until retryAfter has passed.
+	<-g.do("BAD", func() error { return errors.New("unknown symbol") })
+	<-g.do("BAD", func() error { t.Error("failed refresh retried too soon"); return nil })
+}
+
+func TestUniverseGridTemplateRenders(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+
+	type chart struct {
+		Symbol            string
+		SpotPrice         float64
+		ChartData         []StrikeGEX
+		TotalGEXFormatted string
+		Updated           string
+		Stale             bool
+	}
+	data := map[string]interface{}{
+		"Charts": []chart{{
+			Symbol:            "AAPL",
+			SpotPrice:         230,
+			ChartData:         []StrikeGEX{{225, -1e6}, {230, 3e6}},
+			TotalGEXFormatted: formatCurrency(2e6),
+			Updated:           "6 minutes ago",
+			Stale:             true,
+		}},
+		"Pending":   []string{"NVDA"},
+		"Updated":   "6 minutes ago",
+		"Universe":  "mag7",
+		"Title":     "Magnificent Seven",
+		"Truncated": false,
+		"Limit":     maxGridSymbols,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "mag7_gex.html", data); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{`{"strike":225,"gex":-1000000}`, "Snapshots updated 6 minutes ago", "refreshing", `http-equiv="refresh"`, "NVDA"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+}
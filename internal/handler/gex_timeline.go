@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// timelineRange is a selectable span of the GEX timeline.
+type timelineRange struct {
+	// tradingDays keeps only the last N New York trading days that have
+	// data, so 1D on a Monday morning shows Friday rather than an empty
+	// weekend. Zero keeps everything fetched.
+	tradingDays int
+	// fetch is how far back to query; it covers weekends and holidays for
+	// tradingDays.
+	fetch time.Duration
+	// bucket thins long ranges to the last snapshot in each interval.
+	bucket time.Duration
+}
+
+var timelineRanges = map[string]timelineRange{
+	"1d": {tradingDays: 1, fetch: 5 * 24 * time.Hour},
+	"5d": {tradingDays: 5, fetch: 10 * 24 * time.Hour},
+	"1m": {fetch: 31 * 24 * time.Hour, bucket: 30 * time.Minute},
+}
+
+// TimelinePoint is one snapshot on the GEX timeline. Levels are null when the
+// snapshot was stored without them.
+type TimelinePoint struct {
+	Time      time.Time `json:"time"`
+	GEX       float64   `json:"gex"`
+	Spot      *float64  `json:"spot"`
+	FlipLevel *float64  `json:"flip_level"`
+	CallWall  *float64  `json:"call_wall"`
+	PutWall   *float64  `json:"put_wall"`
+}
+
+// HandleGEXTimeline returns net GEX, spot, flip level and call/put walls of
+// ?symbol= over ?range= (1d, 5d or 1m; default 1d), oldest first.
+func (h *GEXHandler) HandleGEXTimeline(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	symbol := strings.ToUpper(strings.TrimSpace(q.Get("symbol")))
+	if symbol == "" {
+		http.Error(w, "Symbol is required", http.StatusBadRequest)
+		return
+	}
+	rangeKey := strings.ToLower(q.Get("range"))
+	if rangeKey == "" {
+		rangeKey = "1d"
+	}
+	rng, ok := timelineRanges[rangeKey]
+	if !ok {
+		http.Error(w, "range must be 1d, 5d or 1m", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.repo.ListGEXTimeline(r.Context(), repository.ListGEXTimelineParams{
+		Symbol:   symbol,
+		FromTime: time.Now().Add(-rng.fetch),
+	})
+	if err != nil {
+		h.logger.Error("failed to fetch GEX timeline", "symbol", symbol, "error", err)
+		http.Error(w, "Failed to load GEX timeline", http.StatusInternalServerError)
+		return
+	}
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.UTC
+	}
+	points := buildTimeline(rows, rng, loc)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"symbol": symbol,
+		"range":  rangeKey,
+		"points": points,
+	})
+}
+
+// buildTimeline converts rows, oldest first, into points trimmed to rng.
+func buildTimeline(rows []repository.ListGEXTimelineRow, rng timelineRange, loc *time.Location) []TimelinePoint {
+	day := func(t time.Time) string { return t.In(loc).Format(time.DateOnly) }
+
+	start := 0
+	if rng.tradingDays > 0 {
+		days := 0
+		for i := len(rows) - 1; i >= 0; i-- {
+			if i == len(rows)-1 || day(rows[i].RecordedAt) != day(rows[i+1].RecordedAt) {
+				days++
+			}
+			if days > rng.tradingDays {
+				break
+			}
+			start = i
+		}
+	}
+
+	points := make([]TimelinePoint, 0, len(rows)-start)
+	for i, row := range rows[start:] {
+		// With a bucket, keep a snapshot only if it is the last in its interval.
+		if next := start + i + 1; rng.bucket > 0 && next < len(rows) &&
+			rows[next].RecordedAt.Truncate(rng.bucket).Equal(row.RecordedAt.Truncate(rng.bucket)) {
+			continue
+		}
+		gexValue, _ := row.GexValue.Float64Value()
+		points = append(points, TimelinePoint{
+			Time:      row.RecordedAt,
+			GEX:       gexValue.Float64,
+			Spot:      spotLevel(row.SpotPrice),
+			FlipLevel: numericLevel(row.FlipLevel),
+			CallWall:  numericLevel(row.CallWall),
+			PutWall:   numericLevel(row.PutWall),
+		})
+	}
+	return points
+}
+
+func numericLevel(n pgtype.Numeric) *float64 {
+	v, err := n.Float64Value()
+	if err != nil || !v.Valid || v.Float64 <= 0 {
+		return nil
+	}
+	return &v.Float64
+}
+
+func spotLevel(t pgtype.Text) *float64 {
+	if !t.Valid {
+		return nil
+	}
+	v, err := strconv.ParseFloat(t.String, 64)
+	if err != nil || v <= 0 {
+		return nil
+	}
+	return &v
+}
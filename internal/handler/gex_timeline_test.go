@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func timelineRows(times ...time.Time) []repository.ListGEXTimelineRow {
+	rows := make([]repository.ListGEXTimelineRow, len(times))
+	for i, t := range times {
+		var gexValue, flip pgtype.Numeric
+		gexValue.Scan("1000")
+		if i%2 == 0 {
+			flip.Scan("101.5")
+		}
+		rows[i] = repository.ListGEXTimelineRow{
+			RecordedAt: t,
+			GexValue:   gexValue,
+			SpotPrice:  pgtype.Text{String: "100.25", Valid: true},
+			FlipLevel:  flip,
+		}
+	}
+	return rows
+}
+
+func TestBuildTimeline(t *testing.T) {
+	loc, _ := time.LoadLocation("America/New_York")
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 10, day, hour, minute, 0, 0, loc) }
+
+	// Thursday and Friday, then nothing over the weekend.
+	rows := timelineRows(
+		at(15, 10, 0), at(15, 15, 30),
+		at(16, 9, 35), at(16, 9, 50), at(16, 10, 5), at(16, 15, 55),
+	)
+
+	oneDay := buildTimeline(rows, timelineRanges["1d"], loc)
+	if len(oneDay) != 4 || !oneDay[0].Time.Equal(at(16, 9, 35)) {
+		t.Errorf("1d kept %d points starting %v, want Friday's 4", len(oneDay), oneDay[0].Time)
+	}
+
+	fiveDay := buildTimeline(rows, timelineRanges["5d"], loc)
+	if len(fiveDay) != len(rows) {
+		t.Errorf("5d kept %d points, want all %d", len(fiveDay), len(rows))
+	}
+
+	// 30 minute buckets keep the last snapshot of each: 9:50 stands in for 9:35.
+	month := buildTimeline(rows, timelineRanges["1m"], loc)
+	want := []time.Time{at(15, 10, 0), at(15, 15, 30), at(16, 9, 50), at(16, 10, 5), at(16, 15, 55)}
+	if len(month) != len(want) {
+		t.Fatalf("1m kept %d points, want %d", len(month), len(want))
+	}
+	for i, p := range month {
+		if !p.Time.Equal(want[i]) {
+			t.Errorf("1m point %d at %v, want %v", i, p.Time, want[i])
+		}
+	}
+
+	p := oneDay[0]
+	if p.GEX != 1000 || p.Spot == nil || *p.Spot != 100.25 || p.FlipLevel == nil || *p.FlipLevel != 101.5 || p.CallWall != nil {
+		t.Errorf("point = %+v", p)
+	}
+	if oneDay[1].FlipLevel != nil {
+		t.Error("a missing flip level should be null")
+	}
+
+	if got := buildTimeline(nil, timelineRanges["1d"], loc); len(got) != 0 {
+		t.Errorf("empty timeline = %v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/macro"
+)
+
+const (
+	defaultMacroDays = 365
+	maxMacroDays     = 5 * 365
+)
+
+// MacroHandler serves the macro dashboard: yields, the curve, fed funds, the
+// VIX and financial conditions from FRED, with the regime they add up to.
+type MacroHandler struct {
+	logger *slog.Logger
+	tmpl   *template.Template
+	store  *macro.Store
+}
+
+func NewMacroHandler(logger *slog.Logger, tmpl *template.Template, store *macro.Store) *MacroHandler {
+	return &MacroHandler{
+		logger: logger,
+		tmpl:   tmpl,
+		store:  store,
+	}
+}
+
+// dashboard reads the dashboard over ?days= (default a year).
+func (h *MacroHandler) dashboard(w http.ResponseWriter, r *http.Request) (*macro.Dashboard, bool) {
+	days, ok := macroDays(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	d, err := h.store.Dashboard(ctx, days)
+	if err != nil {
+		h.logger.Error("Failed to load macro dashboard", slog.Any("error", err))
+		http.Error(w, "Failed to load macro data", http.StatusInternalServerError)
+		return nil, false
+	}
+	return d, true
+}
+
+// GetDashboard returns the readings and regime as JSON.
+func (h *MacroHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	d, ok := h.dashboard(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(d)
+}
+
+// GetSeries returns the observations of ?id= over ?days=.
+func (h *MacroHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	id := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("id")))
+	if id == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	days, ok := macroDays(w, r)
+	if !ok {
+		return
+	}
+
+	points, err := h.store.History(r.Context(), id, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		h.logger.Error("Failed to load macro series", slog.String("series_id", id), slog.Any("error", err))
+		http.Error(w, "Failed to load macro data", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"series_id":    id,
+		"observations": points,
+		"count":        len(points),
+	})
+}
+
+func (h *MacroHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d, ok := h.dashboard(w, r)
+	if !ok {
+		return
+	}
+	err := h.tmpl.ExecuteTemplate(w, "macro.html", map[string]interface{}{
+		"Dashboard": d,
+		"Days":      r.URL.Query().Get("days"),
+	})
+	if err != nil {
+		h.logger.Error("Failed to render macro dashboard", slog.Any("error", err))
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+func macroDays(w http.ResponseWriter, r *http.Request) (int, bool) {
+	s := r.URL.Query().Get("days")
+	if s == "" {
+		return defaultMacroDays, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > maxMacroDays {
+		http.Error(w, "days must be between 1 and "+strconv.Itoa(maxMacroDays), http.StatusBadRequest)
+		return 0, false
+	}
+	return n, true
+}
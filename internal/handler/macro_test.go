@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/macro"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+)
+
+func TestMacroTemplateRenders(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	d := macro.NewDashboard([]repository.MacroSeries{
+		{SeriesID: "DGS10", Name: "10-Year Treasury Yield", Unit: "%"},
+		{SeriesID: "VIXCLS", Name: "VIX Close"},
+		{SeriesID: "NFCI", Name: "Chicago Fed Financial Conditions Index"},
+	}, map[string][]macro.Point{
+		"DGS10":  {{Date: day.AddDate(0, 0, -1), Value: 4.1}, {Date: day, Value: 4.18}},
+		"VIXCLS": {{Date: day, Value: 22.4}},
+	})
+
+	var buf bytes.Buffer
+	err := tmpl.ExecuteTemplate(&buf, "macro.html", map[string]interface{}{
+		"Dashboard": d,
+		"Days":      "",
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"as of 2026-10-16", "4.18%", "Day &#43;8 bp", "22.40", "100th percentile", "Neutral: elevated volatility", `id="spark-2"`, `"date":"2026-10-15"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+}
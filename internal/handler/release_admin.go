@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/fred"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// catalogueTTL is how long the FRED release catalogue is kept; it changes a
+// few times a year.
+const catalogueTTL = 6 * time.Hour
+
+// fredUnits are the FRED units transformations a headline series can use.
+var fredUnits = map[string]bool{
+	"lin": true, "chg": true, "ch1": true, "pch": true, "pc1": true,
+	"pca": true, "cch": true, "cca": true, "log": true,
+}
+
+var seriesIDPattern = regexp.MustCompile(`^[A-Z0-9_.]{1,64}$`)
+
+// ReleaseAdminHandler manages which FRED releases the economic calendar
+// tracks, with a browser over the FRED release catalogue to find new ones.
+type ReleaseAdminHandler struct {
+	logger  *slog.Logger
+	tmpl    *template.Template
+	queries *repository.Queries
+	fred    *fred.Client
+
+	// onChange runs after the tracked releases changed.
+	onChange func()
+
+	mu          sync.Mutex
+	catalogue   []fred.Release
+	catalogueAt time.Time
+}
+
+func NewReleaseAdminHandler(logger *slog.Logger, tmpl *template.Template, db *pgxpool.Pool, fredClient *fred.Client) *ReleaseAdminHandler {
+	return &ReleaseAdminHandler{
+		logger:   logger,
+		tmpl:     tmpl,
+		queries:  repository.New(db),
+		fred:     fredClient,
+		onChange: func() {},
+	}
+}
+
+// SetOnChange sets a function to run after a release is tracked, updated or
+// untracked, e.g. to collect the calendar again.
+func (h *ReleaseAdminHandler) SetOnChange(fn func()) {
+	h.onChange = fn
+}
+
+func (h *ReleaseAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.tmpl.ExecuteTemplate(w, "release_admin.html", map[string]interface{}{
+		"Impacts": []string{"High", "Medium", "Low"},
+	})
+	if err != nil {
+		h.logger.Error("Failed to render release admin", slog.Any("error", err))
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// TrackedReleaseView is a tracked release as the admin API reads and writes
+// it.
+type TrackedReleaseView struct {
+	ReleaseID int32  `json:"release_id"`
+	Name      string `json:"name"`
+	Impact    string `json:"impact"`
+	// ReleaseTime is "15:04" Eastern, empty when the release has no fixed time.
+	ReleaseTime   string   `json:"release_time"`
+	Category      string   `json:"category"`
+	SeriesID      string   `json:"series_id"`
+	Units         string   `json:"units"`
+	RelatedSeries []string `json:"related_series"`
+	UpdatedAt     string   `json:"updated_at,omitempty"`
+}
+
+func newTrackedReleaseView(r repository.TrackedRelease) TrackedReleaseView {
+	v := TrackedReleaseView{
+		ReleaseID:     r.ReleaseID,
+		Name:          r.Name,
+		Impact:        r.Impact,
+		Category:      r.Category,
+		SeriesID:      r.SeriesID.String,
+		Units:         r.Units.String,
+		RelatedSeries: r.RelatedSeries,
+		UpdatedAt:     r.UpdatedAt.Format(time.RFC3339),
+	}
+	if r.ReleaseTime.Valid {
+		d := time.Duration(r.ReleaseTime.Microseconds) * time.Microsecond
+		v.ReleaseTime = fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	if v.RelatedSeries == nil {
+		v.RelatedSeries = []string{}
+	}
+	return v
+}
+
+// params validates v and normalizes its impact, time and series IDs.
+func (v TrackedReleaseView) params() (repository.UpsertTrackedReleaseParams, error) {
+	p := repository.UpsertTrackedReleaseParams{
+		ReleaseID:     v.ReleaseID,
+		Name:          strings.TrimSpace(v.Name),
+		Category:      strings.ToLower(strings.TrimSpace(v.Category)),
+		RelatedSeries: []string{},
+	}
+	if p.ReleaseID <= 0 {
+		return p, fmt.Errorf("release_id is required")
+	}
+	if p.Name == "" {
+		return p, fmt.Errorf("name is required")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(v.Impact)) {
+	case "high":
+		p.Impact = "High"
+	case "medium":
+		p.Impact = "Medium"
+	case "low":
+		p.Impact = "Low"
+	default:
+		return p, fmt.Errorf("impact must be High, Medium or Low")
+	}
+
+	if s := strings.TrimSpace(v.ReleaseTime); s != "" {
+		t, err := time.Parse("15:04", s)
+		if err != nil {
+			return p, fmt.Errorf("invalid release_time %q, use HH:MM", s)
+		}
+		p.ReleaseTime = pgtype.Time{Microseconds: int64(t.Hour()*3600+t.Minute()*60) * 1e6, Valid: true}
+	}
+
+	if id := strings.ToUpper(strings.TrimSpace(v.SeriesID)); id != "" {
+		if !seriesIDPattern.MatchString(id) {
+			return p, fmt.Errorf("invalid series_id %q", v.SeriesID)
+		}
+		units := strings.ToLower(strings.TrimSpace(v.Units))
+		if units == "" {
+			units = "lin"
+		}
+		if !fredUnits[units] {
+			return p, fmt.Errorf("unknown units %q", v.Units)
+		}
+		p.SeriesID = pgtype.Text{String: id, Valid: true}
+		p.Units = pgtype.Text{String: units, Valid: true}
+	} else if v.Units != "" {
+		return p, fmt.Errorf("units need a series_id")
+	}
+
+	for _, s := range v.RelatedSeries {
+		id := strings.ToUpper(strings.TrimSpace(s))
+		if id == "" {
+			continue
+		}
+		if !seriesIDPattern.MatchString(id) {
+			return p, fmt.Errorf("invalid related series %q", s)
+		}
+		p.RelatedSeries = append(p.RelatedSeries, id)
+	}
+	return p, nil
+}
+
+// Releases lists the tracked releases on GET, tracks or updates one from a
+// JSON TrackedReleaseView on POST and untracks ?release_id= on DELETE, which
+// also drops its upcoming dates from the calendar.
+func (h *ReleaseAdminHandler) Releases(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listReleases(w, r)
+	case http.MethodPost:
+		h.saveRelease(w, r)
+	case http.MethodDelete:
+		h.deleteRelease(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ReleaseAdminHandler) listReleases(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.queries.ListTrackedReleases(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list tracked releases", slog.Any("error", err))
+		http.Error(w, "Failed to list tracked releases", http.StatusInternalServerError)
+		return
+	}
+
+	views := make([]TrackedReleaseView, 0, len(rows))
+	for _, row := range rows {
+		views = append(views, newTrackedReleaseView(row))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"releases": views,
+		"count":    len(views),
+	})
+}
+
+func (h *ReleaseAdminHandler) saveRelease(w http.ResponseWriter, r *http.Request) {
+	var v TrackedReleaseView
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&v); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	params, err := v.params()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	row, err := h.queries.UpsertTrackedRelease(r.Context(), params)
+	if err != nil {
+		h.logger.Error("Failed to save tracked release", slog.Int("release_id", int(params.ReleaseID)), slog.Any("error", err))
+		http.Error(w, "Failed to save tracked release", http.StatusInternalServerError)
+		return
+	}
+	h.logger.Info("Tracked release saved", slog.Int("release_id", int(row.ReleaseID)), slog.String("impact", row.Impact))
+	h.onChange()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newTrackedReleaseView(row))
+}
+
+func (h *ReleaseAdminHandler) deleteRelease(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.URL.Query().Get("release_id"), 10, 32)
+	if err != nil {
+		http.Error(w, "release_id is required", http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.queries.DeleteTrackedRelease(r.Context(), int32(id))
+	if err != nil {
+		h.logger.Error("Failed to untrack release", slog.Int64("release_id", id), slog.Any("error", err))
+		http.Error(w, "Failed to untrack release", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "Release is not tracked", http.StatusNotFound)
+		return
+	}
+	if err := h.queries.DeleteUpcomingEconomicReleases(r.Context(), int32(id)); err != nil {
+		h.logger.Error("Failed to drop upcoming dates of untracked release", slog.Int64("release_id", id), slog.Any("error", err))
+	}
+	h.logger.Info("Release untracked", slog.Int64("release_id", id))
+	h.onChange()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CatalogueRelease is an entry of the FRED catalogue and whether we track it.
+type CatalogueRelease struct {
+	fred.Release
+	Tracked bool   `json:"tracked"`
+	Impact  string `json:"impact,omitempty"`
+}
+
+// Catalogue returns the FRED release catalogue, optionally narrowed to names
+// containing ?q=, with the tracked releases marked.
+func (h *ReleaseAdminHandler) Catalogue(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	releases, err := h.releaseCatalogue(ctx)
+	if err != nil {
+		h.logger.Error("Failed to fetch FRED release catalogue", slog.Any("error", err))
+		http.Error(w, "Failed to fetch FRED release catalogue", http.StatusBadGateway)
+		return
+	}
+	rows, err := h.queries.ListTrackedReleases(ctx)
+	if err != nil {
+		h.logger.Error("Failed to list tracked releases", slog.Any("error", err))
+		http.Error(w, "Failed to list tracked releases", http.StatusInternalServerError)
+		return
+	}
+
+	entries := markTracked(releases, rows, r.URL.Query().Get("q"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"releases": entries,
+		"count":    len(entries),
+	})
+}
+
+func markTracked(releases []fred.Release, tracked []repository.TrackedRelease, query string) []CatalogueRelease {
+	impacts := make(map[int]string, len(tracked))
+	for _, t := range tracked {
+		impacts[int(t.ReleaseID)] = t.Impact
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	entries := make([]CatalogueRelease, 0, len(releases))
+	for _, rel := range releases {
+		if query != "" && !strings.Contains(strings.ToLower(rel.Name), query) && strconv.Itoa(rel.ID) != query {
+			continue
+		}
+		impact, ok := impacts[rel.ID]
+		entries = append(entries, CatalogueRelease{Release: rel, Tracked: ok, Impact: impact})
+	}
+	return entries
+}
+
+func (h *ReleaseAdminHandler) releaseCatalogue(ctx context.Context) ([]fred.Release, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.catalogue != nil && time.Since(h.catalogueAt) < catalogueTTL {
+		return h.catalogue, nil
+	}
+	releases, err := h.fred.GetReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h.catalogue, h.catalogueAt = releases, time.Now()
+	return releases, nil
+}
+
+// Series returns the most popular series of ?release_id=, to pick the
+// headline and related series from.
+func (h *ReleaseAdminHandler) Series(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.URL.Query().Get("release_id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "release_id is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+	series, err := h.fred.GetReleaseSeries(ctx, id, 25)
+	if err != nil {
+		h.logger.Error("Failed to fetch release series", slog.Int("release_id", id), slog.Any("error", err))
+		http.Error(w, "Failed to fetch release series", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"release_id": id,
+		"series":     series,
+		"count":      len(series),
+	})
+}
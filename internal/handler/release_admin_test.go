@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/fred"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestTrackedReleaseParams(t *testing.T) {
+	v := TrackedReleaseView{
+		ReleaseID:     54,
+		Name:          " Personal Income and Outlays ",
+		Impact:        "high",
+		ReleaseTime:   "08:30",
+		Category:      "Inflation",
+		SeriesID:      "pcepilfe",
+		Units:         "PC1",
+		RelatedSeries: []string{"pcepi", " ", "PSAVERT"},
+	}
+	p, err := v.params()
+	if err != nil {
+		t.Fatalf("params: %v", err)
+	}
+	if p.Name != "Personal Income and Outlays" || p.Impact != "High" || p.Category != "inflation" {
+		t.Errorf("params = %+v", p)
+	}
+	if p.ReleaseTime.Microseconds != int64(8*time.Hour+30*time.Minute)/1e3 {
+		t.Errorf("release time = %d", p.ReleaseTime.Microseconds)
+	}
+	if p.SeriesID.String != "PCEPILFE" || p.Units.String != "pc1" {
+		t.Errorf("series = %v %v", p.SeriesID, p.Units)
+	}
+	if strings.Join(p.RelatedSeries, ",") != "PCEPI,PSAVERT" {
+		t.Errorf("related series = %v", p.RelatedSeries)
+	}
+
+	// Units default to levels.
+	p, err = TrackedReleaseView{ReleaseID: 1, Name: "ISM", Impact: "Medium", SeriesID: "NAPM"}.params()
+	if err != nil || p.Units.String != "lin" || p.ReleaseTime.Valid {
+		t.Errorf("params = %+v, %v", p, err)
+	}
+
+	for name, bad := range map[string]TrackedReleaseView{
+		"no id":        {Name: "CPI", Impact: "High"},
+		"no name":      {ReleaseID: 10, Impact: "High"},
+		"impact":       {ReleaseID: 10, Name: "CPI", Impact: "Extreme"},
+		"time":         {ReleaseID: 10, Name: "CPI", Impact: "High", ReleaseTime: "8:30am"},
+		"series":       {ReleaseID: 10, Name: "CPI", Impact: "High", SeriesID: "CPI AUCSL"},
+		"units":        {ReleaseID: 10, Name: "CPI", Impact: "High", SeriesID: "CPIAUCSL", Units: "pct"},
+		"orphan units": {ReleaseID: 10, Name: "CPI", Impact: "High", Units: "pc1"},
+		"related":      {ReleaseID: 10, Name: "CPI", Impact: "High", RelatedSeries: []string{"CPI;LFESL"}},
+	} {
+		if _, err := bad.params(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestNewTrackedReleaseView(t *testing.T) {
+	v := newTrackedReleaseView(repository.TrackedRelease{
+		ReleaseID:   10,
+		Name:        "Consumer Price Index",
+		Impact:      "High",
+		ReleaseTime: pgtype.Time{Microseconds: int64(8*time.Hour+30*time.Minute) / 1e3, Valid: true},
+		SeriesID:    pgtype.Text{String: "CPIAUCSL", Valid: true},
+		Units:       pgtype.Text{String: "pch", Valid: true},
+	})
+	if v.ReleaseTime != "08:30" || v.SeriesID != "CPIAUCSL" || v.RelatedSeries == nil {
+		t.Errorf("view = %+v", v)
+	}
+}
+
+func TestMarkTracked(t *testing.T) {
+	catalogue := []fred.Release{
+		{ID: 10, Name: "Consumer Price Index"},
+		{ID: 51, Name: "Producer Price Index"},
+		{ID: 54, Name: "Personal Income and Outlays"},
+	}
+	tracked := []repository.TrackedRelease{{ReleaseID: 10, Impact: "High"}}
+
+	all := markTracked(catalogue, tracked, "")
+	if len(all) != 3 || !all[0].Tracked || all[0].Impact != "High" || all[1].Tracked {
+		t.Errorf("entries = %+v", all)
+	}
+	if got := markTracked(catalogue, tracked, "price"); len(got) != 2 {
+		t.Errorf("search price = %+v", got)
+	}
+	if got := markTracked(catalogue, tracked, "54"); len(got) != 1 || got[0].ID != 54 {
+		t.Errorf("search 54 = %+v", got)
+	}
+}
+
+func TestReleaseAdminTemplateRenders(t *testing.T) {
+	tmpl := template.Must(template.ParseGlob("../../templates/*.html"))
+
+	var buf bytes.Buffer
+	err := tmpl.ExecuteTemplate(&buf, "release_admin.html", map[string]interface{}{
+		"Impacts": []string{"High", "Medium", "Low"},
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Tracked Releases", `<option value="Medium">Medium</option>`, "/api/admin/releases/catalogue"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+)
+
+// UniverseHandler exposes the stored symbol universes and the CSV import.
+type UniverseHandler struct {
+	logger *slog.Logger
+	store  *universe.Store
+}
+
+func NewUniverseHandler(logger *slog.Logger, store *universe.Store) *UniverseHandler {
+	return &UniverseHandler{
+		logger: logger,
+		store:  store,
+	}
+}
+
+type UniverseView struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Owner       string `json:"owner,omitempty"`
+	MemberCount int32  `json:"member_count"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// List returns every universe with its member count.
+func (h *UniverseHandler) List(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.store.List(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list universes", slog.Any("error", err))
+		http.Error(w, "Failed to list universes", http.StatusInternalServerError)
+		return
+	}
+
+	views := make([]UniverseView, 0, len(rows))
+	for _, u := range rows {
+		views = append(views, UniverseView{
+			Slug:        u.Slug,
+			Name:        u.Name,
+			Kind:        u.Kind,
+			Owner:       u.Owner.String,
+			MemberCount: u.MemberCount,
+			UpdatedAt:   u.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"universes": views,
+		"count":     len(views),
+	})
+}
+
+// Members returns the symbols of ?universe=.
+func (h *UniverseHandler) Members(w http.ResponseWriter, r *http.Request) {
+	slug := r.URL.Query().Get("universe")
+	if slug == "" {
+		http.Error(w, "universe is required", http.StatusBadRequest)
+		return
+	}
+
+	symbols, err := h.store.Symbols(r.Context(), slug)
+	if errors.Is(err, universe.ErrNotFound) {
+		http.Error(w, "Unknown universe", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to load universe", slog.String("universe", slug), slog.Any("error", err))
+		http.Error(w, "Failed to load universe", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"universe": slug,
+		"symbols":  symbols,
+		"count":    len(symbols),
+	})
+}
+
+// Import creates or updates ?universe= from a CSV request body, or from the
+// "file" field of a multipart form. ?name=, ?kind= and ?owner= describe the
+// universe; ?mode=append keeps the existing members instead of replacing them.
+func (h *UniverseHandler) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	def := universe.Definition{
+		Slug:  q.Get("universe"),
+		Name:  q.Get("name"),
+		Kind:  q.Get("kind"),
+		Owner: q.Get("owner"),
+	}
+	replace := q.Get("mode") != "append"
+
+	if err := def.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Missing file field", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	symbols, err := universe.ParseCSV(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.store.Import(r.Context(), def, symbols, replace)
+	if err != nil {
+		h.logger.Error("Universe import failed", slog.String("universe", def.Slug), slog.Any("error", err))
+		http.Error(w, "Failed to import universe", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"universe": def.Slug,
+		"imported": n,
+		"replaced": replace,
+	})
+}
+
+// ImportMetadata upserts symbol names, sectors and market caps from a CSV
+// request body, or from the "file" field of a multipart form. See
+// universe.ParseMetadataCSV for the columns.
+func (h *UniverseHandler) ImportMetadata(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Missing file field", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	rows, err := universe.ParseMetadataCSV(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.store.ImportMetadata(r.Context(), rows)
+	if err != nil {
+		h.logger.Error("Symbol metadata import failed", slog.Any("error", err))
+		http.Error(w, "Failed to import symbol metadata", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"imported": n,
+	})
+}
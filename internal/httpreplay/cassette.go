@@ -0,0 +1,85 @@
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Cassette is an ordered list of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request and the response it received.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request holds the parts of a request used to match it on replay. Headers
+// are not recorded because they carry credentials.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response. JSON bodies are stored in JSON so the
+// cassette stays readable and easy to edit by hand; anything else is stored
+// in Body.
+type Response struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Body   string          `json:"body,omitempty"`
+}
+
+func (r Response) body() []byte {
+	if len(r.JSON) > 0 {
+		return r.JSON
+	}
+	return []byte(r.Body)
+}
+
+func newResponse(status int, header http.Header, body []byte) Response {
+	resp := Response{Status: status, Header: header}
+	var buf bytes.Buffer
+	if len(body) > 0 && json.Valid(body) && json.Indent(&buf, body, "", "  ") == nil {
+		resp.JSON = buf.Bytes()
+	} else {
+		resp.Body = string(body)
+	}
+	return resp
+}
+
+// Load reads a cassette from path.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating its directory if needed.
+func (c *Cassette) Save(path string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
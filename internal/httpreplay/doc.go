@@ -0,0 +1,9 @@
+// Package httpreplay records HTTP interactions with third-party APIs to
+// cassette files and replays them, so code that calls Public.com, Alpaca,
+// FRED or an Ethereum node can be tested without credentials or network
+// access.
+//
+// Cassettes live under testdata/cassettes. Tests replay them by default;
+// setting HTTPREPLAY=record sends requests to the real API and rewrites the
+// cassette.
+package httpreplay
@@ -0,0 +1,319 @@
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ErrNoInteraction is returned on replay when the cassette has no recorded
+// response for a request.
+var ErrNoInteraction = errors.New("httpreplay: no recorded interaction")
+
+// Mode selects whether a Recorder talks to the network.
+type Mode int
+
+const (
+	// ModeReplay answers every request from the cassette.
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to Base and saves them on Close.
+	ModeRecord
+)
+
+// ModeFromEnv returns ModeRecord when HTTPREPLAY=record.
+func ModeFromEnv() Mode {
+	if strings.EqualFold(os.Getenv("HTTPREPLAY"), "record") {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+const redacted = "REDACTED"
+
+// DefaultRedactParams are query parameters that hold credentials.
+var DefaultRedactParams = []string{"api_key", "apikey", "token", "key"}
+
+// DefaultRedactFields are JSON body fields that hold credentials.
+var DefaultRedactFields = []string{"secret", "accessToken", "access_token"}
+
+// Recorder is an http.RoundTripper backed by a cassette.
+//
+// On replay a request is matched on method, URL and body. Recorded
+// interactions are handed out in order; once every match has been used the
+// last one is repeated, so polling the same endpoint keeps working.
+type Recorder struct {
+	Mode Mode
+	// Base carries requests while recording. Nil means http.DefaultTransport.
+	Base http.RoundTripper
+	// RedactParams and RedactFields are replaced with "REDACTED" in the
+	// cassette. Matching applies the same redaction to live requests.
+	RedactParams []string
+	RedactFields []string
+	// Secrets are literal values, such as a key embedded in a URL path, that
+	// are replaced wherever they appear in a URL or body.
+	Secrets []string
+	// IgnoreParams are left out when matching, for values such as "today"
+	// that change between the recording and the replay.
+	IgnoreParams []string
+
+	path string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New opens the cassette at path. In replay mode the file must exist; in
+// record mode it is replaced when the recorder is closed.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		Mode:         mode,
+		RedactParams: DefaultRedactParams,
+		RedactFields: DefaultRedactFields,
+		path:         path,
+		cassette:     &Cassette{},
+	}
+	if mode == ModeReplay {
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	}
+	return r, nil
+}
+
+// ForTest opens testdata/cassettes/<name>.json in the mode chosen by
+// HTTPREPLAY and saves it when the test finishes.
+func ForTest(t testing.TB, name string) *Recorder {
+	t.Helper()
+	r, err := New(filepath.Join("testdata", "cassettes", name+".json"), ModeFromEnv())
+	if err != nil {
+		t.Fatalf("open cassette %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		if err := r.Close(); err != nil {
+			t.Errorf("save cassette %s: %v", name, err)
+		}
+	})
+	return r
+}
+
+// Client returns an *http.Client that uses the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Close saves the cassette when recording.
+func (r *Recorder) Close() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.Mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	var header http.Header
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		header = http.Header{"Content-Type": {ct}}
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    r.redactURL(req.URL),
+			Body:   string(r.redactJSON(body)),
+		},
+		Response: newResponse(resp.StatusCode, header, r.redactJSON(respBody)),
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key := r.matchKey(req.Method, r.redactURL(req.URL), r.redactJSON(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, in := range r.cassette.Interactions {
+		if r.matchKey(in.Request.Method, in.Request.URL, []byte(in.Request.Body)) != key {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return r.response(req, in.Response), nil
+		}
+		last = i
+	}
+	if last >= 0 {
+		return r.response(req, r.cassette.Interactions[last].Response), nil
+	}
+	return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, req.Method, r.redactURL(req.URL))
+}
+
+func (r *Recorder) response(req *http.Request, rec Response) *http.Response {
+	body := rec.body()
+	header := rec.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if len(rec.JSON) > 0 && header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json")
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// matchKey identifies a request for replay: method, URL without ignored
+// parameters and the body with JSON normalised.
+func (r *Recorder) matchKey(method, rawURL string, body []byte) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		q := u.Query()
+		for _, p := range r.IgnoreParams {
+			q.Del(p)
+		}
+		u.RawQuery = q.Encode()
+		rawURL = u.String()
+	}
+	var buf bytes.Buffer
+	if json.Compact(&buf, body) == nil {
+		body = buf.Bytes()
+	}
+	return method + " " + rawURL + "\n" + string(body)
+}
+
+func (r *Recorder) redactURL(u *url.URL) string {
+	q := u.Query()
+	for _, p := range r.RedactParams {
+		if q.Has(p) {
+			q.Set(p, redacted)
+		}
+	}
+	c := *u
+	c.RawQuery = q.Encode()
+	return string(r.redactSecrets([]byte(c.String())))
+}
+
+func (r *Recorder) redactSecrets(b []byte) []byte {
+	for _, secret := range r.Secrets {
+		if secret != "" {
+			b = bytes.ReplaceAll(b, []byte(secret), []byte(redacted))
+		}
+	}
+	return b
+}
+
+// redactJSON replaces secrets, and credential fields anywhere in a JSON
+// document.
+func (r *Recorder) redactJSON(body []byte) []byte {
+	body = r.redactSecrets(body)
+	if len(body) == 0 || len(r.RedactFields) == 0 || !json.Valid(body) {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return body
+	}
+	if !r.redactValue(doc) {
+		return body
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func (r *Recorder) redactValue(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if r.isRedactedField(k) {
+				v[k] = redacted
+				changed = true
+				continue
+			}
+			changed = r.redactValue(child) || changed
+		}
+	case []interface{}:
+		for _, child := range v {
+			changed = r.redactValue(child) || changed
+		}
+	}
+	return changed
+}
+
+func (r *Recorder) isRedactedField(name string) bool {
+	for _, f := range r.RedactFields {
+		if strings.EqualFold(f, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
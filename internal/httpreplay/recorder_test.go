@@ -0,0 +1,132 @@
+package httpreplay
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/token" {
+			w.Write([]byte(`{"accessToken":"live-token"}`))
+			return
+		}
+		if n == 2 {
+			w.Write([]byte(`{"price":1}`))
+			return
+		}
+		w.Write([]byte(`{"price":2}`))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := rec.Client()
+	post(t, client, srv.URL+"/token", `{"secret":"hunter2"}`)
+	get(t, client, srv.URL+"/quote?symbol=SPY&api_key=abc")
+	get(t, client, srv.URL+"/quote?symbol=SPY&api_key=abc")
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range c.Interactions {
+		for _, secret := range []string{"hunter2", "abc", "live-token"} {
+			if strings.Contains(in.Request.URL+in.Request.Body+string(in.Response.body()), secret) {
+				t.Errorf("cassette leaks %q: %+v", secret, in)
+			}
+		}
+	}
+
+	replay, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = replay.Client()
+	if got := post(t, client, srv.URL+"/token", `{"secret": "another"}`); !strings.Contains(got, redacted) {
+		t.Errorf("token response = %s, want redacted token", got)
+	}
+	for i, want := range []string{`"price": 1`, `"price": 2`, `"price": 2`} {
+		if got := get(t, client, srv.URL+"/quote?symbol=SPY&api_key=xyz"); !strings.Contains(got, want) {
+			t.Errorf("replay %d = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := (&Cassette{}).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rec.Client().Get("https://example.com/missing")
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestReplayIgnoresParams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dates.json")
+	c := &Cassette{Interactions: []Interaction{{
+		Request:  Request{Method: "GET", URL: "https://example.com/dates?from=2026-01-01&limit=10"},
+		Response: Response{Status: 200, Body: "ok"},
+	}}}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.IgnoreParams = []string{"from"}
+	if got := get(t, rec.Client(), "https://example.com/dates?limit=10&from=2026-10-18"); got != "ok" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func get(t *testing.T, c *http.Client, url string) string {
+	t.Helper()
+	resp, err := c.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	return readAll(t, resp)
+}
+
+func post(t *testing.T, c *http.Client, url, body string) string {
+	t.Helper()
+	resp, err := c.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	return readAll(t, resp)
+}
+
+func readAll(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
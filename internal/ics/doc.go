@@ -0,0 +1,6 @@
+// Package ics writes iCalendar (RFC 5545) feeds that calendar apps can
+// subscribe to, e.g. over webcal://.
+//
+// Timed events are written in New York time with a matching VTIMEZONE, since
+// every market event we publish is scheduled in Eastern time.
+package ics
@@ -0,0 +1,186 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TimeZone is the TZID of timed events.
+const TimeZone = "America/New_York"
+
+var newYork = loadNewYork()
+
+func loadNewYork() *time.Location {
+	loc, err := time.LoadLocation(TimeZone)
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return loc
+}
+
+// vtimezone describes US Eastern time with the DST rules in force since 2007.
+const vtimezone = `BEGIN:VTIMEZONE
+TZID:America/New_York
+BEGIN:DAYLIGHT
+TZOFFSETFROM:-0500
+TZOFFSETTO:-0400
+TZNAME:EDT
+DTSTART:20070311T020000
+RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU
+END:DAYLIGHT
+BEGIN:STANDARD
+TZOFFSETFROM:-0400
+TZOFFSETTO:-0500
+TZNAME:EST
+DTSTART:20071104T020000
+RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU
+END:STANDARD
+END:VTIMEZONE`
+
+// Event is one VEVENT of a calendar.
+type Event struct {
+	// UID must stay the same for the same event across refreshes, so that
+	// calendar apps update it instead of adding a copy.
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Categories  []string
+	// Start is the time of the event, or its day for AllDay events.
+	Start    time.Time
+	AllDay   bool
+	Duration time.Duration
+	Updated  time.Time
+}
+
+// Calendar is a feed of events.
+type Calendar struct {
+	Name        string
+	Description string
+	// Refresh is how often subscribers should poll the feed.
+	Refresh time.Duration
+	Events  []Event
+}
+
+// Write encodes cal. stamp is the DTSTAMP of every event, normally the time
+// the feed is generated.
+func Write(w io.Writer, cal Calendar, stamp time.Time) error {
+	lw := &lineWriter{w: bufio.NewWriter(w)}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:-//GEX Tracker//Market Calendar//EN")
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		lw.line("X-WR-CALNAME:" + escape(cal.Name))
+	}
+	if cal.Description != "" {
+		lw.line("X-WR-CALDESC:" + escape(cal.Description))
+	}
+	lw.line("X-WR-TIMEZONE:" + TimeZone)
+	if cal.Refresh > 0 {
+		lw.line("REFRESH-INTERVAL;VALUE=DURATION:" + duration(cal.Refresh))
+		lw.line("X-PUBLISHED-TTL:" + duration(cal.Refresh))
+	}
+	for _, l := range strings.Split(vtimezone, "\n") {
+		lw.line(l)
+	}
+
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+	for _, e := range cal.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + e.UID)
+		lw.line("DTSTAMP:" + dtstamp)
+		if e.AllDay {
+			d := e.Start
+			lw.line("DTSTART;VALUE=DATE:" + d.Format("20060102"))
+			lw.line("DTEND;VALUE=DATE:" + time.Date(d.Year(), d.Month(), d.Day()+1, 0, 0, 0, 0, time.UTC).Format("20060102"))
+		} else {
+			lw.line("DTSTART;TZID=" + TimeZone + ":" + e.Start.In(newYork).Format("20060102T150405"))
+			if e.Duration > 0 {
+				lw.line("DURATION:" + duration(e.Duration))
+			}
+		}
+		lw.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if len(e.Categories) > 0 {
+			cats := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				cats[i] = escape(c)
+			}
+			lw.line("CATEGORIES:" + strings.Join(cats, ","))
+		}
+		if e.URL != "" {
+			lw.line("URL:" + e.URL)
+		}
+		if !e.Updated.IsZero() {
+			lw.line("LAST-MODIFIED:" + e.Updated.UTC().Format("20060102T150405Z"))
+		}
+		lw.line("TRANSP:TRANSPARENT")
+		lw.line("END:VEVENT")
+	}
+	lw.line("END:VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+	return lw.w.Flush()
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// duration formats d as an RFC 5545 duration, e.g. PT1H30M.
+func duration(d time.Duration) string {
+	s := "PT"
+	if h := int(d.Hours()); h > 0 {
+		s += fmt.Sprintf("%dH", h)
+	}
+	if m := int(d.Minutes()) % 60; m > 0 {
+		s += fmt.Sprintf("%dM", m)
+	}
+	if sec := int(d.Seconds()) % 60; sec > 0 || s == "PT" {
+		s += fmt.Sprintf("%dS", sec)
+	}
+	return s
+}
+
+// lineWriter writes content lines ending in CRLF, folded at 75 octets
+// without splitting a UTF-8 sequence.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		lw.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = 74
+	}
+	lw.write(s + "\r\n")
+}
+
+func (lw *lineWriter) write(s string) {
+	if lw.err == nil {
+		_, lw.err = lw.w.WriteString(s)
+	}
+}
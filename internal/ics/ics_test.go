@@ -0,0 +1,94 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	cpi := time.Date(2026, 11, 12, 8, 30, 0, 0, newYork)
+	cal := Calendar{
+		Name:    "Economic Calendar",
+		Refresh: time.Hour,
+		Events: []Event{
+			{
+				UID:         "economic-10-20261112@gextracker.site",
+				Summary:     "[High] Consumer Price Index",
+				Description: "Actual: 3.1, Prior: 3.0\nSeries: CPIAUCSL; pc1",
+				Categories:  []string{"Economic", "High"},
+				Start:       cpi,
+				Duration:    30 * time.Minute,
+			},
+			{
+				UID:     "expiry-core-20261120@gextracker.site",
+				Summary: "[Medium] Monthly OPEX: SPY",
+				Start:   time.Date(2026, 11, 20, 0, 0, 0, 0, newYork),
+				AllDay:  true,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, cal, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Economic Calendar\r\n",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H\r\n",
+		"TZID:America/New_York\r\n",
+		"DTSTAMP:20261018T120000Z\r\n",
+		"DTSTART;TZID=America/New_York:20261112T083000\r\nDURATION:PT30M\r\n",
+		`DESCRIPTION:Actual: 3.1\, Prior: 3.0\nSeries: CPIAUCSL\; pc1` + "\r\n",
+		"CATEGORIES:Economic,High\r\n",
+		"DTSTART;VALUE=DATE:20261120\r\nDTEND;VALUE=DATE:20261121\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("feed is missing %q", want)
+		}
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 2 {
+		t.Errorf("expected 2 events:\n%s", out)
+	}
+}
+
+func TestLongLinesAreFolded(t *testing.T) {
+	var buf bytes.Buffer
+	summary := strings.Repeat("Ünïcode ", 30)
+	err := Write(&buf, Calendar{Events: []Event{{UID: "x", Summary: summary, Start: time.Now()}}}, time.Now())
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	var unfolded strings.Builder
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+		} else {
+			unfolded.WriteString("\n" + line)
+		}
+	}
+	if !strings.Contains(unfolded.String(), "SUMMARY:"+summary) {
+		t.Error("summary does not unfold to the original")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		time.Hour:                      "PT1H",
+		30 * time.Minute:               "PT30M",
+		90*time.Minute + 5*time.Second: "PT1H30M5S",
+		0:                              "PT0S",
+	} {
+		if got := duration(d); got != want {
+			t.Errorf("duration(%v) = %s, want %s", d, got, want)
+		}
+	}
+}
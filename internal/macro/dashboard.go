@@ -0,0 +1,278 @@
+package macro
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+)
+
+// Series the regime is read from.
+const (
+	SeriesCurve      = "T10Y2Y"
+	SeriesVIX        = "VIXCLS"
+	SeriesConditions = "NFCI"
+)
+
+// Point is one observation of a series.
+type Point struct {
+	Date  time.Time
+	Value float64
+}
+
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Date  string  `json:"date"`
+		Value float64 `json:"value"`
+	}{p.Date.Format("2006-01-02"), p.Value})
+}
+
+// Reading is the latest value of a series with its recent changes and where
+// it sits in the dashboard window.
+type Reading struct {
+	SeriesID string `json:"series_id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Unit     string `json:"unit"`
+	// Date is the date of the latest observation; empty when there is none.
+	Date  string   `json:"date,omitempty"`
+	Value *float64 `json:"value"`
+	// Change is since the previous observation, MonthChange since the last
+	// observation a month before Date.
+	Change      *float64 `json:"change"`
+	MonthChange *float64 `json:"month_change"`
+	// Percentile is the share of the window's observations at or below
+	// Value, 0 to 100.
+	Percentile *float64 `json:"percentile"`
+	Low        *float64 `json:"low"`
+	High       *float64 `json:"high"`
+	History    []Point  `json:"history"`
+}
+
+func newReading(s repository.MacroSeries, history []Point) Reading {
+	r := Reading{
+		SeriesID: s.SeriesID,
+		Name:     s.Name,
+		Category: s.Category,
+		Unit:     s.Unit,
+		History:  history,
+	}
+	if r.History == nil {
+		r.History = []Point{}
+	}
+	if len(history) == 0 {
+		return r
+	}
+
+	last := history[len(history)-1]
+	r.Date = last.Date.Format("2006-01-02")
+	r.Value = ptr(last.Value)
+	if len(history) > 1 {
+		r.Change = ptr(last.Value - history[len(history)-2].Value)
+	}
+	monthAgo := last.Date.AddDate(0, -1, 0)
+	for i := len(history) - 1; i >= 0; i-- {
+		if !history[i].Date.After(monthAgo) {
+			r.MonthChange = ptr(last.Value - history[i].Value)
+			break
+		}
+	}
+
+	low, high, below := math.Inf(1), math.Inf(-1), 0
+	for _, p := range history {
+		low = math.Min(low, p.Value)
+		high = math.Max(high, p.Value)
+		if p.Value <= last.Value {
+			below++
+		}
+	}
+	r.Low, r.High = ptr(low), ptr(high)
+	r.Percentile = ptr(100 * float64(below) / float64(len(history)))
+	return r
+}
+
+// ValueText formats the latest value, e.g. "4.12%".
+func (r Reading) ValueText() string {
+	if r.Value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%s", *r.Value, r.Unit)
+}
+
+// ChangeText formats the change since the previous observation; changes of
+// rates are in basis points.
+func (r Reading) ChangeText() string {
+	return r.changeText(r.Change)
+}
+
+// MonthChangeText formats the change over the past month like ChangeText.
+func (r Reading) MonthChangeText() string {
+	return r.changeText(r.MonthChange)
+}
+
+func (r Reading) changeText(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	if r.Unit == "%" {
+		return fmt.Sprintf("%+.0f bp", math.Round(*v*100))
+	}
+	return fmt.Sprintf("%+.2f", *v)
+}
+
+// PercentileText formats the percentile, e.g. "83rd percentile".
+func (r Reading) PercentileText() string {
+	if r.Percentile == nil {
+		return ""
+	}
+	n := int(math.Round(*r.Percentile))
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s percentile", n, suffix)
+}
+
+// Regime labels, from the curve, the VIX and financial conditions.
+const (
+	CurveInverted = "inverted"
+	CurveFlat     = "flat"
+	CurveNormal   = "normal"
+
+	VolCalm     = "calm"
+	VolNormal   = "normal"
+	VolElevated = "elevated"
+	VolStressed = "stressed"
+
+	ConditionsLoose = "loose"
+	ConditionsTight = "tight"
+
+	RiskOn      = "risk-on"
+	RiskNeutral = "neutral"
+	RiskOff     = "risk-off"
+)
+
+// Regime is the macro backdrop in a few labels. A label is empty when its
+// series has no data.
+type Regime struct {
+	Curve               string `json:"curve"`
+	Volatility          string `json:"volatility"`
+	FinancialConditions string `json:"financial_conditions"`
+	// Risk combines the labels: stressed volatility counts two against, and
+	// elevated volatility, tight conditions and an inverted curve one each.
+	// Two or more is risk-off; none with calm volatility is risk-on.
+	Risk    string `json:"risk"`
+	Summary string `json:"summary"`
+}
+
+func newRegime(readings []Reading) Regime {
+	latest := make(map[string]float64, len(readings))
+	for _, r := range readings {
+		if r.Value != nil {
+			latest[r.SeriesID] = *r.Value
+		}
+	}
+
+	var g Regime
+	if v, ok := latest[SeriesCurve]; ok {
+		switch {
+		case v < 0:
+			g.Curve = CurveInverted
+		case v < 0.25:
+			g.Curve = CurveFlat
+		default:
+			g.Curve = CurveNormal
+		}
+	}
+	if v, ok := latest[SeriesVIX]; ok {
+		switch {
+		case v < 15:
+			g.Volatility = VolCalm
+		case v < 20:
+			g.Volatility = VolNormal
+		case v < 30:
+			g.Volatility = VolElevated
+		default:
+			g.Volatility = VolStressed
+		}
+	}
+	// NFCI is zero at average conditions; positive is tighter than average.
+	if v, ok := latest[SeriesConditions]; ok {
+		g.FinancialConditions = ConditionsLoose
+		if v > 0 {
+			g.FinancialConditions = ConditionsTight
+		}
+	}
+	if g.Volatility == "" {
+		return g
+	}
+
+	score := 0
+	switch g.Volatility {
+	case VolStressed:
+		score += 2
+	case VolElevated:
+		score++
+	}
+	if g.FinancialConditions == ConditionsTight {
+		score++
+	}
+	if g.Curve == CurveInverted {
+		score++
+	}
+	switch {
+	case score >= 2:
+		g.Risk = RiskOff
+	case score == 0 && g.Volatility == VolCalm:
+		g.Risk = RiskOn
+	default:
+		g.Risk = RiskNeutral
+	}
+
+	var parts []string
+	if g.Curve != "" {
+		parts = append(parts, g.Curve+" curve")
+	}
+	parts = append(parts, g.Volatility+" volatility")
+	if g.FinancialConditions != "" {
+		parts = append(parts, g.FinancialConditions+" financial conditions")
+	}
+	g.Summary = strings.ToUpper(g.Risk[:1]) + g.Risk[1:] + ": " + strings.Join(parts, ", ")
+	return g
+}
+
+// Dashboard is every series' reading and the regime they add up to.
+type Dashboard struct {
+	AsOf     string    `json:"as_of,omitempty"`
+	Readings []Reading `json:"readings"`
+	Regime   Regime    `json:"regime"`
+}
+
+// NewDashboard builds the dashboard of series from their history, oldest
+// observation first. AsOf is the latest observation date of any series.
+func NewDashboard(series []repository.MacroSeries, history map[string][]Point) *Dashboard {
+	d := &Dashboard{Readings: make([]Reading, 0, len(series))}
+	for _, s := range series {
+		r := newReading(s, history[s.SeriesID])
+		if r.Date > d.AsOf {
+			d.AsOf = r.Date
+		}
+		d.Readings = append(d.Readings, r)
+	}
+	d.Regime = newRegime(d.Readings)
+	return d
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,87 @@
+package macro
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+)
+
+func points(start time.Time, values ...float64) []Point {
+	ps := make([]Point, len(values))
+	for i, v := range values {
+		ps[i] = Point{Date: start.AddDate(0, 0, 7*i), Value: v}
+	}
+	return ps
+}
+
+func TestNewReading(t *testing.T) {
+	start := time.Date(2026, 8, 7, 0, 0, 0, 0, time.UTC)
+	r := newReading(repository.MacroSeries{SeriesID: "DGS10", Name: "10-Year Treasury Yield", Unit: "%"},
+		points(start, 4.0, 4.4, 4.2, 4.1, 4.3, 4.35))
+
+	if r.Date != "2026-09-11" || *r.Value != 4.35 {
+		t.Fatalf("reading = %+v", r)
+	}
+	if math.Abs(*r.Change-0.05) > 1e-9 || r.ChangeText() != "+5 bp" {
+		t.Errorf("change = %v %s", *r.Change, r.ChangeText())
+	}
+	// A month before Sep 11 is Aug 11; the last observation by then is Aug 7.
+	if math.Abs(*r.MonthChange-0.35) > 1e-9 || r.MonthChangeText() != "+35 bp" {
+		t.Errorf("month change = %v", *r.MonthChange)
+	}
+	if *r.Low != 4.0 || *r.High != 4.4 || math.Abs(*r.Percentile-100*5.0/6) > 1e-9 {
+		t.Errorf("range = %v-%v, percentile %v", *r.Low, *r.High, *r.Percentile)
+	}
+	if r.PercentileText() != "83rd percentile" {
+		t.Errorf("percentile text = %s", r.PercentileText())
+	}
+	if r.ValueText() != "4.35%" {
+		t.Errorf("value text = %s", r.ValueText())
+	}
+
+	empty := newReading(repository.MacroSeries{SeriesID: "NFCI"}, nil)
+	if empty.Value != nil || empty.History == nil || empty.ValueText() != "-" {
+		t.Errorf("empty reading = %+v", empty)
+	}
+}
+
+func TestRegime(t *testing.T) {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	series := []repository.MacroSeries{{SeriesID: SeriesCurve}, {SeriesID: SeriesVIX}, {SeriesID: SeriesConditions}}
+
+	for _, tc := range []struct {
+		curve, vix, nfci float64
+		want             Regime
+	}{
+		{0.5, 13, -0.5, Regime{Curve: CurveNormal, Volatility: VolCalm, FinancialConditions: ConditionsLoose, Risk: RiskOn}},
+		{0.1, 18, -0.5, Regime{Curve: CurveFlat, Volatility: VolNormal, FinancialConditions: ConditionsLoose, Risk: RiskNeutral}},
+		{-0.3, 24, -0.2, Regime{Curve: CurveInverted, Volatility: VolElevated, FinancialConditions: ConditionsLoose, Risk: RiskOff}},
+		{0.8, 35, 0.1, Regime{Curve: CurveNormal, Volatility: VolStressed, FinancialConditions: ConditionsTight, Risk: RiskOff}},
+	} {
+		d := NewDashboard(series, map[string][]Point{
+			SeriesCurve:      points(start, tc.curve),
+			SeriesVIX:        points(start, tc.vix),
+			SeriesConditions: points(start, tc.nfci),
+		})
+		got := d.Regime
+		got.Summary = ""
+		if got != tc.want {
+			t.Errorf("curve %v, VIX %v, NFCI %v: regime = %+v, want %+v", tc.curve, tc.vix, tc.nfci, got, tc.want)
+		}
+	}
+
+	d := NewDashboard(series, map[string][]Point{
+		SeriesCurve: points(start, -0.3),
+		SeriesVIX:   points(start, 24),
+	})
+	if d.Regime.Summary != "Risk-off: inverted curve, elevated volatility" || d.AsOf != "2026-09-01" {
+		t.Errorf("dashboard = %+v", d)
+	}
+
+	// Without the VIX there is no risk call.
+	if g := NewDashboard(series, nil).Regime; g.Risk != "" || g.Summary != "" {
+		t.Errorf("regime without data = %+v", g)
+	}
+}
@@ -0,0 +1,8 @@
+// Package macro ingests the FRED series behind the macro dashboard: Treasury
+// yields, the 10y-2y curve, fed funds, the VIX close and the Chicago Fed's
+// financial conditions index. It summarizes them into readings and a macro
+// regime to read next to GEX.
+//
+// The series to ingest are the rows of the macro_series table; observations
+// are stored in macro_observations.
+package macro
@@ -0,0 +1,116 @@
+package macro
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/fred"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// backfillObservations is how many observations a new series starts
+	// with, about five years of a daily series.
+	backfillObservations = 1300
+	// recentObservations are fetched again on every run, so revisions of the
+	// latest values are picked up.
+	recentObservations = 20
+)
+
+// Store reads and writes the macro series and their observations.
+type Store struct {
+	repo *repository.Queries
+}
+
+func NewStore(db *pgxpool.Pool) *Store {
+	return &Store{repo: repository.New(db)}
+}
+
+// Ingest fetches the latest observations of every configured series from
+// FRED and stores them, backfilling series that have none yet. A series that
+// fails doesn't stop the others; the first error is returned with the number
+// of observations stored.
+func (s *Store) Ingest(ctx context.Context, client *fred.Client) (int, error) {
+	series, err := s.repo.ListMacroSeries(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list macro series: %w", err)
+	}
+
+	stored := 0
+	var firstErr error
+	for _, ms := range series {
+		n, err := s.ingestSeries(ctx, client, ms.SeriesID)
+		stored += n
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("ingest %s: %w", ms.SeriesID, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return stored, firstErr
+}
+
+func (s *Store) ingestSeries(ctx context.Context, client *fred.Client, seriesID string) (int, error) {
+	count, err := s.repo.CountMacroObservations(ctx, seriesID)
+	if err != nil {
+		return 0, err
+	}
+	limit := recentObservations
+	if count == 0 {
+		limit = backfillObservations
+	}
+
+	observations, err := client.GetSeriesObservations(ctx, seriesID, "", time.Time{}, limit)
+	if err != nil {
+		return 0, err
+	}
+	for i, o := range observations {
+		err := s.repo.UpsertMacroObservation(ctx, repository.UpsertMacroObservationParams{
+			SeriesID: seriesID,
+			Date:     pgtype.Date{Time: o.Date, Valid: true},
+			Value:    o.Value,
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(observations), nil
+}
+
+// Dashboard summarizes every series over the past days days.
+func (s *Store) Dashboard(ctx context.Context, days int) (*Dashboard, error) {
+	series, err := s.repo.ListMacroSeries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list macro series: %w", err)
+	}
+	rows, err := s.repo.ListMacroObservationsSince(ctx, pgtype.Date{Time: time.Now().AddDate(0, 0, -days), Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("list macro observations: %w", err)
+	}
+
+	history := make(map[string][]Point, len(series))
+	for _, r := range rows {
+		history[r.SeriesID] = append(history[r.SeriesID], Point{Date: r.Date.Time, Value: r.Value})
+	}
+	return NewDashboard(series, history), nil
+}
+
+// History returns the observations of seriesID since from, oldest first.
+func (s *Store) History(ctx context.Context, seriesID string, from time.Time) ([]Point, error) {
+	rows, err := s.repo.ListMacroSeriesObservations(ctx, repository.ListMacroSeriesObservationsParams{
+		SeriesID: seriesID,
+		Date:     pgtype.Date{Time: from, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list %s observations: %w", seriesID, err)
+	}
+	points := make([]Point, len(rows))
+	for i, r := range rows {
+		points[i] = Point{Date: r.Date.Time, Value: r.Value}
+	}
+	return points, nil
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// AdminAuth only lets requests through that present token, either as a
+// Bearer token or as the password of HTTP basic auth (so the pages can be
+// opened in a browser). An empty token denies everything.
+func AdminAuth(token string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" || !adminTokenMatches(r, token) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="admin"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func adminTokenMatches(r *http.Request, token string) bool {
+	presented := ""
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		presented = strings.TrimSpace(bearer)
+	} else if _, password, ok := r.BasicAuth(); ok {
+		presented = password
+	}
+	if presented == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
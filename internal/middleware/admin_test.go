@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name  string
+		token string
+		setup func(r *http.Request)
+		want  int
+	}{
+		{"no credentials", "secret", func(r *http.Request) {}, http.StatusUnauthorized},
+		{"bearer", "secret", func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") }, http.StatusOK},
+		{"wrong bearer", "secret", func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, http.StatusUnauthorized},
+		{"basic auth", "secret", func(r *http.Request) { r.SetBasicAuth("admin", "secret") }, http.StatusOK},
+		{"unconfigured", "", func(r *http.Request) { r.Header.Set("Authorization", "Bearer ") }, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/collector", nil)
+			tt.setup(req)
+			rec := httptest.NewRecorder()
+
+			AdminAuth(tt.token)(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package outbound
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bucket is a token bucket shared by every caller of a provider.
+type Bucket struct {
+	mu          sync.Mutex
+	rate        float64 // tokens added per second
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewBucket creates a full bucket that refills at rate tokens per second.
+func NewBucket(rate float64, burst int) *Bucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Bucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (b *Bucket) Wait(ctx context.Context) error {
+	for {
+		wait := b.reserve(time.Now())
+		if wait <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and otherwise returns how long
+// to wait before trying again.
+func (b *Bucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	if b.rate <= 0 {
+		return time.Second
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Pause stops handing out tokens for d and empties the bucket, so that once
+// a provider tells us to slow down every caller backs off, not just the one
+// that saw the 429.
+func (b *Bucket) Pause(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	b.tokens = 0
+	b.last = until
+}
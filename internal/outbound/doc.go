@@ -0,0 +1,3 @@
+// Package outbound throttles and retries calls the application makes to
+// third-party data providers.
+package outbound
@@ -0,0 +1,241 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testConfig() Config {
+	return Config{
+		Rate:             1000,
+		Burst:            10,
+		MaxAttempts:      3,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         5 * time.Millisecond,
+		FailureThreshold: 2,
+		Cooldown:         time.Hour,
+	}
+}
+
+func TestTransportRetriesThrottledRequests(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		body := make([]byte, r.ContentLength)
+		r.Body.Read(body)
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: Transport(NewProvider("test", testConfig()), nil)}
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	buf := make([]byte, 16)
+	n, _ := resp.Body.Read(buf)
+	if got := string(buf[:n]); got != "payload" {
+		t.Errorf("retried request body = %q, want %q", got, "payload")
+	}
+	if calls != 2 {
+		t.Errorf("server saw %d calls, want 2", calls)
+	}
+}
+
+func TestTransportGivesUpWithErrThrottled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	p := NewProvider("test", testConfig())
+	client := &http.Client{Transport: Transport(p, nil)}
+	_, err := client.Get(srv.URL)
+	if !errors.Is(err, ErrThrottled) {
+		t.Fatalf("expected ErrThrottled, got %v", err)
+	}
+	if p.Open() {
+		t.Error("throttling should not open the circuit")
+	}
+}
+
+func TestCircuitOpensAfterServerErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	p := NewProvider("test", testConfig())
+	client := &http.Client{Transport: Transport(p, nil)}
+
+	if _, err := client.Get(srv.URL); err == nil {
+		t.Fatal("expected an error from a failing server")
+	}
+	if !p.Open() {
+		t.Fatal("expected circuit to be open after repeated 502s")
+	}
+
+	before := atomic.LoadInt32(&calls)
+	_, err := client.Get(srv.URL)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != before {
+		t.Error("open circuit should not reach the server")
+	}
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	b := NewBreaker(1, time.Minute)
+	now := time.Now()
+
+	b.Failure(now)
+	if b.Allow(now.Add(time.Second)) {
+		t.Fatal("breaker should reject calls during cooldown")
+	}
+	if !b.Allow(now.Add(time.Minute)) {
+		t.Fatal("breaker should allow a probe after cooldown")
+	}
+	if b.Allow(now.Add(time.Minute)) {
+		t.Fatal("breaker should allow only one probe at a time")
+	}
+	b.Success()
+	if b.Open() || !b.Allow(now.Add(time.Minute)) {
+		t.Fatal("successful probe should close the circuit")
+	}
+}
+
+func TestCancelledProbeReleasesBreaker(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxAttempts = 1
+	cfg.FailureThreshold = 1
+	cfg.Cooldown = time.Millisecond
+	p := NewProvider("test", cfg)
+
+	failing := func(ctx context.Context) error { return &RetryableError{Err: errors.New("boom")} }
+	if err := p.Do(context.Background(), failing); err == nil || !p.Open() {
+		t.Fatalf("circuit should open: %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	// The half-open probe is cancelled by its caller mid-flight.
+	ctx, cancel := context.WithCancel(context.Background())
+	err := p.Do(ctx, func(ctx context.Context) error {
+		cancel()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled probe: %v", err)
+	}
+
+	if err := p.Do(context.Background(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("call after a cancelled probe: %v", err)
+	}
+	if p.Open() {
+		t.Error("successful probe should close the circuit")
+	}
+}
+
+func TestProbeReleasedWithoutOutcome(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxAttempts = 1
+	cfg.FailureThreshold = 1
+	cfg.Cooldown = time.Millisecond
+	healthy := func(ctx context.Context) error { return nil }
+
+	for name, probe := range map[string]func(p *Provider) error{
+		// The caller's deadline passes while the bucket is paused.
+		"paused bucket": func(p *Provider) error {
+			p.bucket.Pause(time.Hour)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+			defer cancel()
+			err := p.Do(ctx, healthy)
+			p.bucket = NewBucket(cfg.Rate, cfg.Burst)
+			return err
+		},
+		// A 429 says nothing about whether the provider has recovered.
+		"throttled": func(p *Provider) error {
+			return p.Do(context.Background(), func(ctx context.Context) error {
+				return &RetryableError{Err: ErrThrottled, Throttled: true}
+			})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := NewProvider("test", cfg)
+			p.Do(context.Background(), func(ctx context.Context) error { return &RetryableError{Err: errors.New("boom")} })
+			time.Sleep(2 * time.Millisecond)
+
+			if err := probe(p); err == nil || errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("probe: %v", err)
+			}
+			if err := p.Do(context.Background(), healthy); err != nil {
+				t.Fatalf("call after the probe: %v", err)
+			}
+		})
+	}
+}
+
+func TestBucketPauseAndContext(t *testing.T) {
+	b := NewBucket(1000, 1)
+	b.Pause(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded while paused, got %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	if got := parseRetryAfter("7", now); got != 7*time.Second {
+		t.Errorf("seconds form = %s", got)
+	}
+	date := now.Add(90 * time.Second).Format(http.TimeFormat)
+	if got := parseRetryAfter(date, now); got != 90*time.Second {
+		t.Errorf("date form = %s", got)
+	}
+	if got := parseRetryAfter("soon", now); got != 0 {
+		t.Errorf("garbage = %s", got)
+	}
+}
+
+func TestWithContextCancelsContextlessRequests(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	p := NewProvider("test", testConfig())
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	client := WithContext(ctx, &http.Client{Transport: Transport(p, nil)})
+
+	// Built without a context, the way the Alpaca SDK does it.
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	_, err := client.Do(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if p.breaker.Open() {
+		t.Error("a caller's deadline must not count against the provider")
+	}
+}
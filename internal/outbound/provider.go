@@ -0,0 +1,194 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the provider while its circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Config describes how hard we are allowed to hit a provider.
+type Config struct {
+	// Rate is the sustained number of requests per second.
+	Rate float64
+	// Burst is how many requests may be sent back to back.
+	Burst int
+	// MaxAttempts includes the first try.
+	MaxAttempts int
+	// BaseDelay and MaxDelay bound the exponential backoff between attempts.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// FailureThreshold consecutive failures open the circuit for Cooldown.
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+// DefaultConfig is used for providers without an entry in defaults.
+var DefaultConfig = Config{
+	Rate:             5,
+	Burst:            10,
+	MaxAttempts:      4,
+	BaseDelay:        500 * time.Millisecond,
+	MaxDelay:         30 * time.Second,
+	FailureThreshold: 5,
+	Cooldown:         30 * time.Second,
+}
+
+var defaults = map[string]Config{
+	// Alpaca allows 200 requests per minute on the free data plan.
+	"alpaca": {
+		Rate:             3,
+		Burst:            10,
+		MaxAttempts:      4,
+		BaseDelay:        time.Second,
+		MaxDelay:         30 * time.Second,
+		FailureThreshold: 5,
+		Cooldown:         time.Minute,
+	},
+	"public": DefaultConfig,
+}
+
+// Provider is the shared throttle, retry policy and circuit breaker for one
+// upstream service.
+type Provider struct {
+	Name    string
+	config  Config
+	bucket  *Bucket
+	breaker *Breaker
+}
+
+func NewProvider(name string, cfg Config) *Provider {
+	return &Provider{
+		Name:    name,
+		config:  cfg,
+		bucket:  NewBucket(cfg.Rate, cfg.Burst),
+		breaker: NewBreaker(cfg.FailureThreshold, cfg.Cooldown),
+	}
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]*Provider)
+)
+
+// For returns the process-wide Provider for name, creating it on first use.
+// OUTBOUND_<NAME>_RPS and OUTBOUND_<NAME>_BURST override the default rate.
+func For(name string) *Provider {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if p, ok := registry[name]; ok {
+		return p
+	}
+
+	cfg, ok := defaults[name]
+	if !ok {
+		cfg = DefaultConfig
+	}
+	prefix := "OUTBOUND_" + strings.ToUpper(name)
+	if v, err := strconv.ParseFloat(os.Getenv(prefix+"_RPS"), 64); err == nil && v > 0 {
+		cfg.Rate = v
+	}
+	if v, err := strconv.Atoi(os.Getenv(prefix + "_BURST")); err == nil && v > 0 {
+		cfg.Burst = v
+	}
+
+	p := NewProvider(name, cfg)
+	registry[name] = p
+	return p
+}
+
+// Do runs fn under the provider's limiter and breaker, retrying with
+// exponential backoff while fn returns a *RetryableError.
+func (p *Provider) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		if !p.breaker.Allow(time.Now()) {
+			return fmt.Errorf("%s: %w", p.Name, ErrCircuitOpen)
+		}
+		if err := p.bucket.Wait(ctx); err != nil {
+			// Allow may have handed us the half-open probe; give it back.
+			p.breaker.Release()
+			return err
+		}
+
+		err := fn(ctx)
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about the provider's health.
+			p.breaker.Release()
+			return err
+		}
+
+		var retryable *RetryableError
+		if !errors.As(err, &retryable) {
+			p.breaker.Success()
+			return err
+		}
+
+		if retryable.RetryAfter > 0 {
+			p.bucket.Pause(retryable.RetryAfter)
+		}
+		if retryable.Throttled {
+			p.breaker.Release()
+		} else {
+			p.breaker.Failure(time.Now())
+		}
+
+		if attempt+1 >= p.config.MaxAttempts || ctx.Err() != nil {
+			return retryable.Err
+		}
+
+		if err := sleep(ctx, p.backoff(attempt, retryable.RetryAfter)); err != nil {
+			return err
+		}
+	}
+}
+
+// Open reports whether the provider's circuit is currently open.
+func (p *Provider) Open() bool {
+	return p.breaker.Open()
+}
+
+// RetryableError marks a failure worth retrying. Throttled failures (429)
+// pause the bucket but don't count towards opening the circuit.
+type RetryableError struct {
+	Err        error
+	RetryAfter time.Duration
+	Throttled  bool
+}
+
+func (e *RetryableError) Error() string { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// backoff is exponential with full jitter, but never shorter than what the
+// provider asked for via Retry-After.
+func (p *Provider) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	d := p.config.BaseDelay << attempt
+	if d <= 0 || d > p.config.MaxDelay {
+		d = p.config.MaxDelay
+	}
+	d = time.Duration(rand.Int63n(int64(d) + 1))
+	return max(d, retryAfter)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,172 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrThrottled is returned once a provider keeps answering 429 after every
+// retry has been used.
+var ErrThrottled = errors.New("throttled by provider")
+
+// Transport wraps base so every request made through it is throttled,
+// retried and circuit-broken by p. A nil base uses the transport installed
+// by SetBaseTransport, or http.DefaultTransport.
+func Transport(p *Provider, base http.RoundTripper) http.RoundTripper {
+	return &transport{provider: p, base: base}
+}
+
+var (
+	baseMu        sync.RWMutex
+	baseTransport http.RoundTripper
+)
+
+// SetBaseTransport makes rt carry the requests of every provider client that
+// was built without a base of its own, including ones created earlier. Tests
+// use it to replay recorded responses; the returned func restores the
+// previous transport.
+func SetBaseTransport(rt http.RoundTripper) (restore func()) {
+	baseMu.Lock()
+	prev := baseTransport
+	baseTransport = rt
+	baseMu.Unlock()
+	return func() {
+		baseMu.Lock()
+		baseTransport = prev
+		baseMu.Unlock()
+	}
+}
+
+func defaultBase() http.RoundTripper {
+	baseMu.RLock()
+	defer baseMu.RUnlock()
+	if baseTransport != nil {
+		return baseTransport
+	}
+	return http.DefaultTransport
+}
+
+// HTTPClient returns an *http.Client whose transport goes through p.
+func HTTPClient(p *Provider, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: Transport(p, nil),
+	}
+}
+
+// WithContext returns a copy of c whose requests run under ctx unless they
+// already carry a cancellable context of their own. It lets deadlines and
+// cancellation reach SDKs, such as Alpaca's, that build requests without one.
+func WithContext(ctx context.Context, c *http.Client) *http.Client {
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	bound := *c
+	bound.Transport = &contextTransport{ctx: ctx, base: base}
+	return &bound
+}
+
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context().Done() == nil {
+		req = req.WithContext(t.ctx)
+	}
+	return t.base.RoundTrip(req)
+}
+
+type transport struct {
+	provider *Provider
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = defaultBase()
+	}
+
+	// Requests whose body can't be replayed get a single attempt.
+	rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	var resp *http.Response
+	attempt := 0
+	err := t.provider.Do(req.Context(), func(ctx context.Context) error {
+		r := req
+		if attempt > 0 {
+			r = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return err
+				}
+				r.Body = body
+			}
+		}
+		attempt++
+
+		res, err := base.RoundTrip(r)
+		if err != nil {
+			if ctx.Err() != nil || !rewindable {
+				return err
+			}
+			return &RetryableError{Err: err}
+		}
+
+		if rewindable {
+			switch {
+			case res.StatusCode == http.StatusTooManyRequests:
+				retryAfter := parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+				drain(res)
+				return &RetryableError{
+					Err:        fmt.Errorf("%s: %w (HTTP %d)", t.provider.Name, ErrThrottled, res.StatusCode),
+					RetryAfter: retryAfter,
+					Throttled:  true,
+				}
+			case res.StatusCode >= 500 && res.StatusCode != http.StatusNotImplemented:
+				retryAfter := parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+				drain(res)
+				return &RetryableError{
+					Err:        fmt.Errorf("%s: server error (HTTP %d)", t.provider.Name, res.StatusCode),
+					RetryAfter: retryAfter,
+				}
+			}
+		}
+
+		resp = res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// parseRetryAfter understands both delta-seconds and HTTP-date values.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
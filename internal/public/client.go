@@ -2,29 +2,84 @@ package public
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/outbound"
 )
 
+// Client is a long-lived Public.com API session. It is safe for concurrent
+// use: the access token and account ID are fetched once, cached, refreshed
+// shortly before the token expires and re-fetched if the API answers 401.
+// Use Shared to get the process-wide client for a set of credentials.
 type Client struct {
 	BaseURL    string
 	Secret     string
-	Token      string
-	AccountID  string
 	HTTPClient *http.Client
+
+	// TokenValidity is the lifetime requested for access tokens. Tokens are
+	// refreshed RefreshBefore their expiry.
+	TokenValidity time.Duration
+	RefreshBefore time.Duration
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	accountID   string
+	now         func() time.Time
 }
 
+// NewClient returns a client for the public API, or for PUBLIC_API_BASE_URL
+// when set (e.g. cmd/fakemarket).
 func NewClient(secret, accountID string) *Client {
+	baseURL := os.Getenv("PUBLIC_API_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.public.com"
+	}
 	return &Client{
-		BaseURL:    "https://api.public.com",
-		Secret:     secret,
-		AccountID:  accountID,
-		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+		BaseURL:       baseURL,
+		Secret:        secret,
+		HTTPClient:    outbound.HTTPClient(outbound.For("public"), 15*time.Second),
+		TokenValidity: 60 * time.Minute,
+		RefreshBefore: 5 * time.Minute,
+		accountID:     accountID,
+		now:           time.Now,
+	}
+}
+
+var (
+	sharedMu      sync.Mutex
+	sharedClients = make(map[string]*Client)
+)
+
+// Shared returns the client shared by every caller using the same
+// credentials, creating it on first use.
+func Shared(secret, accountID string) *Client {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	key := secret + "\x00" + accountID
+	if c, ok := sharedClients[key]; ok {
+		return c
 	}
+	c := NewClient(secret, accountID)
+	sharedClients[key] = c
+	return c
+}
+
+// AccountID returns the cached account ID, empty until the session has been
+// established.
+func (c *Client) AccountID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.accountID
 }
 
 type AccessTokenRequest struct {
@@ -36,41 +91,109 @@ type AccessTokenResponse struct {
 	AccessToken string `json:"accessToken"`
 }
 
-func (c *Client) do(method, url string, body interface{}) (*http.Response, error) {
+// send performs a single request with the given token.
+func (c *Client) send(ctx context.Context, method, url, token string, body []byte) (*http.Response, error) {
 	var bodyReader io.Reader
 	if body != nil {
-		data, err := json.Marshal(body)
-		if err != nil {
-			return nil, err
-		}
-		bodyReader = bytes.NewBuffer(data)
+		bodyReader = bytes.NewReader(body)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, err
 	}
 
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	
+
 	req.Header.Set("User-Agent", "public-go-client/1.0")
 
 	return c.HTTPClient.Do(req)
 }
 
-func (c *Client) Authenticate() error {
-	reqBody := AccessTokenRequest{
+// do sends an authenticated request to path (relative to BaseURL, with
+// "{account}" replaced by the account ID). A 401 drops the cached token and
+// the request is retried once with a fresh one.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, accountID, err := c.session(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		url := c.BaseURL + strings.ReplaceAll(path, "{account}", accountID)
+		resp, err := c.send(ctx, method, url, token, data)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+		resp.Body.Close()
+		c.invalidate(token)
+	}
+}
+
+// session returns a valid token and account ID, authenticating and looking
+// up the account if needed. Callers are serialised while the session is
+// being (re)established, so a burst of requests triggers a single login.
+func (c *Client) session(ctx context.Context) (string, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == "" || !c.now().Before(c.tokenExpiry.Add(-c.RefreshBefore)) {
+		if err := c.authenticateLocked(ctx); err != nil {
+			return "", "", err
+		}
+	}
+	if c.accountID == "" {
+		if err := c.fetchAccountIDLocked(ctx); err != nil {
+			return "", "", err
+		}
+	}
+	return c.token, c.accountID, nil
+}
+
+// invalidate drops token if it is still the cached one, so concurrent
+// requests that all saw the same 401 only trigger one refresh.
+func (c *Client) invalidate(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == token {
+		c.token = ""
+	}
+}
+
+// Authenticate forces a new access token to be requested.
+func (c *Client) Authenticate(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.authenticateLocked(ctx)
+}
+
+func (c *Client) authenticateLocked(ctx context.Context) error {
+	reqBody, err := json.Marshal(AccessTokenRequest{
 		Secret:            c.Secret,
-		ValidityInMinutes: 60,
+		ValidityInMinutes: int(c.TokenValidity / time.Minute),
+	})
+	if err != nil {
+		return err
 	}
 
-	url := c.BaseURL + "/userapiauthservice/personal/access-tokens"
-	resp, err := c.do("POST", url, reqBody)
+	issuedAt := c.now()
+	resp, err := c.send(ctx, "POST", c.BaseURL+"/userapiauthservice/personal/access-tokens", "", reqBody)
 	if err != nil {
 		return err
 	}
@@ -85,8 +208,12 @@ func (c *Client) Authenticate() error {
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return err
 	}
+	if res.AccessToken == "" {
+		return fmt.Errorf("auth failed: empty access token")
+	}
 
-	c.Token = res.AccessToken
+	c.token = res.AccessToken
+	c.tokenExpiry = issuedAt.Add(c.TokenValidity)
 	return nil
 }
 
@@ -99,27 +226,31 @@ type AccountResponse struct {
 	} `json:"accounts"`
 }
 
-func (c *Client) FetchAccountID() error {
-	if c.AccountID != "" {
-		return nil
-	}
+// FetchAccountID establishes the session and looks up the account ID unless
+// one was configured or has already been fetched.
+func (c *Client) FetchAccountID(ctx context.Context) error {
+	_, _, err := c.session(ctx)
+	return err
+}
 
-	url := c.BaseURL + "/userapigateway/trading/accounts"
-	resp, err := c.do("GET", url, nil)
+func (c *Client) fetchAccountIDLocked(ctx context.Context) error {
+	resp, err := c.send(ctx, "GET", c.BaseURL+"/userapigateway/trading/accounts", c.token, nil)
 	if err != nil {
 		return err
 	}
-	
+
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
-		url = c.BaseURL + "/userapigateway/trading/account"
-		resp, err = c.do("GET", url, nil)
+		resp, err = c.send(ctx, "GET", c.BaseURL+"/userapigateway/trading/account", c.token, nil)
 		if err != nil {
 			return err
 		}
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.token = ""
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("fetch account failed (HTTP %d): %s", resp.StatusCode, string(body))
@@ -131,12 +262,12 @@ func (c *Client) FetchAccountID() error {
 	}
 
 	if res.Account != nil && res.Account.AccountID != "" {
-		c.AccountID = res.Account.AccountID
+		c.accountID = res.Account.AccountID
 	} else if len(res.Accounts) > 0 {
-		c.AccountID = res.Accounts[0].AccountID
+		c.accountID = res.Accounts[0].AccountID
 	}
 
-	if c.AccountID == "" {
+	if c.accountID == "" {
 		return fmt.Errorf("no account ID found in response")
 	}
 
@@ -158,13 +289,12 @@ type QuoteResponse struct {
 	} `json:"quotes"`
 }
 
-func (c *Client) GetSpotPrice(symbol string) (float64, error) {
+func (c *Client) GetSpotPrice(ctx context.Context, symbol string) (float64, error) {
 	reqBody := QuoteRequest{
 		Instruments: []Instrument{{Symbol: symbol, Type: "EQUITY"}},
 	}
 
-	url := fmt.Sprintf("%s/userapigateway/marketdata/%s/quotes", c.BaseURL, c.AccountID)
-	resp, err := c.do("POST", url, reqBody)
+	resp, err := c.do(ctx, "POST", "/userapigateway/marketdata/{account}/quotes", reqBody)
 	if err != nil {
 		return 0, err
 	}
@@ -211,14 +341,13 @@ type OptionChainResponse struct {
 	Puts  []OptionContract `json:"puts"`
 }
 
-func (c *Client) GetOptionChain(symbol string, expiration string) (*OptionChainResponse, []byte, error) {
+func (c *Client) GetOptionChain(ctx context.Context, symbol string, expiration string) (*OptionChainResponse, []byte, error) {
 	reqBody := OptionChainRequest{
 		Instrument:     Instrument{Symbol: symbol, Type: "EQUITY"},
 		ExpirationDate: expiration,
 	}
 
-	url := fmt.Sprintf("%s/userapigateway/marketdata/%s/option-chain", c.BaseURL, c.AccountID)
-	resp, err := c.do("POST", url, reqBody)
+	resp, err := c.do(ctx, "POST", "/userapigateway/marketdata/{account}/option-chain", reqBody)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -247,7 +376,7 @@ type GreeksResponse struct {
 	} `json:"greeks"`
 }
 
-func (c *Client) GetGreeks(osiSymbols []string) (map[string]float64, error) {
+func (c *Client) GetGreeks(ctx context.Context, osiSymbols []string) (map[string]float64, error) {
 	gammaMap := make(map[string]float64)
 
 	for i := 0; i < len(osiSymbols); i += 250 {
@@ -257,10 +386,9 @@ func (c *Client) GetGreeks(osiSymbols []string) (map[string]float64, error) {
 		}
 		batch := osiSymbols[i:end]
 
-		url := fmt.Sprintf("%s/userapigateway/option-details/%s/greeks?osiSymbols=%s", 
-			c.BaseURL, c.AccountID, strings.Join(batch, ","))
-		
-		resp, err := c.do("GET", url, nil)
+		path := "/userapigateway/option-details/{account}/greeks?osiSymbols=" + strings.Join(batch, ",")
+
+		resp, err := c.do(ctx, "GET", path, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -290,15 +418,14 @@ type ExpirationsResponse struct {
 	Expirations []string `json:"expirations"`
 }
 
-func (c *Client) GetExpirations(symbol string) ([]string, error) {
+func (c *Client) GetExpirations(ctx context.Context, symbol string) ([]string, error) {
 	reqBody := struct {
 		Instrument Instrument `json:"instrument"`
 	}{
 		Instrument: Instrument{Symbol: symbol, Type: "EQUITY"},
 	}
 
-	url := fmt.Sprintf("%s/userapigateway/marketdata/%s/option-expirations", c.BaseURL, c.AccountID)
-	resp, err := c.do("POST", url, reqBody)
+	resp, err := c.do(ctx, "POST", "/userapigateway/marketdata/{account}/option-expirations", reqBody)
 	if err != nil {
 		return nil, err
 	}
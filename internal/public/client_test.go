@@ -0,0 +1,152 @@
+package public
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakePublic serves the auth, account and quote endpoints and counts calls.
+type fakePublic struct {
+	logins   atomic.Int32
+	accounts atomic.Int32
+	quotes   atomic.Int32
+
+	mu    sync.Mutex
+	valid map[string]bool
+}
+
+func (f *fakePublic) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/userapiauthservice/personal/access-tokens", func(w http.ResponseWriter, r *http.Request) {
+		n := f.logins.Add(1)
+		token := fmt.Sprintf("token-%d", n)
+		f.mu.Lock()
+		f.valid[token] = true
+		f.mu.Unlock()
+		json.NewEncoder(w).Encode(AccessTokenResponse{AccessToken: token})
+	})
+	mux.HandleFunc("/userapigateway/trading/accounts", func(w http.ResponseWriter, r *http.Request) {
+		f.accounts.Add(1)
+		w.Write([]byte(`{"accounts":[{"accountId":"ACC1"}]}`))
+	})
+	mux.HandleFunc("/userapigateway/marketdata/ACC1/quotes", func(w http.ResponseWriter, r *http.Request) {
+		f.quotes.Add(1)
+		token := r.Header.Get("Authorization")[len("Bearer "):]
+		f.mu.Lock()
+		ok := f.valid[token]
+		f.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"quotes":[{"last":"501.25"}]}`))
+	})
+	return mux
+}
+
+func newFakeClient(t *testing.T) (*Client, *fakePublic) {
+	t.Helper()
+	f := &fakePublic{valid: make(map[string]bool)}
+	srv := httptest.NewServer(f.handler())
+	t.Cleanup(srv.Close)
+
+	c := NewClient("secret", "")
+	c.BaseURL = srv.URL
+	c.HTTPClient = srv.Client()
+	return c, f
+}
+
+func TestClientReusesSession(t *testing.T) {
+	c, f := newFakeClient(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetSpotPrice(context.Background(), "SPY"); err != nil {
+				t.Errorf("GetSpotPrice: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := f.logins.Load(); got != 1 {
+		t.Errorf("logins = %d, want 1", got)
+	}
+	if got := f.accounts.Load(); got != 1 {
+		t.Errorf("account lookups = %d, want 1", got)
+	}
+	if c.AccountID() != "ACC1" {
+		t.Errorf("AccountID = %q, want ACC1", c.AccountID())
+	}
+}
+
+func TestClientRefreshesBeforeExpiry(t *testing.T) {
+	c, f := newFakeClient(t)
+	now := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	if _, err := c.GetSpotPrice(context.Background(), "SPY"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(50 * time.Minute)
+	if _, err := c.GetSpotPrice(context.Background(), "SPY"); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.logins.Load(); got != 1 {
+		t.Fatalf("logins after 50m = %d, want 1", got)
+	}
+
+	// Inside the refresh margin of a 60 minute token.
+	now = now.Add(6 * time.Minute)
+	if _, err := c.GetSpotPrice(context.Background(), "SPY"); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.logins.Load(); got != 2 {
+		t.Errorf("logins after 56m = %d, want 2", got)
+	}
+}
+
+func TestClientReauthenticatesOn401(t *testing.T) {
+	c, f := newFakeClient(t)
+
+	if _, err := c.GetSpotPrice(context.Background(), "SPY"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The server revokes every token it has issued.
+	f.mu.Lock()
+	f.valid = make(map[string]bool)
+	f.mu.Unlock()
+
+	price, err := c.GetSpotPrice(context.Background(), "SPY")
+	if err != nil {
+		t.Fatalf("GetSpotPrice after revocation: %v", err)
+	}
+	if price != 501.25 {
+		t.Errorf("price = %v, want 501.25", price)
+	}
+	if got := f.logins.Load(); got != 2 {
+		t.Errorf("logins = %d, want 2", got)
+	}
+	if got := f.quotes.Load(); got != 3 {
+		t.Errorf("quote requests = %d, want 3 (one retried)", got)
+	}
+}
+
+func TestSharedReturnsSameClient(t *testing.T) {
+	if Shared("a", "") != Shared("a", "") {
+		t.Error("Shared returned different clients for the same credentials")
+	}
+	if Shared("a", "") == Shared("b", "") {
+		t.Error("Shared returned the same client for different credentials")
+	}
+}
@@ -0,0 +1,54 @@
+package public
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/arnabmitra/eth-proxy/internal/httpreplay"
+)
+
+func TestOptionChainReplay(t *testing.T) {
+	rec := httpreplay.ForTest(t, "public_spy_chain")
+
+	secret := os.Getenv("PUBLIC_PERSONAL_SECRET")
+	if secret == "" {
+		secret = "test-secret"
+	}
+	c := NewClient(secret, "")
+	c.BaseURL = "https://api.public.com"
+	c.HTTPClient = rec.Client()
+	ctx := context.Background()
+
+	expirations, err := c.GetExpirations(ctx, "SPY")
+	if err != nil {
+		t.Fatalf("GetExpirations: %v", err)
+	}
+	if len(expirations) == 0 {
+		t.Fatal("expected expirations")
+	}
+
+	chain, raw, err := c.GetOptionChain(ctx, "SPY", expirations[0])
+	if err != nil {
+		t.Fatalf("GetOptionChain: %v", err)
+	}
+	if len(raw) == 0 || len(chain.Calls) == 0 || len(chain.Puts) == 0 {
+		t.Fatalf("expected calls and puts, got %d/%d", len(chain.Calls), len(chain.Puts))
+	}
+	for _, contract := range append(chain.Calls, chain.Puts...) {
+		if _, err := contract.OptionDetails.StrikePrice.Float64(); err != nil {
+			t.Fatalf("bad strike in %+v: %v", contract, err)
+		}
+		if contract.OptionDetails.Greeks == nil {
+			t.Fatalf("missing greeks in %+v", contract)
+		}
+	}
+
+	price, err := c.GetSpotPrice(ctx, "SPY")
+	if err != nil {
+		t.Fatalf("GetSpotPrice: %v", err)
+	}
+	if price <= 0 {
+		t.Errorf("price = %v", price)
+	}
+}
@@ -12,16 +12,73 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type EconomicRelease struct {
-	ID          uuid.UUID
-	ReleaseID   int32
-	ReleaseName string
-	ReleaseDate pgtype.Date
-	Impact      string
-	CreatedAt   time.Time
+type BtcEtfFlow struct {
+	Ticker      string
+	Date        pgtype.Date
+	FlowUsd     float64
+	HoldingsBtc pgtype.Float8
 	UpdatedAt   time.Time
 }
 
+type BtcEtfFund struct {
+	Ticker    string
+	Name      string
+	Issuer    string
+	Position  int32
+	CreatedAt time.Time
+}
+
+type CollectorRun struct {
+	ID               uuid.UUID
+	Trigger          string
+	StartedAt        time.Time
+	FinishedAt       pgtype.Timestamptz
+	SymbolsAttempted int32
+	Successes        int32
+	Failures         int32
+}
+
+type CollectorRunSymbol struct {
+	ID         uuid.UUID
+	RunID      uuid.UUID
+	Symbol     string
+	Tier       string
+	Status     string
+	Error      pgtype.Text
+	Provider   pgtype.Text
+	StartedAt  time.Time
+	DurationMs int32
+}
+
+type EarningsEvent struct {
+	Symbol       string
+	ReportDate   pgtype.Date
+	Session      string
+	FiscalPeriod string
+	EpsEstimate  pgtype.Float8
+	Source       string
+	UpdatedAt    time.Time
+}
+
+type EconomicRelease struct {
+	ID              uuid.UUID
+	ReleaseID       int32
+	ReleaseName     string
+	ReleaseDate     pgtype.Date
+	Impact          string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ReleaseTime     pgtype.Time
+	SeriesID        pgtype.Text
+	Units           pgtype.Text
+	Period          pgtype.Date
+	Actual          pgtype.Float8
+	Prior           pgtype.Float8
+	Consensus       pgtype.Float8
+	ConsensusSource pgtype.Text
+	ValuesUpdatedAt pgtype.Timestamptz
+}
+
 type GexHistory struct {
 	ID          uuid.UUID
 	Symbol      string
@@ -31,6 +88,17 @@ type GexHistory struct {
 	GexValue    pgtype.Numeric
 	RecordedAt  time.Time
 	SpotPrice   pgtype.Text
+	FlipLevel   pgtype.Numeric
+	CallWall    pgtype.Numeric
+	PutWall     pgtype.Numeric
+}
+
+type GexSnapshot struct {
+	Symbol     string
+	SpotPrice  float64
+	Strikes    []byte
+	Warning    string
+	ComputedAt time.Time
 }
 
 type Guest struct {
@@ -41,6 +109,23 @@ type Guest struct {
 	UpdatedAt time.Time
 }
 
+type MacroObservation struct {
+	SeriesID  string
+	Date      pgtype.Date
+	Value     float64
+	UpdatedAt time.Time
+}
+
+type MacroSeries struct {
+	SeriesID  string
+	Name      string
+	Category  string
+	Unit      string
+	Position  int32
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
 type OptionChain struct {
 	ID          uuid.UUID
 	Symbol      string
@@ -69,3 +154,51 @@ type OptionExpiryDate struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
+
+type ScannerView struct {
+	ID        uuid.UUID
+	Owner     string
+	Name      string
+	Query     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type SymbolMetadatum struct {
+	Symbol    string
+	Name      string
+	Sector    string
+	MarketCap pgtype.Int8
+	UpdatedAt time.Time
+	Industry  string
+}
+
+type TrackedRelease struct {
+	ReleaseID     int32
+	Name          string
+	Impact        string
+	ReleaseTime   pgtype.Time
+	Category      string
+	SeriesID      pgtype.Text
+	Units         pgtype.Text
+	RelatedSeries []string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type Universe struct {
+	ID        uuid.UUID
+	Slug      string
+	Name      string
+	Kind      string
+	Owner     pgtype.Text
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type UniverseMember struct {
+	UniverseID uuid.UUID
+	Symbol     string
+	Position   int32
+	AddedAt    time.Time
+}
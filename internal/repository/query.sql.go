@@ -25,6 +25,122 @@ func (q *Queries) Count(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countMacroObservations = `-- name: CountMacroObservations :one
+SELECT count(*) FROM macro_observations
+WHERE series_id = $1
+`
+
+func (q *Queries) CountMacroObservations(ctx context.Context, seriesID string) (int64, error) {
+	row := q.db.QueryRow(ctx, countMacroObservations, seriesID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createCollectorRun = `-- name: CreateCollectorRun :one
+INSERT INTO collector_runs (trigger, started_at)
+VALUES ($1, $2)
+RETURNING id, trigger, started_at, finished_at, symbols_attempted, successes, failures
+`
+
+type CreateCollectorRunParams struct {
+	Trigger   string
+	StartedAt time.Time
+}
+
+// Collector run ledger
+func (q *Queries) CreateCollectorRun(ctx context.Context, arg CreateCollectorRunParams) (CollectorRun, error) {
+	row := q.db.QueryRow(ctx, createCollectorRun, arg.Trigger, arg.StartedAt)
+	var i CollectorRun
+	err := row.Scan(
+		&i.ID,
+		&i.Trigger,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.SymbolsAttempted,
+		&i.Successes,
+		&i.Failures,
+	)
+	return i, err
+}
+
+const deleteRescheduledEarnings = `-- name: DeleteRescheduledEarnings :exec
+DELETE FROM earnings_events
+WHERE symbol = $1 AND fiscal_period = $2 AND report_date <> $3
+`
+
+type DeleteRescheduledEarningsParams struct {
+	Symbol       string
+	FiscalPeriod string
+	ReportDate   pgtype.Date
+}
+
+// Drops other dates of a symbol's fiscal period once it has been rescheduled.
+func (q *Queries) DeleteRescheduledEarnings(ctx context.Context, arg DeleteRescheduledEarningsParams) error {
+	_, err := q.db.Exec(ctx, deleteRescheduledEarnings, arg.Symbol, arg.FiscalPeriod, arg.ReportDate)
+	return err
+}
+
+const deleteScannerView = `-- name: DeleteScannerView :execrows
+DELETE FROM scanner_views WHERE owner = $1 AND name = $2
+`
+
+type DeleteScannerViewParams struct {
+	Owner string
+	Name  string
+}
+
+func (q *Queries) DeleteScannerView(ctx context.Context, arg DeleteScannerViewParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteScannerView, arg.Owner, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTrackedRelease = `-- name: DeleteTrackedRelease :execrows
+DELETE FROM tracked_releases WHERE release_id = $1
+`
+
+func (q *Queries) DeleteTrackedRelease(ctx context.Context, releaseID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTrackedRelease, releaseID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUniverseMembers = `-- name: DeleteUniverseMembers :exec
+DELETE FROM universe_members WHERE universe_id = $1
+`
+
+func (q *Queries) DeleteUniverseMembers(ctx context.Context, universeID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUniverseMembers, universeID)
+	return err
+}
+
+const deleteUpcomingEconomicReleases = `-- name: DeleteUpcomingEconomicReleases :exec
+DELETE FROM economic_releases WHERE release_id = $1 AND release_date >= CURRENT_DATE
+`
+
+// Drops the scheduled dates of a release that is no longer tracked.
+func (q *Queries) DeleteUpcomingEconomicReleases(ctx context.Context, releaseID int32) error {
+	_, err := q.db.Exec(ctx, deleteUpcomingEconomicReleases, releaseID)
+	return err
+}
+
+const ensureBTCETFFund = `-- name: EnsureBTCETFFund :exec
+INSERT INTO btc_etf_funds (ticker, name, position)
+VALUES ($1, $1, 1000)
+ON CONFLICT (ticker) DO NOTHING
+`
+
+// Adds a fund the flows source reports but the seed list doesn't have.
+func (q *Queries) EnsureBTCETFFund(ctx context.Context, ticker string) error {
+	_, err := q.db.Exec(ctx, ensureBTCETFFund, ticker)
+	return err
+}
+
 const findAll = `-- name: FindAll :many
 SELECT id, message, ip, created_at, updated_at
 FROM guest
@@ -58,79 +174,57 @@ func (q *Queries) FindAll(ctx context.Context, limit int32) ([]Guest, error) {
 	return items, nil
 }
 
-const getGEXAnomalies = `-- name: GetGEXAnomalies :many
-WITH daily_closes AS (
-    SELECT DISTINCT ON (symbol, recorded_at::date)
-        symbol,
-        gex_value,
-        recorded_at
-    FROM gex_history
-    WHERE recorded_at >= NOW() - INTERVAL '30 days'
-    ORDER BY symbol, recorded_at::date DESC, recorded_at DESC
+const getFailingCollectorSymbols = `-- name: GetFailingCollectorSymbols :many
+WITH latest AS (
+    SELECT DISTINCT ON (symbol)
+        symbol, status, error, provider, started_at
+    FROM collector_run_symbols
+    ORDER BY symbol, started_at DESC
 ),
-stats AS (
-    SELECT
-        symbol,
-        AVG(gex_value) as avg_gex,
-        STDDEV(gex_value) as stddev_gex
-    FROM daily_closes
+recent_failures AS (
+    SELECT symbol, COUNT(*) AS failure_count
+    FROM collector_run_symbols
+    WHERE collector_run_symbols.status NOT IN ('success', 'canceled') AND collector_run_symbols.started_at >= $1
     GROUP BY symbol
-),
-latest AS (
-    SELECT DISTINCT ON (symbol)
-        symbol,
-        gex_value,
-        spot_price,
-        recorded_at
-    FROM gex_history
-    ORDER BY symbol, recorded_at DESC
 )
 SELECT
     l.symbol,
-    l.gex_value,
-    l.spot_price,
-    l.recorded_at,
-    COALESCE(s.avg_gex, 0)::numeric as avg_gex,
-    COALESCE(s.stddev_gex, 0)::numeric as stddev_gex,
-    (CASE 
-        WHEN COALESCE(s.stddev_gex, 0) = 0 THEN 0 
-        ELSE (l.gex_value - s.avg_gex) / s.stddev_gex 
-    END)::numeric as z_score
+    l.status,
+    l.error,
+    l.provider,
+    l.started_at AS last_attempt_at,
+    COALESCE(f.failure_count, 0)::bigint AS failure_count
 FROM latest l
-JOIN stats s ON l.symbol = s.symbol
-ORDER BY ABS((CASE 
-        WHEN COALESCE(s.stddev_gex, 0) = 0 THEN 0 
-        ELSE (l.gex_value - s.avg_gex) / s.stddev_gex 
-    END)) DESC
+LEFT JOIN recent_failures f ON f.symbol = l.symbol
+WHERE l.status NOT IN ('success', 'canceled')
+ORDER BY failure_count DESC, l.symbol
 `
 
-type GetGEXAnomaliesRow struct {
-	Symbol     string
-	GexValue   pgtype.Numeric
-	SpotPrice  pgtype.Text
-	RecordedAt time.Time
-	AvgGex     pgtype.Numeric
-	StddevGex  pgtype.Numeric
-	ZScore     pgtype.Numeric
+type GetFailingCollectorSymbolsRow struct {
+	Symbol        string
+	Status        string
+	Error         pgtype.Text
+	Provider      pgtype.Text
+	LastAttemptAt time.Time
+	FailureCount  int64
 }
 
-func (q *Queries) GetGEXAnomalies(ctx context.Context) ([]GetGEXAnomaliesRow, error) {
-	rows, err := q.db.Query(ctx, getGEXAnomalies)
+func (q *Queries) GetFailingCollectorSymbols(ctx context.Context, startedAt time.Time) ([]GetFailingCollectorSymbolsRow, error) {
+	rows, err := q.db.Query(ctx, getFailingCollectorSymbols, startedAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetGEXAnomaliesRow
+	var items []GetFailingCollectorSymbolsRow
 	for rows.Next() {
-		var i GetGEXAnomaliesRow
+		var i GetFailingCollectorSymbolsRow
 		if err := rows.Scan(
 			&i.Symbol,
-			&i.GexValue,
-			&i.SpotPrice,
-			&i.RecordedAt,
-			&i.AvgGex,
-			&i.StddevGex,
-			&i.ZScore,
+			&i.Status,
+			&i.Error,
+			&i.Provider,
+			&i.LastAttemptAt,
+			&i.FailureCount,
 		); err != nil {
 			return nil, err
 		}
@@ -148,6 +242,7 @@ WITH latest AS (
         symbol,
         gex_value as current_gex,
         spot_price as current_price,
+        flip_level,
         expiry_date,
         recorded_at as current_time
     FROM gex_history
@@ -167,6 +262,7 @@ SELECT
     l.symbol,
     l.current_gex,
     l.current_price,
+    l.flip_level,
     l.expiry_date,
     l.current_time,
     COALESCE(p.previous_gex, 0) as previous_gex,
@@ -191,6 +287,7 @@ type GetGEXChangeForSymbolsRow struct {
 	Symbol       string
 	CurrentGex   pgtype.Numeric
 	CurrentPrice pgtype.Text
+	FlipLevel    pgtype.Numeric
 	ExpiryDate   pgtype.Date
 	CurrentTime  time.Time
 	PreviousGex  pgtype.Numeric
@@ -212,6 +309,7 @@ func (q *Queries) GetGEXChangeForSymbols(ctx context.Context, arg GetGEXChangeFo
 			&i.Symbol,
 			&i.CurrentGex,
 			&i.CurrentPrice,
+			&i.FlipLevel,
 			&i.ExpiryDate,
 			&i.CurrentTime,
 			&i.PreviousGex,
@@ -229,8 +327,40 @@ func (q *Queries) GetGEXChangeForSymbols(ctx context.Context, arg GetGEXChangeFo
 	return items, nil
 }
 
+const getGEXFreshness = `-- name: GetGEXFreshness :many
+SELECT symbol, MAX(recorded_at)::timestamptz AS last_recorded_at
+FROM gex_history
+GROUP BY symbol
+ORDER BY last_recorded_at ASC
+`
+
+type GetGEXFreshnessRow struct {
+	Symbol         string
+	LastRecordedAt time.Time
+}
+
+func (q *Queries) GetGEXFreshness(ctx context.Context) ([]GetGEXFreshnessRow, error) {
+	rows, err := q.db.Query(ctx, getGEXFreshness)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGEXFreshnessRow
+	for rows.Next() {
+		var i GetGEXFreshnessRow
+		if err := rows.Scan(&i.Symbol, &i.LastRecordedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getGexHistoryBySymbolAndExpiry = `-- name: GetGexHistoryBySymbolAndExpiry :many
-SELECT id, symbol, expiry_date, expiry_type, option_chain, gex_value, recorded_at, spot_price FROM gex_history
+SELECT id, symbol, expiry_date, expiry_type, option_chain, gex_value, recorded_at, spot_price, flip_level, call_wall, put_wall FROM gex_history
 WHERE symbol = $1 AND expiry_date = $2
 ORDER BY recorded_at DESC
     LIMIT $3
@@ -260,6 +390,9 @@ func (q *Queries) GetGexHistoryBySymbolAndExpiry(ctx context.Context, arg GetGex
 			&i.GexValue,
 			&i.RecordedAt,
 			&i.SpotPrice,
+			&i.FlipLevel,
+			&i.CallWall,
+			&i.PutWall,
 		); err != nil {
 			return nil, err
 		}
@@ -271,58 +404,13 @@ func (q *Queries) GetGexHistoryBySymbolAndExpiry(ctx context.Context, arg GetGex
 	return items, nil
 }
 
-const getHistoricalGEXAnomalies = `-- name: GetHistoricalGEXAnomalies :many
-WITH stats AS (
-    SELECT
-        AVG(gex_value) as avg_gex,
-        STDDEV(gex_value) as stddev_gex
-    FROM gex_history
-    WHERE symbol = $1 AND recorded_at >= NOW() - INTERVAL '30 days'
-)
-SELECT
-    recorded_at,
-    gex_value,
-    (CASE 
-        WHEN COALESCE(s.stddev_gex, 0) = 0 THEN 0 
-        ELSE (gex_value - s.avg_gex) / s.stddev_gex 
-    END)::numeric as z_score
-FROM gex_history, stats s
-WHERE gex_history.symbol = $1 AND recorded_at >= NOW() - INTERVAL '7 days'
-ORDER BY recorded_at ASC
-`
-
-type GetHistoricalGEXAnomaliesRow struct {
-	RecordedAt time.Time
-	GexValue   pgtype.Numeric
-	ZScore     pgtype.Numeric
-}
-
-func (q *Queries) GetHistoricalGEXAnomalies(ctx context.Context, symbol string) ([]GetHistoricalGEXAnomaliesRow, error) {
-	rows, err := q.db.Query(ctx, getHistoricalGEXAnomalies, symbol)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetHistoricalGEXAnomaliesRow
-	for rows.Next() {
-		var i GetHistoricalGEXAnomaliesRow
-		if err := rows.Scan(&i.RecordedAt, &i.GexValue, &i.ZScore); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const getLatestGEXChanges = `-- name: GetLatestGEXChanges :many
 WITH ranked_history AS (
     SELECT
         symbol,
         gex_value,
         spot_price,
+        flip_level,
         expiry_date,
         recorded_at,
         ROW_NUMBER() OVER (PARTITION BY symbol ORDER BY recorded_at DESC) as rn
@@ -333,6 +421,7 @@ latest AS (
         symbol,
         gex_value as current_gex,
         spot_price as current_price,
+        flip_level,
         expiry_date,
         recorded_at as current_time
     FROM ranked_history
@@ -350,6 +439,7 @@ SELECT
     l.symbol,
     l.current_gex,
     l.current_price,
+    l.flip_level,
     l.expiry_date,
     l.current_time,
     COALESCE(p.previous_gex, 0) as previous_gex,
@@ -369,6 +459,7 @@ type GetLatestGEXChangesRow struct {
 	Symbol       string
 	CurrentGex   pgtype.Numeric
 	CurrentPrice pgtype.Text
+	FlipLevel    pgtype.Numeric
 	ExpiryDate   pgtype.Date
 	CurrentTime  time.Time
 	PreviousGex  pgtype.Numeric
@@ -390,6 +481,7 @@ func (q *Queries) GetLatestGEXChanges(ctx context.Context) ([]GetLatestGEXChange
 			&i.Symbol,
 			&i.CurrentGex,
 			&i.CurrentPrice,
+			&i.FlipLevel,
 			&i.ExpiryDate,
 			&i.CurrentTime,
 			&i.PreviousGex,
@@ -459,66 +551,17 @@ func (q *Queries) GetLatestGEXHistoryBySymbol(ctx context.Context, arg GetLatest
 	return items, nil
 }
 
-const getLatestZScores = `-- name: GetLatestZScores :many
-WITH stats AS (
-    SELECT
-        symbol,
-        AVG(gex_value) as avg_gex,
-        STDDEV(gex_value) as stddev_gex
-    FROM gex_history
-    WHERE recorded_at >= NOW() - INTERVAL '30 days'
-    GROUP BY symbol
-),
-latest AS (
-    SELECT DISTINCT ON (symbol)
-        symbol,
-        gex_value,
-        recorded_at
-    FROM gex_history
-    ORDER BY symbol, recorded_at DESC
-)
-SELECT
-    l.symbol,
-    l.gex_value,
-    l.recorded_at,
-    (CASE 
-        WHEN COALESCE(s.stddev_gex, 0) = 0 THEN 0 
-        ELSE (l.gex_value - s.avg_gex) / s.stddev_gex 
-    END)::numeric as z_score
-FROM latest l
-JOIN stats s ON l.symbol = s.symbol
+const getMaxUniversePosition = `-- name: GetMaxUniversePosition :one
+SELECT COALESCE(MAX(position), -1)::int AS max_position
+FROM universe_members
+WHERE universe_id = $1
 `
 
-type GetLatestZScoresRow struct {
-	Symbol     string
-	GexValue   pgtype.Numeric
-	RecordedAt time.Time
-	ZScore     pgtype.Numeric
-}
-
-func (q *Queries) GetLatestZScores(ctx context.Context) ([]GetLatestZScoresRow, error) {
-	rows, err := q.db.Query(ctx, getLatestZScores)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetLatestZScoresRow
-	for rows.Next() {
-		var i GetLatestZScoresRow
-		if err := rows.Scan(
-			&i.Symbol,
-			&i.GexValue,
-			&i.RecordedAt,
-			&i.ZScore,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) GetMaxUniversePosition(ctx context.Context, universeID uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, getMaxUniversePosition, universeID)
+	var max_position int32
+	err := row.Scan(&max_position)
+	return max_position, err
 }
 
 const getOptionChainBySymbolAndExpiry = `-- name: GetOptionChainBySymbolAndExpiry :one
@@ -604,8 +647,31 @@ func (q *Queries) GetOptionExpiryDatesBySymbol(ctx context.Context, symbol strin
 	return i, err
 }
 
+const getScannerView = `-- name: GetScannerView :one
+SELECT id, owner, name, query, created_at, updated_at FROM scanner_views WHERE owner = $1 AND name = $2
+`
+
+type GetScannerViewParams struct {
+	Owner string
+	Name  string
+}
+
+func (q *Queries) GetScannerView(ctx context.Context, arg GetScannerViewParams) (ScannerView, error) {
+	row := q.db.QueryRow(ctx, getScannerView, arg.Owner, arg.Name)
+	var i ScannerView
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.Query,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getThisWeekReleases = `-- name: GetThisWeekReleases :many
-SELECT id, release_id, release_name, release_date, impact, created_at, updated_at FROM economic_releases
+SELECT id, release_id, release_name, release_date, impact, created_at, updated_at, release_time, series_id, units, period, actual, prior, consensus, consensus_source, values_updated_at FROM economic_releases
 WHERE release_date >= CURRENT_DATE - 7 AND release_date <= CURRENT_DATE + 7
 ORDER BY release_date DESC, impact DESC
 `
@@ -627,6 +693,15 @@ func (q *Queries) GetThisWeekReleases(ctx context.Context) ([]EconomicRelease, e
 			&i.Impact,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ReleaseTime,
+			&i.SeriesID,
+			&i.Units,
+			&i.Period,
+			&i.Actual,
+			&i.Prior,
+			&i.Consensus,
+			&i.ConsensusSource,
+			&i.ValuesUpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -638,8 +713,27 @@ func (q *Queries) GetThisWeekReleases(ctx context.Context) ([]EconomicRelease, e
 	return items, nil
 }
 
+const getUniverseBySlug = `-- name: GetUniverseBySlug :one
+SELECT id, slug, name, kind, owner, created_at, updated_at FROM universes WHERE slug = $1
+`
+
+func (q *Queries) GetUniverseBySlug(ctx context.Context, slug string) (Universe, error) {
+	row := q.db.QueryRow(ctx, getUniverseBySlug, slug)
+	var i Universe
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.Kind,
+		&i.Owner,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getUpcomingReleases = `-- name: GetUpcomingReleases :many
-SELECT id, release_id, release_name, release_date, impact, created_at, updated_at FROM economic_releases
+SELECT id, release_id, release_name, release_date, impact, created_at, updated_at, release_time, series_id, units, period, actual, prior, consensus, consensus_source, values_updated_at FROM economic_releases
 WHERE release_date >= $1 AND release_date <= $2
 ORDER BY release_date ASC, impact DESC
 `
@@ -666,6 +760,15 @@ func (q *Queries) GetUpcomingReleases(ctx context.Context, arg GetUpcomingReleas
 			&i.Impact,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ReleaseTime,
+			&i.SeriesID,
+			&i.Units,
+			&i.Period,
+			&i.Actual,
+			&i.Prior,
+			&i.Consensus,
+			&i.ConsensusSource,
+			&i.ValuesUpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -708,11 +811,56 @@ func (q *Queries) Insert(ctx context.Context, arg InsertParams) (Guest, error) {
 	return i, err
 }
 
+const insertCollectorRunSymbol = `-- name: InsertCollectorRunSymbol :one
+INSERT INTO collector_run_symbols (
+    run_id, symbol, tier, status, error, provider, started_at, duration_ms
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, run_id, symbol, tier, status, error, provider, started_at, duration_ms
+`
+
+type InsertCollectorRunSymbolParams struct {
+	RunID      uuid.UUID
+	Symbol     string
+	Tier       string
+	Status     string
+	Error      pgtype.Text
+	Provider   pgtype.Text
+	StartedAt  time.Time
+	DurationMs int32
+}
+
+func (q *Queries) InsertCollectorRunSymbol(ctx context.Context, arg InsertCollectorRunSymbolParams) (CollectorRunSymbol, error) {
+	row := q.db.QueryRow(ctx, insertCollectorRunSymbol,
+		arg.RunID,
+		arg.Symbol,
+		arg.Tier,
+		arg.Status,
+		arg.Error,
+		arg.Provider,
+		arg.StartedAt,
+		arg.DurationMs,
+	)
+	var i CollectorRunSymbol
+	err := row.Scan(
+		&i.ID,
+		&i.RunID,
+		&i.Symbol,
+		&i.Tier,
+		&i.Status,
+		&i.Error,
+		&i.Provider,
+		&i.StartedAt,
+		&i.DurationMs,
+	)
+	return i, err
+}
+
 const insertGEXHistory = `-- name: InsertGEXHistory :one
 INSERT INTO gex_history (
-    id, symbol, expiry_date, expiry_type, option_chain, gex_value, recorded_at, spot_price
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-    RETURNING id, symbol, expiry_date, expiry_type, option_chain, gex_value, recorded_at, spot_price
+    id, symbol, expiry_date, expiry_type, option_chain, gex_value, recorded_at, spot_price, flip_level,
+    call_wall, put_wall
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    RETURNING id, symbol, expiry_date, expiry_type, option_chain, gex_value, recorded_at, spot_price, flip_level, call_wall, put_wall
 `
 
 type InsertGEXHistoryParams struct {
@@ -724,6 +872,9 @@ type InsertGEXHistoryParams struct {
 	GexValue    pgtype.Numeric
 	RecordedAt  time.Time
 	SpotPrice   pgtype.Text
+	FlipLevel   pgtype.Numeric
+	CallWall    pgtype.Numeric
+	PutWall     pgtype.Numeric
 }
 
 func (q *Queries) InsertGEXHistory(ctx context.Context, arg InsertGEXHistoryParams) (GexHistory, error) {
@@ -736,6 +887,9 @@ func (q *Queries) InsertGEXHistory(ctx context.Context, arg InsertGEXHistoryPara
 		arg.GexValue,
 		arg.RecordedAt,
 		arg.SpotPrice,
+		arg.FlipLevel,
+		arg.CallWall,
+		arg.PutWall,
 	)
 	var i GexHistory
 	err := row.Scan(
@@ -747,68 +901,1276 @@ func (q *Queries) InsertGEXHistory(ctx context.Context, arg InsertGEXHistoryPara
 		&i.GexValue,
 		&i.RecordedAt,
 		&i.SpotPrice,
+		&i.FlipLevel,
+		&i.CallWall,
+		&i.PutWall,
 	)
 	return i, err
 }
 
-const upsertEconomicRelease = `-- name: UpsertEconomicRelease :one
-INSERT INTO economic_releases (release_id, release_name, release_date, impact)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (release_id, release_date)
-DO UPDATE SET
-    release_name = EXCLUDED.release_name,
-    impact = EXCLUDED.impact,
-    updated_at = now()
-RETURNING id, release_id, release_name, release_date, impact, created_at, updated_at
+const listBTCETFFlows = `-- name: ListBTCETFFlows :many
+SELECT ticker, date, flow_usd, holdings_btc FROM btc_etf_flows
+ORDER BY date, ticker
 `
 
-type UpsertEconomicReleaseParams struct {
-	ReleaseID   int32
-	ReleaseName string
-	ReleaseDate pgtype.Date
-	Impact      string
+type ListBTCETFFlowsRow struct {
+	Ticker      string
+	Date        pgtype.Date
+	FlowUsd     float64
+	HoldingsBtc pgtype.Float8
 }
 
-// Economic Releases (FRED API)
-func (q *Queries) UpsertEconomicRelease(ctx context.Context, arg UpsertEconomicReleaseParams) (EconomicRelease, error) {
-	row := q.db.QueryRow(ctx, upsertEconomicRelease,
-		arg.ReleaseID,
-		arg.ReleaseName,
-		arg.ReleaseDate,
-		arg.Impact,
-	)
-	var i EconomicRelease
-	err := row.Scan(
-		&i.ID,
-		&i.ReleaseID,
-		&i.ReleaseName,
-		&i.ReleaseDate,
-		&i.Impact,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+// Every flow, oldest first; cumulative flows need them all.
+func (q *Queries) ListBTCETFFlows(ctx context.Context) ([]ListBTCETFFlowsRow, error) {
+	rows, err := q.db.Query(ctx, listBTCETFFlows)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBTCETFFlowsRow
+	for rows.Next() {
+		var i ListBTCETFFlowsRow
+		if err := rows.Scan(
+			&i.Ticker,
+			&i.Date,
+			&i.FlowUsd,
+			&i.HoldingsBtc,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const upsertOptionChain = `-- name: UpsertOptionChain :one
-INSERT INTO option_chain (
-    symbol,
-    spot_price,
-    expiry_date,
-    expiry_type,
-    option_chain
-) VALUES ($1, $2, $3, $4, $5)
-ON CONFLICT (symbol, expiry_date)
-DO UPDATE SET
-    spot_price = EXCLUDED.spot_price,
-    option_chain = EXCLUDED.option_chain,
-    updated_at = now()
-RETURNING id, symbol, spot_price, expiry_date, expiry_type, option_chain, created_at, updated_at
+const listBTCETFFunds = `-- name: ListBTCETFFunds :many
+SELECT ticker, name, issuer, position, created_at FROM btc_etf_funds
+ORDER BY position, ticker
 `
 
-type UpsertOptionChainParams struct {
-	Symbol      string
-	SpotPrice   string
+func (q *Queries) ListBTCETFFunds(ctx context.Context) ([]BtcEtfFund, error) {
+	rows, err := q.db.Query(ctx, listBTCETFFunds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BtcEtfFund
+	for rows.Next() {
+		var i BtcEtfFund
+		if err := rows.Scan(
+			&i.Ticker,
+			&i.Name,
+			&i.Issuer,
+			&i.Position,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCollectorRunSymbols = `-- name: ListCollectorRunSymbols :many
+SELECT id, run_id, symbol, tier, status, error, provider, started_at, duration_ms FROM collector_run_symbols
+WHERE run_id = $1
+ORDER BY started_at ASC
+`
+
+func (q *Queries) ListCollectorRunSymbols(ctx context.Context, runID uuid.UUID) ([]CollectorRunSymbol, error) {
+	rows, err := q.db.Query(ctx, listCollectorRunSymbols, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CollectorRunSymbol
+	for rows.Next() {
+		var i CollectorRunSymbol
+		if err := rows.Scan(
+			&i.ID,
+			&i.RunID,
+			&i.Symbol,
+			&i.Tier,
+			&i.Status,
+			&i.Error,
+			&i.Provider,
+			&i.StartedAt,
+			&i.DurationMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCollectorRuns = `-- name: ListCollectorRuns :many
+SELECT id, trigger, started_at, finished_at, symbols_attempted, successes, failures FROM collector_runs
+ORDER BY started_at DESC
+LIMIT $1
+`
+
+func (q *Queries) ListCollectorRuns(ctx context.Context, limit int32) ([]CollectorRun, error) {
+	rows, err := q.db.Query(ctx, listCollectorRuns, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CollectorRun
+	for rows.Next() {
+		var i CollectorRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.Trigger,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.SymbolsAttempted,
+			&i.Successes,
+			&i.Failures,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDailyGEXCloses = `-- name: ListDailyGEXCloses :many
+SELECT DISTINCT ON ((recorded_at AT TIME ZONE 'America/New_York')::date)
+    recorded_at, gex_value, spot_price
+FROM gex_history
+WHERE symbol = $1 AND recorded_at >= $2
+ORDER BY (recorded_at AT TIME ZONE 'America/New_York')::date, recorded_at DESC
+`
+
+type ListDailyGEXClosesParams struct {
+	Symbol   string
+	FromTime time.Time
+}
+
+type ListDailyGEXClosesRow struct {
+	RecordedAt time.Time
+	GexValue   pgtype.Numeric
+	SpotPrice  pgtype.Text
+}
+
+// The last snapshot of a symbol on each New York trading day since
+// from_time, oldest first.
+func (q *Queries) ListDailyGEXCloses(ctx context.Context, arg ListDailyGEXClosesParams) ([]ListDailyGEXClosesRow, error) {
+	rows, err := q.db.Query(ctx, listDailyGEXCloses, arg.Symbol, arg.FromTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDailyGEXClosesRow
+	for rows.Next() {
+		var i ListDailyGEXClosesRow
+		if err := rows.Scan(&i.RecordedAt, &i.GexValue, &i.SpotPrice); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDailyGEXSnapshots = `-- name: ListDailyGEXSnapshots :many
+WITH daily AS (
+    SELECT DISTINCT ON (symbol, (recorded_at AT TIME ZONE 'America/New_York')::date)
+        id
+    FROM gex_history
+    WHERE gex_history.recorded_at >= $2
+    ORDER BY symbol, (recorded_at AT TIME ZONE 'America/New_York')::date, recorded_at DESC
+)
+SELECT
+    h.symbol,
+    h.recorded_at,
+    h.gex_value,
+    h.spot_price,
+    (CASE WHEN $1::bool THEN (
+        SELECT COALESCE(SUM((o->>'open_interest')::numeric), 0)
+        FROM jsonb_array_elements(CASE
+            WHEN jsonb_typeof(h.option_chain->'options'->'option') = 'array' THEN h.option_chain->'options'->'option'
+            ELSE '[]'::jsonb
+        END) o
+    ) ELSE 0 END)::numeric AS open_interest
+FROM daily d
+JOIN gex_history h ON h.id = d.id
+ORDER BY h.symbol, h.recorded_at
+`
+
+type ListDailyGEXSnapshotsParams struct {
+	WithOpenInterest bool
+	FromTime         time.Time
+}
+
+type ListDailyGEXSnapshotsRow struct {
+	Symbol       string
+	RecordedAt   time.Time
+	GexValue     pgtype.Numeric
+	SpotPrice    pgtype.Text
+	OpenInterest pgtype.Numeric
+}
+
+// Z-score inputs (scores are computed in internal/zscore)
+// The last snapshot of each symbol on each New York trading day since
+// from_time, oldest first. Total open interest is only summed from the stored
+// chain when with_open_interest is set.
+func (q *Queries) ListDailyGEXSnapshots(ctx context.Context, arg ListDailyGEXSnapshotsParams) ([]ListDailyGEXSnapshotsRow, error) {
+	rows, err := q.db.Query(ctx, listDailyGEXSnapshots, arg.WithOpenInterest, arg.FromTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDailyGEXSnapshotsRow
+	for rows.Next() {
+		var i ListDailyGEXSnapshotsRow
+		if err := rows.Scan(
+			&i.Symbol,
+			&i.RecordedAt,
+			&i.GexValue,
+			&i.SpotPrice,
+			&i.OpenInterest,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEarningsEvents = `-- name: ListEarningsEvents :many
+SELECT symbol, report_date, session, fiscal_period, eps_estimate, source, updated_at FROM earnings_events
+WHERE report_date >= $1 AND report_date <= $2
+ORDER BY report_date ASC, symbol ASC
+`
+
+type ListEarningsEventsParams struct {
+	ReportDate   pgtype.Date
+	ReportDate_2 pgtype.Date
+}
+
+func (q *Queries) ListEarningsEvents(ctx context.Context, arg ListEarningsEventsParams) ([]EarningsEvent, error) {
+	rows, err := q.db.Query(ctx, listEarningsEvents, arg.ReportDate, arg.ReportDate_2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EarningsEvent
+	for rows.Next() {
+		var i EarningsEvent
+		if err := rows.Scan(
+			&i.Symbol,
+			&i.ReportDate,
+			&i.Session,
+			&i.FiscalPeriod,
+			&i.EpsEstimate,
+			&i.Source,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGEXHistoryForBackfill = `-- name: ListGEXHistoryForBackfill :many
+SELECT id, symbol, recorded_at, option_chain, spot_price, gex_value, flip_level, call_wall, put_wall
+FROM gex_history
+WHERE (cardinality($1::text[]) = 0 OR symbol = ANY($1::text[]))
+  AND recorded_at >= $2 AND recorded_at < $3
+  AND (recorded_at, id) > ($4::timestamptz, $5::uuid)
+ORDER BY recorded_at, id
+LIMIT $6
+`
+
+type ListGEXHistoryForBackfillParams struct {
+	Symbols         []string
+	FromTime        time.Time
+	ToTime          time.Time
+	AfterRecordedAt time.Time
+	AfterID         uuid.UUID
+	BatchSize       int32
+}
+
+type ListGEXHistoryForBackfillRow struct {
+	ID          uuid.UUID
+	Symbol      string
+	RecordedAt  time.Time
+	OptionChain []byte
+	SpotPrice   pgtype.Text
+	GexValue    pgtype.Numeric
+	FlipLevel   pgtype.Numeric
+	CallWall    pgtype.Numeric
+	PutWall     pgtype.Numeric
+}
+
+func (q *Queries) ListGEXHistoryForBackfill(ctx context.Context, arg ListGEXHistoryForBackfillParams) ([]ListGEXHistoryForBackfillRow, error) {
+	rows, err := q.db.Query(ctx, listGEXHistoryForBackfill,
+		arg.Symbols,
+		arg.FromTime,
+		arg.ToTime,
+		arg.AfterRecordedAt,
+		arg.AfterID,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGEXHistoryForBackfillRow
+	for rows.Next() {
+		var i ListGEXHistoryForBackfillRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Symbol,
+			&i.RecordedAt,
+			&i.OptionChain,
+			&i.SpotPrice,
+			&i.GexValue,
+			&i.FlipLevel,
+			&i.CallWall,
+			&i.PutWall,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGEXHistoryForSymbols = `-- name: ListGEXHistoryForSymbols :many
+SELECT symbol, recorded_at, gex_value, spot_price, flip_level
+FROM gex_history
+WHERE symbol = ANY($1::text[]) AND recorded_at >= $2
+ORDER BY symbol, recorded_at
+`
+
+type ListGEXHistoryForSymbolsParams struct {
+	Symbols  []string
+	FromTime time.Time
+}
+
+type ListGEXHistoryForSymbolsRow struct {
+	Symbol     string
+	RecordedAt time.Time
+	GexValue   pgtype.Numeric
+	SpotPrice  pgtype.Text
+	FlipLevel  pgtype.Numeric
+}
+
+// Net GEX, spot and flip level of several symbols since from_time, for
+// side-by-side comparison.
+func (q *Queries) ListGEXHistoryForSymbols(ctx context.Context, arg ListGEXHistoryForSymbolsParams) ([]ListGEXHistoryForSymbolsRow, error) {
+	rows, err := q.db.Query(ctx, listGEXHistoryForSymbols, arg.Symbols, arg.FromTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGEXHistoryForSymbolsRow
+	for rows.Next() {
+		var i ListGEXHistoryForSymbolsRow
+		if err := rows.Scan(
+			&i.Symbol,
+			&i.RecordedAt,
+			&i.GexValue,
+			&i.SpotPrice,
+			&i.FlipLevel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGEXSnapshots = `-- name: ListGEXSnapshots :many
+SELECT symbol, spot_price, strikes, warning, computed_at
+FROM gex_snapshots
+WHERE symbol = ANY($1::text[])
+`
+
+func (q *Queries) ListGEXSnapshots(ctx context.Context, symbols []string) ([]GexSnapshot, error) {
+	rows, err := q.db.Query(ctx, listGEXSnapshots, symbols)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GexSnapshot
+	for rows.Next() {
+		var i GexSnapshot
+		if err := rows.Scan(
+			&i.Symbol,
+			&i.SpotPrice,
+			&i.Strikes,
+			&i.Warning,
+			&i.ComputedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGEXSnapshotsForSymbol = `-- name: ListGEXSnapshotsForSymbol :many
+SELECT
+    recorded_at,
+    gex_value,
+    spot_price,
+    (CASE WHEN $1::bool THEN (
+        SELECT COALESCE(SUM((o->>'open_interest')::numeric), 0)
+        FROM jsonb_array_elements(CASE
+            WHEN jsonb_typeof(option_chain->'options'->'option') = 'array' THEN option_chain->'options'->'option'
+            ELSE '[]'::jsonb
+        END) o
+    ) ELSE 0 END)::numeric AS open_interest
+FROM gex_history
+WHERE symbol = $2 AND recorded_at >= $3
+ORDER BY recorded_at
+`
+
+type ListGEXSnapshotsForSymbolParams struct {
+	WithOpenInterest bool
+	Symbol           string
+	FromTime         time.Time
+}
+
+type ListGEXSnapshotsForSymbolRow struct {
+	RecordedAt   time.Time
+	GexValue     pgtype.Numeric
+	SpotPrice    pgtype.Text
+	OpenInterest pgtype.Numeric
+}
+
+// Every snapshot of a symbol since from_time, oldest first, with the same
+// optional open interest sum as ListDailyGEXSnapshots.
+func (q *Queries) ListGEXSnapshotsForSymbol(ctx context.Context, arg ListGEXSnapshotsForSymbolParams) ([]ListGEXSnapshotsForSymbolRow, error) {
+	rows, err := q.db.Query(ctx, listGEXSnapshotsForSymbol, arg.WithOpenInterest, arg.Symbol, arg.FromTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGEXSnapshotsForSymbolRow
+	for rows.Next() {
+		var i ListGEXSnapshotsForSymbolRow
+		if err := rows.Scan(
+			&i.RecordedAt,
+			&i.GexValue,
+			&i.SpotPrice,
+			&i.OpenInterest,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGEXTimeline = `-- name: ListGEXTimeline :many
+SELECT recorded_at, gex_value, spot_price, flip_level, call_wall, put_wall
+FROM gex_history
+WHERE symbol = $1 AND recorded_at >= $2
+ORDER BY recorded_at
+`
+
+type ListGEXTimelineParams struct {
+	Symbol   string
+	FromTime time.Time
+}
+
+type ListGEXTimelineRow struct {
+	RecordedAt time.Time
+	GexValue   pgtype.Numeric
+	SpotPrice  pgtype.Text
+	FlipLevel  pgtype.Numeric
+	CallWall   pgtype.Numeric
+	PutWall    pgtype.Numeric
+}
+
+// Net GEX, spot and strike levels of a symbol since from_time, oldest first.
+func (q *Queries) ListGEXTimeline(ctx context.Context, arg ListGEXTimelineParams) ([]ListGEXTimelineRow, error) {
+	rows, err := q.db.Query(ctx, listGEXTimeline, arg.Symbol, arg.FromTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGEXTimelineRow
+	for rows.Next() {
+		var i ListGEXTimelineRow
+		if err := rows.Scan(
+			&i.RecordedAt,
+			&i.GexValue,
+			&i.SpotPrice,
+			&i.FlipLevel,
+			&i.CallWall,
+			&i.PutWall,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMacroObservationsSince = `-- name: ListMacroObservationsSince :many
+SELECT series_id, date, value FROM macro_observations
+WHERE date >= $1
+ORDER BY series_id, date
+`
+
+type ListMacroObservationsSinceRow struct {
+	SeriesID string
+	Date     pgtype.Date
+	Value    float64
+}
+
+// Observations of every series from a date on, oldest first.
+func (q *Queries) ListMacroObservationsSince(ctx context.Context, date pgtype.Date) ([]ListMacroObservationsSinceRow, error) {
+	rows, err := q.db.Query(ctx, listMacroObservationsSince, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMacroObservationsSinceRow
+	for rows.Next() {
+		var i ListMacroObservationsSinceRow
+		if err := rows.Scan(&i.SeriesID, &i.Date, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMacroSeries = `-- name: ListMacroSeries :many
+SELECT series_id, name, category, unit, position, created_at, updated_at FROM macro_series
+ORDER BY position, series_id
+`
+
+func (q *Queries) ListMacroSeries(ctx context.Context) ([]MacroSeries, error) {
+	rows, err := q.db.Query(ctx, listMacroSeries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MacroSeries
+	for rows.Next() {
+		var i MacroSeries
+		if err := rows.Scan(
+			&i.SeriesID,
+			&i.Name,
+			&i.Category,
+			&i.Unit,
+			&i.Position,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMacroSeriesObservations = `-- name: ListMacroSeriesObservations :many
+SELECT date, value FROM macro_observations
+WHERE series_id = $1 AND date >= $2
+ORDER BY date
+`
+
+type ListMacroSeriesObservationsParams struct {
+	SeriesID string
+	Date     pgtype.Date
+}
+
+type ListMacroSeriesObservationsRow struct {
+	Date  pgtype.Date
+	Value float64
+}
+
+func (q *Queries) ListMacroSeriesObservations(ctx context.Context, arg ListMacroSeriesObservationsParams) ([]ListMacroSeriesObservationsRow, error) {
+	rows, err := q.db.Query(ctx, listMacroSeriesObservations, arg.SeriesID, arg.Date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMacroSeriesObservationsRow
+	for rows.Next() {
+		var i ListMacroSeriesObservationsRow
+		if err := rows.Scan(&i.Date, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOptionExpiryDatesForSymbols = `-- name: ListOptionExpiryDatesForSymbols :many
+SELECT id, symbol, expiry_dates, created_at, updated_at FROM option_expiry_dates
+WHERE symbol = ANY($1::text[])
+ORDER BY symbol
+`
+
+func (q *Queries) ListOptionExpiryDatesForSymbols(ctx context.Context, symbols []string) ([]OptionExpiryDate, error) {
+	rows, err := q.db.Query(ctx, listOptionExpiryDatesForSymbols, symbols)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OptionExpiryDate
+	for rows.Next() {
+		var i OptionExpiryDate
+		if err := rows.Scan(
+			&i.ID,
+			&i.Symbol,
+			&i.ExpiryDates,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReleasesAwaitingValues = `-- name: ListReleasesAwaitingValues :many
+SELECT id, release_id, release_name, release_date, impact, created_at, updated_at, release_time, series_id, units, period, actual, prior, consensus, consensus_source, values_updated_at FROM economic_releases
+WHERE series_id IS NOT NULL
+  AND release_date >= CURRENT_DATE - 7 AND release_date <= CURRENT_DATE + 7
+  AND (prior IS NULL OR (actual IS NULL AND release_date <= CURRENT_DATE))
+ORDER BY release_date ASC
+`
+
+// Releases of the past and next week with a headline series that still lack
+// their prior, or their actual once the release date has come.
+func (q *Queries) ListReleasesAwaitingValues(ctx context.Context) ([]EconomicRelease, error) {
+	rows, err := q.db.Query(ctx, listReleasesAwaitingValues)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EconomicRelease
+	for rows.Next() {
+		var i EconomicRelease
+		if err := rows.Scan(
+			&i.ID,
+			&i.ReleaseID,
+			&i.ReleaseName,
+			&i.ReleaseDate,
+			&i.Impact,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ReleaseTime,
+			&i.SeriesID,
+			&i.Units,
+			&i.Period,
+			&i.Actual,
+			&i.Prior,
+			&i.Consensus,
+			&i.ConsensusSource,
+			&i.ValuesUpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReleasesBetween = `-- name: ListReleasesBetween :many
+SELECT id, release_id, release_name, release_date, impact, created_at, updated_at, release_time, series_id, units, period, actual, prior, consensus, consensus_source, values_updated_at FROM economic_releases
+WHERE impact = $1
+  AND release_date >= $2 AND release_date <= $3
+ORDER BY release_date, release_id
+`
+
+type ListReleasesBetweenParams struct {
+	Impact   string
+	FromDate pgtype.Date
+	ToDate   pgtype.Date
+}
+
+// Releases of an impact dated from from_date to to_date, oldest first.
+func (q *Queries) ListReleasesBetween(ctx context.Context, arg ListReleasesBetweenParams) ([]EconomicRelease, error) {
+	rows, err := q.db.Query(ctx, listReleasesBetween, arg.Impact, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EconomicRelease
+	for rows.Next() {
+		var i EconomicRelease
+		if err := rows.Scan(
+			&i.ID,
+			&i.ReleaseID,
+			&i.ReleaseName,
+			&i.ReleaseDate,
+			&i.Impact,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ReleaseTime,
+			&i.SeriesID,
+			&i.Units,
+			&i.Period,
+			&i.Actual,
+			&i.Prior,
+			&i.Consensus,
+			&i.ConsensusSource,
+			&i.ValuesUpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScannerViews = `-- name: ListScannerViews :many
+SELECT id, owner, name, query, created_at, updated_at FROM scanner_views WHERE owner = $1 ORDER BY name
+`
+
+func (q *Queries) ListScannerViews(ctx context.Context, owner string) ([]ScannerView, error) {
+	rows, err := q.db.Query(ctx, listScannerViews, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScannerView
+	for rows.Next() {
+		var i ScannerView
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Name,
+			&i.Query,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSymbolMetadata = `-- name: ListSymbolMetadata :many
+SELECT symbol, name, sector, market_cap, updated_at, industry FROM symbol_metadata ORDER BY symbol
+`
+
+func (q *Queries) ListSymbolMetadata(ctx context.Context) ([]SymbolMetadatum, error) {
+	rows, err := q.db.Query(ctx, listSymbolMetadata)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SymbolMetadatum
+	for rows.Next() {
+		var i SymbolMetadatum
+		if err := rows.Scan(
+			&i.Symbol,
+			&i.Name,
+			&i.Sector,
+			&i.MarketCap,
+			&i.UpdatedAt,
+			&i.Industry,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSymbolsInUniverses = `-- name: ListSymbolsInUniverses :many
+SELECT m.symbol
+FROM universe_members m
+JOIN universes u ON u.id = m.universe_id
+WHERE u.slug = ANY($1::text[])
+GROUP BY m.symbol
+ORDER BY MIN(m.position), m.symbol
+`
+
+func (q *Queries) ListSymbolsInUniverses(ctx context.Context, slugs []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listSymbolsInUniverses, slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, err
+		}
+		items = append(items, symbol)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrackedReleases = `-- name: ListTrackedReleases :many
+SELECT release_id, name, impact, release_time, category, series_id, units, related_series, created_at, updated_at FROM tracked_releases
+ORDER BY CASE impact WHEN 'High' THEN 0 WHEN 'Medium' THEN 1 ELSE 2 END, name
+`
+
+// Tracked FRED releases (managed at /admin/releases)
+func (q *Queries) ListTrackedReleases(ctx context.Context) ([]TrackedRelease, error) {
+	rows, err := q.db.Query(ctx, listTrackedReleases)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrackedRelease
+	for rows.Next() {
+		var i TrackedRelease
+		if err := rows.Scan(
+			&i.ReleaseID,
+			&i.Name,
+			&i.Impact,
+			&i.ReleaseTime,
+			&i.Category,
+			&i.SeriesID,
+			&i.Units,
+			&i.RelatedSeries,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUniverseSymbols = `-- name: ListUniverseSymbols :many
+SELECT m.symbol
+FROM universe_members m
+JOIN universes u ON u.id = m.universe_id
+WHERE u.slug = $1
+ORDER BY m.position, m.symbol
+`
+
+func (q *Queries) ListUniverseSymbols(ctx context.Context, slug string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listUniverseSymbols, slug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, err
+		}
+		items = append(items, symbol)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUniverses = `-- name: ListUniverses :many
+SELECT u.id, u.slug, u.name, u.kind, u.owner, u.updated_at, COUNT(m.symbol)::int AS member_count
+FROM universes u
+LEFT JOIN universe_members m ON m.universe_id = u.id
+GROUP BY u.id
+ORDER BY u.kind, u.name
+`
+
+type ListUniversesRow struct {
+	ID          uuid.UUID
+	Slug        string
+	Name        string
+	Kind        string
+	Owner       pgtype.Text
+	UpdatedAt   time.Time
+	MemberCount int32
+}
+
+func (q *Queries) ListUniverses(ctx context.Context) ([]ListUniversesRow, error) {
+	rows, err := q.db.Query(ctx, listUniverses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUniversesRow
+	for rows.Next() {
+		var i ListUniversesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Name,
+			&i.Kind,
+			&i.Owner,
+			&i.UpdatedAt,
+			&i.MemberCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setEconomicReleaseConsensus = `-- name: SetEconomicReleaseConsensus :execrows
+UPDATE economic_releases
+SET consensus = $3, consensus_source = $4, updated_at = now()
+WHERE release_id = $1 AND release_date = $2
+`
+
+type SetEconomicReleaseConsensusParams struct {
+	ReleaseID       int32
+	ReleaseDate     pgtype.Date
+	Consensus       pgtype.Float8
+	ConsensusSource pgtype.Text
+}
+
+func (q *Queries) SetEconomicReleaseConsensus(ctx context.Context, arg SetEconomicReleaseConsensusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setEconomicReleaseConsensus,
+		arg.ReleaseID,
+		arg.ReleaseDate,
+		arg.Consensus,
+		arg.ConsensusSource,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateCollectorRunCounts = `-- name: UpdateCollectorRunCounts :exec
+UPDATE collector_runs
+SET symbols_attempted = symbols_attempted + 1,
+    successes = successes + $1::integer,
+    failures = failures + $2::integer,
+    -- Workers finish in any order; the run ends with its latest attempt.
+    finished_at = GREATEST(COALESCE(finished_at, started_at), $3::timestamptz)
+WHERE id = $4
+`
+
+type UpdateCollectorRunCountsParams struct {
+	Successes  int32
+	Failures   int32
+	FinishedAt time.Time
+	ID         uuid.UUID
+}
+
+func (q *Queries) UpdateCollectorRunCounts(ctx context.Context, arg UpdateCollectorRunCountsParams) error {
+	_, err := q.db.Exec(ctx, updateCollectorRunCounts,
+		arg.Successes,
+		arg.Failures,
+		arg.FinishedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const updateEconomicReleaseValues = `-- name: UpdateEconomicReleaseValues :exec
+UPDATE economic_releases
+SET period = $2, actual = $3, prior = $4, values_updated_at = now()
+WHERE id = $1
+`
+
+type UpdateEconomicReleaseValuesParams struct {
+	ID     uuid.UUID
+	Period pgtype.Date
+	Actual pgtype.Float8
+	Prior  pgtype.Float8
+}
+
+func (q *Queries) UpdateEconomicReleaseValues(ctx context.Context, arg UpdateEconomicReleaseValuesParams) error {
+	_, err := q.db.Exec(ctx, updateEconomicReleaseValues,
+		arg.ID,
+		arg.Period,
+		arg.Actual,
+		arg.Prior,
+	)
+	return err
+}
+
+const updateGEXHistoryValue = `-- name: UpdateGEXHistoryValue :exec
+UPDATE gex_history
+SET gex_value = $2, flip_level = $3, call_wall = $4, put_wall = $5
+WHERE id = $1
+`
+
+type UpdateGEXHistoryValueParams struct {
+	ID        uuid.UUID
+	GexValue  pgtype.Numeric
+	FlipLevel pgtype.Numeric
+	CallWall  pgtype.Numeric
+	PutWall   pgtype.Numeric
+}
+
+func (q *Queries) UpdateGEXHistoryValue(ctx context.Context, arg UpdateGEXHistoryValueParams) error {
+	_, err := q.db.Exec(ctx, updateGEXHistoryValue,
+		arg.ID,
+		arg.GexValue,
+		arg.FlipLevel,
+		arg.CallWall,
+		arg.PutWall,
+	)
+	return err
+}
+
+const upsertBTCETFFlow = `-- name: UpsertBTCETFFlow :exec
+INSERT INTO btc_etf_flows (ticker, date, flow_usd, holdings_btc)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (ticker, date) DO UPDATE SET
+    flow_usd = EXCLUDED.flow_usd,
+    holdings_btc = COALESCE(EXCLUDED.holdings_btc, btc_etf_flows.holdings_btc),
+    updated_at = now()
+WHERE btc_etf_flows.flow_usd <> EXCLUDED.flow_usd
+   OR btc_etf_flows.holdings_btc IS DISTINCT FROM COALESCE(EXCLUDED.holdings_btc, btc_etf_flows.holdings_btc)
+`
+
+type UpsertBTCETFFlowParams struct {
+	Ticker      string
+	Date        pgtype.Date
+	FlowUsd     float64
+	HoldingsBtc pgtype.Float8
+}
+
+func (q *Queries) UpsertBTCETFFlow(ctx context.Context, arg UpsertBTCETFFlowParams) error {
+	_, err := q.db.Exec(ctx, upsertBTCETFFlow,
+		arg.Ticker,
+		arg.Date,
+		arg.FlowUsd,
+		arg.HoldingsBtc,
+	)
+	return err
+}
+
+const upsertEarningsEvent = `-- name: UpsertEarningsEvent :exec
+INSERT INTO earnings_events (symbol, report_date, session, fiscal_period, eps_estimate, source)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (symbol, report_date)
+DO UPDATE SET
+    session = EXCLUDED.session,
+    fiscal_period = EXCLUDED.fiscal_period,
+    eps_estimate = EXCLUDED.eps_estimate,
+    source = EXCLUDED.source,
+    updated_at = now()
+`
+
+type UpsertEarningsEventParams struct {
+	Symbol       string
+	ReportDate   pgtype.Date
+	Session      string
+	FiscalPeriod string
+	EpsEstimate  pgtype.Float8
+	Source       string
+}
+
+// Earnings calendar (internal/events)
+func (q *Queries) UpsertEarningsEvent(ctx context.Context, arg UpsertEarningsEventParams) error {
+	_, err := q.db.Exec(ctx, upsertEarningsEvent,
+		arg.Symbol,
+		arg.ReportDate,
+		arg.Session,
+		arg.FiscalPeriod,
+		arg.EpsEstimate,
+		arg.Source,
+	)
+	return err
+}
+
+const upsertEconomicRelease = `-- name: UpsertEconomicRelease :one
+INSERT INTO economic_releases (release_id, release_name, release_date, impact, release_time, series_id, units)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (release_id, release_date)
+DO UPDATE SET
+    release_name = EXCLUDED.release_name,
+    impact = EXCLUDED.impact,
+    release_time = EXCLUDED.release_time,
+    series_id = EXCLUDED.series_id,
+    units = EXCLUDED.units,
+    updated_at = now()
+RETURNING id, release_id, release_name, release_date, impact, created_at, updated_at, release_time, series_id, units, period, actual, prior, consensus, consensus_source, values_updated_at
+`
+
+type UpsertEconomicReleaseParams struct {
+	ReleaseID   int32
+	ReleaseName string
+	ReleaseDate pgtype.Date
+	Impact      string
+	ReleaseTime pgtype.Time
+	SeriesID    pgtype.Text
+	Units       pgtype.Text
+}
+
+// Economic Releases (FRED API)
+func (q *Queries) UpsertEconomicRelease(ctx context.Context, arg UpsertEconomicReleaseParams) (EconomicRelease, error) {
+	row := q.db.QueryRow(ctx, upsertEconomicRelease,
+		arg.ReleaseID,
+		arg.ReleaseName,
+		arg.ReleaseDate,
+		arg.Impact,
+		arg.ReleaseTime,
+		arg.SeriesID,
+		arg.Units,
+	)
+	var i EconomicRelease
+	err := row.Scan(
+		&i.ID,
+		&i.ReleaseID,
+		&i.ReleaseName,
+		&i.ReleaseDate,
+		&i.Impact,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ReleaseTime,
+		&i.SeriesID,
+		&i.Units,
+		&i.Period,
+		&i.Actual,
+		&i.Prior,
+		&i.Consensus,
+		&i.ConsensusSource,
+		&i.ValuesUpdatedAt,
+	)
+	return i, err
+}
+
+const upsertGEXSnapshot = `-- name: UpsertGEXSnapshot :exec
+INSERT INTO gex_snapshots (symbol, spot_price, strikes, warning, computed_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (symbol) DO UPDATE SET
+    spot_price = EXCLUDED.spot_price,
+    strikes = EXCLUDED.strikes,
+    warning = EXCLUDED.warning,
+    computed_at = EXCLUDED.computed_at
+`
+
+type UpsertGEXSnapshotParams struct {
+	Symbol     string
+	SpotPrice  float64
+	Strikes    []byte
+	Warning    string
+	ComputedAt time.Time
+}
+
+func (q *Queries) UpsertGEXSnapshot(ctx context.Context, arg UpsertGEXSnapshotParams) error {
+	_, err := q.db.Exec(ctx, upsertGEXSnapshot,
+		arg.Symbol,
+		arg.SpotPrice,
+		arg.Strikes,
+		arg.Warning,
+		arg.ComputedAt,
+	)
+	return err
+}
+
+const upsertMacroObservation = `-- name: UpsertMacroObservation :exec
+INSERT INTO macro_observations (series_id, date, value)
+VALUES ($1, $2, $3)
+ON CONFLICT (series_id, date) DO UPDATE SET
+    value = EXCLUDED.value,
+    updated_at = now()
+WHERE macro_observations.value <> EXCLUDED.value
+`
+
+type UpsertMacroObservationParams struct {
+	SeriesID string
+	Date     pgtype.Date
+	Value    float64
+}
+
+func (q *Queries) UpsertMacroObservation(ctx context.Context, arg UpsertMacroObservationParams) error {
+	_, err := q.db.Exec(ctx, upsertMacroObservation, arg.SeriesID, arg.Date, arg.Value)
+	return err
+}
+
+const upsertOptionChain = `-- name: UpsertOptionChain :one
+INSERT INTO option_chain (
+    symbol,
+    spot_price,
+    expiry_date,
+    expiry_type,
+    option_chain
+) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (symbol, expiry_date)
+DO UPDATE SET
+    spot_price = EXCLUDED.spot_price,
+    option_chain = EXCLUDED.option_chain,
+    updated_at = now()
+RETURNING id, symbol, spot_price, expiry_date, expiry_type, option_chain, created_at, updated_at
+`
+
+type UpsertOptionChainParams struct {
+	Symbol      string
+	SpotPrice   string
 	ExpiryDate  pgtype.Date
 	ExpiryType  string
 	OptionChain []byte
@@ -906,3 +2268,167 @@ func (q *Queries) UpsertOptionExpiryDates(ctx context.Context, arg UpsertOptionE
 	)
 	return i, err
 }
+
+const upsertScannerView = `-- name: UpsertScannerView :one
+INSERT INTO scanner_views (owner, name, query)
+VALUES ($1, $2, $3)
+ON CONFLICT (owner, name) DO UPDATE SET query = EXCLUDED.query, updated_at = now()
+RETURNING id, owner, name, query, created_at, updated_at
+`
+
+type UpsertScannerViewParams struct {
+	Owner string
+	Name  string
+	Query string
+}
+
+func (q *Queries) UpsertScannerView(ctx context.Context, arg UpsertScannerViewParams) (ScannerView, error) {
+	row := q.db.QueryRow(ctx, upsertScannerView, arg.Owner, arg.Name, arg.Query)
+	var i ScannerView
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.Query,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertSymbolMetadata = `-- name: UpsertSymbolMetadata :exec
+INSERT INTO symbol_metadata (symbol, name, sector, industry, market_cap)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (symbol) DO UPDATE
+SET name = COALESCE(NULLIF(EXCLUDED.name, ''), symbol_metadata.name),
+    sector = COALESCE(NULLIF(EXCLUDED.sector, ''), symbol_metadata.sector),
+    industry = COALESCE(NULLIF(EXCLUDED.industry, ''), symbol_metadata.industry),
+    market_cap = COALESCE(EXCLUDED.market_cap, symbol_metadata.market_cap),
+    updated_at = now()
+`
+
+type UpsertSymbolMetadataParams struct {
+	Symbol    string
+	Name      string
+	Sector    string
+	Industry  string
+	MarketCap pgtype.Int8
+}
+
+// Blank fields keep the stored value so partial CSVs don't wipe the seed data.
+func (q *Queries) UpsertSymbolMetadata(ctx context.Context, arg UpsertSymbolMetadataParams) error {
+	_, err := q.db.Exec(ctx, upsertSymbolMetadata,
+		arg.Symbol,
+		arg.Name,
+		arg.Sector,
+		arg.Industry,
+		arg.MarketCap,
+	)
+	return err
+}
+
+const upsertTrackedRelease = `-- name: UpsertTrackedRelease :one
+INSERT INTO tracked_releases (release_id, name, impact, release_time, category, series_id, units, related_series)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (release_id)
+DO UPDATE SET
+    name = EXCLUDED.name,
+    impact = EXCLUDED.impact,
+    release_time = EXCLUDED.release_time,
+    category = EXCLUDED.category,
+    series_id = EXCLUDED.series_id,
+    units = EXCLUDED.units,
+    related_series = EXCLUDED.related_series,
+    updated_at = now()
+RETURNING release_id, name, impact, release_time, category, series_id, units, related_series, created_at, updated_at
+`
+
+type UpsertTrackedReleaseParams struct {
+	ReleaseID     int32
+	Name          string
+	Impact        string
+	ReleaseTime   pgtype.Time
+	Category      string
+	SeriesID      pgtype.Text
+	Units         pgtype.Text
+	RelatedSeries []string
+}
+
+func (q *Queries) UpsertTrackedRelease(ctx context.Context, arg UpsertTrackedReleaseParams) (TrackedRelease, error) {
+	row := q.db.QueryRow(ctx, upsertTrackedRelease,
+		arg.ReleaseID,
+		arg.Name,
+		arg.Impact,
+		arg.ReleaseTime,
+		arg.Category,
+		arg.SeriesID,
+		arg.Units,
+		arg.RelatedSeries,
+	)
+	var i TrackedRelease
+	err := row.Scan(
+		&i.ReleaseID,
+		&i.Name,
+		&i.Impact,
+		&i.ReleaseTime,
+		&i.Category,
+		&i.SeriesID,
+		&i.Units,
+		&i.RelatedSeries,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUniverse = `-- name: UpsertUniverse :one
+INSERT INTO universes (slug, name, kind, owner)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (slug) DO UPDATE
+SET name = EXCLUDED.name, kind = EXCLUDED.kind, owner = EXCLUDED.owner, updated_at = now()
+RETURNING id, slug, name, kind, owner, created_at, updated_at
+`
+
+type UpsertUniverseParams struct {
+	Slug  string
+	Name  string
+	Kind  string
+	Owner pgtype.Text
+}
+
+func (q *Queries) UpsertUniverse(ctx context.Context, arg UpsertUniverseParams) (Universe, error) {
+	row := q.db.QueryRow(ctx, upsertUniverse,
+		arg.Slug,
+		arg.Name,
+		arg.Kind,
+		arg.Owner,
+	)
+	var i Universe
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.Kind,
+		&i.Owner,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUniverseMember = `-- name: UpsertUniverseMember :exec
+INSERT INTO universe_members (universe_id, symbol, position)
+VALUES ($1, $2, $3)
+ON CONFLICT (universe_id, symbol) DO UPDATE SET position = EXCLUDED.position
+`
+
+type UpsertUniverseMemberParams struct {
+	UniverseID uuid.UUID
+	Symbol     string
+	Position   int32
+}
+
+func (q *Queries) UpsertUniverseMember(ctx context.Context, arg UpsertUniverseMemberParams) error {
+	_, err := q.db.Exec(ctx, upsertUniverseMember, arg.UniverseID, arg.Symbol, arg.Position)
+	return err
+}
@@ -0,0 +1,79 @@
+package universe
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var symbolPattern = regexp.MustCompile(`^[A-Z][A-Z0-9.\-]{0,9}$`)
+
+// ValidSymbol reports whether symbol looks like an upper-case ticker.
+func ValidSymbol(symbol string) bool {
+	return symbolPattern.MatchString(symbol)
+}
+
+// ParseCSV reads symbols from a CSV export. If the first row has a "symbol"
+// or "ticker" column that column is used, otherwise the first column of every
+// row is. Symbols are upper-cased and de-duplicated in order of appearance;
+// malformed symbols are reported as an error rather than silently dropped.
+func ParseCSV(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	column := 0
+	first := true
+	seen := make(map[string]bool)
+	var symbols []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+
+		if first {
+			first = false
+			if idx, ok := headerColumn(record); ok {
+				column = idx
+				continue
+			}
+		}
+		if column >= len(record) {
+			continue
+		}
+
+		symbol := strings.ToUpper(strings.TrimSpace(record[column]))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		if !symbolPattern.MatchString(symbol) {
+			line, _ := reader.FieldPos(column)
+			return nil, fmt.Errorf("line %d: invalid symbol %q", line, symbol)
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols found in csv")
+	}
+	return symbols, nil
+}
+
+func headerColumn(record []string) (int, bool) {
+	for i, field := range record {
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "symbol", "ticker":
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,62 @@
+package universe
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "header with ticker column",
+			input: "Name,Ticker,Weight\nApple,aapl,7.1\nMicrosoft,MSFT,6.5\nApple,AAPL,7.1\n",
+			want:  []string{"AAPL", "MSFT"},
+		},
+		{
+			name:  "no header",
+			input: "SPY\nqqq\n\n# comment\nBRK.B\n",
+			want:  []string{"SPY", "QQQ", "BRK.B"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCSV(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseCSV: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCSV = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCSVRejectsBadInput(t *testing.T) {
+	for _, input := range []string{"", "symbol\n", "SPY\nnot a symbol\n"} {
+		if _, err := ParseCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseCSV(%q): expected error", input)
+		}
+	}
+}
+
+func TestDefinitionValidate(t *testing.T) {
+	def := Definition{Slug: " My-List "}
+	if err := def.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if def.Slug != "my-list" || def.Kind != KindWatchlist || def.Name != "my-list" {
+		t.Errorf("unexpected defaults: %+v", def)
+	}
+
+	for _, bad := range []Definition{{Slug: "bad slug"}, {Slug: "ok", Kind: "other"}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v): expected error", bad)
+		}
+	}
+}
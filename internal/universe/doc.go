@@ -0,0 +1,5 @@
+// Package universe manages the named symbol lists (indexes, sector ETFs and
+// user watchlists) that the collector, scanner, grid pages and alerts work on,
+// and the per-symbol reference data (name, sector, market cap) the scanner
+// filters on.
+package universe
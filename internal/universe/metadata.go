@@ -0,0 +1,160 @@
+package universe
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Metadata is the reference data stored for a symbol: its name, sector,
+// industry and market cap in USD (zero when unknown).
+type Metadata struct {
+	Symbol    string
+	Name      string
+	Sector    string
+	Industry  string
+	MarketCap int64
+}
+
+// ParseMetadataCSV reads symbol metadata from a CSV with a header row. The
+// symbol (or ticker) column is required; name, sector, industry and
+// market_cap (or marketcap) are optional. Market caps may use a B, M or T
+// suffix, as in "2.9T". Later rows for the same symbol replace earlier ones.
+func ParseMetadataCSV(r io.Reader) ([]Metadata, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, field := range header {
+		name := strings.ToLower(strings.TrimSpace(field))
+		switch name {
+		case "ticker":
+			name = "symbol"
+		case "marketcap", "market cap":
+			name = "market_cap"
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["symbol"]; !ok {
+		return nil, fmt.Errorf("csv has no symbol or ticker column")
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	index := make(map[string]int)
+	var rows []Metadata
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		m := Metadata{
+			Symbol:   strings.ToUpper(field(record, "symbol")),
+			Name:     field(record, "name"),
+			Sector:   field(record, "sector"),
+			Industry: field(record, "industry"),
+		}
+		if m.Symbol == "" {
+			continue
+		}
+		if !symbolPattern.MatchString(m.Symbol) {
+			return nil, fmt.Errorf("line %d: invalid symbol %q", line, m.Symbol)
+		}
+		if m.MarketCap, err = parseMarketCap(field(record, "market_cap")); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if i, ok := index[m.Symbol]; ok {
+			rows[i] = m
+			continue
+		}
+		index[m.Symbol] = len(rows)
+		rows = append(rows, m)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no symbols found in csv")
+	}
+	return rows, nil
+}
+
+func parseMarketCap(s string) (int64, error) {
+	raw := s
+	s = strings.TrimPrefix(strings.ReplaceAll(s, ",", ""), "$")
+	if s == "" {
+		return 0, nil
+	}
+	multiplier := 1.0
+	switch strings.ToUpper(s[len(s)-1:]) {
+	case "T":
+		multiplier = 1e12
+	case "B":
+		multiplier = 1e9
+	case "M":
+		multiplier = 1e6
+	}
+	if multiplier != 1 {
+		s = s[:len(s)-1]
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid market cap %q", raw)
+	}
+	return int64(v * multiplier), nil
+}
+
+// ImportMetadata upserts rows into the symbol metadata table and returns the
+// number written. Blank fields keep the values already stored.
+func (s *Store) ImportMetadata(ctx context.Context, rows []Metadata) (int, error) {
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no symbols to import")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin import: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	q := s.repo.WithTx(tx)
+
+	for _, m := range rows {
+		err := q.UpsertSymbolMetadata(ctx, repository.UpsertSymbolMetadataParams{
+			Symbol:    m.Symbol,
+			Name:      m.Name,
+			Sector:    m.Sector,
+			Industry:  m.Industry,
+			MarketCap: pgtype.Int8{Int64: m.MarketCap, Valid: m.MarketCap > 0},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("upsert %s: %w", m.Symbol, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit import: %w", err)
+	}
+	return len(rows), nil
+}
@@ -0,0 +1,42 @@
+package universe
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMetadataCSV(t *testing.T) {
+	input := "Ticker,Name,Sector,Industry,Market Cap\n" +
+		"aapl,Apple Inc.,Information Technology,Technology Hardware,3.4T\n" +
+		"JPM,JPMorgan Chase,Financials,Banks,\"$612,500,000,000\"\n" +
+		"XYZ,Small Co,,,850M\n" +
+		"AAPL,Apple,Information Technology,Technology Hardware,3.5T\n"
+
+	got, err := ParseMetadataCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseMetadataCSV: %v", err)
+	}
+	want := []Metadata{
+		{Symbol: "AAPL", Name: "Apple", Sector: "Information Technology", Industry: "Technology Hardware", MarketCap: 3.5e12},
+		{Symbol: "JPM", Name: "JPMorgan Chase", Sector: "Financials", Industry: "Banks", MarketCap: 612.5e9},
+		{Symbol: "XYZ", Name: "Small Co", MarketCap: 850e6},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseMetadataCSV =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseMetadataCSVRejectsBadInput(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"name,sector\nApple,Tech\n",
+		"symbol,market_cap\nAAPL,lots\n",
+		"symbol\nnot a symbol\n",
+		"symbol\n",
+	} {
+		if _, err := ParseMetadataCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseMetadataCSV(%q): expected error", input)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package universe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Well-known universes seeded by the migration.
+const (
+	SP500      = "sp500"
+	Nasdaq100  = "nasdaq100"
+	Mag7       = "mag7"
+	SectorETFs = "sector-etfs"
+)
+
+// Universe kinds.
+const (
+	KindIndex     = "index"
+	KindSector    = "sector"
+	KindWatchlist = "watchlist"
+)
+
+// ErrNotFound is returned when a universe slug does not exist.
+var ErrNotFound = errors.New("universe not found")
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// Store reads and writes universes and their members.
+type Store struct {
+	db   *pgxpool.Pool
+	repo *repository.Queries
+}
+
+func NewStore(db *pgxpool.Pool) *Store {
+	return &Store{
+		db:   db,
+		repo: repository.New(db),
+	}
+}
+
+// List returns every universe with its member count.
+func (s *Store) List(ctx context.Context) ([]repository.ListUniversesRow, error) {
+	return s.repo.ListUniverses(ctx)
+}
+
+// Get returns the universe slug.
+func (s *Store) Get(ctx context.Context, slug string) (repository.Universe, error) {
+	u, err := s.repo.GetUniverseBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return u, fmt.Errorf("%w: %s", ErrNotFound, slug)
+		}
+		return u, fmt.Errorf("get universe %s: %w", slug, err)
+	}
+	return u, nil
+}
+
+// Symbols returns the members of slug in their stored order.
+func (s *Store) Symbols(ctx context.Context, slug string) ([]string, error) {
+	if _, err := s.Get(ctx, slug); err != nil {
+		return nil, err
+	}
+	return s.repo.ListUniverseSymbols(ctx, slug)
+}
+
+// Union returns the distinct members of all the given universes. Unknown
+// slugs contribute nothing.
+func (s *Store) Union(ctx context.Context, slugs []string) ([]string, error) {
+	return s.repo.ListSymbolsInUniverses(ctx, slugs)
+}
+
+// Definition describes a universe to create or update.
+type Definition struct {
+	Slug  string
+	Name  string
+	Kind  string
+	Owner string
+}
+
+// Validate checks the slug and kind and fills in a name if it is missing.
+func (d *Definition) Validate() error {
+	d.Slug = strings.ToLower(strings.TrimSpace(d.Slug))
+	if !slugPattern.MatchString(d.Slug) {
+		return fmt.Errorf("invalid universe slug %q", d.Slug)
+	}
+	switch d.Kind {
+	case "":
+		d.Kind = KindWatchlist
+	case KindIndex, KindSector, KindWatchlist:
+	default:
+		return fmt.Errorf("invalid universe kind %q", d.Kind)
+	}
+	if strings.TrimSpace(d.Name) == "" {
+		d.Name = d.Slug
+	}
+	return nil
+}
+
+// Import creates or updates the universe described by def and stores
+// symbols as its members. With replace the existing members are dropped
+// first; otherwise the symbols are appended. It returns the number of
+// symbols written.
+func (s *Store) Import(ctx context.Context, def Definition, symbols []string, replace bool) (int, error) {
+	if err := def.Validate(); err != nil {
+		return 0, err
+	}
+	if len(symbols) == 0 {
+		return 0, fmt.Errorf("no symbols to import")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin import: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	q := s.repo.WithTx(tx)
+
+	u, err := q.UpsertUniverse(ctx, repository.UpsertUniverseParams{
+		Slug:  def.Slug,
+		Name:  def.Name,
+		Kind:  def.Kind,
+		Owner: pgtype.Text{String: def.Owner, Valid: def.Owner != ""},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("upsert universe: %w", err)
+	}
+
+	start := int32(0)
+	if replace {
+		if err := q.DeleteUniverseMembers(ctx, u.ID); err != nil {
+			return 0, fmt.Errorf("clear universe members: %w", err)
+		}
+	} else {
+		maxPosition, err := q.GetMaxUniversePosition(ctx, u.ID)
+		if err != nil {
+			return 0, fmt.Errorf("get universe position: %w", err)
+		}
+		start = maxPosition + 1
+	}
+
+	for i, symbol := range symbols {
+		err := q.UpsertUniverseMember(ctx, repository.UpsertUniverseMemberParams{
+			UniverseID: u.ID,
+			Symbol:     symbol,
+			Position:   start + int32(i),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("add %s: %w", symbol, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit import: %w", err)
+	}
+	return len(symbols), nil
+}
+
+// ImportCSV parses r with ParseCSV and imports the result.
+func (s *Store) ImportCSV(ctx context.Context, def Definition, r io.Reader, replace bool) (int, error) {
+	symbols, err := ParseCSV(r)
+	if err != nil {
+		return 0, err
+	}
+	return s.Import(ctx, def, symbols, replace)
+}
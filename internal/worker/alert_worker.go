@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+	"github.com/arnabmitra/eth-proxy/internal/zscore"
 )
 
 type AlertWorker struct {
@@ -20,15 +22,26 @@ type AlertWorker struct {
 	interval       time.Duration
 	stop           chan struct{}
 	lastAlerted    map[string]time.Time
+	universes      *universe.Store
+	universeSlugs  []string
+	scorer         *zscore.Scorer
+	zscore         zscore.Config
 }
 
-func NewAlertWorker(repo *repository.Queries, logger *slog.Logger) *AlertWorker {
+// NewAlertWorker creates a worker that alerts on extreme z-scores. When
+// universeSlugs is non-empty only members of those universes are alerted on.
+// Z-scores are computed with zcfg.
+func NewAlertWorker(repo *repository.Queries, logger *slog.Logger, universes *universe.Store, universeSlugs []string, zcfg zscore.Config) *AlertWorker {
 	return &AlertWorker{
-		repo:        repo,
-		logger:      logger,
-		interval:    15 * time.Minute,
-		stop:        make(chan struct{}),
-		lastAlerted: make(map[string]time.Time),
+		repo:          repo,
+		logger:        logger,
+		interval:      15 * time.Minute,
+		stop:          make(chan struct{}),
+		lastAlerted:   make(map[string]time.Time),
+		universes:     universes,
+		universeSlugs: universeSlugs,
+		scorer:        zscore.NewScorer(repo),
+		zscore:        zcfg.WithDefaults(zscore.Default()),
 	}
 }
 
@@ -61,15 +74,30 @@ func (w *AlertWorker) checkAlerts() {
 	}
 
 	ctx := context.Background()
-	results, err := w.repo.GetLatestZScores(ctx)
+	results, err := w.scorer.Latest(ctx, w.zscore, time.Now())
 	if err != nil {
-		w.logger.Error("failed to get latest z-scores for alerts", "error", err)
+		w.logger.Error("failed to get latest z-scores for alerts", "method", w.zscore.String(), "error", err)
 		return
 	}
 
+	var watched map[string]bool
+	if len(w.universeSlugs) > 0 && w.universes != nil {
+		symbols, err := w.universes.Union(ctx, w.universeSlugs)
+		if err != nil {
+			w.logger.Error("failed to load alert universes", "error", err)
+			return
+		}
+		watched = make(map[string]bool, len(symbols))
+		for _, s := range symbols {
+			watched[s] = true
+		}
+	}
+
 	for _, r := range results {
-		z, _ := r.ZScore.Float64Value()
-		zScore := z.Float64
+		if watched != nil && !watched[r.Symbol] {
+			continue
+		}
+		zScore := r.Score
 
 		// ALERT LOGIC: Extreme levels (Absolute Z > 2.5)
 		if math.Abs(zScore) >= 2.5 {
@@ -78,8 +106,8 @@ func (w *AlertWorker) checkAlerts() {
 				continue
 			}
 
-			message := fmt.Sprintf("⚠️ GEX ALERT: %s is at extreme deviation: %.2fσ. Current GEX: %.0f", 
-				r.Symbol, zScore, r.GexValue)
+			message := fmt.Sprintf("⚠️ GEX ALERT: %s is at extreme deviation: %s (%s). Current GEX: %.0f", 
+				r.Symbol, zscore.FormatScore(zscore.Method(r.Method), zScore), r.Method, r.GEX)
 			
 			w.sendAlert(r.Symbol, message)
 			w.lastAlerted[r.Symbol] = time.Now()
@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/handler/gex"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// BackfillOptions selects the gex_history rows to recompute. An empty Symbols
+// matches every symbol; a zero To means now.
+type BackfillOptions struct {
+	Symbols   []string
+	From      time.Time
+	To        time.Time
+	DryRun    bool
+	BatchSize int
+}
+
+// BackfillResult counts what a backfill did.
+type BackfillResult struct {
+	Scanned   int `json:"scanned"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"`
+}
+
+// Backfill recomputes gex_value, the flip level and the call and put walls of
+// stored gex_history snapshots from the option chain and spot price saved
+// alongside them, so history stays consistent after the GEX formula changes
+// and rows collected before a level was recorded get one. Rows whose snapshot
+// can't be parsed or that have no spot price are skipped.
+func Backfill(ctx context.Context, repo *repository.Queries, opts BackfillOptions) (BackfillResult, error) {
+	var result BackfillResult
+
+	if opts.To.IsZero() {
+		opts.To = time.Now()
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.Symbols == nil {
+		opts.Symbols = []string{}
+	}
+
+	afterRecordedAt := opts.From.Add(-time.Microsecond)
+	afterID := uuid.Nil
+	for {
+		rows, err := repo.ListGEXHistoryForBackfill(ctx, repository.ListGEXHistoryForBackfillParams{
+			Symbols:         opts.Symbols,
+			FromTime:        opts.From,
+			ToTime:          opts.To,
+			AfterRecordedAt: afterRecordedAt,
+			AfterID:         afterID,
+			BatchSize:       int32(opts.BatchSize),
+		})
+		if err != nil {
+			return result, fmt.Errorf("list gex history: %w", err)
+		}
+
+		for _, row := range rows {
+			result.Scanned++
+			afterRecordedAt, afterID = row.RecordedAt, row.ID
+
+			value, levels, ok := recomputeGEX(row.OptionChain, row.SpotPrice)
+			if !ok {
+				result.Skipped++
+				continue
+			}
+			formatted := fmt.Sprintf("%.2f", value)
+			if sameNumeric(row.GexValue, formatted) && sameLevel(row.FlipLevel, levels.Flip) &&
+				sameLevel(row.CallWall, levels.CallWall) && sameLevel(row.PutWall, levels.PutWall) {
+				result.Unchanged++
+				continue
+			}
+
+			result.Updated++
+			if opts.DryRun {
+				continue
+			}
+
+			var gexValue pgtype.Numeric
+			if err := gexValue.Scan(formatted); err != nil {
+				return result, fmt.Errorf("convert gex value for %s: %w", row.ID, err)
+			}
+			err = repo.UpdateGEXHistoryValue(ctx, repository.UpdateGEXHistoryValueParams{
+				ID:        row.ID,
+				GexValue:  gexValue,
+				FlipLevel: levelNumeric(levels.Flip),
+				CallWall:  levelNumeric(levels.CallWall),
+				PutWall:   levelNumeric(levels.PutWall),
+			})
+			if err != nil {
+				return result, fmt.Errorf("update gex history %s: %w", row.ID, err)
+			}
+		}
+
+		if len(rows) < opts.BatchSize {
+			return result, nil
+		}
+	}
+}
+
+func recomputeGEX(optionChain []byte, spotPrice pgtype.Text) (float64, gex.Levels, bool) {
+	if !spotPrice.Valid {
+		return 0, gex.Levels{}, false
+	}
+	price, err := strconv.ParseFloat(spotPrice.String, 64)
+	if err != nil || price <= 0 {
+		return 0, gex.Levels{}, false
+	}
+
+	var response gex.Response
+	if err := json.Unmarshal(optionChain, &response); err != nil {
+		return 0, gex.Levels{}, false
+	}
+	options := response.Options.Option
+	if len(options) == 0 {
+		return 0, gex.Levels{}, false
+	}
+	return gex.TotalGEX(gex.CalculateGEXPerStrike(options, price)), gex.CalculateLevels(options, price), true
+}
+
+func sameNumeric(n pgtype.Numeric, formatted string) bool {
+	current, err := n.Float64Value()
+	if err != nil || !current.Valid {
+		return false
+	}
+	return fmt.Sprintf("%.2f", current.Float64) == formatted
+}
+
+// sameLevel reports whether a stored strike level matches level, treating
+// NULL as zero.
+func sameLevel(n pgtype.Numeric, level float64) bool {
+	current, err := n.Float64Value()
+	if err != nil || !current.Valid {
+		return level <= 0
+	}
+	return fmt.Sprintf("%.2f", current.Float64) == fmt.Sprintf("%.2f", level)
+}
+
+// levelNumeric converts a strike level to a numeric, NULL when it is zero.
+func levelNumeric(level float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	if level > 0 {
+		n.Scan(strconv.FormatFloat(level, 'f', 2, 64))
+	}
+	return n
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestRecomputeGEX(t *testing.T) {
+	chain := []byte(`{"options":{"option":[
+		{"strike":100,"option_type":"call","open_interest":10,"greeks":{"gamma":0.05}},
+		{"strike":100,"option_type":"put","open_interest":4,"greeks":{"gamma":0.05}},
+		{"strike":105,"option_type":"call","open_interest":0,"greeks":{"gamma":0.02}}
+	]}}`)
+
+	got, levels, ok := recomputeGEX(chain, pgtype.Text{String: "100.00", Valid: true})
+	if !ok {
+		t.Fatal("expected snapshot to be recomputed")
+	}
+	// (10 - 4) contracts * 0.05 gamma * 100 multiplier * 100 spot
+	if want := 3000.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("recomputeGEX = %v, want %v", got, want)
+	}
+	if levels.CallWall != 100 || levels.PutWall != 100 || levels.Flip != 100 {
+		t.Errorf("levels = %+v, want all at 100", levels)
+	}
+
+	var stored pgtype.Numeric
+	stored.Scan("100.00")
+	if !sameLevel(stored, levels.CallWall) || sameLevel(pgtype.Numeric{}, levels.PutWall) || !sameLevel(pgtype.Numeric{}, 0) {
+		t.Error("sameLevel doesn't treat NULL as an unknown level")
+	}
+}
+
+func TestRecomputeGEXSkipsIncompleteSnapshots(t *testing.T) {
+	chain := []byte(`{"options":{"option":[{"strike":100,"option_type":"call","open_interest":1,"greeks":{"gamma":0.1}}]}}`)
+
+	tests := []struct {
+		name  string
+		chain []byte
+		spot  pgtype.Text
+	}{
+		{"no spot price", chain, pgtype.Text{}},
+		{"bad spot price", chain, pgtype.Text{String: "n/a", Valid: true}},
+		{"bad json", []byte(`{`), pgtype.Text{String: "100", Valid: true}},
+		{"empty chain", []byte(`{"options":{"option":[]}}`), pgtype.Text{String: "100", Valid: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, ok := recomputeGEX(tt.chain, tt.spot); ok {
+				t.Error("expected snapshot to be skipped")
+			}
+		})
+	}
+}
+
+func TestCollectRequestNormalize(t *testing.T) {
+	req := CollectRequest{Symbols: []string{" spy", "SPY", "", "qqq"}, Expiries: []string{"2026-10-23", " "}}.Normalize()
+
+	if len(req.Symbols) != 2 || req.Symbols[0] != "SPY" || req.Symbols[1] != "QQQ" {
+		t.Errorf("symbols = %v, want [SPY QQQ]", req.Symbols)
+	}
+	if len(req.Expiries) != 1 {
+		t.Errorf("expiries = %v, want one entry", req.Expiries)
+	}
+	if req.Trigger != TriggerManual {
+		t.Errorf("trigger = %q, want %q", req.Trigger, TriggerManual)
+	}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	if err := (CollectRequest{Symbols: []string{"SPY"}, Expiries: []string{"10/23"}}).Validate(); err == nil {
+		t.Error("expected malformed expiry to be rejected")
+	}
+}
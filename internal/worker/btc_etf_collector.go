@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/btcetf"
+)
+
+// BTCETFCollector keeps the spot bitcoin ETF flows up to date. Issuers
+// publish a day's flows over the following morning, and sources revise them,
+// so the whole source is read again every few hours.
+type BTCETFCollector struct {
+	source   *btcetf.Source
+	store    *btcetf.Store
+	logger   *slog.Logger
+	interval time.Duration
+	stop     chan struct{}
+}
+
+func NewBTCETFCollector(source *btcetf.Source, store *btcetf.Store, logger *slog.Logger) *BTCETFCollector {
+	return &BTCETFCollector{
+		source:   source,
+		store:    store,
+		logger:   logger,
+		interval: 3 * time.Hour,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (c *BTCETFCollector) Start() {
+	// Stopping the collector cancels any fetch still in flight.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		c.collect(ctx)
+
+		for {
+			select {
+			case <-ticker.C:
+				c.collect(ctx)
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (c *BTCETFCollector) Stop() {
+	close(c.stop)
+}
+
+func (c *BTCETFCollector) collect(parent context.Context) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(parent, 2*time.Minute)
+	defer cancel()
+
+	n, err := c.store.Ingest(ctx, c.source)
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		c.logger.Error("BTC ETF flows collection failed", slog.Int("stored", n), slog.Any("error", err))
+		return
+	}
+	c.logger.Info("BTC ETF flows collected", slog.Int("stored", n), slog.Duration("took", time.Since(start)))
+}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -15,32 +16,54 @@ type EconomicCalendarCollector struct {
 	fredClient *fred.Client
 	queries    *repository.Queries
 	interval   time.Duration
-	stop       chan struct{}
+	// valuesInterval is how often actual and prior values are looked for, so
+	// an actual shows up within the hour of its release.
+	valuesInterval time.Duration
+	refresh        chan struct{}
+	stop           chan struct{}
 }
 
 func NewEconomicCalendarCollector(queries *repository.Queries) *EconomicCalendarCollector {
 	apiKey := os.Getenv("FRED_API_KEY")
 	
 	return &EconomicCalendarCollector{
-		fredClient: fred.NewClient(apiKey),
-		queries:    queries,
-		interval:   24 * time.Hour, // Collect once per day
-		stop:       make(chan struct{}),
+		fredClient:     fred.NewClient(apiKey),
+		queries:        queries,
+		interval:       24 * time.Hour, // Collect once per day
+		valuesInterval: time.Hour,
+		refresh:        make(chan struct{}, 1),
+		stop:           make(chan struct{}),
 	}
 }
 
 func (c *EconomicCalendarCollector) Start() {
+	// Stopping the collector cancels any fetch still in flight.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+
 	go func() {
 		ticker := time.NewTicker(c.interval)
 		defer ticker.Stop()
+		valuesTicker := time.NewTicker(c.valuesInterval)
+		defer valuesTicker.Stop()
 
 		// Run immediately on start
-		c.collect()
+		c.collect(ctx)
+		c.collectValues(ctx)
 
 		for {
 			select {
 			case <-ticker.C:
-				c.collect()
+				c.collect(ctx)
+				c.collectValues(ctx)
+			case <-valuesTicker.C:
+				c.collectValues(ctx)
+			case <-c.refresh:
+				c.collect(ctx)
+				c.collectValues(ctx)
 			case <-c.stop:
 				return
 			}
@@ -48,19 +71,39 @@ func (c *EconomicCalendarCollector) Start() {
 	}()
 }
 
+// Refresh schedules a collection without waiting for the daily one, e.g.
+// after the tracked releases changed. It doesn't block; a refresh already
+// pending covers later calls.
+func (c *EconomicCalendarCollector) Refresh() {
+	select {
+	case c.refresh <- struct{}{}:
+	default:
+	}
+}
+
 func (c *EconomicCalendarCollector) Stop() {
 	close(c.stop)
 }
 
-func (c *EconomicCalendarCollector) collect() {
+func (c *EconomicCalendarCollector) collect(parent context.Context) {
 	startTime := time.Now()
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(parent, 30*time.Second)
 	defer cancel()
 
 	fmt.Printf("[%s] Starting economic calendar collection from FRED\n", startTime.Format(time.RFC3339))
 
-	// Fetch filtered releases for next 90 days
-	releases, err := c.fredClient.GetFilteredReleases(90)
+	rows, err := c.queries.ListTrackedReleases(ctx)
+	if err != nil {
+		fmt.Printf("Error loading tracked releases: %v\n", err)
+		return
+	}
+
+	// Fetch tracked releases for next 90 days
+	releases, err := c.fredClient.GetFilteredReleases(ctx, 90, trackedReleases(rows))
+	if errors.Is(err, context.Canceled) {
+		fmt.Printf("[%s] Economic calendar collection canceled\n", time.Now().Format(time.RFC3339))
+		return
+	}
 	if err != nil {
 		fmt.Printf("Error fetching FRED releases: %v\n", err)
 		return
@@ -73,6 +116,9 @@ func (c *EconomicCalendarCollector) collect() {
 			ReleaseName: release.ReleaseName,
 			ReleaseDate: pgtype.Date{Time: release.Date, Valid: true},
 			Impact:      release.Impact,
+			ReleaseTime: pgtype.Time{Microseconds: release.ReleaseTime.Microseconds(), Valid: release.ReleaseTime > 0},
+			SeriesID:    pgtype.Text{String: release.SeriesID, Valid: release.SeriesID != ""},
+			Units:       pgtype.Text{String: release.Units, Valid: release.Units != ""},
 		})
 
 		if err != nil {
@@ -85,3 +131,71 @@ func (c *EconomicCalendarCollector) collect() {
 	fmt.Printf("[%s] Completed economic calendar collection. Stored %d releases in %v\n",
 		time.Now().Format(time.RFC3339), storedCount, time.Since(startTime))
 }
+
+// trackedReleases converts the configured releases to what the FRED client
+// filters on.
+func trackedReleases(rows []repository.TrackedRelease) map[int]fred.TrackedRelease {
+	tracked := make(map[int]fred.TrackedRelease, len(rows))
+	for _, r := range rows {
+		tracked[int(r.ReleaseID)] = fred.TrackedRelease{
+			Impact:        r.Impact,
+			ReleaseTime:   time.Duration(r.ReleaseTime.Microseconds) * time.Microsecond,
+			Category:      r.Category,
+			SeriesID:      r.SeriesID.String,
+			Units:         r.Units.String,
+			RelatedSeries: r.RelatedSeries,
+		}
+	}
+	return tracked
+}
+
+// collectValues fetches the actual and prior values of recent releases that
+// don't have them yet.
+func (c *EconomicCalendarCollector) collectValues(parent context.Context) {
+	ctx, cancel := context.WithTimeout(parent, 2*time.Minute)
+	defer cancel()
+
+	releases, err := c.queries.ListReleasesAwaitingValues(ctx)
+	if err != nil {
+		fmt.Printf("Error listing releases awaiting values: %v\n", err)
+		return
+	}
+
+	updated := 0
+	for _, release := range releases {
+		values, err := c.fredClient.GetReleaseValues(ctx, release.SeriesID.String, release.Units.String, release.ReleaseDate.Time)
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		if err != nil {
+			fmt.Printf("Error fetching values of %s for %s: %v\n", release.SeriesID.String, release.ReleaseName, err)
+			continue
+		}
+
+		err = c.queries.UpdateEconomicReleaseValues(ctx, repository.UpdateEconomicReleaseValuesParams{
+			ID:     release.ID,
+			Period: pgtype.Date{Time: values.Period, Valid: !values.Period.IsZero()},
+			Actual: float8(values.Actual),
+			Prior:  float8(values.Prior),
+		})
+		if err != nil {
+			fmt.Printf("Error storing values of %s: %v\n", release.ReleaseName, err)
+			continue
+		}
+		if values.Actual != nil {
+			updated++
+		}
+	}
+
+	if len(releases) > 0 {
+		fmt.Printf("[%s] Checked %d releases for values, %d with actuals\n",
+			time.Now().Format(time.RFC3339), len(releases), updated)
+	}
+}
+
+func float8(v *float64) pgtype.Float8 {
+	if v == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *v, Valid: true}
+}
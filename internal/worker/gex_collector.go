@@ -3,51 +3,93 @@ package worker
 import (
 	"context"
 	json "encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"net/http"
 	"time"
 
+	"github.com/alpacahq/alpaca-trade-api-go/v3/alpaca"
 	"github.com/arnabmitra/eth-proxy/internal/handler"
 	"github.com/arnabmitra/eth-proxy/internal/handler/gex"
+	"github.com/arnabmitra/eth-proxy/internal/outbound"
 )
 
 type GexCollector struct {
-	gexHandler     *handler.GEXHandler
-	symbols        []string
-	interval       time.Duration
-	stop           chan struct{}
-	maxConcurrent  int
-	rateLimitDelay time.Duration
+	gexHandler    *handler.GEXHandler
+	ledger        *RunLedger
+	scheduler     *Scheduler
+	stop          chan struct{}
+	maxConcurrent int
+	jobTimeout    time.Duration
+
+	scheduleSource ScheduleSource
+	reloadEvery    time.Duration
 }
 
-func NewGEXCollector(gexHandler *handler.GEXHandler, symbols []string, interval time.Duration) *GexCollector {
-	if interval == 0 {
-		interval = 30 * time.Minute // Default to 15 minutes
-	}
+// ScheduleSource returns the current set of symbol schedules, e.g. built from
+// the universes stored in the database.
+type ScheduleSource func(ctx context.Context) ([]SymbolSchedule, error)
+
+// NewGEXCollector creates a collector that refreshes each symbol on its own
+// schedule and records every attempt in ledger. Use DefaultSchedules to build
+// schedules from a plain symbol list.
+func NewGEXCollector(gexHandler *handler.GEXHandler, ledger *RunLedger, schedules []SymbolSchedule) *GexCollector {
 	return &GexCollector{
-		gexHandler:     gexHandler,
-		symbols:        symbols,
-		interval:       interval,
-		stop:           make(chan struct{}),
-		maxConcurrent:  5, // Process 5 stocks concurrently to avoid rate limits
-		rateLimitDelay: 2 * time.Second,
+		gexHandler:    gexHandler,
+		ledger:        ledger,
+		scheduler:     NewScheduler(schedules, 0.1, time.Now()),
+		stop:          make(chan struct{}),
+		maxConcurrent: 5, // Process 5 stocks concurrently to avoid rate limits
+		jobTimeout:    2 * time.Minute,
 	}
 }
 
+// SetScheduleSource makes the collector re-read its schedules from src every
+// interval so universe changes take effect without a restart. It must be
+// called before Start.
+func (c *GexCollector) SetScheduleSource(src ScheduleSource, every time.Duration) {
+	c.scheduleSource = src
+	c.reloadEvery = every
+}
+
 func (c *GexCollector) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := make(chan SymbolSchedule)
+
+	for i := 0; i < c.maxConcurrent; i++ {
+		go c.worker(ctx, jobs)
+	}
+
 	go func() {
-		ticker := time.NewTicker(c.interval)
-		defer ticker.Stop()
-		// Run immediately on start
-		c.collectGEXData()
+		defer cancel()
+		defer close(jobs)
+
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		var lastReload time.Time
 		for {
 			select {
-			case <-ticker.C:
-				c.collectGEXData()
+			case <-timer.C:
 			case <-c.stop:
 				return
+			}
+
+			if c.scheduleSource != nil && time.Since(lastReload) >= c.reloadEvery {
+				c.reloadSchedules(ctx)
+				lastReload = time.Now()
+			}
 
+			// Sending blocks while all workers are busy, so a slow provider
+			// naturally holds back the next batch.
+			for _, sched := range c.scheduler.Due(time.Now()) {
+				select {
+				case jobs <- sched:
+				case <-c.stop:
+					return
+				}
 			}
+
+			timer.Reset(min(c.scheduler.NextWake(time.Now()), time.Minute))
 		}
 	}()
 }
@@ -56,199 +98,203 @@ func (c *GexCollector) Stop() {
 	close(c.stop)
 }
 
-// isMarketOpen checks if the US stock market is currently open (7:30 AM - 4:00 PM ET, Mon-Fri).
-func isMarketOpen() bool {
-	loc, err := time.LoadLocation("America/New_York")
+func (c *GexCollector) reloadSchedules(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	schedules, err := c.scheduleSource(ctx)
 	if err != nil {
-		fmt.Printf("Error loading location: %v\n", err)
-		return false // Default to not open if location cannot be loaded
+		fmt.Printf("[%s] Error reloading collector schedules, keeping current set: %v\n",
+			time.Now().Format(time.RFC3339), err)
+		return
 	}
-
-	now := time.Now().In(loc)
-	weekday := now.Weekday()
-	hour := now.Hour()
-	minute := now.Minute()
-
-	// Check if it's a weekday (Monday to Friday)
-	if weekday < time.Monday || weekday > time.Friday {
-		return false
+	if len(schedules) == 0 {
+		fmt.Printf("[%s] Collector schedule source returned no symbols, keeping current set\n",
+			time.Now().Format(time.RFC3339))
+		return
 	}
 
-	// Check if it's within market hours (9:30 AM to 4:00 PM ET)
-	// Market opens at 9:30
-	if hour < 9 || (hour == 9 && minute < 30) {
-		return false
+	added, removed := c.scheduler.Reconcile(schedules, time.Now())
+	if added > 0 || removed > 0 {
+		fmt.Printf("[%s] Collector schedules reloaded: %d added, %d removed, %d total\n",
+			time.Now().Format(time.RFC3339), added, removed, c.scheduler.Len())
 	}
+}
 
-	// Market closes at 4:00 PM ET
-	if hour >= 16 {
+// isMarketOpen checks if the US stock market is currently open (9:30 AM - 4:00 PM ET, Mon-Fri).
+func isMarketOpen() bool {
+	return MarketHours.Contains(time.Now())
+}
+
+// isRateLimited reports whether err is a provider throttling us, either
+// directly or because the outbound circuit breaker has tripped. Alpaca
+// answers 403 as well as 429 once a plan's request quota is used up.
+func isRateLimited(err error) bool {
+	if err == nil {
 		return false
 	}
-
-	return true
+	if errors.Is(err, outbound.ErrThrottled) || errors.Is(err, outbound.ErrCircuitOpen) {
+		return true
+	}
+	var apiErr *alpaca.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusForbidden
+	}
+	return false
 }
 
-type gexResult struct {
-	symbol string
-	err    error
+func (c *GexCollector) worker(ctx context.Context, jobs <-chan SymbolSchedule) {
+	apiKey, apiSecret := gex.GetAlpacaConfig()
+
+	for sched := range jobs {
+		if apiKey == "" || apiSecret == "" {
+			fmt.Printf("[%s] Skipping GEX for %s: ALPACA_API_KEY or ALPACA_API_SECRET not set\n",
+				time.Now().Format(time.RFC3339), sched.Symbol)
+			c.scheduler.Complete(sched.Symbol, time.Now(), false)
+			continue
+		}
+
+		startedAt := time.Now()
+		jobCtx, cancel := context.WithTimeout(ctx, c.jobTimeout)
+		provider, err := c.collectSymbolGEX(jobCtx, sched.Symbol, "", apiKey, apiSecret)
+		cancel()
+
+		status := attemptStatus(err)
+		limited := status == AttemptRateLimited
+		switch status {
+		case AttemptSuccess:
+		case AttemptCanceled:
+			fmt.Printf("[%s] Collection for %s canceled\n",
+				time.Now().Format(time.RFC3339), sched.Symbol)
+		default:
+			fmt.Printf("[%s] Error collecting GEX for %s (tier %s): %v\n",
+				time.Now().Format(time.RFC3339), sched.Symbol, sched.Tier.Name, err)
+		}
+		c.recordAttempt(ctx, Attempt{
+			Symbol:    sched.Symbol,
+			Tier:      sched.Tier.Name,
+			Status:    status,
+			Err:       err,
+			Provider:  provider,
+			StartedAt: startedAt,
+			Duration:  time.Since(startedAt),
+		})
+		c.scheduler.Complete(sched.Symbol, time.Now(), limited)
+		if limited {
+			fmt.Printf("[%s] Rate limited on %s, pausing collection until %s\n",
+				time.Now().Format(time.RFC3339), sched.Symbol, c.scheduler.PausedUntil().Format(time.RFC3339))
+		}
+	}
 }
 
-func (c *GexCollector) collectGEXData() {
-	if !isMarketOpen() {
-		fmt.Printf("Market is closed. Skipping GEX data collection.\\n")
-		return
+// collectSymbolGEX stores a fresh chain for symbol and returns the provider
+// that served it. An empty expiry means the nearest available expiry.
+func (c *GexCollector) collectSymbolGEX(ctx context.Context, symbol string, expiry string, apiKey string, apiSecret string) (string, error) {
+	if expiry == "" {
+		nearest, err := c.nearestExpiry(ctx, symbol, apiKey, apiSecret)
+		if err != nil {
+			return "", err
+		}
+		expiry = nearest
 	}
-	startTime := time.Now()
-	fmt.Printf("[%s] Starting GEX data collection for %d symbols\\n", startTime.Format(time.RFC3339), len(c.symbols))
 
-	// Create channels for work distribution and results
-	symbolChan := make(chan string, len(c.symbols))
-	resultChan := make(chan gexResult, len(c.symbols))
+	// Get current price
+	price, err := gex.GetSpotPrice(ctx, apiKey, apiSecret, symbol)
+	if err != nil {
+		return "", fmt.Errorf("failed to get spot price: %w", err)
+	}
 
-	// Create context with timeout for entire operation
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-	defer cancel()
+	// Fetch options chain
+	options, jsonOption, warning, err := gex.FetchOptionsChain(ctx, symbol, expiry, apiKey, apiSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch options chain: %w", err)
+	}
 
-	// Start worker goroutines
-	for i := 0; i < c.maxConcurrent; i++ {
-		go c.worker(ctx, symbolChan, resultChan)
-	}
-
-	// Send all symbols to the work channel
-	for _, symbol := range c.symbols {
-		symbolChan <- symbol
-	}
-	close(symbolChan)
-
-	// Collect results
-	successCount := 0
-	errorCount := 0
-	for i := 0; i < len(c.symbols); i++ {
-		result := <-resultChan
-		if result.err != nil {
-			errorCount++
-			fmt.Printf("[%s] Error collecting GEX for %s: %v\\n",
-				time.Now().Format(time.RFC3339), result.symbol, result.err)
-		} else {
-			successCount++
-		}
+	if warning != "" {
+		fmt.Printf("Warning during collection for %s: %s\n", symbol, warning)
 	}
 
-	fmt.Printf("[%s] Completed GEX data collection in %v. Success: %d, Errors: %d\\n",
-		time.Now().Format(time.RFC3339), time.Since(startTime), successCount, errorCount)
-}
+	if jsonOption == nil {
+		return "", fmt.Errorf("nil options chain returned")
+	}
 
-func (c *GexCollector) worker(ctx context.Context, symbolChan <-chan string, resultChan chan<- gexResult) {
-	apiKey := os.Getenv("ALPACA_API_KEY")
-	apiSecret := os.Getenv("ALPACA_API_SECRET")
-	if apiKey == "" || apiSecret == "" {
-		return
+	var source struct {
+		Provider string `json:"provider"`
 	}
+	_ = json.Unmarshal([]byte(*jsonOption), &source)
 
-	for symbol := range symbolChan {
-		// Check if market is still open
-		if !isMarketOpen() {
-			resultChan <- gexResult{symbol: symbol, err: fmt.Errorf("market closed")}
-			continue
-		}
+	totalGEX := gex.TotalGEX(gex.CalculateGEXPerStrike(options, price))
 
-		// Rate limiting delay
-		time.Sleep(c.rateLimitDelay)
+	fmt.Printf("[%s] %s: Total GEX=%.2f, Price=%.2f, Expiry=%s\n",
+		time.Now().Format(time.RFC3339), symbol, totalGEX, price, expiry)
 
-		err := c.collectSymbolGEX(ctx, symbol, apiKey, apiSecret)
-		resultChan <- gexResult{symbol: symbol, err: err}
+	// Store in the database
+	err = c.gexHandler.StoreOptionChain(ctx, options, symbol, *jsonOption, fmt.Sprintf("%.2f", price), fmt.Sprintf("%.2f", totalGEX))
+	if err != nil {
+		return source.Provider, fmt.Errorf("failed to store option chain: %w", err)
 	}
+
+	return source.Provider, nil
 }
 
-func (c *GexCollector) collectSymbolGEX(ctx context.Context, symbol string, apiKey string, apiSecret string) error {
-	// Get the nearest expiry date
+// nearestExpiry returns the first stored expiry for symbol, fetching and
+// storing the expiry list first if there is none.
+func (c *GexCollector) nearestExpiry(ctx context.Context, symbol string, apiKey string, apiSecret string) (string, error) {
 	expiryDates, err := c.gexHandler.GetExpiryDates(ctx, symbol)
 	if err != nil || len(expiryDates) == 0 {
-		expirationDates, err := gex.GetExpirationDates(apiKey, apiSecret, symbol)
+		expirationDates, err := gex.GetExpirationDates(ctx, apiKey, apiSecret, symbol)
 		if err != nil {
-			return fmt.Errorf("failed to get expiration dates: %w", err)
+			return "", fmt.Errorf("failed to get expiration dates: %w", err)
 		}
 
 		expirationDatesJSON, err := json.MarshalIndent(expirationDates, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal expiration dates: %w", err)
+			return "", fmt.Errorf("failed to marshal expiration dates: %w", err)
 		}
 
 		err = c.gexHandler.StoreExpiryDatesInOptionExpiryDates(ctx, symbol, expirationDatesJSON)
 		if err != nil {
-			return fmt.Errorf("failed to store expiry dates: %w", err)
+			return "", fmt.Errorf("failed to store expiry dates: %w", err)
 		}
 
 		expiryDates, err = c.gexHandler.GetExpiryDates(ctx, symbol)
 		if err != nil {
-			return fmt.Errorf("failed to get stored expiry dates: %w", err)
+			return "", fmt.Errorf("failed to get stored expiry dates: %w", err)
 		}
 	}
 
 	if len(expiryDates) == 0 {
-		return fmt.Errorf("no expiry dates available")
-	}
-
-	// Use only the nearest expiry date
-	nearestExpiry := expiryDates[0]
-
-	// Get current price
-	price, err := gex.GetSpotPrice(apiKey, apiSecret, symbol)
-	if err != nil {
-		// Check if it's a rate limit error (403)
-		if err.Error() == "unexpected status code: 403" {
-			time.Sleep(5 * time.Second) // Back off on rate limit
-			// Retry once
-			price, err = gex.GetSpotPrice(apiKey, apiSecret, symbol)
-			if err != nil {
-				return fmt.Errorf("failed to get spot price after retry: %w", err)
-			}
-		} else {
-			return fmt.Errorf("failed to get spot price: %w", err)
-		}
-	}
-
-	// Fetch options chain
-	options, jsonOption, warning, err := gex.FetchOptionsChain(symbol, nearestExpiry, apiKey, apiSecret)
-	if err != nil {
-		// Check if it's a rate limit error
-		if err.Error() == "unexpected status code: 403" {
-			time.Sleep(5 * time.Second)
-			// Retry once
-			options, jsonOption, warning, err = gex.FetchOptionsChain(symbol, nearestExpiry, apiKey, apiSecret)
-			if err != nil {
-				return fmt.Errorf("failed to fetch options chain after retry: %w", err)
-			}
-		} else {
-			return fmt.Errorf("failed to fetch options chain: %w", err)
-		}
-	}
-
-	if warning != "" {
-		fmt.Printf("Warning during collection for %s: %s\n", symbol, warning)
+		return "", fmt.Errorf("no expiry dates available")
 	}
+	return expiryDates[0], nil
+}
 
-	if jsonOption == nil {
-		return fmt.Errorf("nil options chain returned")
+func attemptStatus(err error) string {
+	switch {
+	case err == nil:
+		return AttemptSuccess
+	case errors.Is(err, context.Canceled):
+		return AttemptCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return AttemptTimeout
+	case isRateLimited(err):
+		return AttemptRateLimited
+	default:
+		return AttemptFailure
 	}
+}
 
-	// Calculate GEX for the nearest expiry
-	gexByStrike := gex.CalculateGEXPerStrike(options, price)
-
-	// Calculate total GEX (sum of all strikes)
-	totalGEX := 0.0
-	for _, gexValue := range gexByStrike {
-		totalGEX += gexValue
+// recordAttempt writes to the run ledger; ledger failures are logged but never
+// fail the collection itself. The write is detached from ctx's cancellation so
+// attempts interrupted by shutdown are still recorded.
+func (c *GexCollector) recordAttempt(ctx context.Context, a Attempt) {
+	if c.ledger == nil {
+		return
 	}
-
-	fmt.Printf("[%s] %s: Total GEX=%.2f, Price=%.2f, Expiry=%s\\n",
-		time.Now().Format(time.RFC3339), symbol, totalGEX, price, nearestExpiry)
-
-	// Store in the database
-	err = c.gexHandler.StoreOptionChain(ctx, options, symbol, *jsonOption, fmt.Sprintf("%.2f", price), fmt.Sprintf("%.2f", totalGEX))
-	if err != nil {
-		return fmt.Errorf("failed to store option chain: %w", err)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := c.ledger.Record(ctx, a); err != nil {
+		fmt.Printf("[%s] Error recording collector attempt for %s: %v\n",
+			time.Now().Format(time.RFC3339), a.Symbol, err)
 	}
-
-	return nil
 }
@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Attempt statuses recorded in collector_run_symbols.
+const (
+	AttemptSuccess     = "success"
+	AttemptFailure     = "failure"
+	AttemptRateLimited = "rate_limited"
+	// AttemptCanceled and AttemptTimeout mark attempts cut short by the
+	// caller's context rather than by the provider.
+	AttemptCanceled = "canceled"
+	AttemptTimeout  = "timeout"
+)
+
+// Attempt is the outcome of collecting a single symbol.
+type Attempt struct {
+	Symbol    string
+	Tier      string
+	Status    string
+	Err       error
+	Provider  string
+	StartedAt time.Time
+	Duration  time.Duration
+}
+
+// RunLedger records collector activity in collector_runs. The scheduler
+// dispatches symbols continuously, so scheduled attempts are grouped into
+// runs covering a fixed window of time.
+type RunLedger struct {
+	repo   *repository.Queries
+	window time.Duration
+
+	mu           sync.Mutex
+	currentID    uuid.UUID
+	currentStart time.Time
+}
+
+func NewRunLedger(repo *repository.Queries) *RunLedger {
+	return &RunLedger{
+		repo:   repo,
+		window: 30 * time.Minute,
+	}
+}
+
+// Record stores a scheduled attempt, opening a new run if the current one
+// has covered its window.
+func (l *RunLedger) Record(ctx context.Context, a Attempt) error {
+	runID, err := l.scheduledRun(ctx, a.StartedAt)
+	if err != nil {
+		return err
+	}
+	return l.RecordInRun(ctx, runID, a)
+}
+
+// StartRun opens a run outside the scheduled window, e.g. for a manual trigger.
+func (l *RunLedger) StartRun(ctx context.Context, trigger string, startedAt time.Time) (uuid.UUID, error) {
+	run, err := l.repo.CreateCollectorRun(ctx, repository.CreateCollectorRunParams{
+		Trigger:   trigger,
+		StartedAt: startedAt,
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("create collector run: %w", err)
+	}
+	return run.ID, nil
+}
+
+// RecordInRun stores an attempt against a specific run and updates its totals.
+func (l *RunLedger) RecordInRun(ctx context.Context, runID uuid.UUID, a Attempt) error {
+	var errText pgtype.Text
+	if a.Err != nil {
+		errText = pgtype.Text{String: a.Err.Error(), Valid: true}
+	}
+
+	_, err := l.repo.InsertCollectorRunSymbol(ctx, repository.InsertCollectorRunSymbolParams{
+		RunID:      runID,
+		Symbol:     a.Symbol,
+		Tier:       a.Tier,
+		Status:     a.Status,
+		Error:      errText,
+		Provider:   pgtype.Text{String: a.Provider, Valid: a.Provider != ""},
+		StartedAt:  a.StartedAt,
+		DurationMs: int32(a.Duration.Milliseconds()),
+	})
+	if err != nil {
+		return fmt.Errorf("record collector attempt: %w", err)
+	}
+
+	// Canceled attempts say nothing about the provider, so they count as
+	// neither a success nor a failure.
+	var successes, failures int32
+	switch a.Status {
+	case AttemptSuccess:
+		successes = 1
+	case AttemptCanceled:
+	default:
+		failures = 1
+	}
+	err = l.repo.UpdateCollectorRunCounts(ctx, repository.UpdateCollectorRunCountsParams{
+		ID:         runID,
+		Successes:  successes,
+		Failures:   failures,
+		FinishedAt: a.StartedAt.Add(a.Duration),
+	})
+	if err != nil {
+		return fmt.Errorf("update collector run: %w", err)
+	}
+	return nil
+}
+
+func (l *RunLedger) scheduledRun(ctx context.Context, at time.Time) (uuid.UUID, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.currentID != uuid.Nil && at.Sub(l.currentStart) < l.window {
+		return l.currentID, nil
+	}
+
+	id, err := l.StartRun(ctx, "scheduled", at)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	l.currentID = id
+	l.currentStart = at
+	return id, nil
+}
@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alpacahq/alpaca-trade-api-go/v3/alpaca"
+	"github.com/arnabmitra/eth-proxy/internal/outbound"
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestAttemptStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"success", nil, AttemptSuccess},
+		{"canceled", fmt.Errorf("failed to get spot price: %w", &url.Error{Op: "Get", URL: "x", Err: context.Canceled}), AttemptCanceled},
+		{"deadline", fmt.Errorf("failed to fetch options chain: %w", context.DeadlineExceeded), AttemptTimeout},
+		{"throttled", fmt.Errorf("wrapped: %w", outbound.ErrThrottled), AttemptRateLimited},
+		{"alpaca quota", fmt.Errorf("failed to get spot price: %w", &alpaca.APIError{StatusCode: 429, Message: "too many requests"}), AttemptRateLimited},
+		{"alpaca forbidden", fmt.Errorf("error getting contracts: %w", &alpaca.APIError{StatusCode: 403, Message: "forbidden"}), AttemptRateLimited},
+		{"alpaca not found", fmt.Errorf("error getting contracts: %w", &alpaca.APIError{StatusCode: 404, Message: "not found"}), AttemptFailure},
+		{"digits in the message", errors.New("no contracts found for SPY at strike 429 (id 4031)"), AttemptFailure},
+		{"failure", errors.New("no contracts found"), AttemptFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attemptStatus(tt.err); got != tt.want {
+				t.Errorf("attemptStatus(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRecordInRunOutOfOrder needs a migrated database in TEST_DATABASE_URL.
+func TestRecordInRunOutOfOrder(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	repo := repository.New(pool)
+	ledger := NewRunLedger(repo)
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	runID, err := ledger.StartRun(ctx, "test", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pool.Exec(ctx, "DELETE FROM collector_runs WHERE id = $1", runID) })
+
+	// The slow attempt finishes last but its worker records it first.
+	slow := Attempt{Symbol: "SPY", Tier: "core", Status: AttemptSuccess, StartedAt: start, Duration: 90 * time.Second}
+	fast := Attempt{Symbol: "QQQ", Tier: "core", Status: AttemptFailure, Err: errors.New("boom"), StartedAt: start.Add(10 * time.Second), Duration: 5 * time.Second}
+	for _, a := range []Attempt{slow, fast} {
+		if err := ledger.RecordInRun(ctx, runID, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := repo.ListCollectorRuns(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, run := range runs {
+		if run.ID != runID {
+			continue
+		}
+		if want := start.Add(90 * time.Second); !run.FinishedAt.Time.Equal(want) {
+			t.Errorf("finished_at = %s, want %s", run.FinishedAt.Time, want)
+		}
+		if run.SymbolsAttempted != 2 || run.Successes != 1 || run.Failures != 1 {
+			t.Errorf("run = %+v", run)
+		}
+		return
+	}
+	t.Fatalf("run %s not listed", runID)
+}
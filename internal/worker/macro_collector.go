@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/fred"
+	"github.com/arnabmitra/eth-proxy/internal/macro"
+)
+
+// MacroCollector keeps the macro dashboard's FRED series up to date. FRED
+// publishes the daily series the next morning, so a few runs a day are
+// enough.
+type MacroCollector struct {
+	fredClient *fred.Client
+	store      *macro.Store
+	logger     *slog.Logger
+	interval   time.Duration
+	stop       chan struct{}
+}
+
+func NewMacroCollector(store *macro.Store, logger *slog.Logger) *MacroCollector {
+	return &MacroCollector{
+		fredClient: fred.NewClient(os.Getenv("FRED_API_KEY")),
+		store:      store,
+		logger:     logger,
+		interval:   4 * time.Hour,
+		stop:       make(chan struct{}),
+	}
+}
+
+func (c *MacroCollector) Start() {
+	// Stopping the collector cancels any fetch still in flight.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		c.collect(ctx)
+
+		for {
+			select {
+			case <-ticker.C:
+				c.collect(ctx)
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (c *MacroCollector) Stop() {
+	close(c.stop)
+}
+
+func (c *MacroCollector) collect(parent context.Context) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(parent, 2*time.Minute)
+	defer cancel()
+
+	n, err := c.store.Ingest(ctx, c.fredClient)
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		c.logger.Error("Macro series collection failed", slog.Int("stored", n), slog.Any("error", err))
+		return
+	}
+	c.logger.Info("Macro series collected", slog.Int("stored", n), slog.Duration("took", time.Since(start)))
+}
@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/handler/gex"
+	"github.com/google/uuid"
+)
+
+// Triggers recorded against runs that were not started by the scheduler.
+const (
+	TriggerManual = "manual"
+	TriggerCLI    = "cli"
+)
+
+// CollectRequest selects what an on-demand collection fetches. Each symbol is
+// collected once per expiry; with no expiries only the nearest is collected.
+type CollectRequest struct {
+	Symbols  []string
+	Expiries []string
+	Trigger  string
+}
+
+// Normalize upper-cases and de-duplicates symbols and drops blank entries.
+func (r CollectRequest) Normalize() CollectRequest {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, s := range r.Symbols {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		symbols = append(symbols, s)
+	}
+	var expiries []string
+	for _, e := range r.Expiries {
+		if e = strings.TrimSpace(e); e != "" {
+			expiries = append(expiries, e)
+		}
+	}
+	trigger := r.Trigger
+	if trigger == "" {
+		trigger = TriggerManual
+	}
+	return CollectRequest{Symbols: symbols, Expiries: expiries, Trigger: trigger}
+}
+
+// Validate rejects requests without symbols or with malformed expiries.
+func (r CollectRequest) Validate() error {
+	if len(r.Symbols) == 0 {
+		return fmt.Errorf("no symbols requested")
+	}
+	for _, e := range r.Expiries {
+		if _, err := time.Parse("2006-01-02", e); err != nil {
+			return fmt.Errorf("invalid expiry %q, want YYYY-MM-DD", e)
+		}
+	}
+	return nil
+}
+
+// StartManualRun opens a ledger run for req so callers can hand out its ID
+// before the collection finishes. It returns uuid.Nil without a ledger.
+func (c *GexCollector) StartManualRun(ctx context.Context, req CollectRequest) (uuid.UUID, error) {
+	if c.ledger == nil {
+		return uuid.Nil, nil
+	}
+	return c.ledger.StartRun(ctx, req.Normalize().Trigger, time.Now())
+}
+
+// CollectNow collects req immediately, outside the schedule, and records the
+// attempts against runID (see StartManualRun). It does not touch the
+// scheduler, so a symbol may also be collected by its regular tier.
+func (c *GexCollector) CollectNow(ctx context.Context, runID uuid.UUID, req CollectRequest) ([]Attempt, error) {
+	req = req.Normalize()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	apiKey, apiSecret := gex.GetAlpacaConfig()
+	if apiKey == "" || apiSecret == "" {
+		return nil, fmt.Errorf("ALPACA_API_KEY or ALPACA_API_SECRET not set")
+	}
+
+	expiries := req.Expiries
+	if len(expiries) == 0 {
+		expiries = []string{""}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		attempts []Attempt
+		sem      = make(chan struct{}, c.maxConcurrent)
+	)
+	for _, symbol := range req.Symbols {
+		for _, expiry := range expiries {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(symbol, expiry string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				startedAt := time.Now()
+				jobCtx, cancel := context.WithTimeout(ctx, c.jobTimeout)
+				provider, err := c.collectSymbolGEX(jobCtx, symbol, expiry, apiKey, apiSecret)
+				cancel()
+
+				a := Attempt{
+					Symbol:    symbol,
+					Tier:      req.Trigger,
+					Status:    attemptStatus(err),
+					Err:       err,
+					Provider:  provider,
+					StartedAt: startedAt,
+					Duration:  time.Since(startedAt),
+				}
+				if c.ledger != nil && runID != uuid.Nil {
+					recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+					err := c.ledger.RecordInRun(recordCtx, runID, a)
+					cancel()
+					if err != nil {
+						fmt.Printf("[%s] Error recording collector attempt for %s: %v\n",
+							time.Now().Format(time.RFC3339), symbol, err)
+					}
+				}
+
+				mu.Lock()
+				attempts = append(attempts, a)
+				mu.Unlock()
+			}(symbol, expiry)
+		}
+	}
+	wg.Wait()
+
+	return attempts, nil
+}
@@ -0,0 +1,431 @@
+package worker
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ActiveWindow is the part of a trading day (America/New_York) during which
+// a symbol is collected. Start and End are offsets from midnight.
+type ActiveWindow struct {
+	Start       time.Duration
+	End         time.Duration
+	WeekendsToo bool
+}
+
+var (
+	// MarketHours covers the regular session, 9:30 AM - 4:00 PM ET.
+	MarketHours = ActiveWindow{Start: 9*time.Hour + 30*time.Minute, End: 16 * time.Hour}
+	// ExtendedHours covers pre-market and after-hours, 4:00 AM - 8:00 PM ET.
+	ExtendedHours = ActiveWindow{Start: 4 * time.Hour, End: 20 * time.Hour}
+)
+
+var newYork = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		fmt.Printf("Error loading location: %v\n", err)
+		return time.UTC
+	}
+	return loc
+}()
+
+// Contains reports whether t falls inside the window.
+func (w ActiveWindow) Contains(t time.Time) bool {
+	t = t.In(newYork)
+	if !w.WeekendsToo && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return false
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, newYork)
+	offset := t.Sub(midnight)
+	return offset >= w.Start && offset < w.End
+}
+
+// NextOpen returns the earliest time at or after t that is inside the window.
+func (w ActiveWindow) NextOpen(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	t = t.In(newYork)
+	for i := 0; i < 8; i++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()+i, 0, 0, 0, 0, newYork)
+		open := day.Add(w.Start)
+		if open.Before(t) {
+			continue
+		}
+		if w.Contains(open) {
+			return open
+		}
+	}
+	return t.Add(24 * time.Hour)
+}
+
+// Tier groups symbols that share a collection cadence. When several jobs are
+// due at once the ones with the higher Priority are dispatched first.
+type Tier struct {
+	Name     string
+	Interval time.Duration
+	Priority int
+	Window   ActiveWindow
+}
+
+var (
+	CoreTier     = Tier{Name: "core", Interval: 5 * time.Minute, Priority: 100, Window: MarketHours}
+	IndexETFTier = Tier{Name: "index-etf", Interval: 10 * time.Minute, Priority: 50, Window: MarketHours}
+	DefaultTier  = Tier{Name: "default", Interval: time.Hour, Priority: 0, Window: MarketHours}
+)
+
+// SymbolSchedule is the cadence for a single symbol.
+type SymbolSchedule struct {
+	Symbol string
+	Tier   Tier
+}
+
+// DefaultSchedules puts SPY and QQQ in the core tier, the other broad index
+// ETFs in the index-etf tier and every remaining symbol in the default tier.
+func DefaultSchedules(symbols []string) []SymbolSchedule {
+	tiers := map[string]Tier{
+		"SPY": CoreTier,
+		"QQQ": CoreTier,
+		"IWM": IndexETFTier,
+		"DIA": IndexETFTier,
+		"RSP": IndexETFTier,
+		"MDY": IndexETFTier,
+	}
+
+	seen := make(map[string]bool)
+	var schedules []SymbolSchedule
+	for _, symbol := range []string{"SPY", "QQQ", "IWM", "DIA", "RSP", "MDY"} {
+		seen[symbol] = true
+		schedules = append(schedules, SymbolSchedule{Symbol: symbol, Tier: tiers[symbol]})
+	}
+	for _, symbol := range symbols {
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		schedules = append(schedules, SymbolSchedule{Symbol: symbol, Tier: DefaultTier})
+	}
+	return schedules
+}
+
+// ApplyScheduleOverrides parses a spec such as "SPY=2m:200,NVDA=15m" and
+// overrides the interval (and optionally priority) of the listed symbols,
+// adding them if they are not already scheduled.
+func ApplyScheduleOverrides(schedules []SymbolSchedule, spec string) ([]SymbolSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return schedules, nil
+	}
+
+	index := make(map[string]int, len(schedules))
+	for i, s := range schedules {
+		index[s.Symbol] = i
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		symbol, rule, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid schedule entry %q", entry)
+		}
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+		intervalStr, priorityStr, hasPriority := strings.Cut(rule, ":")
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid interval for %s: %q", symbol, intervalStr)
+		}
+
+		tier := DefaultTier
+		if i, ok := index[symbol]; ok {
+			tier = schedules[i].Tier
+		}
+		tier.Name = "custom"
+		tier.Interval = interval
+		if hasPriority {
+			priority, err := strconv.Atoi(priorityStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid priority for %s: %q", symbol, priorityStr)
+			}
+			tier.Priority = priority
+		}
+
+		if i, ok := index[symbol]; ok {
+			schedules[i].Tier = tier
+		} else {
+			index[symbol] = len(schedules)
+			schedules = append(schedules, SymbolSchedule{Symbol: symbol, Tier: tier})
+		}
+	}
+	return schedules, nil
+}
+
+type job struct {
+	schedule SymbolSchedule
+	next     time.Time
+	index    int
+	removed  bool
+}
+
+// jobQueue is a min-heap on next run time, ties broken by priority.
+type jobQueue []*job
+
+func (q jobQueue) Len() int { return len(q) }
+func (q jobQueue) Less(i, j int) bool {
+	if q[i].next.Equal(q[j].next) {
+		return q[i].schedule.Tier.Priority > q[j].schedule.Tier.Priority
+	}
+	return q[i].next.Before(q[j].next)
+}
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+func (q *jobQueue) Push(x any) {
+	j := x.(*job)
+	j.index = len(*q)
+	*q = append(*q, j)
+}
+func (q *jobQueue) Pop() any {
+	old := *q
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return j
+}
+
+// Scheduler decides which symbols are due for collection. A job is removed
+// from the queue while it is in flight and put back by Complete, so a slow
+// symbol is never dispatched twice.
+type Scheduler struct {
+	mu       sync.Mutex
+	queue    jobQueue
+	inflight map[string]*job
+
+	// jitter is the fraction of the interval added or removed at random
+	// from each reschedule so symbols in the same tier drift apart.
+	jitter float64
+	rand   *rand.Rand
+
+	// When a provider rate-limits us, nothing is dispatched until
+	// pausedUntil; consecutive rate limits double the pause up to maxBackoff.
+	pausedUntil time.Time
+	backoff     time.Duration
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+}
+
+// NewScheduler staggers the first run of each tier's symbols evenly across
+// the tier interval so a restart doesn't fire the whole universe at once.
+func NewScheduler(schedules []SymbolSchedule, jitter float64, now time.Time) *Scheduler {
+	s := &Scheduler{
+		inflight:   make(map[string]*job),
+		jitter:     jitter,
+		rand:       rand.New(rand.NewSource(now.UnixNano())),
+		minBackoff: 30 * time.Second,
+		maxBackoff: 15 * time.Minute,
+	}
+
+	byTier := make(map[string][]SymbolSchedule)
+	var tierOrder []string
+	for _, sched := range schedules {
+		key := fmt.Sprintf("%s/%s", sched.Tier.Name, sched.Tier.Interval)
+		if _, ok := byTier[key]; !ok {
+			tierOrder = append(tierOrder, key)
+		}
+		byTier[key] = append(byTier[key], sched)
+	}
+
+	for _, key := range tierOrder {
+		members := byTier[key]
+		step := members[0].Tier.Interval / time.Duration(len(members))
+		for i, sched := range members {
+			heap.Push(&s.queue, &job{schedule: sched, next: now.Add(time.Duration(i) * step)})
+		}
+	}
+	return s
+}
+
+// Due removes and returns every job whose next run time has passed and whose
+// active window is open, highest priority first. Jobs that are due but
+// outside their window are pushed to the window's next opening.
+func (s *Scheduler) Due(now time.Time) []SymbolSchedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Before(s.pausedUntil) {
+		return nil
+	}
+
+	var due, closed []*job
+	for s.queue.Len() > 0 && !s.queue[0].next.After(now) {
+		j := heap.Pop(&s.queue).(*job)
+		if !j.schedule.Tier.Window.Contains(now) {
+			closed = append(closed, j)
+			continue
+		}
+		due = append(due, j)
+	}
+	for _, j := range closed {
+		j.next = j.schedule.Tier.Window.NextOpen(now).Add(s.startupSpread(j.schedule.Tier.Interval))
+		heap.Push(&s.queue, j)
+	}
+
+	sort.SliceStable(due, func(a, b int) bool {
+		return due[a].schedule.Tier.Priority > due[b].schedule.Tier.Priority
+	})
+
+	result := make([]SymbolSchedule, len(due))
+	for i, j := range due {
+		s.inflight[j.schedule.Symbol] = j
+		result[i] = j.schedule
+	}
+	return result
+}
+
+// Complete reschedules a dispatched symbol. A rate-limited result pauses the
+// whole scheduler (backpressure); any other result resets the backoff.
+func (s *Scheduler) Complete(symbol string, now time.Time, rateLimited bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.inflight[symbol]
+	if !ok {
+		return
+	}
+	delete(s.inflight, symbol)
+	if j.removed {
+		return
+	}
+
+	if rateLimited {
+		if s.backoff == 0 {
+			s.backoff = s.minBackoff
+		} else {
+			s.backoff = min(s.backoff*2, s.maxBackoff)
+		}
+		s.pausedUntil = now.Add(s.backoff)
+		// Retry the symbol as soon as the pause lifts rather than waiting a
+		// full interval.
+		j.next = s.pausedUntil
+	} else {
+		s.backoff = 0
+		j.next = s.withJitter(now.Add(j.schedule.Tier.Interval), j.schedule.Tier.Interval)
+	}
+	heap.Push(&s.queue, j)
+}
+
+// Reconcile replaces the set of scheduled symbols. New symbols are spread
+// across their tier interval from now, symbols no longer present are dropped
+// (an in-flight one finishes but is not rescheduled) and symbols whose tier
+// changed keep their next run time but use the new tier from then on.
+func (s *Scheduler) Reconcile(schedules []SymbolSchedule, now time.Time) (added, removed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[string]SymbolSchedule, len(schedules))
+	for _, sched := range schedules {
+		wanted[sched.Symbol] = sched
+	}
+
+	// Filter into a new slice and re-heapify: removing from the heap while
+	// walking it by index moves jobs past the walk.
+	kept := make(jobQueue, 0, len(s.queue))
+	for _, j := range s.queue {
+		sched, ok := wanted[j.schedule.Symbol]
+		if !ok {
+			removed++
+			continue
+		}
+		j.schedule = sched
+		j.index = len(kept)
+		kept = append(kept, j)
+		delete(wanted, sched.Symbol)
+	}
+	s.queue = kept
+	heap.Init(&s.queue)
+
+	for symbol, j := range s.inflight {
+		sched, ok := wanted[symbol]
+		if !ok {
+			if !j.removed {
+				j.removed = true
+				removed++
+			}
+			continue
+		}
+		j.schedule = sched
+		j.removed = false
+		delete(wanted, symbol)
+	}
+
+	for _, sched := range schedules {
+		if _, ok := wanted[sched.Symbol]; !ok {
+			continue
+		}
+		delete(wanted, sched.Symbol)
+		heap.Push(&s.queue, &job{schedule: sched, next: now.Add(s.startupSpread(sched.Tier.Interval))})
+		added++
+	}
+	return added, removed
+}
+
+// Len reports how many symbols are scheduled, including in-flight ones.
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.queue.Len()
+	for _, j := range s.inflight {
+		if !j.removed {
+			n++
+		}
+	}
+	return n
+}
+
+// NextWake returns how long the dispatcher can sleep before something may be due.
+func (s *Scheduler) NextWake(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Before(s.pausedUntil) {
+		return s.pausedUntil.Sub(now)
+	}
+	if s.queue.Len() == 0 {
+		return time.Minute
+	}
+	wait := s.queue[0].next.Sub(now)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// PausedUntil reports when the current rate-limit backoff ends.
+func (s *Scheduler) PausedUntil() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pausedUntil
+}
+
+// startupSpread returns a random offset within the interval so symbols
+// don't all fire the moment their window opens.
+func (s *Scheduler) startupSpread(interval time.Duration) time.Duration {
+	return time.Duration(s.rand.Int63n(int64(interval) + 1))
+}
+
+func (s *Scheduler) withJitter(t time.Time, interval time.Duration) time.Time {
+	if s.jitter <= 0 {
+		return t
+	}
+	spread := float64(interval) * s.jitter
+	return t.Add(time.Duration((s.rand.Float64()*2 - 1) * spread))
+}
@@ -0,0 +1,223 @@
+package worker
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// tuesdayAt returns a Tuesday in New York at the given clock time.
+func tuesdayAt(hour, minute int) time.Time {
+	return time.Date(2025, time.March, 4, hour, minute, 0, 0, newYork)
+}
+
+func TestActiveWindow(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"before open", tuesdayAt(9, 29), false},
+		{"at open", tuesdayAt(9, 30), true},
+		{"midday", tuesdayAt(12, 0), true},
+		{"at close", tuesdayAt(16, 0), false},
+		{"saturday", time.Date(2025, time.March, 8, 12, 0, 0, 0, newYork), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarketHours.Contains(tt.at); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+
+	friday := time.Date(2025, time.March, 7, 17, 0, 0, 0, newYork)
+	monday := time.Date(2025, time.March, 10, 9, 30, 0, 0, newYork)
+	if got := MarketHours.NextOpen(friday); !got.Equal(monday) {
+		t.Errorf("NextOpen(friday evening) = %s, want %s", got, monday)
+	}
+}
+
+func TestSchedulerPriorityAndCadence(t *testing.T) {
+	now := tuesdayAt(10, 0)
+	schedules := DefaultSchedules([]string{"AAPL"})
+	s := NewScheduler(schedules, 0, now)
+
+	due := s.Due(now)
+	if len(due) == 0 || due[0].Symbol != "SPY" {
+		t.Fatalf("expected SPY to be dispatched first, got %+v", due)
+	}
+	for _, d := range due {
+		if d.Symbol == "QQQ" {
+			t.Fatalf("QQQ should be staggered behind SPY, got %+v", due)
+		}
+	}
+
+	s.Complete("SPY", now, false)
+	if got := s.Due(now.Add(4 * time.Minute)); containsSymbol(got, "SPY") {
+		t.Errorf("SPY dispatched again before its 5 minute interval")
+	}
+	if got := s.Due(now.Add(5 * time.Minute)); !containsSymbol(got, "SPY") {
+		t.Errorf("SPY not dispatched after its 5 minute interval")
+	}
+}
+
+func TestSchedulerBackpressure(t *testing.T) {
+	now := tuesdayAt(10, 0)
+	s := NewScheduler([]SymbolSchedule{
+		{Symbol: "SPY", Tier: CoreTier},
+	}, 0, now)
+
+	if due := s.Due(now); len(due) != 1 {
+		t.Fatalf("expected SPY to be due, got %+v", due)
+	}
+	s.Complete("SPY", now, true)
+
+	if due := s.Due(now.Add(10 * time.Second)); len(due) != 0 {
+		t.Errorf("expected nothing dispatched while paused, got %+v", due)
+	}
+	if wait := s.NextWake(now); wait != s.minBackoff {
+		t.Errorf("NextWake = %s, want %s", wait, s.minBackoff)
+	}
+	if due := s.Due(now.Add(s.minBackoff)); !containsSymbol(due, "SPY") {
+		t.Errorf("expected SPY retried once the pause lifts, got %+v", due)
+	}
+
+	s.Complete("SPY", now.Add(s.minBackoff), true)
+	if got := s.PausedUntil().Sub(now.Add(s.minBackoff)); got != 2*s.minBackoff {
+		t.Errorf("second rate limit paused for %s, want %s", got, 2*s.minBackoff)
+	}
+}
+
+func TestSchedulerOutsideWindow(t *testing.T) {
+	now := tuesdayAt(8, 0)
+	s := NewScheduler([]SymbolSchedule{{Symbol: "SPY", Tier: CoreTier}}, 0, now)
+
+	if due := s.Due(now); len(due) != 0 {
+		t.Fatalf("expected nothing due before the open, got %+v", due)
+	}
+	if wait := s.NextWake(now); wait < 90*time.Minute {
+		t.Errorf("expected SPY pushed to the open, next wake in %s", wait)
+	}
+}
+
+func TestApplyScheduleOverrides(t *testing.T) {
+	schedules, err := ApplyScheduleOverrides(DefaultSchedules([]string{"AAPL"}), "aapl=15m:75, TSLA=20m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bySymbol := make(map[string]Tier)
+	for _, s := range schedules {
+		bySymbol[s.Symbol] = s.Tier
+	}
+	if tier := bySymbol["AAPL"]; tier.Interval != 15*time.Minute || tier.Priority != 75 {
+		t.Errorf("AAPL tier = %+v", tier)
+	}
+	if tier, ok := bySymbol["TSLA"]; !ok || tier.Interval != 20*time.Minute {
+		t.Errorf("TSLA tier = %+v, present %v", tier, ok)
+	}
+
+	if _, err := ApplyScheduleOverrides(nil, "SPY=fast"); err == nil {
+		t.Error("expected an error for an invalid interval")
+	}
+}
+
+func containsSymbol(schedules []SymbolSchedule, symbol string) bool {
+	for _, s := range schedules {
+		if s.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSchedulerReconcile(t *testing.T) {
+	now := tuesdayAt(10, 0)
+	s := NewScheduler([]SymbolSchedule{
+		{Symbol: "AAPL", Tier: DefaultTier},
+		{Symbol: "MSFT", Tier: DefaultTier},
+	}, 0, now)
+
+	// AAPL is in flight when the universe changes.
+	if due := s.Due(now); !containsSymbol(due, "AAPL") {
+		t.Fatalf("expected AAPL to be due, got %+v", due)
+	}
+
+	added, removed := s.Reconcile([]SymbolSchedule{
+		{Symbol: "MSFT", Tier: CoreTier},
+		{Symbol: "NVDA", Tier: DefaultTier},
+	}, now)
+	if added != 1 || removed != 1 {
+		t.Fatalf("Reconcile = (%d added, %d removed), want (1, 1)", added, removed)
+	}
+	if got := s.Len(); got != 2 {
+		t.Errorf("Len = %d, want 2", got)
+	}
+
+	// The removed in-flight symbol must not come back once it completes.
+	s.Complete("AAPL", now, false)
+	later := now.Add(2 * time.Hour)
+	due := s.Due(later)
+	if containsSymbol(due, "AAPL") {
+		t.Errorf("AAPL rescheduled after being removed: %+v", due)
+	}
+	if !containsSymbol(due, "MSFT") || !containsSymbol(due, "NVDA") {
+		t.Errorf("expected MSFT and NVDA to be due, got %+v", due)
+	}
+}
+
+func TestSchedulerReconcileRandomized(t *testing.T) {
+	tiers := []Tier{CoreTier, IndexETFTier, DefaultTier}
+	for seed := int64(0); seed < 200; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		now := tuesdayAt(10, 0)
+
+		var initial []SymbolSchedule
+		for i := 0; i < 4+r.Intn(12); i++ {
+			initial = append(initial, SymbolSchedule{Symbol: fmt.Sprintf("S%d", i), Tier: tiers[r.Intn(len(tiers))]})
+		}
+		s := NewScheduler(initial, 0.2, now)
+		// Put some symbols in flight.
+		s.Due(now.Add(time.Duration(r.Intn(10)) * time.Minute))
+
+		var wanted []SymbolSchedule
+		for _, sched := range initial {
+			if r.Intn(2) == 0 {
+				sched.Tier = tiers[r.Intn(len(tiers))]
+				wanted = append(wanted, sched)
+			}
+		}
+		for i := 0; i < r.Intn(4); i++ {
+			wanted = append(wanted, SymbolSchedule{Symbol: fmt.Sprintf("N%d", i), Tier: DefaultTier})
+		}
+		s.Reconcile(wanted, now)
+
+		seen := make(map[string]int)
+		for i, j := range s.queue {
+			if j.index != i {
+				t.Errorf("seed %d: %s has index %d at %d", seed, j.schedule.Symbol, j.index, i)
+			}
+			seen[j.schedule.Symbol]++
+		}
+		for symbol, j := range s.inflight {
+			if !j.removed {
+				seen[symbol]++
+			}
+		}
+		for _, sched := range wanted {
+			if seen[sched.Symbol] != 1 {
+				t.Errorf("seed %d: %s scheduled %d times", seed, sched.Symbol, seen[sched.Symbol])
+			}
+			delete(seen, sched.Symbol)
+		}
+		for symbol := range seen {
+			t.Errorf("seed %d: dropped symbol %s still scheduled", seed, symbol)
+		}
+		if s.Len() != len(wanted) {
+			t.Errorf("seed %d: Len = %d, want %d", seed, s.Len(), len(wanted))
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/handler"
+	"github.com/arnabmitra/eth-proxy/internal/universe"
+)
+
+// SnapshotRefresher keeps the all-expiry GEX snapshots of a set of universes
+// fresh, so the pages that show them never wait on the provider. Symbols
+// outside those universes are refreshed on demand when a page asks for them.
+type SnapshotRefresher struct {
+	gexHandler    *handler.GEXHandler
+	universes     *universe.Store
+	universeSlugs []string
+	logger        *slog.Logger
+	interval      time.Duration
+	stop          chan struct{}
+}
+
+// NewSnapshotRefresher creates a refresher for the members of universeSlugs.
+// It runs twice per snapshot TTL so warm snapshots are never served stale.
+func NewSnapshotRefresher(gexHandler *handler.GEXHandler, universes *universe.Store, universeSlugs []string, logger *slog.Logger) *SnapshotRefresher {
+	return &SnapshotRefresher{
+		gexHandler:    gexHandler,
+		universes:     universes,
+		universeSlugs: universeSlugs,
+		logger:        logger,
+		interval:      gexHandler.SnapshotTTL() / 2,
+		stop:          make(chan struct{}),
+	}
+}
+
+func (r *SnapshotRefresher) Start() {
+	// Stopping the refresher cancels the symbol in flight and ends the run.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-r.stop
+		cancel()
+	}()
+
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.refresh(ctx)
+
+		for {
+			select {
+			case <-ticker.C:
+				r.refresh(ctx)
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (r *SnapshotRefresher) Stop() {
+	close(r.stop)
+}
+
+// symbolTimeout bounds the wait for one symbol's refresh, so a slow or
+// throttled symbol costs the run that long and no more. It matches the
+// handler's own bound on the calculation.
+const symbolTimeout = 2 * time.Minute
+
+// refresh recalculates each symbol in turn, so a run costs the provider one
+// symbol's calls at a time however many symbols there are. A symbol that
+// fails is logged and skipped; only shutdown ends a run early.
+func (r *SnapshotRefresher) refresh(ctx context.Context) {
+	loadCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	symbols, err := r.universes.Union(loadCtx, r.universeSlugs)
+	cancel()
+	if err != nil {
+		r.logger.Error("failed to load snapshot universes", "universes", r.universeSlugs, "error", err)
+		return
+	}
+
+	start := time.Now()
+	failed := 0
+	for _, symbol := range symbols {
+		if ctx.Err() != nil {
+			return
+		}
+		symbolCtx, cancel := context.WithTimeout(ctx, symbolTimeout)
+		err := r.gexHandler.RefreshSnapshot(symbolCtx, symbol)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			failed++
+			r.logger.Warn("GEX snapshot refresh failed", "symbol", symbol, "error", err)
+		}
+	}
+	r.logger.Info("refreshed GEX snapshots", "symbols", len(symbols), "failed", failed, "duration", time.Since(start))
+}
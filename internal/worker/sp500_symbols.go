@@ -2,6 +2,8 @@ package worker
 
 // SP500Symbols returns the list of S&P 500 stock symbols
 // This is a subset for now - can be expanded to full 500
+// The "sp500" universe in the database is seeded from this list; the collector
+// only falls back to it when the universes can't be read.
 func SP500Symbols() []string {
 	return []string{
 		// Top weighted stocks in S&P 500
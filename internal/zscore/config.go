@@ -0,0 +1,184 @@
+package zscore
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Method is how a value is compared with its lookback window.
+type Method string
+
+const (
+	// MethodStdDev is the plain (x - mean) / stddev over daily snapshots.
+	MethodStdDev Method = "stddev"
+	// MethodMAD uses the median and the median absolute deviation, scaled so
+	// it matches the standard deviation for normal data. Robust to outliers.
+	MethodMAD Method = "mad"
+	// MethodEWMA weights recent days more heavily, with the weight halving
+	// every HalfLifeDays.
+	MethodEWMA Method = "ewma"
+	// MethodPercentile ranks the value within the window and maps the rank to
+	// the normal quantile, so |score| thresholds mean the same as for the
+	// other methods.
+	MethodPercentile Method = "percentile"
+)
+
+// Normalization scales GEX before scoring.
+type Normalization string
+
+const (
+	NormNone Normalization = "none"
+	// NormMarketCap divides GEX by market cap, moved with the share price
+	// from the stored market cap. Symbols without a market cap are not scored.
+	NormMarketCap Normalization = "market_cap"
+	// NormOpenInterest divides GEX by the total open interest of the stored
+	// chain, giving GEX per open contract.
+	NormOpenInterest Normalization = "open_interest"
+)
+
+// Lookback and half-life bounds, in days.
+const (
+	MinLookbackDays = 5
+	MaxLookbackDays = 365
+)
+
+// Config selects the scoring method. Zero fields mean "use the default", so
+// a Config parsed from a request can be layered over a configured default
+// with WithDefaults.
+type Config struct {
+	Method       Method        `json:"method"`
+	LookbackDays int           `json:"lookback_days"`
+	Normalize    Normalization `json:"normalize"`
+	// HalfLifeDays only applies to MethodEWMA.
+	HalfLifeDays int `json:"half_life_days,omitempty"`
+}
+
+// Default is the original behaviour: mean and standard deviation of 30 days
+// of daily snapshots.
+func Default() Config {
+	return Config{
+		Method:       MethodStdDev,
+		LookbackDays: 30,
+		Normalize:    NormNone,
+		HalfLifeDays: 10,
+	}
+}
+
+// FromEnv returns Default overridden by ZSCORE_METHOD, ZSCORE_LOOKBACK_DAYS,
+// ZSCORE_NORMALIZE and ZSCORE_HALF_LIFE_DAYS. Invalid values are reported and
+// the default kept.
+func FromEnv() (Config, error) {
+	q := url.Values{}
+	for key, env := range map[string]string{
+		"z_method":   "ZSCORE_METHOD",
+		"z_lookback": "ZSCORE_LOOKBACK_DAYS",
+		"z_norm":     "ZSCORE_NORMALIZE",
+		"z_halflife": "ZSCORE_HALF_LIFE_DAYS",
+	} {
+		if v := strings.TrimSpace(os.Getenv(env)); v != "" {
+			q.Set(key, v)
+		}
+	}
+	cfg, err := Parse(q)
+	return cfg.WithDefaults(Default()), err
+}
+
+// Parse reads z_method, z_lookback, z_norm and z_halflife from q. Fields that
+// are missing or invalid are left zero; the first invalid one is reported.
+func Parse(q url.Values) (Config, error) {
+	var cfg Config
+	var errs []error
+
+	switch m := Method(q.Get("z_method")); m {
+	case "":
+	case MethodStdDev, MethodMAD, MethodEWMA, MethodPercentile:
+		cfg.Method = m
+	default:
+		errs = append(errs, fmt.Errorf("unknown z-score method %q", m))
+	}
+
+	switch n := Normalization(q.Get("z_norm")); n {
+	case "":
+	case NormNone, NormMarketCap, NormOpenInterest:
+		cfg.Normalize = n
+	default:
+		errs = append(errs, fmt.Errorf("unknown z-score normalization %q", n))
+	}
+
+	if v := q.Get("z_lookback"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < MinLookbackDays || days > MaxLookbackDays {
+			errs = append(errs, fmt.Errorf("z-score lookback must be %d to %d days", MinLookbackDays, MaxLookbackDays))
+		} else {
+			cfg.LookbackDays = days
+		}
+	}
+
+	if v := q.Get("z_halflife"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 || days > MaxLookbackDays {
+			errs = append(errs, fmt.Errorf("z-score half-life must be 1 to %d days", MaxLookbackDays))
+		} else {
+			cfg.HalfLifeDays = days
+		}
+	}
+
+	if len(errs) > 0 {
+		return cfg, errs[0]
+	}
+	return cfg, nil
+}
+
+// WithDefaults fills the zero fields of c from def.
+func (c Config) WithDefaults(def Config) Config {
+	if c.Method == "" {
+		c.Method = def.Method
+	}
+	if c.LookbackDays == 0 {
+		c.LookbackDays = def.LookbackDays
+	}
+	if c.Normalize == "" {
+		c.Normalize = def.Normalize
+	}
+	if c.HalfLifeDays == 0 {
+		c.HalfLifeDays = def.HalfLifeDays
+	}
+	return c
+}
+
+// Values encodes the non-zero fields of c as query parameters.
+func (c Config) Values() url.Values {
+	q := url.Values{}
+	if c.Method != "" {
+		q.Set("z_method", string(c.Method))
+	}
+	if c.LookbackDays != 0 {
+		q.Set("z_lookback", strconv.Itoa(c.LookbackDays))
+	}
+	if c.Normalize != "" {
+		q.Set("z_norm", string(c.Normalize))
+	}
+	if c.HalfLifeDays != 0 {
+		q.Set("z_halflife", strconv.Itoa(c.HalfLifeDays))
+	}
+	return q
+}
+
+// String describes the method, e.g. "mad, 60d, per open contract".
+func (c Config) String() string {
+	parts := []string{string(c.Method)}
+	if c.Method == MethodEWMA {
+		parts[0] = fmt.Sprintf("ewma (half-life %dd)", c.HalfLifeDays)
+	}
+	parts = append(parts, fmt.Sprintf("%dd", c.LookbackDays))
+	switch c.Normalize {
+	case NormMarketCap:
+		parts = append(parts, "per market cap")
+	case NormOpenInterest:
+		parts = append(parts, "per open contract")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,6 @@
+// Package zscore scores how unusual a symbol's gamma exposure is against its
+// own recent history. The lookback window, the method (standard deviation,
+// median/MAD, EWMA or percentile rank) and an optional normalization by
+// market cap or open interest are configurable, and every score carries a
+// label of the method that produced it.
+package zscore
@@ -0,0 +1,152 @@
+package zscore
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// madScale makes the median absolute deviation comparable to the standard
+// deviation for normally distributed data.
+const madScale = 1.4826
+
+// point is one scored value and its age in days relative to the value being
+// scored.
+type point struct {
+	value float64
+	age   float64
+}
+
+// score compares the last point with all points in the window, itself
+// included. It needs at least two points and returns 0 when the window has
+// no spread.
+func score(cfg Config, window []point) (float64, bool) {
+	if len(window) < 2 {
+		return 0, false
+	}
+	x := window[len(window)-1].value
+	if flat(window) {
+		return 0, true
+	}
+
+	switch cfg.Method {
+	case MethodMAD:
+		values := make([]float64, len(window))
+		for i, p := range window {
+			values[i] = p.value
+		}
+		med := median(values)
+		for i := range values {
+			values[i] = math.Abs(values[i] - med)
+		}
+		return ratio(x-med, madScale*median(values)), true
+
+	case MethodEWMA:
+		halfLife := float64(cfg.HalfLifeDays)
+		if halfLife <= 0 {
+			halfLife = float64(Default().HalfLifeDays)
+		}
+		var sumW, sumWX float64
+		weights := make([]float64, len(window))
+		for i, p := range window {
+			weights[i] = math.Pow(0.5, p.age/halfLife)
+			sumW += weights[i]
+			sumWX += weights[i] * p.value
+		}
+		mean := sumWX / sumW
+		var sumWD float64
+		for i, p := range window {
+			sumWD += weights[i] * (p.value - mean) * (p.value - mean)
+		}
+		return ratio(x-mean, math.Sqrt(sumWD/sumW)), true
+
+	case MethodPercentile:
+		var below, equal float64
+		for _, p := range window {
+			switch {
+			case p.value < x:
+				below++
+			case p.value == x:
+				equal++
+			}
+		}
+		// x is always in the window, so the rank stays strictly inside (0, 1).
+		rank := (below + equal/2) / float64(len(window))
+		return math.Sqrt2 * math.Erfinv(2*rank-1), true
+
+	default:
+		var sum float64
+		for _, p := range window {
+			sum += p.value
+		}
+		mean := sum / float64(len(window))
+		var ss float64
+		for _, p := range window {
+			ss += (p.value - mean) * (p.value - mean)
+		}
+		return ratio(x-mean, math.Sqrt(ss/float64(len(window)-1))), true
+	}
+}
+
+// flat reports whether every point has the same value. Weighted means can
+// pick up rounding error, so this is checked up front rather than relying on
+// a zero spread.
+func flat(window []point) bool {
+	for _, p := range window {
+		if p.value != window[0].value {
+			return false
+		}
+	}
+	return true
+}
+
+func ratio(num, den float64) float64 {
+	if den == 0 {
+		return 0
+	}
+	return num / den
+}
+
+// median sorts values in place and returns their median.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+// FormatScore writes score in the unit of method: standard deviations for
+// stddev and ewma, scaled MADs for mad and the window percentile the score
+// was mapped from for percentile, e.g. "2.63σ", "3.10 MAD", "99th pct".
+func FormatScore(method Method, score float64) string {
+	switch method {
+	case MethodMAD:
+		return fmt.Sprintf("%.2f MAD", score)
+	case MethodPercentile:
+		// Invert the normal quantile back to the rank; the rank is never 0
+		// or 1, so neither is the rounded percentile.
+		pct := int(math.Round(50 * (1 + math.Erf(score/math.Sqrt2))))
+		pct = min(max(pct, 1), 99)
+		return ordinal(pct) + " pct"
+	default:
+		return fmt.Sprintf("%.2fσ", score)
+	}
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
@@ -0,0 +1,263 @@
+package zscore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Source is the part of the repository the scorer reads from.
+type Source interface {
+	ListDailyGEXSnapshots(ctx context.Context, arg repository.ListDailyGEXSnapshotsParams) ([]repository.ListDailyGEXSnapshotsRow, error)
+	ListGEXSnapshotsForSymbol(ctx context.Context, arg repository.ListGEXSnapshotsForSymbolParams) ([]repository.ListGEXSnapshotsForSymbolRow, error)
+	ListSymbolMetadata(ctx context.Context) ([]repository.SymbolMetadatum, error)
+}
+
+// Result is the score of a symbol's latest snapshot.
+type Result struct {
+	Symbol string  `json:"symbol"`
+	Score  float64 `json:"zscore"`
+	// GEX and Spot are the raw values of the scored snapshot; Value is the
+	// GEX after normalization.
+	GEX        float64   `json:"gex"`
+	Value      float64   `json:"value"`
+	Spot       float64   `json:"spot"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Observations is the number of snapshots in the window.
+	Observations int    `json:"observations"`
+	Method       string `json:"method"`
+}
+
+// Point is one entry of a symbol's Z-score history.
+type Point struct {
+	Time         time.Time `json:"time"`
+	Score        float64   `json:"zscore"`
+	Value        float64   `json:"value"`
+	Observations int       `json:"observations"`
+	Method       string    `json:"method"`
+}
+
+// Scorer computes Z-scores from the stored GEX history.
+type Scorer struct {
+	src Source
+	loc *time.Location
+}
+
+func NewScorer(src Source) *Scorer {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.UTC
+	}
+	return &Scorer{src: src, loc: loc}
+}
+
+// sample is a snapshot with its normalized value.
+type sample struct {
+	at    time.Time
+	gex   float64
+	spot  float64
+	oi    float64
+	value float64
+}
+
+// Latest scores the latest snapshot of every symbol against the daily
+// closes in the lookback window before now. Symbols with fewer than two
+// usable snapshots, or no market cap when normalizing by it, are left out.
+func (s *Scorer) Latest(ctx context.Context, cfg Config, now time.Time) (map[string]Result, error) {
+	cfg = cfg.WithDefaults(Default())
+	rows, err := s.src.ListDailyGEXSnapshots(ctx, repository.ListDailyGEXSnapshotsParams{
+		WithOpenInterest: cfg.Normalize == NormOpenInterest,
+		FromTime:         now.AddDate(0, 0, -cfg.LookbackDays),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list daily snapshots: %w", err)
+	}
+	caps, err := s.marketCaps(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	bySymbol := make(map[string][]sample)
+	for _, row := range rows {
+		bySymbol[row.Symbol] = append(bySymbol[row.Symbol], newSample(row.RecordedAt, row.GexValue, row.SpotPrice, row.OpenInterest))
+	}
+
+	results := make(map[string]Result, len(bySymbol))
+	for symbol, series := range bySymbol {
+		last := series[len(series)-1]
+		series = normalize(cfg, series, caps[symbol])
+		if len(series) == 0 || !series[len(series)-1].at.Equal(last.at) {
+			continue
+		}
+		last = series[len(series)-1]
+		z, ok := score(cfg, window(series, last.at))
+		if !ok {
+			continue
+		}
+		results[symbol] = Result{
+			Symbol:       symbol,
+			Score:        z,
+			GEX:          last.gex,
+			Value:        last.value,
+			Spot:         last.spot,
+			RecordedAt:   last.at,
+			Observations: len(series),
+			Method:       cfg.String(),
+		}
+	}
+	return results, nil
+}
+
+// History scores every snapshot of symbol since from. Each snapshot is
+// compared with the daily closes of the lookback window before its own
+// trading day, so the history shows how unusual each reading was at the time.
+func (s *Scorer) History(ctx context.Context, cfg Config, symbol string, from time.Time) ([]Point, error) {
+	cfg = cfg.WithDefaults(Default())
+	rows, err := s.src.ListGEXSnapshotsForSymbol(ctx, repository.ListGEXSnapshotsForSymbolParams{
+		WithOpenInterest: cfg.Normalize == NormOpenInterest,
+		Symbol:           symbol,
+		FromTime:         from.AddDate(0, 0, -cfg.LookbackDays),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list snapshots for %s: %w", symbol, err)
+	}
+	caps, err := s.marketCaps(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]sample, 0, len(rows))
+	for _, row := range rows {
+		series = append(series, newSample(row.RecordedAt, row.GexValue, row.SpotPrice, row.OpenInterest))
+	}
+	series = normalize(cfg, series, caps[symbol])
+
+	// Daily closes: the last sample of each New York trading day.
+	var closes []sample
+	for i, smp := range series {
+		if i+1 == len(series) || s.day(series[i+1].at) != s.day(smp.at) {
+			closes = append(closes, smp)
+		}
+	}
+
+	method := cfg.String()
+	points := make([]Point, 0, len(series))
+	start := 0
+	for _, smp := range series {
+		if smp.at.Before(from) {
+			continue
+		}
+		cutoff := smp.at.AddDate(0, 0, -cfg.LookbackDays)
+		for start < len(closes) && closes[start].at.Before(cutoff) {
+			start++
+		}
+		var prior []sample
+		for _, c := range closes[start:] {
+			if s.day(c.at) >= s.day(smp.at) {
+				break
+			}
+			prior = append(prior, c)
+		}
+		win := append(prior, smp)
+		z, ok := score(cfg, window(win, smp.at))
+		if !ok {
+			continue
+		}
+		points = append(points, Point{
+			Time:         smp.at,
+			Score:        z,
+			Value:        smp.value,
+			Observations: len(win),
+			Method:       method,
+		})
+	}
+	return points, nil
+}
+
+func (s *Scorer) day(t time.Time) string {
+	return t.In(s.loc).Format(time.DateOnly)
+}
+
+// marketCaps returns the stored market caps when cfg normalizes by them.
+func (s *Scorer) marketCaps(ctx context.Context, cfg Config) (map[string]float64, error) {
+	if cfg.Normalize != NormMarketCap {
+		return nil, nil
+	}
+	metadata, err := s.src.ListSymbolMetadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list symbol metadata: %w", err)
+	}
+	caps := make(map[string]float64, len(metadata))
+	for _, m := range metadata {
+		if m.MarketCap.Valid && m.MarketCap.Int64 > 0 {
+			caps[m.Symbol] = float64(m.MarketCap.Int64)
+		}
+	}
+	return caps, nil
+}
+
+func newSample(at time.Time, gex pgtype.Numeric, spot pgtype.Text, oi pgtype.Numeric) sample {
+	smp := sample{at: at}
+	if f, err := gex.Float64Value(); err == nil {
+		smp.gex = f.Float64
+	}
+	if spot.Valid {
+		smp.spot, _ = strconv.ParseFloat(spot.String, 64)
+	}
+	if f, err := oi.Float64Value(); err == nil {
+		smp.oi = f.Float64
+	}
+	return smp
+}
+
+// normalize sets the value of each sample and drops samples that cannot be
+// normalized. Market caps are stored once, so the cap at each snapshot is
+// estimated by moving marketCap with the spot price relative to the latest
+// snapshot.
+func normalize(cfg Config, series []sample, marketCap float64) []sample {
+	out := series[:0:0]
+	switch cfg.Normalize {
+	case NormMarketCap:
+		if marketCap <= 0 || len(series) == 0 {
+			return nil
+		}
+		ref := series[len(series)-1].spot
+		if ref <= 0 {
+			return nil
+		}
+		for _, smp := range series {
+			if smp.spot <= 0 {
+				continue
+			}
+			smp.value = smp.gex / (marketCap * smp.spot / ref)
+			out = append(out, smp)
+		}
+	case NormOpenInterest:
+		for _, smp := range series {
+			if smp.oi <= 0 {
+				continue
+			}
+			smp.value = smp.gex / smp.oi
+			out = append(out, smp)
+		}
+	default:
+		for _, smp := range series {
+			smp.value = smp.gex
+			out = append(out, smp)
+		}
+	}
+	return out
+}
+
+// window converts samples to points aged relative to at.
+func window(series []sample, at time.Time) []point {
+	out := make([]point, len(series))
+	for i, smp := range series {
+		out[i] = point{value: smp.value, age: at.Sub(smp.at).Hours() / 24}
+	}
+	return out
+}
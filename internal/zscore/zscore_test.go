@@ -0,0 +1,238 @@
+package zscore
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/arnabmitra/eth-proxy/internal/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func points(values ...float64) []point {
+	out := make([]point, len(values))
+	for i, v := range values {
+		out[i] = point{value: v, age: float64(len(values) - 1 - i)}
+	}
+	return out
+}
+
+func TestScoreMethods(t *testing.T) {
+	values := points(10, 12, 11, 13, 9, 10, 30)
+
+	tests := []struct {
+		method Method
+		want   float64
+	}{
+		// mean 95/7, sample stddev 7.3679
+		{MethodStdDev, 2.2298},
+		// median 11, MAD 1 * 1.4826
+		{MethodMAD, 12.8153},
+		// the largest of 7 values: rank 6.5/7
+		{MethodPercentile, 1.4652},
+	}
+	for _, tt := range tests {
+		z, ok := score(Config{Method: tt.method}, values)
+		if !ok || math.Abs(z-tt.want) > 1e-3 {
+			t.Errorf("%s score = %.4f, %v; want %.4f", tt.method, z, ok, tt.want)
+		}
+	}
+
+	// With a very long half-life EWMA matches the population standard deviation.
+	z, _ := score(Config{Method: MethodEWMA, HalfLifeDays: 1e6}, values)
+	if want := 2.2298 * math.Sqrt(7.0/6.0); math.Abs(z-want) > 1e-3 {
+		t.Errorf("ewma with flat weights = %.4f, want %.4f", z, want)
+	}
+	// A short half-life weights the spike itself heavily, so it looks less unusual.
+	short, _ := score(Config{Method: MethodEWMA, HalfLifeDays: 1}, values)
+	if short >= z {
+		t.Errorf("ewma with 1d half-life = %.4f, want below %.4f", short, z)
+	}
+
+	if _, ok := score(Config{Method: MethodStdDev}, points(1)); ok {
+		t.Error("scored a single value")
+	}
+	for _, m := range []Method{MethodStdDev, MethodMAD, MethodEWMA, MethodPercentile} {
+		if z, ok := score(Config{Method: m, HalfLifeDays: 10}, points(5, 5, 5)); !ok || z != 0 {
+			t.Errorf("%s on a flat window = %v, %v; want 0", m, z, ok)
+		}
+	}
+}
+
+func TestFormatScore(t *testing.T) {
+	tests := []struct {
+		method Method
+		score  float64
+		want   string
+	}{
+		{MethodStdDev, 2.634, "2.63σ"},
+		{MethodEWMA, -3, "-3.00σ"},
+		{MethodMAD, 3.1, "3.10 MAD"},
+		{MethodPercentile, 2.054, "98th pct"},
+		{MethodPercentile, -2.5, "1st pct"},
+		{MethodPercentile, 0, "50th pct"},
+		{MethodPercentile, 4, "99th pct"},
+	}
+	for _, tt := range tests {
+		if got := FormatScore(tt.method, tt.score); got != tt.want {
+			t.Errorf("FormatScore(%s, %v) = %q, want %q", tt.method, tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	q, _ := url.ParseQuery("z_method=mad&z_lookback=60&z_norm=open_interest&z_halflife=5")
+	cfg, err := Parse(q)
+	want := Config{Method: MethodMAD, LookbackDays: 60, Normalize: NormOpenInterest, HalfLifeDays: 5}
+	if err != nil || cfg != want {
+		t.Fatalf("Parse = %+v, %v; want %+v", cfg, err, want)
+	}
+	if back, _ := Parse(cfg.Values()); back != cfg {
+		t.Errorf("round trip = %+v, want %+v", back, cfg)
+	}
+	if got := cfg.String(); got != "mad, 60d, per open contract" {
+		t.Errorf("String = %q", got)
+	}
+
+	q, _ = url.ParseQuery("z_method=magic&z_lookback=2&z_norm=market_cap")
+	cfg, err = Parse(q)
+	if err == nil {
+		t.Error("invalid method and lookback were not reported")
+	}
+	if cfg != (Config{Normalize: NormMarketCap}) {
+		t.Errorf("Parse kept invalid values: %+v", cfg)
+	}
+	if got := cfg.WithDefaults(Default()); got.Method != MethodStdDev || got.LookbackDays != 30 || got.Normalize != NormMarketCap {
+		t.Errorf("WithDefaults = %+v", got)
+	}
+	if got := Default().WithDefaults(Config{Method: MethodEWMA}).String(); got != "stddev, 30d" {
+		t.Errorf("Default().String() = %q", got)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("ZSCORE_METHOD", "ewma")
+	t.Setenv("ZSCORE_HALF_LIFE_DAYS", "7")
+	t.Setenv("ZSCORE_LOOKBACK_DAYS", "lots")
+	cfg, err := FromEnv()
+	if err == nil {
+		t.Error("invalid lookback was not reported")
+	}
+	want := Config{Method: MethodEWMA, LookbackDays: 30, Normalize: NormNone, HalfLifeDays: 7}
+	if cfg != want {
+		t.Errorf("FromEnv = %+v, want %+v", cfg, want)
+	}
+	if got := cfg.String(); got != "ewma (half-life 7d), 30d" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+type fakeSource struct {
+	daily    []repository.ListDailyGEXSnapshotsRow
+	snaps    []repository.ListGEXSnapshotsForSymbolRow
+	metadata []repository.SymbolMetadatum
+	from     time.Time
+}
+
+func (f *fakeSource) ListDailyGEXSnapshots(_ context.Context, arg repository.ListDailyGEXSnapshotsParams) ([]repository.ListDailyGEXSnapshotsRow, error) {
+	f.from = arg.FromTime
+	return f.daily, nil
+}
+
+func (f *fakeSource) ListGEXSnapshotsForSymbol(_ context.Context, arg repository.ListGEXSnapshotsForSymbolParams) ([]repository.ListGEXSnapshotsForSymbolRow, error) {
+	f.from = arg.FromTime
+	return f.snaps, nil
+}
+
+func (f *fakeSource) ListSymbolMetadata(context.Context) ([]repository.SymbolMetadatum, error) {
+	return f.metadata, nil
+}
+
+func numeric(v float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	n.Scan(strconv.FormatFloat(v, 'f', -1, 64))
+	return n
+}
+
+func spot(v float64) pgtype.Text {
+	return pgtype.Text{String: strconv.FormatFloat(v, 'f', -1, 64), Valid: true}
+}
+
+func TestScorerLatest(t *testing.T) {
+	now := time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC)
+	src := &fakeSource{}
+	for i, gex := range []float64{100, 110, 90, 105, 95, 200} {
+		at := now.AddDate(0, 0, i-5)
+		src.daily = append(src.daily,
+			repository.ListDailyGEXSnapshotsRow{Symbol: "AAA", RecordedAt: at, GexValue: numeric(gex), SpotPrice: spot(50), OpenInterest: numeric(10)},
+		)
+	}
+	// A single snapshot can't be scored.
+	src.daily = append(src.daily, repository.ListDailyGEXSnapshotsRow{Symbol: "BBB", RecordedAt: now, GexValue: numeric(1), SpotPrice: spot(10)})
+
+	scores, err := NewScorer(src).Latest(context.Background(), Config{LookbackDays: 10}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !src.from.Equal(now.AddDate(0, 0, -10)) {
+		t.Errorf("queried from %v", src.from)
+	}
+	if _, ok := scores["BBB"]; ok || len(scores) != 1 {
+		t.Fatalf("scored %v", scores)
+	}
+	r := scores["AAA"]
+	if math.Abs(r.Score-2.0113) > 1e-3 || r.GEX != 200 || r.Spot != 50 || r.Observations != 6 || r.Method != "stddev, 10d" {
+		t.Errorf("AAA = %+v", r)
+	}
+
+	// Normalizing by market cap needs one; dividing by a constant leaves the score alone.
+	src.metadata = []repository.SymbolMetadatum{{Symbol: "AAA", MarketCap: pgtype.Int8{Int64: 1e9, Valid: true}}}
+	scores, _ = NewScorer(src).Latest(context.Background(), Config{LookbackDays: 10, Normalize: NormMarketCap}, now)
+	if got := scores["AAA"]; math.Abs(got.Score-r.Score) > 1e-9 || got.Value != 200/1e9 || got.Method != "stddev, 10d, per market cap" {
+		t.Errorf("market cap normalized AAA = %+v", got)
+	}
+}
+
+func TestScorerHistory(t *testing.T) {
+	loc, _ := time.LoadLocation("America/New_York")
+	day := func(d, hour int) time.Time { return time.Date(2026, 10, d, hour, 0, 0, 0, loc) }
+
+	src := &fakeSource{snaps: []repository.ListGEXSnapshotsForSymbolRow{
+		{RecordedAt: day(12, 10), GexValue: numeric(50), SpotPrice: spot(100), OpenInterest: numeric(5)},
+		{RecordedAt: day(12, 15), GexValue: numeric(100), SpotPrice: spot(100), OpenInterest: numeric(10)},
+		{RecordedAt: day(13, 15), GexValue: numeric(120), SpotPrice: spot(100), OpenInterest: numeric(10)},
+		{RecordedAt: day(14, 15), GexValue: numeric(80), SpotPrice: spot(100), OpenInterest: numeric(0)},
+		{RecordedAt: day(15, 10), GexValue: numeric(300), SpotPrice: spot(100), OpenInterest: numeric(10)},
+		{RecordedAt: day(15, 15), GexValue: numeric(90), SpotPrice: spot(100), OpenInterest: numeric(10)},
+	}}
+	from := day(14, 0)
+
+	points, err := NewScorer(src).History(context.Background(), Config{LookbackDays: 5}, "AAA", from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !src.from.Equal(from.AddDate(0, 0, -5)) {
+		t.Errorf("queried from %v", src.from)
+	}
+	if len(points) != 3 {
+		t.Fatalf("got %d points, want 3", len(points))
+	}
+	// Each snapshot is scored against the closes of earlier days only, so the
+	// 10:00 spike on the 15th doesn't leak into the 15:00 score.
+	wantObs := []int{3, 4, 4}
+	wantZ := []float64{-1, 1.4804, -0.4392}
+	for i, p := range points {
+		if p.Observations != wantObs[i] || math.Abs(p.Score-wantZ[i]) > 1e-3 || p.Method != "stddev, 5d" {
+			t.Errorf("point %d = %+v, want %d observations and z %.4f", i, p, wantObs[i], wantZ[i])
+		}
+	}
+
+	// Snapshots without open interest are skipped when normalizing by it.
+	points, _ = NewScorer(src).History(context.Background(), Config{LookbackDays: 5, Normalize: NormOpenInterest}, "AAA", from)
+	if len(points) != 2 || points[0].Value != 30 || points[1].Value != 9 {
+		t.Errorf("open interest history = %+v", points)
+	}
+}
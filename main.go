@@ -16,6 +16,16 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
+	// Maintenance subcommands, e.g. `eth-proxy collect -symbols SPY` or
+	// `eth-proxy backfill -from 2026-01-01`, run once and exit.
+	if len(os.Args) > 1 {
+		if err := app.RunCommand(ctx, logger, os.Args[1:]); err != nil {
+			logger.Error("command failed", slog.String("command", os.Args[1]), slog.Any("error", err))
+			os.Exit(1)
+		}
+		return
+	}
+
 	a := app.New(logger)
 
 	if err := a.Start(ctx); err != nil {
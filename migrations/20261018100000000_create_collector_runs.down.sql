@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS collector_run_symbols;
+DROP TABLE IF EXISTS collector_runs;
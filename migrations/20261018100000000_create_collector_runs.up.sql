@@ -0,0 +1,27 @@
+-- Ledger of GEX collector runs and the per-symbol attempts made in each one
+CREATE TABLE collector_runs (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    trigger varchar(20) NOT NULL DEFAULT 'scheduled',
+    started_at timestamptz NOT NULL DEFAULT now(),
+    finished_at timestamptz,
+    symbols_attempted integer NOT NULL DEFAULT 0,
+    successes integer NOT NULL DEFAULT 0,
+    failures integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_collector_runs_started_at ON collector_runs(started_at DESC);
+
+CREATE TABLE collector_run_symbols (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id uuid NOT NULL REFERENCES collector_runs(id) ON DELETE CASCADE,
+    symbol varchar(10) NOT NULL,
+    tier varchar(20) NOT NULL,
+    status varchar(20) NOT NULL,  -- success, failure, rate_limited
+    error text,
+    provider varchar(20),
+    started_at timestamptz NOT NULL,
+    duration_ms integer NOT NULL
+);
+
+CREATE INDEX idx_collector_run_symbols_run_id ON collector_run_symbols(run_id);
+CREATE INDEX idx_collector_run_symbols_symbol_started_at ON collector_run_symbols(symbol, started_at DESC);
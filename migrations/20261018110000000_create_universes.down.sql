@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS universe_members;
+DROP TABLE IF EXISTS universes;
@@ -0,0 +1,437 @@
+CREATE TABLE universes (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug varchar(50) NOT NULL UNIQUE,
+    name varchar(100) NOT NULL,
+    kind varchar(20) NOT NULL DEFAULT 'index', -- index, sector, watchlist
+    owner varchar(100),                        -- set for user watchlists
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE universe_members (
+    universe_id uuid NOT NULL REFERENCES universes(id) ON DELETE CASCADE,
+    symbol varchar(10) NOT NULL,
+    position integer NOT NULL DEFAULT 0,
+    added_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (universe_id, symbol)
+);
+
+CREATE INDEX idx_universe_members_symbol ON universe_members(symbol);
+
+-- Seed the lists that used to be compiled in so behaviour is unchanged.
+INSERT INTO universes (slug, name, kind) VALUES
+    ('sp500', 'S&P 500', 'index'),
+    ('nasdaq100', 'Nasdaq 100', 'index'),
+    ('mag7', 'Magnificent Seven', 'index'),
+    ('sector-etfs', 'Sector ETFs', 'sector');
+
+INSERT INTO universe_members (universe_id, symbol, position)
+SELECT u.id, m.symbol, m.position
+FROM universes u, (VALUES
+    ('AAPL', 0),
+    ('MSFT', 1),
+    ('NVDA', 2),
+    ('AMZN', 3),
+    ('GOOGL', 4),
+    ('META', 5),
+    ('TSLA', 6),
+    ('BRK.B', 7),
+    ('AVGO', 8),
+    ('LLY', 9),
+    ('JPM', 10),
+    ('UNH', 11),
+    ('XOM', 12),
+    ('V', 13),
+    ('MA', 14),
+    ('PG', 15),
+    ('COST', 16),
+    ('JNJ', 17),
+    ('HD', 18),
+    ('ABBV', 19),
+    ('NFLX', 20),
+    ('CRM', 21),
+    ('BAC', 22),
+    ('CVX', 23),
+    ('KO', 24),
+    ('WMT', 25),
+    ('MRK', 26),
+    ('ORCL', 27),
+    ('AMD', 28),
+    ('PEP', 29),
+    ('TMO', 30),
+    ('ADBE', 31),
+    ('ACN', 32),
+    ('LIN', 33),
+    ('CSCO', 34),
+    ('MCD', 35),
+    ('ABT', 36),
+    ('DHR', 37),
+    ('INTC', 38),
+    ('TXN', 39),
+    ('NKE', 40),
+    ('DIS', 41),
+    ('VZ', 42),
+    ('CMCSA', 43),
+    ('WFC', 44),
+    ('PM', 45),
+    ('COP', 46),
+    ('NEE', 47),
+    ('IBM', 48),
+    ('QCOM', 49),
+    ('UNP', 50),
+    ('RTX', 51),
+    ('INTU', 52),
+    ('LOW', 53),
+    ('AMGN', 54),
+    ('HON', 55),
+    ('GE', 56),
+    ('BA', 57),
+    ('SPGI', 58),
+    ('CAT', 59),
+    ('BLK', 60),
+    ('UPS', 61),
+    ('SBUX', 62),
+    ('AXP', 63),
+    ('DE', 64),
+    ('GILD', 65),
+    ('ELV', 66),
+    ('BKNG', 67),
+    ('ADI', 68),
+    ('PLD', 69),
+    ('MMC', 70),
+    ('TJX', 71),
+    ('MDLZ', 72),
+    ('VRTX', 73),
+    ('SYK', 74),
+    ('ADP', 75),
+    ('ISRG', 76),
+    ('CI', 77),
+    ('REGN', 78),
+    ('AMT', 79),
+    ('ZTS', 80),
+    ('PGR', 81),
+    ('SCHW', 82),
+    ('CB', 83),
+    ('SO', 84),
+    ('DUK', 85),
+    ('CME', 86),
+    ('BSX', 87),
+    ('ETN', 88),
+    ('FISV', 89),
+    ('MO', 90),
+    ('ITW', 91),
+    ('BDX', 92),
+    ('APH', 93),
+    ('MMM', 94),
+    ('NOC', 95),
+    ('HCA', 96),
+    ('PNC', 97),
+    ('GD', 98),
+    ('CL', 99),
+    ('USB', 100),
+    ('SHW', 101),
+    ('AON', 102),
+    ('EMR', 103),
+    ('MU', 104),
+    ('PANW', 105),
+    ('SNPS', 106),
+    ('CDNS', 107),
+    ('KLAC', 108),
+    ('AMAT', 109),
+    ('LRCX', 110),
+    ('MRVL', 111),
+    ('FTNT', 112),
+    ('CRWD', 113),
+    ('DDOG', 114),
+    ('NET', 115),
+    ('ZS', 116),
+    ('SNOW', 117),
+    ('TEAM', 118),
+    ('NOW', 119),
+    ('WDAY', 120),
+    ('PLTR', 121),
+    ('SQ', 122),
+    ('SHOP', 123),
+    ('ROKU', 124),
+    ('ZM', 125),
+    ('DOCU', 126),
+    ('OKTA', 127),
+    ('WM', 128),
+    ('SLB', 129),
+    ('EOG', 130),
+    ('MPC', 131),
+    ('PSX', 132),
+    ('VLO', 133),
+    ('NSC', 134),
+    ('FDX', 135),
+    ('CSX', 136),
+    ('HUM', 137),
+    ('MCK', 138),
+    ('COR', 139),
+    ('CNC', 140),
+    ('EW', 141),
+    ('DXCM', 142),
+    ('ZBH', 143),
+    ('STZ', 144),
+    ('MNST', 145),
+    ('KDP', 146),
+    ('GIS', 147),
+    ('SYY', 148),
+    ('ADM', 149),
+    ('TSN', 150),
+    ('K', 151),
+    ('CHD', 152),
+    ('CLX', 153),
+    ('KMB', 154),
+    ('EL', 155),
+    ('TGT', 156),
+    ('DG', 157),
+    ('DLTR', 158),
+    ('ORLY', 159),
+    ('AZO', 160),
+    ('F', 161),
+    ('GM', 162),
+    ('MAR', 163),
+    ('HLT', 164),
+    ('RCL', 165),
+    ('CCL', 166),
+    ('NCLH', 167),
+    ('LVS', 168),
+    ('WYNN', 169),
+    ('MGM', 170),
+    ('EXPE', 171),
+    ('CMG', 172),
+    ('YUM', 173),
+    ('DRI', 174),
+    ('LULU', 175),
+    ('PH', 176),
+    ('A', 177),
+    ('MTD', 178),
+    ('WAT', 179),
+    ('PKI', 180),
+    ('IQV', 181),
+    ('D', 182),
+    ('AEP', 183),
+    ('EXC', 184),
+    ('XEL', 185),
+    ('ED', 186),
+    ('PEG', 187),
+    ('SRE', 188),
+    ('WEC', 189),
+    ('ES', 190),
+    ('DTE', 191),
+    ('FE', 192),
+    ('PPL', 193),
+    ('AEE', 194),
+    ('ETR', 195),
+    ('CNP', 196),
+    ('CMS', 197),
+    ('LNT', 198),
+    ('ATO', 199),
+    ('PNW', 200),
+    ('NI', 201),
+    ('SR', 202),
+    ('OGE', 203),
+    ('EVRG', 204),
+    ('CEG', 205),
+    ('VST', 206),
+    ('NRG', 207),
+    ('WRK', 208),
+    ('AMCR', 209),
+    ('IP', 210),
+    ('VMC', 211),
+    ('MLM', 212),
+    ('DD', 213),
+    ('DOW', 214),
+    ('APD', 215),
+    ('NEM', 216),
+    ('FCX', 217),
+    ('ALB', 218),
+    ('FMC', 219),
+    ('CTVA', 220),
+    ('MOS', 221),
+    ('CF', 222),
+    ('NUE', 223),
+    ('STLD', 224),
+    ('RS', 225),
+    ('T', 226),
+    ('TMUS', 227),
+    ('LUMN', 228),
+    ('CHTR', 229),
+    ('PARA', 230),
+    ('WBD', 231),
+    ('FOXA', 232),
+    ('FOX', 233),
+    ('LYV', 234),
+    ('EA', 235),
+    ('TTWO', 236),
+    ('MTCH', 237),
+    ('IAC', 238),
+    ('NYT', 239),
+    ('IPG', 240),
+    ('OMC', 241),
+    ('GOOG', 242),
+    ('EBAY', 243),
+    ('ETSY', 244),
+    ('ABNB', 245),
+    ('BK', 246),
+    ('STT', 247),
+    ('NTRS', 248),
+    ('BEN', 249),
+    ('IVZ', 250),
+    ('AMP', 251),
+    ('TROW', 252),
+    ('MS', 253),
+    ('GS', 254),
+    ('C', 255),
+    ('TFC', 256),
+    ('COF', 257),
+    ('DFS', 258),
+    ('PYPL', 259),
+    ('AFRM', 260),
+    ('HOOD', 261),
+    ('COIN', 262),
+    ('MSTR', 263),
+    ('RIOT', 264),
+    ('MARA', 265),
+    ('CLSK', 266),
+    ('BITO', 267),
+    ('IBIT', 268)
+) AS m(symbol, position)
+WHERE u.slug = 'sp500';
+
+INSERT INTO universe_members (universe_id, symbol, position)
+SELECT u.id, m.symbol, m.position
+FROM universes u, (VALUES
+    ('AAPL', 0),
+    ('MSFT', 1),
+    ('NVDA', 2),
+    ('AMZN', 3),
+    ('AVGO', 4),
+    ('META', 5),
+    ('GOOGL', 6),
+    ('GOOG', 7),
+    ('TSLA', 8),
+    ('COST', 9),
+    ('NFLX', 10),
+    ('PLTR', 11),
+    ('TMUS', 12),
+    ('ASML', 13),
+    ('CSCO', 14),
+    ('AMD', 15),
+    ('AZN', 16),
+    ('LIN', 17),
+    ('INTU', 18),
+    ('ISRG', 19),
+    ('PEP', 20),
+    ('TXN', 21),
+    ('BKNG', 22),
+    ('QCOM', 23),
+    ('AMGN', 24),
+    ('ADBE', 25),
+    ('PDD', 26),
+    ('AMAT', 27),
+    ('ARM', 28),
+    ('GILD', 29),
+    ('HON', 30),
+    ('CMCSA', 31),
+    ('MU', 32),
+    ('APP', 33),
+    ('PANW', 34),
+    ('ADP', 35),
+    ('LRCX', 36),
+    ('KLAC', 37),
+    ('VRTX', 38),
+    ('ADI', 39),
+    ('SBUX', 40),
+    ('INTC', 41),
+    ('CRWD', 42),
+    ('MELI', 43),
+    ('CEG', 44),
+    ('DASH', 45),
+    ('MSTR', 46),
+    ('CTAS', 47),
+    ('ORLY', 48),
+    ('CDNS', 49),
+    ('SNPS', 50),
+    ('ABNB', 51),
+    ('MAR', 52),
+    ('FTNT', 53),
+    ('PYPL', 54),
+    ('MDLZ', 55),
+    ('REGN', 56),
+    ('WDAY', 57),
+    ('ADSK', 58),
+    ('MNST', 59),
+    ('ROP', 60),
+    ('AEP', 61),
+    ('CSX', 62),
+    ('CHTR', 63),
+    ('AXON', 64),
+    ('NXPI', 65),
+    ('PCAR', 66),
+    ('FAST', 67),
+    ('PAYX', 68),
+    ('TTWO', 69),
+    ('KDP', 70),
+    ('CPRT', 71),
+    ('ZS', 72),
+    ('EXC', 73),
+    ('DDOG', 74),
+    ('VRSK', 75),
+    ('ROST', 76),
+    ('IDXX', 77),
+    ('CTSH', 78),
+    ('XEL', 79),
+    ('BKR', 80),
+    ('CCEP', 81),
+    ('FANG', 82),
+    ('EA', 83),
+    ('KHC', 84),
+    ('TEAM', 85),
+    ('MCHP', 86),
+    ('GEHC', 87),
+    ('ODFL', 88),
+    ('LULU', 89),
+    ('ON', 90),
+    ('TTD', 91),
+    ('CSGP', 92),
+    ('DXCM', 93),
+    ('ANSS', 94),
+    ('BIIB', 95),
+    ('CDW', 96),
+    ('GFS', 97),
+    ('WBD', 98),
+    ('MRVL', 99)
+) AS m(symbol, position)
+WHERE u.slug = 'nasdaq100';
+
+INSERT INTO universe_members (universe_id, symbol, position)
+SELECT u.id, m.symbol, m.position
+FROM universes u, (VALUES
+    ('AAPL', 0),
+    ('MSFT', 1),
+    ('GOOG', 2),
+    ('AMZN', 3),
+    ('NVDA', 4),
+    ('TSLA', 5),
+    ('META', 6)
+) AS m(symbol, position)
+WHERE u.slug = 'mag7';
+
+INSERT INTO universe_members (universe_id, symbol, position)
+SELECT u.id, m.symbol, m.position
+FROM universes u, (VALUES
+    ('XLK', 0),
+    ('XLF', 1),
+    ('XLE', 2),
+    ('XLV', 3),
+    ('XLY', 4),
+    ('XLP', 5),
+    ('XLI', 6),
+    ('XLB', 7),
+    ('XLU', 8),
+    ('XLRE', 9),
+    ('XLC', 10)
+) AS m(symbol, position)
+WHERE u.slug = 'sector-etfs';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS scanner_views;
+ALTER TABLE gex_history DROP COLUMN IF EXISTS flip_level;
+DROP TABLE IF EXISTS symbol_metadata;
@@ -0,0 +1,27 @@
+-- Reference data the GEX scanner filters on. Imported from CSV; symbols
+-- without a row are only excluded when a sector or market cap filter is set.
+CREATE TABLE symbol_metadata (
+    symbol varchar(10) PRIMARY KEY,
+    name varchar(200) NOT NULL DEFAULT '',
+    sector varchar(100) NOT NULL DEFAULT '',
+    market_cap bigint,                         -- USD, NULL when unknown
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_symbol_metadata_sector ON symbol_metadata(sector);
+
+-- Gamma flip level of the stored chain, NULL for rows collected before it
+-- was recorded.
+ALTER TABLE gex_history ADD COLUMN flip_level numeric;
+
+-- Named scanner filter sets. There are no accounts, so owner is the
+-- anonymous visitor id from the scanner cookie.
+CREATE TABLE scanner_views (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner varchar(100) NOT NULL,
+    name varchar(100) NOT NULL,
+    query text NOT NULL,                       -- encoded filter query string
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (owner, name)
+);
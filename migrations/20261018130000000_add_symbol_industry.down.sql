@@ -0,0 +1 @@
+ALTER TABLE symbol_metadata DROP COLUMN IF EXISTS industry;
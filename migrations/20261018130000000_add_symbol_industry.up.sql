@@ -0,0 +1,324 @@
+-- Industry classification alongside the sector, so GEX can be rolled up at
+-- either level.
+ALTER TABLE symbol_metadata ADD COLUMN industry varchar(100) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_symbol_metadata_industry ON symbol_metadata(industry);
+
+-- GICS sector and industry for the seeded universes. Rows already imported
+-- are left alone; re-import with `import-symbols` to change them.
+INSERT INTO symbol_metadata (symbol, sector, industry) VALUES
+    ('A', 'Health Care', 'Life Sciences Tools & Services'),
+    ('AAPL', 'Information Technology', 'Technology Hardware'),
+    ('ABBV', 'Health Care', 'Biotechnology'),
+    ('ABNB', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('ABT', 'Health Care', 'Health Care Equipment'),
+    ('ACN', 'Information Technology', 'IT Services'),
+    ('ADBE', 'Information Technology', 'Software'),
+    ('ADI', 'Information Technology', 'Semiconductors'),
+    ('ADM', 'Consumer Staples', 'Food Products'),
+    ('ADP', 'Industrials', 'Professional Services'),
+    ('ADSK', 'Information Technology', 'Software'),
+    ('AEE', 'Utilities', 'Multi-Utilities'),
+    ('AEP', 'Utilities', 'Electric Utilities'),
+    ('AFRM', 'Financials', 'Financial Services'),
+    ('ALB', 'Materials', 'Chemicals'),
+    ('AMAT', 'Information Technology', 'Semiconductors'),
+    ('AMCR', 'Materials', 'Containers & Packaging'),
+    ('AMD', 'Information Technology', 'Semiconductors'),
+    ('AMGN', 'Health Care', 'Biotechnology'),
+    ('AMP', 'Financials', 'Capital Markets'),
+    ('AMT', 'Real Estate', 'Specialized REITs'),
+    ('AMZN', 'Consumer Discretionary', 'Broadline Retail'),
+    ('ANSS', 'Information Technology', 'Software'),
+    ('AON', 'Financials', 'Insurance'),
+    ('APD', 'Materials', 'Chemicals'),
+    ('APH', 'Information Technology', 'Electronic Equipment'),
+    ('APP', 'Information Technology', 'Software'),
+    ('ARM', 'Information Technology', 'Semiconductors'),
+    ('ASML', 'Information Technology', 'Semiconductors'),
+    ('ATO', 'Utilities', 'Gas Utilities'),
+    ('AVGO', 'Information Technology', 'Semiconductors'),
+    ('AXON', 'Industrials', 'Aerospace & Defense'),
+    ('AXP', 'Financials', 'Consumer Finance'),
+    ('AZN', 'Health Care', 'Pharmaceuticals'),
+    ('AZO', 'Consumer Discretionary', 'Specialty Retail'),
+    ('BA', 'Industrials', 'Aerospace & Defense'),
+    ('BAC', 'Financials', 'Banks'),
+    ('BDX', 'Health Care', 'Health Care Equipment'),
+    ('BEN', 'Financials', 'Capital Markets'),
+    ('BIIB', 'Health Care', 'Biotechnology'),
+    ('BITO', 'Funds', 'Bitcoin ETF'),
+    ('BK', 'Financials', 'Capital Markets'),
+    ('BKNG', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('BKR', 'Energy', 'Energy Equipment & Services'),
+    ('BLK', 'Financials', 'Capital Markets'),
+    ('BRK.B', 'Financials', 'Financial Services'),
+    ('BSX', 'Health Care', 'Health Care Equipment'),
+    ('C', 'Financials', 'Banks'),
+    ('CAT', 'Industrials', 'Machinery'),
+    ('CB', 'Financials', 'Insurance'),
+    ('CCEP', 'Consumer Staples', 'Beverages'),
+    ('CCL', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('CDNS', 'Information Technology', 'Software'),
+    ('CDW', 'Information Technology', 'Electronic Equipment'),
+    ('CEG', 'Utilities', 'Electric Utilities'),
+    ('CF', 'Materials', 'Chemicals'),
+    ('CHD', 'Consumer Staples', 'Household Products'),
+    ('CHTR', 'Communication Services', 'Media'),
+    ('CI', 'Health Care', 'Health Care Providers & Services'),
+    ('CL', 'Consumer Staples', 'Household Products'),
+    ('CLSK', 'Information Technology', 'Software'),
+    ('CLX', 'Consumer Staples', 'Household Products'),
+    ('CMCSA', 'Communication Services', 'Media'),
+    ('CME', 'Financials', 'Capital Markets'),
+    ('CMG', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('CMS', 'Utilities', 'Multi-Utilities'),
+    ('CNC', 'Health Care', 'Health Care Providers & Services'),
+    ('CNP', 'Utilities', 'Multi-Utilities'),
+    ('COF', 'Financials', 'Consumer Finance'),
+    ('COIN', 'Financials', 'Capital Markets'),
+    ('COP', 'Energy', 'Oil, Gas & Consumable Fuels'),
+    ('COR', 'Health Care', 'Health Care Providers & Services'),
+    ('COST', 'Consumer Staples', 'Consumer Staples Distribution & Retail'),
+    ('CPRT', 'Industrials', 'Commercial Services & Supplies'),
+    ('CRM', 'Information Technology', 'Software'),
+    ('CRWD', 'Information Technology', 'Software'),
+    ('CSCO', 'Information Technology', 'Communications Equipment'),
+    ('CSGP', 'Real Estate', 'Real Estate Management & Development'),
+    ('CSX', 'Industrials', 'Ground Transportation'),
+    ('CTAS', 'Industrials', 'Commercial Services & Supplies'),
+    ('CTSH', 'Information Technology', 'IT Services'),
+    ('CTVA', 'Materials', 'Chemicals'),
+    ('CVX', 'Energy', 'Oil, Gas & Consumable Fuels'),
+    ('D', 'Utilities', 'Multi-Utilities'),
+    ('DASH', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('DD', 'Materials', 'Chemicals'),
+    ('DDOG', 'Information Technology', 'Software'),
+    ('DE', 'Industrials', 'Machinery'),
+    ('DFS', 'Financials', 'Consumer Finance'),
+    ('DG', 'Consumer Staples', 'Consumer Staples Distribution & Retail'),
+    ('DHR', 'Health Care', 'Life Sciences Tools & Services'),
+    ('DIS', 'Communication Services', 'Entertainment'),
+    ('DLTR', 'Consumer Staples', 'Consumer Staples Distribution & Retail'),
+    ('DOCU', 'Information Technology', 'Software'),
+    ('DOW', 'Materials', 'Chemicals'),
+    ('DRI', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('DTE', 'Utilities', 'Multi-Utilities'),
+    ('DUK', 'Utilities', 'Electric Utilities'),
+    ('DXCM', 'Health Care', 'Health Care Equipment'),
+    ('EA', 'Communication Services', 'Entertainment'),
+    ('EBAY', 'Consumer Discretionary', 'Broadline Retail'),
+    ('ED', 'Utilities', 'Multi-Utilities'),
+    ('EL', 'Consumer Staples', 'Personal Care Products'),
+    ('ELV', 'Health Care', 'Health Care Providers & Services'),
+    ('EMR', 'Industrials', 'Electrical Equipment'),
+    ('EOG', 'Energy', 'Oil, Gas & Consumable Fuels'),
+    ('ES', 'Utilities', 'Electric Utilities'),
+    ('ETN', 'Industrials', 'Electrical Equipment'),
+    ('ETR', 'Utilities', 'Electric Utilities'),
+    ('ETSY', 'Consumer Discretionary', 'Broadline Retail'),
+    ('EVRG', 'Utilities', 'Electric Utilities'),
+    ('EW', 'Health Care', 'Health Care Equipment'),
+    ('EXC', 'Utilities', 'Electric Utilities'),
+    ('EXPE', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('F', 'Consumer Discretionary', 'Automobiles'),
+    ('FANG', 'Energy', 'Oil, Gas & Consumable Fuels'),
+    ('FAST', 'Industrials', 'Trading Companies & Distributors'),
+    ('FCX', 'Materials', 'Metals & Mining'),
+    ('FDX', 'Industrials', 'Air Freight & Logistics'),
+    ('FE', 'Utilities', 'Electric Utilities'),
+    ('FISV', 'Financials', 'Financial Services'),
+    ('FMC', 'Materials', 'Chemicals'),
+    ('FOX', 'Communication Services', 'Media'),
+    ('FOXA', 'Communication Services', 'Media'),
+    ('FTNT', 'Information Technology', 'Software'),
+    ('GD', 'Industrials', 'Aerospace & Defense'),
+    ('GE', 'Industrials', 'Aerospace & Defense'),
+    ('GEHC', 'Health Care', 'Health Care Equipment'),
+    ('GFS', 'Information Technology', 'Semiconductors'),
+    ('GILD', 'Health Care', 'Biotechnology'),
+    ('GIS', 'Consumer Staples', 'Food Products'),
+    ('GM', 'Consumer Discretionary', 'Automobiles'),
+    ('GOOG', 'Communication Services', 'Interactive Media & Services'),
+    ('GOOGL', 'Communication Services', 'Interactive Media & Services'),
+    ('GS', 'Financials', 'Capital Markets'),
+    ('HCA', 'Health Care', 'Health Care Providers & Services'),
+    ('HD', 'Consumer Discretionary', 'Specialty Retail'),
+    ('HLT', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('HON', 'Industrials', 'Industrial Conglomerates'),
+    ('HOOD', 'Financials', 'Capital Markets'),
+    ('HUM', 'Health Care', 'Health Care Providers & Services'),
+    ('IAC', 'Communication Services', 'Interactive Media & Services'),
+    ('IBIT', 'Funds', 'Bitcoin ETF'),
+    ('IBM', 'Information Technology', 'IT Services'),
+    ('IDXX', 'Health Care', 'Health Care Equipment'),
+    ('INTC', 'Information Technology', 'Semiconductors'),
+    ('INTU', 'Information Technology', 'Software'),
+    ('IP', 'Materials', 'Containers & Packaging'),
+    ('IPG', 'Communication Services', 'Media'),
+    ('IQV', 'Health Care', 'Life Sciences Tools & Services'),
+    ('ISRG', 'Health Care', 'Health Care Equipment'),
+    ('ITW', 'Industrials', 'Machinery'),
+    ('IVZ', 'Financials', 'Capital Markets'),
+    ('JNJ', 'Health Care', 'Pharmaceuticals'),
+    ('JPM', 'Financials', 'Banks'),
+    ('K', 'Consumer Staples', 'Food Products'),
+    ('KDP', 'Consumer Staples', 'Beverages'),
+    ('KHC', 'Consumer Staples', 'Food Products'),
+    ('KLAC', 'Information Technology', 'Semiconductors'),
+    ('KMB', 'Consumer Staples', 'Household Products'),
+    ('KO', 'Consumer Staples', 'Beverages'),
+    ('LIN', 'Materials', 'Chemicals'),
+    ('LLY', 'Health Care', 'Pharmaceuticals'),
+    ('LNT', 'Utilities', 'Electric Utilities'),
+    ('LOW', 'Consumer Discretionary', 'Specialty Retail'),
+    ('LRCX', 'Information Technology', 'Semiconductors'),
+    ('LULU', 'Consumer Discretionary', 'Textiles, Apparel & Luxury Goods'),
+    ('LUMN', 'Communication Services', 'Diversified Telecommunication Services'),
+    ('LVS', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('LYV', 'Communication Services', 'Entertainment'),
+    ('MA', 'Financials', 'Financial Services'),
+    ('MAR', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('MARA', 'Information Technology', 'Software'),
+    ('MCD', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('MCHP', 'Information Technology', 'Semiconductors'),
+    ('MCK', 'Health Care', 'Health Care Providers & Services'),
+    ('MDLZ', 'Consumer Staples', 'Food Products'),
+    ('MELI', 'Consumer Discretionary', 'Broadline Retail'),
+    ('META', 'Communication Services', 'Interactive Media & Services'),
+    ('MGM', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('MLM', 'Materials', 'Construction Materials'),
+    ('MMC', 'Financials', 'Insurance'),
+    ('MMM', 'Industrials', 'Industrial Conglomerates'),
+    ('MNST', 'Consumer Staples', 'Beverages'),
+    ('MO', 'Consumer Staples', 'Tobacco'),
+    ('MOS', 'Materials', 'Chemicals'),
+    ('MPC', 'Energy', 'Oil, Gas & Consumable Fuels'),
+    ('MRK', 'Health Care', 'Pharmaceuticals'),
+    ('MRVL', 'Information Technology', 'Semiconductors'),
+    ('MS', 'Financials', 'Capital Markets'),
+    ('MSFT', 'Information Technology', 'Software'),
+    ('MSTR', 'Information Technology', 'Software'),
+    ('MTCH', 'Communication Services', 'Interactive Media & Services'),
+    ('MTD', 'Health Care', 'Life Sciences Tools & Services'),
+    ('MU', 'Information Technology', 'Semiconductors'),
+    ('NCLH', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('NEE', 'Utilities', 'Electric Utilities'),
+    ('NEM', 'Materials', 'Metals & Mining'),
+    ('NET', 'Information Technology', 'IT Services'),
+    ('NFLX', 'Communication Services', 'Entertainment'),
+    ('NI', 'Utilities', 'Multi-Utilities'),
+    ('NKE', 'Consumer Discretionary', 'Textiles, Apparel & Luxury Goods'),
+    ('NOC', 'Industrials', 'Aerospace & Defense'),
+    ('NOW', 'Information Technology', 'Software'),
+    ('NRG', 'Utilities', 'Independent Power Producers'),
+    ('NSC', 'Industrials', 'Ground Transportation'),
+    ('NTRS', 'Financials', 'Capital Markets'),
+    ('NUE', 'Materials', 'Metals & Mining'),
+    ('NVDA', 'Information Technology', 'Semiconductors'),
+    ('NXPI', 'Information Technology', 'Semiconductors'),
+    ('NYT', 'Communication Services', 'Media'),
+    ('ODFL', 'Industrials', 'Ground Transportation'),
+    ('OGE', 'Utilities', 'Electric Utilities'),
+    ('OKTA', 'Information Technology', 'IT Services'),
+    ('OMC', 'Communication Services', 'Media'),
+    ('ON', 'Information Technology', 'Semiconductors'),
+    ('ORCL', 'Information Technology', 'Software'),
+    ('ORLY', 'Consumer Discretionary', 'Specialty Retail'),
+    ('PANW', 'Information Technology', 'Software'),
+    ('PARA', 'Communication Services', 'Media'),
+    ('PAYX', 'Industrials', 'Professional Services'),
+    ('PCAR', 'Industrials', 'Machinery'),
+    ('PDD', 'Consumer Discretionary', 'Broadline Retail'),
+    ('PEG', 'Utilities', 'Multi-Utilities'),
+    ('PEP', 'Consumer Staples', 'Beverages'),
+    ('PG', 'Consumer Staples', 'Household Products'),
+    ('PGR', 'Financials', 'Insurance'),
+    ('PH', 'Industrials', 'Machinery'),
+    ('PKI', 'Health Care', 'Life Sciences Tools & Services'),
+    ('PLD', 'Real Estate', 'Industrial REITs'),
+    ('PLTR', 'Information Technology', 'Software'),
+    ('PM', 'Consumer Staples', 'Tobacco'),
+    ('PNC', 'Financials', 'Banks'),
+    ('PNW', 'Utilities', 'Electric Utilities'),
+    ('PPL', 'Utilities', 'Electric Utilities'),
+    ('PSX', 'Energy', 'Oil, Gas & Consumable Fuels'),
+    ('PYPL', 'Financials', 'Financial Services'),
+    ('QCOM', 'Information Technology', 'Semiconductors'),
+    ('RCL', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('REGN', 'Health Care', 'Biotechnology'),
+    ('RIOT', 'Information Technology', 'Software'),
+    ('ROKU', 'Communication Services', 'Entertainment'),
+    ('ROP', 'Information Technology', 'Software'),
+    ('ROST', 'Consumer Discretionary', 'Specialty Retail'),
+    ('RS', 'Materials', 'Metals & Mining'),
+    ('RTX', 'Industrials', 'Aerospace & Defense'),
+    ('SBUX', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('SCHW', 'Financials', 'Capital Markets'),
+    ('SHOP', 'Information Technology', 'IT Services'),
+    ('SHW', 'Materials', 'Chemicals'),
+    ('SLB', 'Energy', 'Energy Equipment & Services'),
+    ('SNOW', 'Information Technology', 'IT Services'),
+    ('SNPS', 'Information Technology', 'Software'),
+    ('SO', 'Utilities', 'Electric Utilities'),
+    ('SPGI', 'Financials', 'Capital Markets'),
+    ('SQ', 'Financials', 'Financial Services'),
+    ('SR', 'Utilities', 'Gas Utilities'),
+    ('SRE', 'Utilities', 'Multi-Utilities'),
+    ('STLD', 'Materials', 'Metals & Mining'),
+    ('STT', 'Financials', 'Capital Markets'),
+    ('STZ', 'Consumer Staples', 'Beverages'),
+    ('SYK', 'Health Care', 'Health Care Equipment'),
+    ('SYY', 'Consumer Staples', 'Consumer Staples Distribution & Retail'),
+    ('T', 'Communication Services', 'Diversified Telecommunication Services'),
+    ('TEAM', 'Information Technology', 'Software'),
+    ('TFC', 'Financials', 'Banks'),
+    ('TGT', 'Consumer Staples', 'Consumer Staples Distribution & Retail'),
+    ('TJX', 'Consumer Discretionary', 'Specialty Retail'),
+    ('TMO', 'Health Care', 'Life Sciences Tools & Services'),
+    ('TMUS', 'Communication Services', 'Wireless Telecommunication Services'),
+    ('TROW', 'Financials', 'Capital Markets'),
+    ('TSLA', 'Consumer Discretionary', 'Automobiles'),
+    ('TSN', 'Consumer Staples', 'Food Products'),
+    ('TTD', 'Communication Services', 'Media'),
+    ('TTWO', 'Communication Services', 'Entertainment'),
+    ('TXN', 'Information Technology', 'Semiconductors'),
+    ('UNH', 'Health Care', 'Health Care Providers & Services'),
+    ('UNP', 'Industrials', 'Ground Transportation'),
+    ('UPS', 'Industrials', 'Air Freight & Logistics'),
+    ('USB', 'Financials', 'Banks'),
+    ('V', 'Financials', 'Financial Services'),
+    ('VLO', 'Energy', 'Oil, Gas & Consumable Fuels'),
+    ('VMC', 'Materials', 'Construction Materials'),
+    ('VRSK', 'Industrials', 'Professional Services'),
+    ('VRTX', 'Health Care', 'Biotechnology'),
+    ('VST', 'Utilities', 'Independent Power Producers'),
+    ('VZ', 'Communication Services', 'Diversified Telecommunication Services'),
+    ('WAT', 'Health Care', 'Life Sciences Tools & Services'),
+    ('WBD', 'Communication Services', 'Entertainment'),
+    ('WDAY', 'Information Technology', 'Software'),
+    ('WEC', 'Utilities', 'Multi-Utilities'),
+    ('WFC', 'Financials', 'Banks'),
+    ('WM', 'Industrials', 'Commercial Services & Supplies'),
+    ('WMT', 'Consumer Staples', 'Consumer Staples Distribution & Retail'),
+    ('WRK', 'Materials', 'Containers & Packaging'),
+    ('WYNN', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('XEL', 'Utilities', 'Electric Utilities'),
+    ('XLB', 'Materials', 'Sector ETF'),
+    ('XLC', 'Communication Services', 'Sector ETF'),
+    ('XLE', 'Energy', 'Sector ETF'),
+    ('XLF', 'Financials', 'Sector ETF'),
+    ('XLI', 'Industrials', 'Sector ETF'),
+    ('XLK', 'Information Technology', 'Sector ETF'),
+    ('XLP', 'Consumer Staples', 'Sector ETF'),
+    ('XLRE', 'Real Estate', 'Sector ETF'),
+    ('XLU', 'Utilities', 'Sector ETF'),
+    ('XLV', 'Health Care', 'Sector ETF'),
+    ('XLY', 'Consumer Discretionary', 'Sector ETF'),
+    ('XOM', 'Energy', 'Oil, Gas & Consumable Fuels'),
+    ('YUM', 'Consumer Discretionary', 'Hotels, Restaurants & Leisure'),
+    ('ZBH', 'Health Care', 'Health Care Equipment'),
+    ('ZM', 'Information Technology', 'Software'),
+    ('ZS', 'Information Technology', 'Software'),
+    ('ZTS', 'Health Care', 'Pharmaceuticals')
+ON CONFLICT (symbol) DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_gex_history_symbol_recorded_at;
+ALTER TABLE gex_history DROP COLUMN IF EXISTS put_wall;
+ALTER TABLE gex_history DROP COLUMN IF EXISTS call_wall;
@@ -0,0 +1,7 @@
+-- Call and put walls of the stored chain, NULL for rows collected before they
+-- were recorded (run `backfill` to fill them in).
+ALTER TABLE gex_history ADD COLUMN call_wall numeric;
+ALTER TABLE gex_history ADD COLUMN put_wall numeric;
+
+-- The timeline reads one symbol's snapshots over a time range.
+CREATE INDEX idx_gex_history_symbol_recorded_at ON gex_history(symbol, recorded_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS gex_snapshots;
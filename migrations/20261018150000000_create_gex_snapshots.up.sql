@@ -0,0 +1,10 @@
+-- Precomputed all-expiry GEX of a symbol. The snapshot refresher keeps these
+-- warm so the MAG7, universe grid and all-expiry pages never fetch chains on
+-- a page view.
+CREATE TABLE gex_snapshots (
+    symbol text PRIMARY KEY,
+    spot_price double precision NOT NULL,
+    strikes jsonb NOT NULL,  -- [{"strike": ..., "gex": ...}], ordered by strike
+    warning text NOT NULL DEFAULT '',
+    computed_at timestamptz NOT NULL
+);
@@ -0,0 +1,9 @@
+ALTER TABLE economic_releases DROP COLUMN IF EXISTS values_updated_at;
+ALTER TABLE economic_releases DROP COLUMN IF EXISTS consensus_source;
+ALTER TABLE economic_releases DROP COLUMN IF EXISTS consensus;
+ALTER TABLE economic_releases DROP COLUMN IF EXISTS prior;
+ALTER TABLE economic_releases DROP COLUMN IF EXISTS actual;
+ALTER TABLE economic_releases DROP COLUMN IF EXISTS period;
+ALTER TABLE economic_releases DROP COLUMN IF EXISTS units;
+ALTER TABLE economic_releases DROP COLUMN IF EXISTS series_id;
+ALTER TABLE economic_releases DROP COLUMN IF EXISTS release_time;
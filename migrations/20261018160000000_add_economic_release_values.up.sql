@@ -0,0 +1,12 @@
+-- Release time (Eastern) and headline series of a release, with the values it
+-- published. actual and prior come from FRED series/observations in the
+-- series' units; consensus is entered or imported by an admin.
+ALTER TABLE economic_releases ADD COLUMN release_time time;
+ALTER TABLE economic_releases ADD COLUMN series_id varchar(64);
+ALTER TABLE economic_releases ADD COLUMN units varchar(16);
+ALTER TABLE economic_releases ADD COLUMN period date;
+ALTER TABLE economic_releases ADD COLUMN actual double precision;
+ALTER TABLE economic_releases ADD COLUMN prior double precision;
+ALTER TABLE economic_releases ADD COLUMN consensus double precision;
+ALTER TABLE economic_releases ADD COLUMN consensus_source varchar(255);
+ALTER TABLE economic_releases ADD COLUMN values_updated_at timestamptz;
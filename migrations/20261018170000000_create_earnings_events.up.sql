@@ -0,0 +1,14 @@
+-- Imported earnings dates. session is 'bmo' (before the open), 'amc' (after
+-- the close) or '' when unknown.
+CREATE TABLE earnings_events (
+    symbol text NOT NULL,
+    report_date date NOT NULL,
+    session varchar(8) NOT NULL DEFAULT '',
+    fiscal_period varchar(16) NOT NULL DEFAULT '',
+    eps_estimate double precision,
+    source varchar(255) NOT NULL DEFAULT '',
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (symbol, report_date)
+);
+
+CREATE INDEX idx_earnings_events_report_date ON earnings_events(report_date);
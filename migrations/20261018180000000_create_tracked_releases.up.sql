@@ -0,0 +1,35 @@
+-- FRED releases the economic calendar collects, with their impact and
+-- metadata. Seeded with the releases that used to be compiled in; manage them
+-- at /admin/releases.
+CREATE TABLE tracked_releases (
+    release_id integer PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    impact varchar(50) NOT NULL,
+    release_time time,                 -- Eastern, NULL when not fixed
+    category varchar(64) NOT NULL DEFAULT '',
+    series_id varchar(64),             -- headline series, in units
+    units varchar(16),
+    related_series text[] NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+INSERT INTO tracked_releases (release_id, name, impact, release_time, category, series_id, units, related_series) VALUES
+    -- High Impact - Market Moving
+    (10, 'Consumer Price Index', 'High', '08:30', 'inflation', 'CPIAUCSL', 'pch', '{CPILFESL}'),
+    (50, 'Employment Situation', 'High', '08:30', 'employment', 'PAYEMS', 'chg', '{UNRATE,CES0500000003}'),
+    (9, 'Advance Monthly Sales for Retail and Food Services', 'High', '08:30', 'consumption', 'RSAFS', 'pch', '{RSFSXMV}'),
+    (192, 'Job Openings and Labor Turnover Survey', 'High', '10:00', 'employment', 'JTSJOL', 'lin', '{JTSQUR}'),
+    (436, 'Monthly Retail Trade and Food Services', 'High', '10:00', 'consumption', NULL, NULL, '{}'),
+    -- Medium Impact - Important Economic Indicators
+    (46, 'Producer Price Index', 'Medium', '08:30', 'inflation', 'PPIFIS', 'pch', '{}'),
+    (479, 'Consumer Expenditure Surveys', 'Medium', '10:00', 'consumption', NULL, NULL, '{}'),
+    (11, 'Employment Cost Index', 'Medium', '08:30', 'employment', 'ECIALLCIV', 'pch', '{}'),
+    (386, 'GDPNow', 'Medium', NULL, 'growth', 'GDPNOW', 'lin', '{}'),
+    (296, 'Housing Vacancies and Homeownership', 'Medium', '10:00', 'housing', NULL, NULL, '{RHORUSQ156N}'),
+    (92, 'Selected Real Retail Sales Series', 'Medium', '08:30', 'consumption', NULL, NULL, '{}'),
+    -- Low Impact - Regional/Supplementary Data
+    (112, 'State Employment and Unemployment', 'Low', '10:00', 'regional', NULL, NULL, '{}'),
+    (113, 'Metropolitan Area Employment and Unemployment', 'Low', '10:00', 'regional', NULL, NULL, '{}'),
+    (308, 'State and Metro Area Employment, Hours, and Earnings', 'Low', '10:00', 'regional', NULL, NULL, '{}'),
+    (477, 'Monthly State Retail Sales', 'Low', '10:00', 'regional', NULL, NULL, '{}');
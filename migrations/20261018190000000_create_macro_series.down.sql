@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS macro_observations;
+DROP TABLE IF EXISTS macro_series;
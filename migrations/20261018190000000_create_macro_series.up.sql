@@ -0,0 +1,29 @@
+-- FRED series behind the macro dashboard, and their stored observations.
+-- Add a row to macro_series to ingest another series.
+CREATE TABLE macro_series (
+    series_id varchar(64) PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    category varchar(64) NOT NULL,
+    unit varchar(32) NOT NULL DEFAULT '',  -- display unit, e.g. '%'
+    position integer NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE macro_observations (
+    series_id varchar(64) NOT NULL REFERENCES macro_series(series_id) ON DELETE CASCADE,
+    date date NOT NULL,
+    value double precision NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (series_id, date)
+);
+
+CREATE INDEX idx_macro_observations_date ON macro_observations(date DESC);
+
+INSERT INTO macro_series (series_id, name, category, unit, position) VALUES
+    ('DGS2', '2-Year Treasury Yield', 'rates', '%', 1),
+    ('DGS10', '10-Year Treasury Yield', 'rates', '%', 2),
+    ('T10Y2Y', '10Y-2Y Treasury Spread', 'curve', '%', 3),
+    ('DFF', 'Effective Fed Funds Rate', 'rates', '%', 4),
+    ('VIXCLS', 'VIX Close', 'volatility', '', 5),
+    ('NFCI', 'Chicago Fed Financial Conditions Index', 'financial_conditions', '', 6);
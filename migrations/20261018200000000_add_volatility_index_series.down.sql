@@ -0,0 +1 @@
+DELETE FROM macro_series WHERE series_id IN ('VXNCLS', 'RVXCLS');
@@ -0,0 +1,6 @@
+-- Volatility indexes of the index ETFs, read by the event studies on the
+-- economic calendar for the move each one priced into a release.
+INSERT INTO macro_series (series_id, name, category, unit, position) VALUES
+    ('VXNCLS', 'Nasdaq-100 Volatility Close', 'volatility', '', 7),
+    ('RVXCLS', 'Russell 2000 Volatility Close', 'volatility', '', 8)
+ON CONFLICT (series_id) DO NOTHING;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS btc_etf_flows;
+DROP TABLE IF EXISTS btc_etf_funds;
@@ -0,0 +1,35 @@
+-- US spot bitcoin ETFs and their daily flows, ingested from the source in
+-- BTC_ETF_FLOWS_URL. Funds the source reports that aren't listed here are
+-- added with their ticker as the name.
+CREATE TABLE btc_etf_funds (
+    ticker varchar(16) PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    issuer varchar(255) NOT NULL DEFAULT '',
+    position integer NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE btc_etf_flows (
+    ticker varchar(16) NOT NULL REFERENCES btc_etf_funds(ticker) ON DELETE CASCADE,
+    date date NOT NULL,
+    flow_usd double precision NOT NULL,   -- net creations less redemptions, in US$
+    holdings_btc double precision,        -- BTC held at the close, when reported
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (ticker, date)
+);
+
+CREATE INDEX idx_btc_etf_flows_date ON btc_etf_flows(date DESC);
+
+INSERT INTO btc_etf_funds (ticker, name, issuer, position) VALUES
+    ('IBIT', 'iShares Bitcoin Trust', 'BlackRock', 1),
+    ('FBTC', 'Fidelity Wise Origin Bitcoin Fund', 'Fidelity', 2),
+    ('BITB', 'Bitwise Bitcoin ETF', 'Bitwise', 3),
+    ('ARKB', 'ARK 21Shares Bitcoin ETF', 'ARK Invest / 21Shares', 4),
+    ('BTCO', 'Invesco Galaxy Bitcoin ETF', 'Invesco / Galaxy', 5),
+    ('EZBC', 'Franklin Bitcoin ETF', 'Franklin Templeton', 6),
+    ('BRRR', 'CoinShares Bitcoin ETF', 'CoinShares', 7),
+    ('HODL', 'VanEck Bitcoin ETF', 'VanEck', 8),
+    ('BTCW', 'WisdomTree Bitcoin Fund', 'WisdomTree', 9),
+    ('DEFI', 'Hashdex Bitcoin ETF', 'Hashdex', 10),
+    ('GBTC', 'Grayscale Bitcoin Trust ETF', 'Grayscale', 11),
+    ('BTC', 'Grayscale Bitcoin Mini Trust ETF', 'Grayscale', 12);
@@ -45,6 +45,11 @@ RETURNING *;
 SELECT * FROM option_expiry_dates
 WHERE symbol = $1;
 
+-- name: ListOptionExpiryDatesForSymbols :many
+SELECT * FROM option_expiry_dates
+WHERE symbol = ANY(sqlc.arg(symbols)::text[])
+ORDER BY symbol;
+
 -- name: UpsertOptionChain :one
 INSERT INTO option_chain (
     symbol,
@@ -66,11 +71,26 @@ WHERE symbol = $1 and expiry_date = $2;
 
 -- name: InsertGEXHistory :one
 INSERT INTO gex_history (
-    id, symbol, expiry_date, expiry_type, option_chain, gex_value, recorded_at, spot_price
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    id, symbol, expiry_date, expiry_type, option_chain, gex_value, recorded_at, spot_price, flip_level,
+    call_wall, put_wall
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
     RETURNING *;
 
 
+-- name: ListGEXHistoryForBackfill :many
+SELECT id, symbol, recorded_at, option_chain, spot_price, gex_value, flip_level, call_wall, put_wall
+FROM gex_history
+WHERE (cardinality(sqlc.arg(symbols)::text[]) = 0 OR symbol = ANY(sqlc.arg(symbols)::text[]))
+  AND recorded_at >= sqlc.arg(from_time) AND recorded_at < sqlc.arg(to_time)
+  AND (recorded_at, id) > (sqlc.arg(after_recorded_at)::timestamptz, sqlc.arg(after_id)::uuid)
+ORDER BY recorded_at, id
+LIMIT sqlc.arg(batch_size);
+
+-- name: UpdateGEXHistoryValue :exec
+UPDATE gex_history
+SET gex_value = $2, flip_level = $3, call_wall = $4, put_wall = $5
+WHERE id = $1;
+
 -- name: GetGexHistoryBySymbolAndExpiry :many
 SELECT * FROM gex_history
 WHERE symbol = $1 AND expiry_date = $2
@@ -84,12 +104,51 @@ WHERE symbol = $1 AND recorded_at >= $2
 ORDER BY recorded_at DESC
     LIMIT $3;
 
+-- name: ListGEXTimeline :many
+-- Net GEX, spot and strike levels of a symbol since from_time, oldest first.
+SELECT recorded_at, gex_value, spot_price, flip_level, call_wall, put_wall
+FROM gex_history
+WHERE symbol = $1 AND recorded_at >= sqlc.arg(from_time)
+ORDER BY recorded_at;
+
+-- name: ListDailyGEXCloses :many
+-- The last snapshot of a symbol on each New York trading day since
+-- from_time, oldest first.
+SELECT DISTINCT ON ((recorded_at AT TIME ZONE 'America/New_York')::date)
+    recorded_at, gex_value, spot_price
+FROM gex_history
+WHERE symbol = $1 AND recorded_at >= sqlc.arg(from_time)
+ORDER BY (recorded_at AT TIME ZONE 'America/New_York')::date, recorded_at DESC;
+
+-- name: ListGEXHistoryForSymbols :many
+-- Net GEX, spot and flip level of several symbols since from_time, for
+-- side-by-side comparison.
+SELECT symbol, recorded_at, gex_value, spot_price, flip_level
+FROM gex_history
+WHERE symbol = ANY(sqlc.arg(symbols)::text[]) AND recorded_at >= sqlc.arg(from_time)
+ORDER BY symbol, recorded_at;
+
+-- name: UpsertGEXSnapshot :exec
+INSERT INTO gex_snapshots (symbol, spot_price, strikes, warning, computed_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (symbol) DO UPDATE SET
+    spot_price = EXCLUDED.spot_price,
+    strikes = EXCLUDED.strikes,
+    warning = EXCLUDED.warning,
+    computed_at = EXCLUDED.computed_at;
+
+-- name: ListGEXSnapshots :many
+SELECT symbol, spot_price, strikes, warning, computed_at
+FROM gex_snapshots
+WHERE symbol = ANY(sqlc.arg(symbols)::text[]);
+
 -- name: GetLatestGEXChanges :many
 WITH ranked_history AS (
     SELECT
         symbol,
         gex_value,
         spot_price,
+        flip_level,
         expiry_date,
         recorded_at,
         ROW_NUMBER() OVER (PARTITION BY symbol ORDER BY recorded_at DESC) as rn
@@ -100,6 +159,7 @@ latest AS (
         symbol,
         gex_value as current_gex,
         spot_price as current_price,
+        flip_level,
         expiry_date,
         recorded_at as current_time
     FROM ranked_history
@@ -117,6 +177,7 @@ SELECT
     l.symbol,
     l.current_gex,
     l.current_price,
+    l.flip_level,
     l.expiry_date,
     l.current_time,
     COALESCE(p.previous_gex, 0) as previous_gex,
@@ -137,6 +198,7 @@ WITH latest AS (
         symbol,
         gex_value as current_gex,
         spot_price as current_price,
+        flip_level,
         expiry_date,
         recorded_at as current_time
     FROM gex_history
@@ -156,6 +218,7 @@ SELECT
     l.symbol,
     l.current_gex,
     l.current_price,
+    l.flip_level,
     l.expiry_date,
     l.current_time,
     COALESCE(p.previous_gex, 0) as previous_gex,
@@ -172,12 +235,15 @@ ORDER BY ABS(l.current_gex - COALESCE(p.previous_gex, 0)) DESC;
 
 -- Economic Releases (FRED API)
 -- name: UpsertEconomicRelease :one
-INSERT INTO economic_releases (release_id, release_name, release_date, impact)
-VALUES ($1, $2, $3, $4)
+INSERT INTO economic_releases (release_id, release_name, release_date, impact, release_time, series_id, units)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 ON CONFLICT (release_id, release_date)
 DO UPDATE SET
     release_name = EXCLUDED.release_name,
     impact = EXCLUDED.impact,
+    release_time = EXCLUDED.release_time,
+    series_id = EXCLUDED.series_id,
+    units = EXCLUDED.units,
     updated_at = now()
 RETURNING *;
 
@@ -191,95 +257,311 @@ SELECT * FROM economic_releases
 WHERE release_date >= $1 AND release_date <= $2
 ORDER BY release_date ASC, impact DESC;
 
--- name: GetGEXAnomalies :many
-WITH daily_closes AS (
-    SELECT DISTINCT ON (symbol, recorded_at::date)
-        symbol,
-        gex_value,
-        recorded_at
-    FROM gex_history
-    WHERE recorded_at >= NOW() - INTERVAL '30 days'
-    ORDER BY symbol, recorded_at::date DESC, recorded_at DESC
-),
-stats AS (
-    SELECT
-        symbol,
-        AVG(gex_value) as avg_gex,
-        STDDEV(gex_value) as stddev_gex
-    FROM daily_closes
-    GROUP BY symbol
-),
-latest AS (
-    SELECT DISTINCT ON (symbol)
-        symbol,
-        gex_value,
-        spot_price,
-        recorded_at
+-- name: ListReleasesAwaitingValues :many
+-- Releases of the past and next week with a headline series that still lack
+-- their prior, or their actual once the release date has come.
+SELECT * FROM economic_releases
+WHERE series_id IS NOT NULL
+  AND release_date >= CURRENT_DATE - 7 AND release_date <= CURRENT_DATE + 7
+  AND (prior IS NULL OR (actual IS NULL AND release_date <= CURRENT_DATE))
+ORDER BY release_date ASC;
+
+-- name: UpdateEconomicReleaseValues :exec
+UPDATE economic_releases
+SET period = $2, actual = $3, prior = $4, values_updated_at = now()
+WHERE id = $1;
+
+-- name: ListReleasesBetween :many
+-- Releases of an impact dated from from_date to to_date, oldest first.
+SELECT * FROM economic_releases
+WHERE impact = sqlc.arg(impact)
+  AND release_date >= sqlc.arg(from_date) AND release_date <= sqlc.arg(to_date)
+ORDER BY release_date, release_id;
+
+-- name: SetEconomicReleaseConsensus :execrows
+UPDATE economic_releases
+SET consensus = $3, consensus_source = $4, updated_at = now()
+WHERE release_id = $1 AND release_date = $2;
+
+-- Tracked FRED releases (managed at /admin/releases)
+-- name: ListTrackedReleases :many
+SELECT * FROM tracked_releases
+ORDER BY CASE impact WHEN 'High' THEN 0 WHEN 'Medium' THEN 1 ELSE 2 END, name;
+
+-- name: UpsertTrackedRelease :one
+INSERT INTO tracked_releases (release_id, name, impact, release_time, category, series_id, units, related_series)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (release_id)
+DO UPDATE SET
+    name = EXCLUDED.name,
+    impact = EXCLUDED.impact,
+    release_time = EXCLUDED.release_time,
+    category = EXCLUDED.category,
+    series_id = EXCLUDED.series_id,
+    units = EXCLUDED.units,
+    related_series = EXCLUDED.related_series,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteTrackedRelease :execrows
+DELETE FROM tracked_releases WHERE release_id = $1;
+
+-- name: DeleteUpcomingEconomicReleases :exec
+-- Drops the scheduled dates of a release that is no longer tracked.
+DELETE FROM economic_releases WHERE release_id = $1 AND release_date >= CURRENT_DATE;
+
+-- Earnings calendar (internal/events)
+-- name: UpsertEarningsEvent :exec
+INSERT INTO earnings_events (symbol, report_date, session, fiscal_period, eps_estimate, source)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (symbol, report_date)
+DO UPDATE SET
+    session = EXCLUDED.session,
+    fiscal_period = EXCLUDED.fiscal_period,
+    eps_estimate = EXCLUDED.eps_estimate,
+    source = EXCLUDED.source,
+    updated_at = now();
+
+-- name: DeleteRescheduledEarnings :exec
+-- Drops other dates of a symbol's fiscal period once it has been rescheduled.
+DELETE FROM earnings_events
+WHERE symbol = $1 AND fiscal_period = $2 AND report_date <> $3;
+
+-- name: ListEarningsEvents :many
+SELECT * FROM earnings_events
+WHERE report_date >= $1 AND report_date <= $2
+ORDER BY report_date ASC, symbol ASC;
+
+-- Z-score inputs (scores are computed in internal/zscore)
+-- name: ListDailyGEXSnapshots :many
+-- The last snapshot of each symbol on each New York trading day since
+-- from_time, oldest first. Total open interest is only summed from the stored
+-- chain when with_open_interest is set.
+WITH daily AS (
+    SELECT DISTINCT ON (symbol, (recorded_at AT TIME ZONE 'America/New_York')::date)
+        id
     FROM gex_history
-    ORDER BY symbol, recorded_at DESC
+    WHERE gex_history.recorded_at >= sqlc.arg(from_time)
+    ORDER BY symbol, (recorded_at AT TIME ZONE 'America/New_York')::date, recorded_at DESC
 )
 SELECT
-    l.symbol,
-    l.gex_value,
-    l.spot_price,
-    l.recorded_at,
-    COALESCE(s.avg_gex, 0)::numeric as avg_gex,
-    COALESCE(s.stddev_gex, 0)::numeric as stddev_gex,
-    (CASE 
-        WHEN COALESCE(s.stddev_gex, 0) = 0 THEN 0 
-        ELSE (l.gex_value - s.avg_gex) / s.stddev_gex 
-    END)::numeric as z_score
-FROM latest l
-JOIN stats s ON l.symbol = s.symbol
-ORDER BY ABS((CASE 
-        WHEN COALESCE(s.stddev_gex, 0) = 0 THEN 0 
-        ELSE (l.gex_value - s.avg_gex) / s.stddev_gex 
-    END)) DESC;
-
--- name: GetHistoricalGEXAnomalies :many
-WITH stats AS (
-    SELECT
-        AVG(gex_value) as avg_gex,
-        STDDEV(gex_value) as stddev_gex
-    FROM gex_history
-    WHERE symbol = $1 AND recorded_at >= NOW() - INTERVAL '30 days'
-)
+    h.symbol,
+    h.recorded_at,
+    h.gex_value,
+    h.spot_price,
+    (CASE WHEN sqlc.arg(with_open_interest)::bool THEN (
+        SELECT COALESCE(SUM((o->>'open_interest')::numeric), 0)
+        FROM jsonb_array_elements(CASE
+            WHEN jsonb_typeof(h.option_chain->'options'->'option') = 'array' THEN h.option_chain->'options'->'option'
+            ELSE '[]'::jsonb
+        END) o
+    ) ELSE 0 END)::numeric AS open_interest
+FROM daily d
+JOIN gex_history h ON h.id = d.id
+ORDER BY h.symbol, h.recorded_at;
+
+-- name: ListGEXSnapshotsForSymbol :many
+-- Every snapshot of a symbol since from_time, oldest first, with the same
+-- optional open interest sum as ListDailyGEXSnapshots.
 SELECT
     recorded_at,
     gex_value,
-    (CASE 
-        WHEN COALESCE(s.stddev_gex, 0) = 0 THEN 0 
-        ELSE (gex_value - s.avg_gex) / s.stddev_gex 
-    END)::numeric as z_score
-FROM gex_history, stats s
-WHERE gex_history.symbol = $1 AND recorded_at >= NOW() - INTERVAL '7 days'
-ORDER BY recorded_at ASC;
-
--- name: GetLatestZScores :many
-WITH stats AS (
-    SELECT
-        symbol,
-        AVG(gex_value) as avg_gex,
-        STDDEV(gex_value) as stddev_gex
-    FROM gex_history
-    WHERE recorded_at >= NOW() - INTERVAL '30 days'
-    GROUP BY symbol
-),
-latest AS (
+    spot_price,
+    (CASE WHEN sqlc.arg(with_open_interest)::bool THEN (
+        SELECT COALESCE(SUM((o->>'open_interest')::numeric), 0)
+        FROM jsonb_array_elements(CASE
+            WHEN jsonb_typeof(option_chain->'options'->'option') = 'array' THEN option_chain->'options'->'option'
+            ELSE '[]'::jsonb
+        END) o
+    ) ELSE 0 END)::numeric AS open_interest
+FROM gex_history
+WHERE symbol = sqlc.arg(symbol) AND recorded_at >= sqlc.arg(from_time)
+ORDER BY recorded_at;
+
+-- Collector run ledger
+-- name: CreateCollectorRun :one
+INSERT INTO collector_runs (trigger, started_at)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: InsertCollectorRunSymbol :one
+INSERT INTO collector_run_symbols (
+    run_id, symbol, tier, status, error, provider, started_at, duration_ms
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: UpdateCollectorRunCounts :exec
+UPDATE collector_runs
+SET symbols_attempted = symbols_attempted + 1,
+    successes = successes + sqlc.arg(successes)::integer,
+    failures = failures + sqlc.arg(failures)::integer,
+    -- Workers finish in any order; the run ends with its latest attempt.
+    finished_at = GREATEST(COALESCE(finished_at, started_at), sqlc.arg(finished_at)::timestamptz)
+WHERE id = sqlc.arg(id);
+
+-- name: ListCollectorRuns :many
+SELECT * FROM collector_runs
+ORDER BY started_at DESC
+LIMIT $1;
+
+-- name: ListCollectorRunSymbols :many
+SELECT * FROM collector_run_symbols
+WHERE run_id = $1
+ORDER BY started_at ASC;
+
+-- name: GetFailingCollectorSymbols :many
+WITH latest AS (
     SELECT DISTINCT ON (symbol)
-        symbol,
-        gex_value,
-        recorded_at
-    FROM gex_history
-    ORDER BY symbol, recorded_at DESC
+        symbol, status, error, provider, started_at
+    FROM collector_run_symbols
+    ORDER BY symbol, started_at DESC
+),
+recent_failures AS (
+    SELECT symbol, COUNT(*) AS failure_count
+    FROM collector_run_symbols
+    WHERE collector_run_symbols.status NOT IN ('success', 'canceled') AND collector_run_symbols.started_at >= $1
+    GROUP BY symbol
 )
 SELECT
     l.symbol,
-    l.gex_value,
-    l.recorded_at,
-    (CASE 
-        WHEN COALESCE(s.stddev_gex, 0) = 0 THEN 0 
-        ELSE (l.gex_value - s.avg_gex) / s.stddev_gex 
-    END)::numeric as z_score
+    l.status,
+    l.error,
+    l.provider,
+    l.started_at AS last_attempt_at,
+    COALESCE(f.failure_count, 0)::bigint AS failure_count
 FROM latest l
-JOIN stats s ON l.symbol = s.symbol;
\ No newline at end of file
+LEFT JOIN recent_failures f ON f.symbol = l.symbol
+WHERE l.status NOT IN ('success', 'canceled')
+ORDER BY failure_count DESC, l.symbol;
+
+-- name: GetGEXFreshness :many
+SELECT symbol, MAX(recorded_at)::timestamptz AS last_recorded_at
+FROM gex_history
+GROUP BY symbol
+ORDER BY last_recorded_at ASC;
+
+-- name: ListUniverses :many
+SELECT u.id, u.slug, u.name, u.kind, u.owner, u.updated_at, COUNT(m.symbol)::int AS member_count
+FROM universes u
+LEFT JOIN universe_members m ON m.universe_id = u.id
+GROUP BY u.id
+ORDER BY u.kind, u.name;
+
+-- name: GetUniverseBySlug :one
+SELECT * FROM universes WHERE slug = $1;
+
+-- name: ListUniverseSymbols :many
+SELECT m.symbol
+FROM universe_members m
+JOIN universes u ON u.id = m.universe_id
+WHERE u.slug = $1
+ORDER BY m.position, m.symbol;
+
+-- name: ListSymbolsInUniverses :many
+SELECT m.symbol
+FROM universe_members m
+JOIN universes u ON u.id = m.universe_id
+WHERE u.slug = ANY(sqlc.arg(slugs)::text[])
+GROUP BY m.symbol
+ORDER BY MIN(m.position), m.symbol;
+
+-- name: UpsertUniverse :one
+INSERT INTO universes (slug, name, kind, owner)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (slug) DO UPDATE
+SET name = EXCLUDED.name, kind = EXCLUDED.kind, owner = EXCLUDED.owner, updated_at = now()
+RETURNING *;
+
+-- name: DeleteUniverseMembers :exec
+DELETE FROM universe_members WHERE universe_id = $1;
+
+-- name: UpsertUniverseMember :exec
+INSERT INTO universe_members (universe_id, symbol, position)
+VALUES ($1, $2, $3)
+ON CONFLICT (universe_id, symbol) DO UPDATE SET position = EXCLUDED.position;
+
+-- name: GetMaxUniversePosition :one
+SELECT COALESCE(MAX(position), -1)::int AS max_position
+FROM universe_members
+WHERE universe_id = $1;
+
+-- name: ListSymbolMetadata :many
+SELECT * FROM symbol_metadata ORDER BY symbol;
+
+-- name: UpsertSymbolMetadata :exec
+-- Blank fields keep the stored value so partial CSVs don't wipe the seed data.
+INSERT INTO symbol_metadata (symbol, name, sector, industry, market_cap)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (symbol) DO UPDATE
+SET name = COALESCE(NULLIF(EXCLUDED.name, ''), symbol_metadata.name),
+    sector = COALESCE(NULLIF(EXCLUDED.sector, ''), symbol_metadata.sector),
+    industry = COALESCE(NULLIF(EXCLUDED.industry, ''), symbol_metadata.industry),
+    market_cap = COALESCE(EXCLUDED.market_cap, symbol_metadata.market_cap),
+    updated_at = now();
+
+-- name: ListScannerViews :many
+SELECT * FROM scanner_views WHERE owner = $1 ORDER BY name;
+
+-- name: GetScannerView :one
+SELECT * FROM scanner_views WHERE owner = $1 AND name = $2;
+
+-- name: UpsertScannerView :one
+INSERT INTO scanner_views (owner, name, query)
+VALUES ($1, $2, $3)
+ON CONFLICT (owner, name) DO UPDATE SET query = EXCLUDED.query, updated_at = now()
+RETURNING *;
+
+-- name: DeleteScannerView :execrows
+DELETE FROM scanner_views WHERE owner = $1 AND name = $2;
+
+-- name: ListMacroSeries :many
+SELECT * FROM macro_series
+ORDER BY position, series_id;
+
+-- name: CountMacroObservations :one
+SELECT count(*) FROM macro_observations
+WHERE series_id = $1;
+
+-- name: UpsertMacroObservation :exec
+INSERT INTO macro_observations (series_id, date, value)
+VALUES ($1, $2, $3)
+ON CONFLICT (series_id, date) DO UPDATE SET
+    value = EXCLUDED.value,
+    updated_at = now()
+WHERE macro_observations.value <> EXCLUDED.value;
+
+-- name: ListMacroObservationsSince :many
+-- Observations of every series from a date on, oldest first.
+SELECT series_id, date, value FROM macro_observations
+WHERE date >= $1
+ORDER BY series_id, date;
+
+-- name: ListMacroSeriesObservations :many
+SELECT date, value FROM macro_observations
+WHERE series_id = $1 AND date >= $2
+ORDER BY date;
+
+-- name: ListBTCETFFunds :many
+SELECT * FROM btc_etf_funds
+ORDER BY position, ticker;
+
+-- name: EnsureBTCETFFund :exec
+-- Adds a fund the flows source reports but the seed list doesn't have.
+INSERT INTO btc_etf_funds (ticker, name, position)
+VALUES ($1, $1, 1000)
+ON CONFLICT (ticker) DO NOTHING;
+
+-- name: UpsertBTCETFFlow :exec
+INSERT INTO btc_etf_flows (ticker, date, flow_usd, holdings_btc)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (ticker, date) DO UPDATE SET
+    flow_usd = EXCLUDED.flow_usd,
+    holdings_btc = COALESCE(EXCLUDED.holdings_btc, btc_etf_flows.holdings_btc),
+    updated_at = now()
+WHERE btc_etf_flows.flow_usd <> EXCLUDED.flow_usd
+   OR btc_etf_flows.holdings_btc IS DISTINCT FROM COALESCE(EXCLUDED.holdings_btc, btc_etf_flows.holdings_btc);
+
+-- name: ListBTCETFFlows :many
+-- Every flow, oldest first; cumulative flows need them all.
+SELECT ticker, date, flow_usd, holdings_btc FROM btc_etf_flows
+ORDER BY date, ticker;